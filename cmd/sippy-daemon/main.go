@@ -21,6 +21,7 @@ import (
 	"github.com/openshift/sippy/pkg/github/commenter"
 	"github.com/openshift/sippy/pkg/sippyserver"
 	"github.com/openshift/sippy/pkg/version"
+	"github.com/openshift/sippy/pkg/webhooks"
 )
 
 var logLevel = "info"
@@ -32,6 +33,7 @@ type SippyDaemonFlags struct {
 	GoogleCloudFlags *flags.GoogleCloudFlags
 
 	GithubCommenterFlags *flags.GithubCommenterFlags
+	WebhookFlags         *flags.WebhookFlags
 	MetricsAddr          string
 }
 
@@ -42,6 +44,7 @@ func NewSippyDaemonFlags() *SippyDaemonFlags {
 		CacheFlags:           flags.NewCacheFlags(),
 		GithubCommenterFlags: flags.NewGithubCommenterFlags(),
 		GoogleCloudFlags:     flags.NewGoogleCloudFlags(),
+		WebhookFlags:         flags.NewWebhookFlags(),
 	}
 }
 
@@ -51,6 +54,7 @@ func (f *SippyDaemonFlags) BindFlags(fs *pflag.FlagSet) {
 	f.DBFlags.BindFlags(fs)
 	f.GithubCommenterFlags.BindFlags(fs)
 	f.GoogleCloudFlags.BindFlags(fs)
+	f.WebhookFlags.BindFlags(fs)
 
 	fs.StringVar(&f.MetricsAddr, "listen-metrics", f.MetricsAddr, "The address to serve prometheus metrics on (default :2112)")
 }
@@ -65,7 +69,7 @@ func NewSippyDaemonCommand() *cobra.Command {
 	// rootCmd represents the base command when called without any subcommands
 	cmd := &cobra.Command{
 		Use:   "sippy-daemon",
-		Short: "Sippy daemon is used for on-going tasks like monitoring git repos for reporting risk analysis.",
+		Short: "Sippy daemon is used for on-going tasks like monitoring git repos for reporting risk analysis and delivering webhooks.",
 		PersistentPreRun: func(c *cobra.Command, args []string) {
 			fmt.Fprintf(os.Stdout, "sippy built from %s\n", version.Get().GitCommit)
 		},
@@ -122,6 +126,14 @@ func NewSippyDaemonCommand() *cobra.Command {
 				processes = append(processes, sippyserver.NewWorkProcessor(dbc, bigQueryClient, gcsClient.Bucket(f.GoogleCloudFlags.StorageBucket), cacheClient, ghCommenter, 10, 5*time.Minute, 5*time.Second, f.GithubCommenterFlags.CommentProcessingDryRun))
 			}
 
+			if f.WebhookFlags.Delivery {
+				dbc, err := f.DBFlags.GetDBClient()
+				if err != nil {
+					return err
+				}
+				processes = append(processes, webhooks.NewDispatcher(dbc, f.WebhookFlags.DeliveryInterval, f.WebhookFlags.AllowedHosts))
+			}
+
 			daemonServer := sippyserver.NewDaemonServer(processes)
 
 			// Serve our metrics endpoint for prometheus to scrape
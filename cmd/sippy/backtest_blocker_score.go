@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/flags"
+	"github.com/openshift/sippy/pkg/flags/configflags"
+)
+
+type BacktestBlockerScoreFlags struct {
+	DBFlags        *flags.PostgresFlags
+	ConfigFlags    *configflags.ConfigFlags
+	Release        string
+	Stream         string
+	Architecture   string
+	Since          time.Duration
+	PredictAfter   int
+	Threshold      int
+	ActualFraction float64
+}
+
+func NewBacktestBlockerScoreFlags() *BacktestBlockerScoreFlags {
+	return &BacktestBlockerScoreFlags{
+		DBFlags:        flags.NewPostgresDatabaseFlags(),
+		ConfigFlags:    configflags.NewConfigFlags(),
+		Stream:         "nightly",
+		Architecture:   "amd64",
+		Since:          90 * 24 * time.Hour,
+		PredictAfter:   2,
+		Threshold:      api.DefaultSuspectBlockerScore,
+		ActualFraction: 0.5,
+	}
+}
+
+func (f *BacktestBlockerScoreFlags) BindFlags(fs *pflag.FlagSet) {
+	f.DBFlags.BindFlags(fs)
+	f.ConfigFlags.BindFlags(fs)
+	fs.StringVar(&f.Release, "release", f.Release, "Release of the payload stream to replay, e.g. 4.22")
+	fs.StringVar(&f.Stream, "stream", f.Stream, "Payload stream to replay")
+	fs.StringVar(&f.Architecture, "arch", f.Architecture, "Architecture of the payload stream to replay")
+	fs.DurationVar(&f.Since, "since", f.Since, "How far back to replay rejected streaks")
+	fs.IntVar(&f.PredictAfter, "predict-after", f.PredictAfter, "Number of rejected payloads into a streak at which blockers are predicted")
+	fs.IntVar(&f.Threshold, "threshold", f.Threshold, "Blocker score at which a test is predicted to be a blocker")
+	fs.Float64Var(&f.ActualFraction, "actual-fraction", f.ActualFraction,
+		"Fraction of a streak's payloads a test must fail in, including the last, to have actually been a blocker")
+}
+
+func (f *BacktestBlockerScoreFlags) Validate() error {
+	if f.Release == "" {
+		return fmt.Errorf("--release is required")
+	}
+	if f.PredictAfter < 1 {
+		return fmt.Errorf("--predict-after must be at least 1")
+	}
+	if f.ActualFraction <= 0 || f.ActualFraction > 1 {
+		return fmt.Errorf("--actual-fraction must be greater than 0 and at most 1")
+	}
+	return nil
+}
+
+func NewBacktestBlockerScoreCommand() *cobra.Command {
+	f := NewBacktestBlockerScoreFlags()
+
+	cmd := &cobra.Command{
+		Use:   "backtest-blocker-score",
+		Short: "Replay historical rejected payload streaks to measure blocker scoring",
+		Long: `Replays the completed rejected streaks of a payload stream, scoring the test failures seen in the first
+payloads of each streak with the blocker weights from the sippy config, and reports the precision and recall of
+the predicted blockers against the tests that kept failing until the streak ended.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.Validate(); err != nil {
+				return err
+			}
+
+			cfg, err := f.ConfigFlags.GetConfig()
+			if err != nil {
+				return err
+			}
+			dbc, err := f.DBFlags.GetDBClient()
+			if err != nil {
+				return err
+			}
+
+			end := time.Now()
+			if pinned := f.DBFlags.GetPinnedTime(); pinned != nil {
+				end = *pinned
+			}
+			backtest, err := api.BacktestBlockerScorer(dbc, api.NewBlockerScorer(cfg, f.Release, f.Stream), api.BlockerBacktestOptions{
+				Release:        f.Release,
+				Stream:         f.Stream,
+				Architecture:   f.Architecture,
+				Start:          end.Add(-f.Since),
+				End:            end,
+				PredictAfter:   f.PredictAfter,
+				Threshold:      f.Threshold,
+				ActualFraction: f.ActualFraction,
+			})
+			if err != nil {
+				return err
+			}
+			log.WithFields(log.Fields{
+				"streaks":   len(backtest.Streaks),
+				"precision": backtest.Precision,
+				"recall":    backtest.Recall,
+			}).Info("blocker score backtest complete")
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(backtest)
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/componentreadiness"
+	"github.com/openshift/sippy/pkg/apis/cache"
+	"github.com/openshift/sippy/pkg/bigquery/bqlabel"
+	"github.com/openshift/sippy/pkg/flags"
+)
+
+type CacheCommandFlags struct {
+	CacheFlags              *flags.CacheFlags
+	BigQueryFlags           *flags.BigQueryFlags
+	GoogleCloudFlags        *flags.GoogleCloudFlags
+	ComponentReadinessFlags *flags.ComponentReadinessFlags
+
+	Key    string
+	Prefix string
+	View   string
+	Output string
+}
+
+func NewCacheCommandFlags() *CacheCommandFlags {
+	return &CacheCommandFlags{
+		CacheFlags:              flags.NewCacheFlags(),
+		BigQueryFlags:           flags.NewBigQueryFlags(),
+		GoogleCloudFlags:        flags.NewGoogleCloudFlags(),
+		ComponentReadinessFlags: flags.NewComponentReadinessFlags(),
+	}
+}
+
+func (f *CacheCommandFlags) BindFlags(fs *pflag.FlagSet) {
+	f.CacheFlags.BindFlags(fs)
+	f.BigQueryFlags.BindFlags(fs)
+	f.GoogleCloudFlags.BindFlags(fs)
+	f.ComponentReadinessFlags.BindFlags(fs)
+	fs.StringVar(&f.Key, "key", "", "Select the entry with exactly this key")
+	fs.StringVar(&f.Prefix, "prefix", "", "Select entries whose keys start with this prefix, e.g. ComponentReport~")
+	fs.StringVar(&f.View, "view", "", "Select the cached component and test details reports for this component readiness view")
+	fs.StringVar(&f.Output, "output", "table", "Output format: table or json")
+}
+
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and purge cached data",
+		Long: "Inspect and purge cached data. With --enable-persistent-cache and google credentials these operate on the " +
+			"BigQuery persistent cache and the cache in front of it, as the component readiness server does.",
+	}
+
+	cmd.AddCommand(newCacheListCommand())
+	cmd.AddCommand(newCacheDeleteCommand())
+	return cmd
+}
+
+func newCacheListCommand() *cobra.Command {
+	f := NewCacheCommandFlags()
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cached entries with their size and TTL",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			c, err := f.getCache(ctx)
+			if err != nil {
+				return err
+			}
+			entries, err := f.selectEntries(ctx, c)
+			if err != nil {
+				return err
+			}
+			return f.print(api.NewCacheEntries(entries, time.Now(), ""))
+		},
+	}
+	f.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func newCacheDeleteCommand() *cobra.Command {
+	f := NewCacheCommandFlags()
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete cached entries, e.g. all component readiness reports for a view after a bad data load",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if f.Key == "" && f.Prefix == "" && f.View == "" {
+				return fmt.Errorf("one of --key, --prefix or --view is required")
+			}
+			ctx := context.Background()
+			c, err := f.getCache(ctx)
+			if err != nil {
+				return err
+			}
+			entries, err := f.selectEntries(ctx, c)
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				log.Infof("dry run: would delete %d entries", len(entries))
+				return f.print(api.NewCacheEntries(entries, time.Now(), ""))
+			}
+			deleted, err := api.DeleteCacheEntries(ctx, c, entries)
+			log.Infof("deleted %d of %d entries", len(deleted), len(entries))
+			return err
+		},
+	}
+	f.BindFlags(cmd.Flags())
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the entries that would be deleted without deleting them")
+	return cmd
+}
+
+func (f *CacheCommandFlags) getCache(ctx context.Context) (cache.Cache, error) {
+	cacheClient, err := f.CacheFlags.GetCacheClient()
+	if err != nil {
+		return nil, errors.WithMessage(err, "couldn't get cache client")
+	}
+	if f.CacheFlags.EnablePersistentCaching && f.GoogleCloudFlags.ServiceAccountCredentialFile != "" {
+		opCtx := bqlabel.OperationalContext{
+			App:         bqlabel.AppSippy,
+			Command:     "cache",
+			Environment: bqlabel.EnvCli,
+			Operator:    os.Getenv("USER"),
+		}
+		bigQueryClient, err := f.BigQueryFlags.GetBigQueryClient(ctx, opCtx, cacheClient, f.GoogleCloudFlags.ServiceAccountCredentialFile)
+		if err != nil {
+			return nil, errors.WithMessage(err, "couldn't get bigquery client")
+		}
+		return f.CacheFlags.DecorateBiqQueryClientWithPersistentCache(bigQueryClient).Cache, nil
+	}
+	if cacheClient == nil {
+		return nil, fmt.Errorf("no cache configured; specify --redis-url or enable persistent caching")
+	}
+	return cacheClient, nil
+}
+
+func (f *CacheCommandFlags) selectEntries(ctx context.Context, c cache.Cache) ([]cache.Entry, error) {
+	switch {
+	case f.Key != "":
+		entries, err := c.List(ctx, f.Key)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Key == f.Key {
+				return []cache.Entry{entry}, nil
+			}
+		}
+		return nil, nil
+	case f.View != "":
+		views, err := f.ComponentReadinessFlags.ParseViewsFile()
+		if err != nil {
+			return nil, err
+		}
+		view, found := componentreadiness.FindViewByName(f.View, views.ComponentReadiness)
+		if !found {
+			return nil, fmt.Errorf("no view named %s in --views", f.View)
+		}
+		return componentreadiness.ViewCacheEntries(ctx, c, view)
+	default:
+		return c.List(ctx, f.Prefix)
+	}
+}
+
+func (f *CacheCommandFlags) print(entries api.CacheEntries) error {
+	if f.Output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SIZE\tTTL\tKEY")
+	for _, entry := range entries.Entries {
+		ttl := "none"
+		if entry.ExpiresAt != nil {
+			ttl = (time.Duration(entry.TTLSeconds) * time.Second).String()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", entry.Size, ttl, entry.Key)
+	}
+	fmt.Fprintf(w, "%d entries, %d bytes\n", len(entries.Entries), entries.TotalSize)
+	return w.Flush()
+}
@@ -74,6 +74,8 @@ func NewComponentReadinessCommand() *cobra.Command {
 	}
 
 	f.BindFlags(cmd.Flags())
+	cmd.AddCommand(NewComponentReadinessDiffCommand())
+	cmd.AddCommand(NewComponentReadinessExportCommand())
 
 	return cmd
 }
@@ -131,6 +133,10 @@ func (f *ComponentReadinessFlags) runServerMode() error {
 	if err != nil {
 		log.WithError(err).Fatal("could not load frontend")
 	}
+	chatPrompts, err := fs.Sub(resources.ChatPrompts, "chat/prompts")
+	if err != nil {
+		log.WithError(err).Fatal("could not load chat prompts")
+	}
 
 	cacheClient, err := f.CacheFlags.GetCacheClient()
 	if err != nil {
@@ -197,6 +203,7 @@ func (f *ComponentReadinessFlags) runServerMode() error {
 		nil,
 		webRoot,
 		&resources.Static,
+		chatPrompts,
 		dbc,
 		gcsClient,
 		f.GoogleCloudFlags.StorageBucket,
@@ -205,11 +212,13 @@ func (f *ComponentReadinessFlags) runServerMode() error {
 		nil,
 		cacheClient,
 		f.ComponentReadinessFlags.CRTimeRoundingFactor,
+		f.ComponentReadinessFlags.CRStaleWhileRevalidate,
 		views,
 		config,
 		f.APIFlags.EnableWriteEndpoints,
 		"", // No chat API in Component Readiness
 		jiraClient,
+		f.APIFlags.WebhookAllowedHosts,
 	)
 
 	if f.APIFlags.MetricsAddr != "" {
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/sippy/pkg/api/componentreadiness"
+	bqprovider "github.com/openshift/sippy/pkg/api/componentreadiness/dataprovider/bigquery"
+	"github.com/openshift/sippy/pkg/apis/cache"
+	bqcachedclient "github.com/openshift/sippy/pkg/bigquery"
+	"github.com/openshift/sippy/pkg/flags"
+	"github.com/openshift/sippy/pkg/flags/configflags"
+)
+
+type ComponentReadinessDiffFlags struct {
+	GoogleCloudFlags        *flags.GoogleCloudFlags
+	BigQueryFlags           *flags.BigQueryFlags
+	CacheFlags              *flags.CacheFlags
+	ComponentReadinessFlags *flags.ComponentReadinessFlags
+	ConfigFlags             *configflags.ConfigFlags
+	DBFlags                 *flags.PostgresFlags
+
+	View   string
+	From   string
+	To     string
+	Output string
+}
+
+func NewComponentReadinessDiffCommand() *cobra.Command {
+	f := &ComponentReadinessDiffFlags{
+		GoogleCloudFlags:        flags.NewGoogleCloudFlags(),
+		BigQueryFlags:           flags.NewBigQueryFlags(),
+		CacheFlags:              flags.NewCacheFlags(),
+		ComponentReadinessFlags: flags.NewComponentReadinessFlags(),
+		ConfigFlags:             configflags.NewConfigFlags(),
+		DBFlags:                 flags.NewPostgresDatabaseFlags(),
+		To:                      "now",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Diff a view's component report between two sample end dates",
+		Long: `Generates, or loads from cache, a view's component report with its sample window ending at two
+points in time, and prints the cells that newly regressed, cleared or changed status and the per-test
+pass rate deltas as JSON. Times accept the same formats as views, e.g. now-1d, ga-7d or RFC3339.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if f.View == "" || f.From == "" {
+				return fmt.Errorf("--view and --from are required")
+			}
+			if err := f.GoogleCloudFlags.Validate(); err != nil {
+				return errors.WithMessage(err, "error validating options")
+			}
+			return f.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func (f *ComponentReadinessDiffFlags) BindFlags(fs *pflag.FlagSet) {
+	f.GoogleCloudFlags.BindFlags(fs)
+	f.BigQueryFlags.BindFlags(fs)
+	f.CacheFlags.BindFlags(fs)
+	f.ComponentReadinessFlags.BindFlags(fs)
+	f.ConfigFlags.BindFlags(fs)
+	f.DBFlags.BindFlags(fs)
+	fs.StringVar(&f.View, "view", f.View, "Name of the view to diff")
+	fs.StringVar(&f.From, "from", f.From, "Sample end time of the earlier report")
+	fs.StringVar(&f.To, "to", f.To, "Sample end time of the later report")
+	fs.StringVar(&f.Output, "output", f.Output, "Path to write the diff to, defaults to stdout")
+}
+
+func (f *ComponentReadinessDiffFlags) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	views, err := f.ComponentReadinessFlags.ParseViewsFile()
+	if err != nil {
+		return errors.WithMessage(err, "unable to load views")
+	}
+	view, found := componentreadiness.FindViewByName(f.View, views.ComponentReadiness)
+	if !found {
+		return fmt.Errorf("view %q not found in views", f.View)
+	}
+	config, err := f.ConfigFlags.GetConfig()
+	if err != nil {
+		return errors.WithMessage(err, "error reading config file")
+	}
+
+	cacheClient, err := f.CacheFlags.GetCacheClient()
+	if err != nil {
+		return errors.WithMessage(err, "couldn't get cache client")
+	}
+	opCtx, ctx := bqcachedclient.OpCtxForCronEnv(ctx, "component-readiness diff")
+	bigQueryClient, err := f.BigQueryFlags.GetBigQueryClient(ctx, opCtx, cacheClient, f.GoogleCloudFlags.ServiceAccountCredentialFile)
+	if err != nil {
+		return errors.WithMessage(err, "couldn't get bigquery client")
+	}
+	provider := bqprovider.NewBigQueryProvider(bigQueryClient, config.ComponentReadinessConfig.VariantJunitTableOverrides)
+	releases, err := provider.QueryReleases(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "couldn't query releases")
+	}
+
+	// the database supplies regression tracking and triage, as it does for reports served by the API
+	dbc, err := f.DBFlags.GetDBClient()
+	if err != nil {
+		log.WithError(err).Warn("unable to connect to postgres, reports will not reflect regression tracking or triage")
+	}
+
+	diff, err := componentreadiness.GetReportDiff(ctx, provider, dbc, view, releases,
+		cache.NewStandardCROptions(f.ComponentReadinessFlags.CRTimeRoundingFactor), f.From, f.To, "")
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if f.Output != "" {
+		out, err = os.Create(f.Output)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diff)
+}
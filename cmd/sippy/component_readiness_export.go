@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/sippy/pkg/api/componentreadiness"
+	bqprovider "github.com/openshift/sippy/pkg/api/componentreadiness/dataprovider/bigquery"
+	"github.com/openshift/sippy/pkg/api/componentreadiness/utils"
+	crtype "github.com/openshift/sippy/pkg/apis/api/componentreport"
+	"github.com/openshift/sippy/pkg/apis/cache"
+	bqcachedclient "github.com/openshift/sippy/pkg/bigquery"
+	"github.com/openshift/sippy/pkg/flags"
+	"github.com/openshift/sippy/pkg/flags/configflags"
+)
+
+type ComponentReadinessExportFlags struct {
+	GoogleCloudFlags        *flags.GoogleCloudFlags
+	BigQueryFlags           *flags.BigQueryFlags
+	CacheFlags              *flags.CacheFlags
+	ComponentReadinessFlags *flags.ComponentReadinessFlags
+	ConfigFlags             *configflags.ConfigFlags
+	DBFlags                 *flags.PostgresFlags
+
+	View      string
+	Formats   []string
+	OutputDir string
+}
+
+func NewComponentReadinessExportCommand() *cobra.Command {
+	f := &ComponentReadinessExportFlags{
+		GoogleCloudFlags:        flags.NewGoogleCloudFlags(),
+		BigQueryFlags:           flags.NewBigQueryFlags(),
+		CacheFlags:              flags.NewCacheFlags(),
+		ComponentReadinessFlags: flags.NewComponentReadinessFlags(),
+		ConfigFlags:             configflags.NewConfigFlags(),
+		DBFlags:                 flags.NewPostgresDatabaseFlags(),
+		Formats:                 []string{string(componentreadiness.ExportCSV), string(componentreadiness.ExportMarkdown)},
+		OutputDir:               ".",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export a view's component report to files",
+		Long: `Generates, or loads from cache, a view's component report and writes it to <view>.<ext> in the
+output directory for each requested format: json, csv (one row per regressed test and variant),
+markdown (summary tables for release status emails) or junit (untriaged regressions as failures).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if f.View == "" {
+				return fmt.Errorf("--view is required")
+			}
+			if err := f.GoogleCloudFlags.Validate(); err != nil {
+				return errors.WithMessage(err, "error validating options")
+			}
+			return f.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func (f *ComponentReadinessExportFlags) BindFlags(fs *pflag.FlagSet) {
+	f.GoogleCloudFlags.BindFlags(fs)
+	f.BigQueryFlags.BindFlags(fs)
+	f.CacheFlags.BindFlags(fs)
+	f.ComponentReadinessFlags.BindFlags(fs)
+	f.ConfigFlags.BindFlags(fs)
+	f.DBFlags.BindFlags(fs)
+	fs.StringVar(&f.View, "view", f.View, "Name of the view to export")
+	fs.StringSliceVar(&f.Formats, "format", f.Formats, "Formats to export: json, csv, markdown or junit")
+	fs.StringVar(&f.OutputDir, "output-dir", f.OutputDir, "Directory to write the exported reports to")
+}
+
+func (f *ComponentReadinessExportFlags) Run() error {
+	formats := make([]componentreadiness.ExportFormat, 0, len(f.Formats))
+	for _, name := range f.Formats {
+		format, err := componentreadiness.ParseExportFormat(name)
+		if err != nil {
+			return err
+		}
+		formats = append(formats, format)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	views, err := f.ComponentReadinessFlags.ParseViewsFile()
+	if err != nil {
+		return errors.WithMessage(err, "unable to load views")
+	}
+	view, found := componentreadiness.FindViewByName(f.View, views.ComponentReadiness)
+	if !found {
+		return fmt.Errorf("view %q not found in views", f.View)
+	}
+	config, err := f.ConfigFlags.GetConfig()
+	if err != nil {
+		return errors.WithMessage(err, "error reading config file")
+	}
+
+	cacheClient, err := f.CacheFlags.GetCacheClient()
+	if err != nil {
+		return errors.WithMessage(err, "couldn't get cache client")
+	}
+	opCtx, ctx := bqcachedclient.OpCtxForCronEnv(ctx, "component-readiness export")
+	bigQueryClient, err := f.BigQueryFlags.GetBigQueryClient(ctx, opCtx, cacheClient, f.GoogleCloudFlags.ServiceAccountCredentialFile)
+	if err != nil {
+		return errors.WithMessage(err, "couldn't get bigquery client")
+	}
+	provider := bqprovider.NewBigQueryProvider(bigQueryClient, config.ComponentReadinessConfig.VariantJunitTableOverrides)
+	releases, err := provider.QueryReleases(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "couldn't query releases")
+	}
+
+	opts, err := utils.ViewRequestOptions(releases, view, cache.NewStandardCROptions(f.ComponentReadinessFlags.CRTimeRoundingFactor))
+	if err != nil {
+		return errors.WithMessagef(err, "couldn't build request options for view %s", view.Name)
+	}
+
+	// the database supplies regression tracking and triage, as it does for reports served by the API
+	dbc, err := f.DBFlags.GetDBClient()
+	if err != nil {
+		log.WithError(err).Warn("unable to connect to postgres, reports will not reflect regression tracking or triage")
+	}
+
+	report, errs := componentreadiness.GetComponentReport(ctx, provider, dbc, opts, "")
+	if len(errs) > 0 {
+		return fmt.Errorf("error generating report for view %s: %v", view.Name, errs)
+	}
+
+	for _, format := range formats {
+		path := filepath.Join(f.OutputDir, fmt.Sprintf("%s.%s", view.Name, format.FileExtension()))
+		if err := writeExport(path, format, report, view.Name); err != nil {
+			return errors.WithMessagef(err, "couldn't write %s", path)
+		}
+		log.Infof("wrote %s", path)
+	}
+	return nil
+}
+
+func writeExport(path string, format componentreadiness.ExportFormat, report crtype.ComponentReport, title string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := componentreadiness.WriteReport(out, format, report, title); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
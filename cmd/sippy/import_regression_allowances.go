@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/flags"
+	"github.com/openshift/sippy/pkg/regressionallowances"
+)
+
+func init() {
+	f := flags.NewPostgresDatabaseFlags()
+	var user string
+
+	cmd := &cobra.Command{
+		Use:   "import-regression-allowances",
+		Short: "Import the intentional regression allowances compiled into sippy into the database",
+		Long: `Import the intentional regression allowances from pkg/regressionallowances/regressions into the
+regression_allowances table, where component readiness reads them from and they can be managed via
+/api/component_readiness/allowances. Allowances already in the database are left untouched, so this
+is safe to re-run. Once a release has allowances in the database its compiled-in allowances no longer
+apply, so re-run this after adding to the regressions directory; note that doing so recreates any
+compiled-in allowances deleted through the API.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dbc, err := f.GetDBClient()
+			if err != nil {
+				return errors.WithMessage(err, "could not connect to database")
+			}
+
+			ctx := context.WithValue(context.Background(), models.CurrentUserKey, user)
+			created, err := regressionallowances.ImportEmbeddedAllowances(ctx, dbc)
+			if err != nil {
+				return errors.WithMessage(err, "could not import regression allowances")
+			}
+			log.Infof("imported %d regression allowances", created)
+			return nil
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+	cmd.Flags().StringVar(&user, "user", "sippy-import", "User recorded in the audit log for imported allowances")
+
+	rootCmd.AddCommand(cmd)
+}
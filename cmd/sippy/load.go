@@ -42,6 +42,7 @@ import (
 	"github.com/openshift/sippy/pkg/db"
 	"github.com/openshift/sippy/pkg/flags"
 	"github.com/openshift/sippy/pkg/github/commenter"
+	"github.com/openshift/sippy/pkg/webhooks"
 )
 
 type LoadFlags struct {
@@ -65,6 +66,7 @@ type LoadFlags struct {
 	LogLevel                string
 	ProwLoadSince           string
 	SkipMatviewRefresh      bool
+	WebhookBaseURL          string
 }
 
 // want a single total load and refresh time
@@ -106,6 +108,7 @@ func (f *LoadFlags) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&f.LogLevel, "log-level", "info", "Log level")
 	fs.StringVar(&f.ProwLoadSince, "prow-load-since", "", "Override how far back to load prow jobs (e.g. 2024-01-15T00:00:00Z or 72h for 72 hours ago)")
 	fs.BoolVar(&f.SkipMatviewRefresh, "skip-matview-refresh", false, "Skip refreshing materialized views after loading")
+	fs.StringVar(&f.WebhookBaseURL, "webhook-base-url", webhooks.DefaultBaseURL, "Sippy URL that links in webhook events point to")
 }
 
 // nolint:gocyclo
@@ -217,7 +220,7 @@ func NewLoadCommand() *cobra.Command {
 					if jErr != nil {
 						return errors.Wrap(jErr, "CRITICAL error getting jira client which prevents regression tracking")
 					}
-					regressionStore := componentreadiness.NewPostgresRegressionStore(dbc, jiraClient)
+					regressionStore := componentreadiness.NewPostgresRegressionStore(dbc, jiraClient, f.WebhookBaseURL)
 
 					rcl, err := regressioncacheloader.New(
 						dbc, bqc, config, views.ComponentReadiness, releaseConfigs,
@@ -285,7 +288,7 @@ func NewLoadCommand() *cobra.Command {
 					if bigqueryErr != nil {
 						return errors.WithMessage(err, "could not get bigquery client")
 					}
-					loaders = append(loaders, bugloader.New(dbc, bqc))
+					loaders = append(loaders, bugloader.New(dbc, bqc, f.WebhookBaseURL))
 				}
 
 				// Load Job Variants into BigQuery
@@ -45,6 +45,8 @@ func main() {
 		NewVersionCommand(),
 		NewAnnotateJobRunsCommand(),
 		NewSeedDataCommand(),
+		NewCacheCommand(),
+		NewBacktestBlockerScoreCommand(),
 	)
 
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info",
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"crypto/md5" // nolint: gosec
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -118,6 +120,12 @@ rolled off the 1 week window.
 			}
 			log.Infof("Processed %d Test models", len(f.TestNames))
 
+			// Map the tests to components
+			if err := createTestOwnerships(dbc, f.TestNames); err != nil {
+				return errors.WithMessage(err, "failed to create TestOwnerships")
+			}
+			log.Infof("Processed %d TestOwnerships", len(f.TestNames))
+
 			// Create labels and symptoms
 			if err := createLabelsAndSymptoms(dbc); err != nil {
 				return errors.WithMessage(err, "failed to create labels and symptoms")
@@ -162,12 +170,13 @@ func createProwJobsForRelease(dbc *db.DB, release string, jobsPerRelease int) er
 			jobTier = "JobTier:hidden" // odd = hidden
 		}
 
+		// Component readiness only considers jobs with a periodic-, release- or aggregator- prefix
 		prowJob := models.ProwJob{
 			Kind:    models.ProwKind("periodic"),
-			Name:    fmt.Sprintf("sippy-test-job-%s-test-%d", release, i),
+			Name:    fmt.Sprintf("periodic-ci-openshift-sippy-test-job-%s-test-%d", release, i),
 			Release: release,
 			// TestGridURL, Bugs, and JobRuns are left empty as requested
-			Variants: []string{"Platform:aws", "Upgrade:none", jobTier},
+			Variants: []string{"Platform:aws", "Architecture:amd64", "Network:ovn", "Upgrade:none", jobTier},
 		}
 
 		// Use FirstOrCreate to avoid duplicates - only creates if a ProwJob with this name doesn't exist
@@ -209,6 +218,53 @@ func createTestModels(dbc *db.DB, testNames []string) error {
 	return nil
 }
 
+// createTestOwnerships assigns each test a component and capability, which component readiness
+// requires to report on a test.
+func createTestOwnerships(dbc *db.DB, testNames []string) error {
+	var suite models.Suite
+	if err := dbc.DB.Where("name = ?", "ourtests").First(&suite).Error; err != nil {
+		return fmt.Errorf("failed to find Suite 'ourtests': %v", err)
+	}
+
+	for _, testName := range testNames {
+		var test models.Test
+		if err := dbc.DB.Where("name = ?", testName).First(&test).Error; err != nil {
+			return fmt.Errorf("failed to find Test %s: %v", testName, err)
+		}
+
+		component, capability := seedComponentForTest(testName)
+		ownership := models.TestOwnership{
+			APIVersion:   "v1",
+			Kind:         "TestOwnership",
+			UniqueID:     fmt.Sprintf("%s:%x", suite.Name, md5.Sum([]byte(testName))), // nolint: gosec
+			Name:         testName,
+			TestID:       test.ID,
+			Suite:        suite.Name,
+			SuiteID:      &suite.ID,
+			Component:    component,
+			Capabilities: []string{capability},
+		}
+
+		var existing models.TestOwnership
+		if err := dbc.DB.Where("name = ? AND suite = ?", ownership.Name, ownership.Suite).FirstOrCreate(&existing, ownership).Error; err != nil {
+			return fmt.Errorf("failed to create or find TestOwnership for %s: %v", testName, err)
+		}
+	}
+
+	return nil
+}
+
+func seedComponentForTest(testName string) (string, string) {
+	switch {
+	case strings.HasPrefix(testName, "install should succeed"):
+		return "Installer", "Install"
+	case strings.Contains(strings.ToLower(testName), "upgrade"):
+		return "Cluster Version Operator", "Upgrade"
+	default:
+		return "Sippy", "Other"
+	}
+}
+
 func createTestSuite(dbc *db.DB) error {
 	suite := models.Suite{
 		Name: "ourtests",
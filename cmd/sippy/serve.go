@@ -19,6 +19,7 @@ import (
 	resources "github.com/openshift/sippy"
 	"github.com/openshift/sippy/pkg/api/componentreadiness/dataprovider"
 	bqprovider "github.com/openshift/sippy/pkg/api/componentreadiness/dataprovider/bigquery"
+	pgprovider "github.com/openshift/sippy/pkg/api/componentreadiness/dataprovider/postgres"
 	"github.com/openshift/sippy/pkg/apis/cache"
 	"github.com/openshift/sippy/pkg/bigquery"
 	"github.com/openshift/sippy/pkg/bigquery/bqlabel"
@@ -69,7 +70,7 @@ func (f *ServerFlags) BindFlags(flagSet *pflag.FlagSet) {
 	f.ConfigFlags.BindFlags(flagSet)
 	f.APIFlags.BindFlags(flagSet)
 	f.JiraFlags.BindFlags(flagSet)
-	flagSet.StringVar(&f.DataProvider, "data-provider", "bigquery", "Data provider for component readiness: bigquery")
+	flagSet.StringVar(&f.DataProvider, "data-provider", "bigquery", "Data provider for component readiness: bigquery or postgres")
 }
 
 func (f *ServerFlags) Validate() error {
@@ -132,8 +133,11 @@ func NewServeCommand() *cobra.Command {
 					crDataProvider = bqprovider.NewBigQueryProvider(bigQueryClient, config.ComponentReadinessConfig.VariantJunitTableOverrides)
 				}
 
+			case "postgres":
+				crDataProvider = pgprovider.NewPostgresProvider(dbc, cacheClient)
+
 			default:
-				return fmt.Errorf("unknown --data-provider %q, must be bigquery", f.DataProvider)
+				return fmt.Errorf("unknown --data-provider %q, must be bigquery or postgres", f.DataProvider)
 			}
 
 			gcsClient, err = gcs.NewGCSClient(context.TODO(),
@@ -155,6 +159,10 @@ func NewServeCommand() *cobra.Command {
 			if err != nil {
 				log.WithError(err).Fatal("could not load frontend")
 			}
+			chatPrompts, err := fs.Sub(resources.ChatPrompts, "chat/prompts")
+			if err != nil {
+				log.WithError(err).Fatal("could not load chat prompts")
+			}
 
 			pinnedDateTime := f.DBFlags.GetPinnedTime()
 
@@ -180,6 +188,7 @@ func NewServeCommand() *cobra.Command {
 				variantManager,
 				webRoot,
 				&resources.Static,
+				chatPrompts,
 				dbc,
 				gcsClient,
 				f.GoogleCloudFlags.StorageBucket,
@@ -188,11 +197,13 @@ func NewServeCommand() *cobra.Command {
 				pinnedDateTime,
 				cacheClient,
 				f.ComponentReadinessFlags.CRTimeRoundingFactor,
+				f.ComponentReadinessFlags.CRStaleWhileRevalidate,
 				views,
 				config,
 				f.APIFlags.EnableWriteEndpoints,
 				f.APIFlags.ChatAPIURL,
 				jiraClient,
+				f.APIFlags.WebhookAllowedHosts,
 			)
 
 			if f.APIFlags.MetricsAddr != "" {
@@ -12,5 +12,7 @@ func NewVariantsCommand() *cobra.Command {
 
 	cmd.AddCommand(NewVariantSnapshotCommand())
 	cmd.AddCommand(NewVariantsGenerateCommand())
+	cmd.AddCommand(NewVariantsExplainCommand())
+	cmd.AddCommand(NewVariantsDriftCommand())
 	return cmd
 }
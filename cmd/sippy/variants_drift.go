@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/api/option"
+
+	bqcachedclient "github.com/openshift/sippy/pkg/bigquery"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/flags"
+	"github.com/openshift/sippy/pkg/variantregistry"
+)
+
+type VariantsDriftFlags struct {
+	BigQueryFlags    *flags.BigQueryFlags
+	GoogleCloudFlags *flags.GoogleCloudFlags
+	DBFlags          *flags.PostgresFlags
+	ExpectedFile     string
+	JobVariantsTable string
+	Stores           []string
+	FailOn           []string
+	IgnoredVariants  []string
+	SaveReport       bool
+}
+
+func NewVariantsDriftFlags() *VariantsDriftFlags {
+	policy := variantregistry.DefaultDriftPolicy()
+	return &VariantsDriftFlags{
+		BigQueryFlags:    flags.NewBigQueryFlags(),
+		GoogleCloudFlags: flags.NewGoogleCloudFlags(),
+		DBFlags:          flags.NewPostgresDatabaseFlags(),
+		ExpectedFile:     "expected-job-variants.json",
+		JobVariantsTable: "job_variants",
+		Stores:           []string{variantregistry.DriftStoreBigQuery, variantregistry.DriftStorePostgres},
+		FailOn:           policy.FailOn,
+	}
+}
+
+func (f *VariantsDriftFlags) BindFlags(fs *pflag.FlagSet) {
+	f.BigQueryFlags.BindFlags(fs)
+	f.GoogleCloudFlags.BindFlags(fs)
+	f.DBFlags.BindFlags(fs)
+	fs.StringVar(&f.ExpectedFile, "expected", f.ExpectedFile, "Expected job variants JSON written by 'sippy variants generate'")
+	fs.StringVar(&f.JobVariantsTable, "job-variants-table", f.JobVariantsTable, "BigQuery table holding the job variant registry")
+	fs.StringSliceVar(&f.Stores, "stores", f.Stores, "Stores to check for drift: bigquery, postgres")
+	fs.StringSliceVar(&f.FailOn, "fail-on", f.FailOn, "Drift categories that are unexpected and fail the command: added, removed, changed")
+	fs.StringSliceVar(&f.IgnoredVariants, "ignore-variant", f.IgnoredVariants, "Variants whose changes are never unexpected")
+	fs.BoolVar(&f.SaveReport, "save", false, "Save the report to the database, where it is served by /api/variants/drift")
+}
+
+func (f *VariantsDriftFlags) policy() variantregistry.DriftPolicy {
+	return variantregistry.DriftPolicy{FailOn: f.FailOn, IgnoredVariants: f.IgnoredVariants}
+}
+
+func (f *VariantsDriftFlags) Validate() error {
+	for _, store := range f.Stores {
+		if store != variantregistry.DriftStoreBigQuery && store != variantregistry.DriftStorePostgres {
+			return fmt.Errorf("unknown store %q, must be bigquery or postgres", store)
+		}
+	}
+	return f.policy().Validate()
+}
+
+func (f *VariantsDriftFlags) usesStore(store string) bool {
+	for _, s := range f.Stores {
+		if s == store {
+			return true
+		}
+	}
+	return false
+}
+
+func NewVariantsDriftCommand() *cobra.Command {
+	f := NewVariantsDriftFlags()
+
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Report how stored job variants differ from the expected variants",
+		Long: `Compares the expected job variants written by 'sippy variants generate' with the variants currently stored
+in the BigQuery job variant registry and with prow jobs in the database. Jobs are categorized as added, removed,
+or changed per variant. The database only stores important variants for jobs it has seen, so only changes are
+reported for it. The report is printed as JSON, and the command exits non-zero when drift is unexpected.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			report, err := f.Run()
+			if err != nil {
+				return err
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return err
+			}
+			if report.Unexpected {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("unexpected job variant drift")
+			}
+			return nil
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *VariantsDriftFlags) Run() (*models.VariantDriftReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	data, err := os.ReadFile(f.ExpectedFile)
+	if err != nil {
+		return nil, err
+	}
+	expected := variantregistry.JobVariants{}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return nil, errors.Wrapf(err, "error parsing expected job variants in %s", f.ExpectedFile)
+	}
+	log.Infof("loaded %d expected jobs from %s", len(expected), f.ExpectedFile)
+
+	var drifts []models.VariantStoreDrift
+	if f.usesStore(variantregistry.DriftStoreBigQuery) {
+		bigQueryClient, err := bigquery.NewClient(ctx, f.BigQueryFlags.BigQueryProject,
+			option.WithCredentialsFile(f.GoogleCloudFlags.ServiceAccountCredentialFile))
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting BigQuery client")
+		}
+		opCtx, ctx := bqcachedclient.OpCtxForCronEnv(ctx, "variants drift")
+		table := fmt.Sprintf("%s.%s.%s", f.BigQueryFlags.BigQueryProject, f.BigQueryFlags.BigQueryDataset, f.JobVariantsTable)
+		stored, err := variantregistry.LoadCurrentJobVariants(ctx, bigQueryClient, opCtx, table)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, variantregistry.DiffJobVariants(variantregistry.DriftStoreBigQuery, expected, stored, nil, false))
+	}
+
+	needsDB := f.usesStore(variantregistry.DriftStorePostgres) || f.SaveReport
+	if !needsDB {
+		report := variantregistry.NewVariantDriftReport(expected, f.policy(), drifts...)
+		return &report, nil
+	}
+
+	dbc, err := f.DBFlags.GetDBClient()
+	if err != nil {
+		return nil, err
+	}
+	if f.usesStore(variantregistry.DriftStorePostgres) {
+		stored, err := variantregistry.LoadPostgresJobVariants(dbc)
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading prow job variants")
+		}
+		drifts = append(drifts, variantregistry.PostgresDrift(expected, stored))
+	}
+
+	report := variantregistry.NewVariantDriftReport(expected, f.policy(), drifts...)
+	if f.SaveReport {
+		if res := dbc.DB.Create(&report); res.Error != nil {
+			return nil, errors.Wrap(res.Error, "error saving variant drift report")
+		}
+	}
+	return &report, nil
+}
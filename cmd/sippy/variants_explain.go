@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/sippy/pkg/api"
+	bqcachedclient "github.com/openshift/sippy/pkg/bigquery"
+	"github.com/openshift/sippy/pkg/bigquery/bqlabel"
+	"github.com/openshift/sippy/pkg/flags"
+	"github.com/openshift/sippy/pkg/flags/configflags"
+	"github.com/openshift/sippy/pkg/variantregistry"
+)
+
+type VariantsExplainFlags struct {
+	BigQueryFlags           *flags.BigQueryFlags
+	GoogleCloudFlags        *flags.GoogleCloudFlags
+	ConfigFlags             *configflags.ConfigFlags
+	ComponentReadinessFlags *flags.ComponentReadinessFlags
+	VariantRulesFlags       *flags.VariantRulesFlags
+	ClusterDataFile         string
+	Output                  string
+}
+
+func NewVariantsExplainFlags() *VariantsExplainFlags {
+	return &VariantsExplainFlags{
+		BigQueryFlags:           flags.NewBigQueryFlags(),
+		GoogleCloudFlags:        flags.NewGoogleCloudFlags(),
+		ConfigFlags:             configflags.NewConfigFlags(),
+		ComponentReadinessFlags: flags.NewComponentReadinessFlags(),
+		VariantRulesFlags:       flags.NewVariantRulesFlags(),
+		Output:                  "text",
+	}
+}
+
+func (f *VariantsExplainFlags) BindFlags(fs *pflag.FlagSet) {
+	f.BigQueryFlags.BindFlags(fs)
+	f.GoogleCloudFlags.BindFlags(fs)
+	f.ConfigFlags.BindFlags(fs)
+	f.ComponentReadinessFlags.BindFlags(fs)
+	f.VariantRulesFlags.BindFlags(fs)
+	fs.StringVar(&f.ClusterDataFile, "cluster-data", f.ClusterDataFile, "Path to a job run's cluster-data.json to merge in, as variant generation does")
+	fs.StringVar(&f.Output, "output", f.Output, "Output format: text or json")
+}
+
+func NewVariantsExplainCommand() *cobra.Command {
+	f := NewVariantsExplainFlags()
+
+	cmd := &cobra.Command{
+		Use:   "explain <job>",
+		Short: "Explain which variant rule set each of a job's variants",
+		Long: `Calculates a job's variants from its name, and optionally a run's cluster-data.json, and prints the rule,
+builtin, default, variants file or view adjustment that set each variant. Synthetic release claims are only
+applied when a Google service account credential file is given to look up releases in BigQuery.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if f.ConfigFlags.Path == "" {
+				return fmt.Errorf("--config is required")
+			}
+			if f.Output != "text" && f.Output != "json" {
+				return fmt.Errorf("--output must be text or json")
+			}
+			return f.Run(args[0])
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *VariantsExplainFlags) Run(jobName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	cfg, err := f.ConfigFlags.GetConfig()
+	if err != nil {
+		return err
+	}
+	views, err := f.ComponentReadinessFlags.ParseViewsFile()
+	if err != nil {
+		return err
+	}
+	rules, err := f.VariantRulesFlags.GetRuleSet()
+	if err != nil {
+		return err
+	}
+
+	var syntheticReleaseJobOverrides map[string]string
+	if f.GoogleCloudFlags.ServiceAccountCredentialFile != "" {
+		opCtx, ctx := bqcachedclient.OpCtxForCronEnv(ctx, "variants explain")
+		bqClient, err := f.BigQueryFlags.GetBigQueryClient(ctx, opCtx, nil, f.GoogleCloudFlags.ServiceAccountCredentialFile)
+		if err != nil {
+			return errors.Wrap(err, "error getting BigQuery client")
+		}
+		releaseConfigs, err := api.GetReleasesFromBigQuery(ctx, bqClient)
+		if err != nil {
+			return errors.Wrap(err, "error loading releases from BigQuery")
+		}
+		syntheticReleaseJobOverrides, err = variantregistry.BuildSyntheticReleaseJobOverrides(cfg.Releases, releaseConfigs)
+		if err != nil {
+			return errors.Wrap(err, "error building synthetic release job overrides")
+		}
+	}
+
+	var clusterData []byte
+	if f.ClusterDataFile != "" {
+		clusterData, err = os.ReadFile(f.ClusterDataFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	loader := variantregistry.NewOCPVariantLoader(nil, bqlabel.OperationalContext{}, "", "", "", nil,
+		cfg, views.ComponentReadiness, syntheticReleaseJobOverrides, rules)
+	variants, explanation, err := loader.ExplainJobVariants(log.WithField("job", jobName), jobName, clusterData)
+	if err != nil {
+		return err
+	}
+
+	if f.Output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]any{"job": jobName, "variants": variants, "explanation": explanation})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VARIANT\tVALUE\tSOURCE\tDETAIL")
+	for _, source := range explanation {
+		detail := source.Detail
+		if source.Rule != nil {
+			detail = fmt.Sprintf("#%d %s", *source.Rule, detail)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", source.Variant, source.Value, source.Source, detail)
+	}
+	return w.Flush()
+}
@@ -27,6 +27,7 @@ type VariantsGenerateFlags struct {
 	GoogleCloudFlags        *flags.GoogleCloudFlags
 	ConfigFlags             *configflags.ConfigFlags
 	ComponentReadinessFlags *flags.ComponentReadinessFlags
+	VariantRulesFlags       *flags.VariantRulesFlags
 	OutputFile              string
 	Mode                    string
 	BigqueryJobsTable       string
@@ -38,6 +39,7 @@ func NewVariantsGenerateFlags() *VariantsGenerateFlags {
 		GoogleCloudFlags:        flags.NewGoogleCloudFlags(),
 		ConfigFlags:             configflags.NewConfigFlags(),
 		ComponentReadinessFlags: flags.NewComponentReadinessFlags(),
+		VariantRulesFlags:       flags.NewVariantRulesFlags(),
 	}
 }
 
@@ -46,6 +48,7 @@ func (f *VariantsGenerateFlags) BindFlags(fs *pflag.FlagSet) {
 	f.GoogleCloudFlags.BindFlags(fs)
 	f.ConfigFlags.BindFlags(fs)
 	f.ComponentReadinessFlags.BindFlags(fs)
+	f.VariantRulesFlags.BindFlags(fs)
 	fs.StringVar(&f.OutputFile, "o", "expected-job-variants.json", "Output json file for job variant data")
 	fs.StringVar(&f.Mode, "mode", "ocp", "Implementation of job variant generator")
 	fs.StringVar(&f.BigqueryJobsTable, "bigquery-jobs-table", "jobs", "Jobs table to load job names from")
@@ -94,6 +97,10 @@ func NewVariantsGenerateCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			rules, err := f.VariantRulesFlags.GetRuleSet()
+			if err != nil {
+				return err
+			}
 
 			var jsonData []byte
 			switch f.Mode {
@@ -122,7 +129,8 @@ func NewVariantsGenerateCommand() *cobra.Command {
 					gcsClient,
 					config,
 					views.ComponentReadiness,
-					syntheticReleaseJobOverrides)
+					syntheticReleaseJobOverrides,
+					rules)
 				expectedVariants, err := jvs.LoadExpectedJobVariants(ctx)
 				if err != nil {
 					return err
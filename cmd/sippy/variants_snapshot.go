@@ -22,6 +22,7 @@ type VariantSnapshotFlags struct {
 	GoogleCloudFlags        *flags.GoogleCloudFlags
 	ConfigFlags             *configflags.ConfigFlags
 	ComponentReadinessFlags *flags.ComponentReadinessFlags
+	VariantRulesFlags       *flags.VariantRulesFlags
 }
 
 func NewVariantSnapshotFlags() *VariantSnapshotFlags {
@@ -30,6 +31,7 @@ func NewVariantSnapshotFlags() *VariantSnapshotFlags {
 		GoogleCloudFlags:        flags.NewGoogleCloudFlags(),
 		ConfigFlags:             configflags.NewConfigFlags(),
 		ComponentReadinessFlags: flags.NewComponentReadinessFlags(),
+		VariantRulesFlags:       flags.NewVariantRulesFlags(),
 		Path:                    "pkg/variantregistry/snapshot.yaml",
 	}
 }
@@ -39,6 +41,7 @@ func (f *VariantSnapshotFlags) BindFlags(fs *pflag.FlagSet) {
 	f.GoogleCloudFlags.BindFlags(fs)
 	f.ConfigFlags.BindFlags(fs)
 	f.ComponentReadinessFlags.BindFlags(fs)
+	f.VariantRulesFlags.BindFlags(fs)
 	fs.StringVar(&f.Path, "out", f.Path, "Path to write results to")
 }
 
@@ -79,9 +82,13 @@ func NewVariantSnapshotCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			rules, err := f.VariantRulesFlags.GetRuleSet()
+			if err != nil {
+				return err
+			}
 
 			lgr := log.New()
-			snapshot := variantregistry.NewVariantSnapshot(cfg, views.ComponentReadiness, syntheticReleaseJobOverrides, lgr)
+			snapshot := variantregistry.NewVariantSnapshot(cfg, views.ComponentReadiness, syntheticReleaseJobOverrides, rules, lgr)
 			if err := snapshot.Save(f.Path); err != nil {
 				lgr.WithError(err).Fatal("error updating snapshot")
 			}
@@ -7,3 +7,8 @@ var SippyNG embed.FS
 
 //go:embed static
 var Static embed.FS
+
+// ChatPrompts are the prompt templates shared by the chat service and the MCP server.
+//
+//go:embed chat/prompts
+var ChatPrompts embed.FS
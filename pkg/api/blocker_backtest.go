@@ -0,0 +1,235 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/db/query"
+	"github.com/openshift/sippy/pkg/testidentification"
+)
+
+// BlockerBacktestOptions control how a blocker scorer is replayed against historical rejected streaks.
+type BlockerBacktestOptions struct {
+	Release      string    `json:"release"`
+	Stream       string    `json:"stream"`
+	Architecture string    `json:"architecture"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	// PredictAfter is how many rejected payloads into a streak the scorer is run, as it would be by someone
+	// investigating the streak. Streaks shorter than this are skipped.
+	PredictAfter int `json:"predict_after"`
+	// Threshold is the blocker score at which a test is predicted to be a blocker.
+	Threshold int `json:"threshold"`
+	// ActualFraction is the fraction of a streak's payloads a test must fail in, including the last rejected payload
+	// before the streak ended, to have actually been a blocker.
+	ActualFraction float64 `json:"actual_fraction"`
+}
+
+// BlockerBacktest reports how well a scorer's early predictions matched the tests that turned out to block each
+// streak.
+type BlockerBacktest struct {
+	Options        BlockerBacktestOptions  `json:"options"`
+	Streaks        []BlockerBacktestStreak `json:"streaks"`
+	TruePositives  int                     `json:"true_positives"`
+	FalsePositives int                     `json:"false_positives"`
+	FalseNegatives int                     `json:"false_negatives"`
+	// Precision and Recall are zero when undefined, such as when nothing was predicted.
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+}
+
+// BlockerBacktestStreak is a completed rejected streak, oldest payload first, with the tests predicted to block it
+// and those that did.
+type BlockerBacktestStreak struct {
+	Payloads []string `json:"payloads"`
+	// AcceptedPayload is the payload that ended the streak.
+	AcceptedPayload string   `json:"accepted_payload"`
+	Predicted       []string `json:"predicted"`
+	Actual          []string `json:"actual"`
+	TruePositives   int      `json:"true_positives"`
+	FalsePositives  int      `json:"false_positives"`
+	FalseNegatives  int      `json:"false_negatives"`
+}
+
+// rejectedStreak is a run of rejected payloads, oldest first, ended by an accepted payload.
+type rejectedStreak struct {
+	payloads []models.ReleaseTag
+	accepted models.ReleaseTag
+}
+
+// BacktestBlockerScorer replays the completed rejected streaks of a payload stream, scoring the failures seen in the
+// first payloads of each streak and comparing the predicted blockers with the tests that kept failing until the
+// streak ended. Triage and regression allowance signals reflect their current state, not their state at the time.
+func BacktestBlockerScorer(dbc *db.DB, scorer BlockerScorer, opts BlockerBacktestOptions) (*BlockerBacktest, error) {
+	logger := log.WithFields(log.Fields{"release": opts.Release, "stream": opts.Stream, "arch": opts.Architecture})
+
+	var tags []models.ReleaseTag
+	res := dbc.DB.Where("release = ?", opts.Release).
+		Where("stream = ?", opts.Stream).
+		Where("architecture = ?", opts.Architecture).
+		Where("release_time >= ? AND release_time < ?", opts.Start, opts.End).
+		Order("release_time ASC").Find(&tags)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	streaks := findRejectedStreaks(tags, opts.PredictAfter)
+	logger.Infof("replaying %d rejected streaks from %d payloads", len(streaks), len(tags))
+
+	backtest := &BlockerBacktest{Options: opts, Streaks: []BlockerBacktestStreak{}}
+	for _, streak := range streaks {
+		failuresByPayload := map[string][]models.PayloadFailedTest{}
+		var payloads []string
+		for _, p := range streak.payloads {
+			failures, err := query.GetTestFailuresForPayload(dbc.DB, p.ReleaseTag)
+			if err != nil {
+				return nil, err
+			}
+			failuresByPayload[p.ReleaseTag] = failures
+			payloads = append(payloads, p.ReleaseTag)
+		}
+
+		since := streak.payloads[0].ReleaseTime.Add(-14 * 24 * time.Hour)
+		loadSignals := func(consecutive []string, failures []*apitype.TestFailureAnalysis) error {
+			return scorer.LoadSignals(dbc, opts.Release, consecutive, failures, since)
+		}
+		result, err := evaluateStreak(scorer, payloads, failuresByPayload, opts, loadSignals)
+		if err != nil {
+			return nil, err
+		}
+		result.AcceptedPayload = streak.accepted.ReleaseTag
+		backtest.add(result)
+	}
+	backtest.summarize()
+	return backtest, nil
+}
+
+// findRejectedStreaks finds the runs of at least minLength rejected payloads that were ended by an accepted payload,
+// for payloads ordered oldest first.
+func findRejectedStreaks(tags []models.ReleaseTag, minLength int) []rejectedStreak {
+	var streaks []rejectedStreak
+	var current []models.ReleaseTag
+	for _, tag := range tags {
+		switch tag.Phase {
+		case apitype.PayloadRejected:
+			current = append(current, tag)
+		case apitype.PayloadAccepted:
+			if len(current) > 0 && len(current) >= minLength {
+				streaks = append(streaks, rejectedStreak{payloads: current, accepted: tag})
+			}
+			current = nil
+		}
+	}
+	return streaks
+}
+
+// evaluateStreak scores the failures in the first payloads of a streak, oldest first, and compares the predicted
+// blockers with the actual ones. As for the actual blockers, the openshift-tests aggregate test is never predicted.
+func evaluateStreak(scorer BlockerScorer, payloads []string, failuresByPayload map[string][]models.PayloadFailedTest,
+	opts BlockerBacktestOptions, loadSignals func([]string, []*apitype.TestFailureAnalysis) error) (BlockerBacktestStreak, error) {
+	prefix := payloads
+	if opts.PredictAfter > 0 && opts.PredictAfter < len(payloads) {
+		prefix = payloads[:opts.PredictAfter]
+	}
+
+	testNameToAnalysis := map[string]*apitype.TestFailureAnalysis{}
+	for _, p := range prefix {
+		processFailedTests(failuresByPayload[p], testNameToAnalysis)
+	}
+	analyses := make([]*apitype.TestFailureAnalysis, 0, len(testNameToAnalysis))
+	for _, ta := range testNameToAnalysis {
+		analyses = append(analyses, ta)
+	}
+
+	consecutive := reversed(prefix)
+	if loadSignals != nil {
+		if err := loadSignals(consecutive, analyses); err != nil {
+			return BlockerBacktestStreak{}, err
+		}
+	}
+	predicted := map[string]bool{}
+	for _, ta := range analyses {
+		scorer.Score(consecutive, ta)
+		if ta.BlockerScore >= opts.Threshold && ta.Name != testidentification.OpenShiftTestsName {
+			predicted[ta.Name] = true
+		}
+	}
+
+	actual := actualBlockers(payloads, failuresByPayload, opts.ActualFraction)
+	result := BlockerBacktestStreak{
+		Payloads:  payloads,
+		Predicted: sortedKeys(predicted),
+		Actual:    sortedKeys(actual),
+	}
+	for name := range predicted {
+		if actual[name] {
+			result.TruePositives++
+		} else {
+			result.FalsePositives++
+		}
+	}
+	for name := range actual {
+		if !predicted[name] {
+			result.FalseNegatives++
+		}
+	}
+	return result, nil
+}
+
+// actualBlockers are the tests that failed in the last payload of the streak and in at least the given fraction of
+// its payloads.
+func actualBlockers(payloads []string, failuresByPayload map[string][]models.PayloadFailedTest, fraction float64) map[string]bool {
+	failedIn := map[string]map[string]bool{}
+	for _, p := range payloads {
+		for _, ft := range failuresByPayload[p] {
+			if ft.Name == testidentification.OpenShiftTestsName {
+				continue
+			}
+			if failedIn[ft.Name] == nil {
+				failedIn[ft.Name] = map[string]bool{}
+			}
+			failedIn[ft.Name][p] = true
+		}
+	}
+
+	actual := map[string]bool{}
+	last := payloads[len(payloads)-1]
+	for name, in := range failedIn {
+		if !in[last] {
+			continue
+		}
+		if float64(len(in)) >= fraction*float64(len(payloads)) {
+			actual[name] = true
+		}
+	}
+	return actual
+}
+
+func (b *BlockerBacktest) add(streak BlockerBacktestStreak) {
+	b.Streaks = append(b.Streaks, streak)
+	b.TruePositives += streak.TruePositives
+	b.FalsePositives += streak.FalsePositives
+	b.FalseNegatives += streak.FalseNegatives
+}
+
+func (b *BlockerBacktest) summarize() {
+	if predicted := b.TruePositives + b.FalsePositives; predicted > 0 {
+		b.Precision = float64(b.TruePositives) / float64(predicted)
+	}
+	if actual := b.TruePositives + b.FalseNegatives; actual > 0 {
+		b.Recall = float64(b.TruePositives) / float64(actual)
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/testidentification"
+)
+
+func TestFindRejectedStreaks(t *testing.T) {
+	tag := func(name, phase string) models.ReleaseTag { return models.ReleaseTag{ReleaseTag: name, Phase: phase} }
+	tags := []models.ReleaseTag{
+		tag("p1", apitype.PayloadRejected),
+		tag("p2", apitype.PayloadAccepted),
+		tag("p3", apitype.PayloadRejected),
+		tag("p4", apitype.PayloadRejected),
+		tag("p5", apitype.PayloadAccepted),
+		tag("p6", apitype.PayloadRejected),
+		tag("p7", apitype.PayloadRejected),
+	}
+	streaks := findRejectedStreaks(tags, 2)
+	require.Len(t, streaks, 1, "short and unfinished streaks are skipped")
+	assert.Equal(t, "p3", streaks[0].payloads[0].ReleaseTag)
+	assert.Equal(t, "p5", streaks[0].accepted.ReleaseTag)
+}
+
+func TestEvaluateStreak(t *testing.T) {
+	failure := func(payload, test string) models.PayloadFailedTest {
+		return models.PayloadFailedTest{ReleaseTag: payload, Name: test, ProwJobName: "job"}
+	}
+	payloads := []string{"p1", "p2", "p3", "p4"}
+	failuresByPayload := map[string][]models.PayloadFailedTest{
+		"p1": {failure("p1", "blocker"), failure("p1", "flake"), failure("p1", testidentification.OpenShiftTestsName)},
+		"p2": {failure("p2", "blocker"), failure("p2", "flake"), failure("p2", testidentification.OpenShiftTestsName)},
+		"p3": {failure("p3", "blocker"), failure("p3", "late blocker")},
+		"p4": {failure("p4", "blocker"), failure("p4", "late blocker")},
+	}
+	opts := BlockerBacktestOptions{PredictAfter: 2, Threshold: 50, ActualFraction: 0.5}
+
+	var signalled []string
+	loadSignals := func(consecutive []string, failures []*apitype.TestFailureAnalysis) error {
+		signalled = consecutive
+		return nil
+	}
+	result, err := evaluateStreak(&WeightedBlockerScorer{Weights: DefaultBlockerWeights()}, payloads, failuresByPayload, opts, loadSignals)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p2", "p1"}, signalled, "only the first payloads are scored, most recent first")
+	assert.Equal(t, []string{"blocker", "flake"}, result.Predicted)
+	assert.Equal(t, []string{"blocker", "late blocker"}, result.Actual)
+	assert.Equal(t, 1, result.TruePositives)
+	assert.Equal(t, 1, result.FalsePositives)
+	assert.Equal(t, 1, result.FalseNegatives)
+
+	backtest := &BlockerBacktest{}
+	backtest.add(result)
+	backtest.add(BlockerBacktestStreak{TruePositives: 1})
+	backtest.summarize()
+	assert.InDelta(t, 2.0/3.0, backtest.Precision, 0.001)
+	assert.InDelta(t, 2.0/3.0, backtest.Recall, 0.001)
+}
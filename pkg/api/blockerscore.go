@@ -0,0 +1,259 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	v1 "github.com/openshift/sippy/pkg/apis/config/v1"
+	sippyprocessingv1 "github.com/openshift/sippy/pkg/apis/sippyprocessing/v1"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/regressionallowances"
+)
+
+// BlockerScorer scores how likely a failing test is to be blocking a payload stream, setting its BlockerScore between
+// 0 (not a blocker) and 100 (almost certainly a blocker) and explaining the score in its BlockerScoreReasons.
+//
+// consecutiveFailedPayloadTags is the current streak of rejected payload tags, most recent first. If the most recent
+// payload was accepted it is empty.
+type BlockerScorer interface {
+	// LoadSignals looks up the signals the scorer uses beyond payload failures for a release's failing tests.
+	// Periodic failures are counted since the given time.
+	LoadSignals(dbc *db.DB, release string, consecutiveFailedPayloadTags []string, failures []*apitype.TestFailureAnalysis, since time.Time) error
+	Score(consecutiveFailedPayloadTags []string, ta *apitype.TestFailureAnalysis)
+}
+
+// BlockerWeights are the resolved weights of a WeightedBlockerScorer, see v1.BlockerScoreWeights.
+type BlockerWeights struct {
+	ConsecutiveFailure    int `json:"consecutive_failure"`
+	StreakOverrideMinimum int `json:"streak_override_minimum"`
+	BlockingJob           int `json:"blocking_job"`
+	OpenTriage            int `json:"open_triage"`
+	IntentionalRegression int `json:"intentional_regression"`
+	PeriodicFailures      int `json:"periodic_failures"`
+}
+
+// DefaultBlockerWeights scores 25 per consecutive failure, so at 4 we are sure the test is a blocker. The additional
+// signals are not weighted, and so not loaded, until they have been tuned by backtesting.
+func DefaultBlockerWeights() BlockerWeights {
+	return BlockerWeights{
+		ConsecutiveFailure:    25,
+		StreakOverrideMinimum: 50,
+	}
+}
+
+// ResolveBlockerWeights applies the configured default weights, then those for the stream, then those for the
+// release and stream, over the built-in defaults.
+func ResolveBlockerWeights(cfg *v1.SippyConfig, release, stream string) BlockerWeights {
+	weights := DefaultBlockerWeights()
+	if cfg == nil {
+		return weights
+	}
+	weights.apply(cfg.BlockerScoring.Default)
+	weights.apply(cfg.BlockerScoring.Streams[stream])
+	weights.apply(cfg.BlockerScoring.Streams[release+"-"+stream])
+	return weights
+}
+
+func (w *BlockerWeights) apply(o v1.BlockerScoreWeights) {
+	for dst, src := range map[*int]*int{
+		&w.ConsecutiveFailure:    o.ConsecutiveFailure,
+		&w.StreakOverrideMinimum: o.StreakOverrideMinimum,
+		&w.BlockingJob:           o.BlockingJob,
+		&w.OpenTriage:            o.OpenTriage,
+		&w.IntentionalRegression: o.IntentionalRegression,
+		&w.PeriodicFailures:      o.PeriodicFailures,
+	} {
+		if src != nil {
+			*dst = *src
+		}
+	}
+}
+
+// NewBlockerScorer returns the scorer configured for a release's payload stream.
+func NewBlockerScorer(cfg *v1.SippyConfig, release, stream string) BlockerScorer {
+	return &WeightedBlockerScorer{Weights: ResolveBlockerWeights(cfg, release, stream)}
+}
+
+// WeightedBlockerScorer scores failures in the current rejected streak, then adjusts the score by the weights of any
+// signals found for the test. Signals with no weight are neither loaded nor explained.
+type WeightedBlockerScorer struct {
+	Weights BlockerWeights
+}
+
+func (s *WeightedBlockerScorer) LoadSignals(dbc *db.DB, release string, consecutiveFailedPayloadTags []string, failures []*apitype.TestFailureAnalysis, since time.Time) error {
+	return loadBlockerSignals(dbc, s.Weights, release, consecutiveFailedPayloadTags, failures, since)
+}
+
+func (s *WeightedBlockerScorer) Score(consecutiveFailedPayloadTags []string, ta *apitype.TestFailureAnalysis) {
+	if len(consecutiveFailedPayloadTags) == 0 {
+		// our most recent state is Accepted, could be intermittent, but for the purposes of a blocker
+		// we have to assume 0.
+		ta.BlockerScore = 0
+		ta.BlockerScoreReasons = append(ta.BlockerScoreReasons, "most recent payload was Accepted, test may be failing intermittently but cannot be fully blocking")
+		return
+	}
+
+	failedInConsecPayloads := 0
+	var failedInConsecBreakFound bool
+	failedInStreak := 0
+	for _, payloadTag := range consecutiveFailedPayloadTags {
+		if _, ok := ta.FailedPayloads[payloadTag]; ok {
+			if !failedInConsecBreakFound {
+				failedInConsecPayloads++
+			}
+			failedInStreak++
+		} else {
+			// We didn't fail in a payload in the current streak of failures, but this could be infra
+			// failures, so we keep checking if we failed in more beyond this.
+			failedInConsecBreakFound = true
+		}
+	}
+
+	ta.BlockerScore = int(math.Min(float64(failedInConsecPayloads*s.Weights.ConsecutiveFailure), 100))
+	message := fmt.Sprintf("failed in %d most recent rejected payloads", failedInConsecPayloads)
+	ta.BlockerScoreReasons = append(ta.BlockerScoreReasons, message)
+
+	// Override the score if we see we failed in a more substantial portion of the current rejected streak
+	// (a test can disappear in a run if it fails on infra or other reasons).
+	failedInStreakPercentage := int((float64(failedInStreak) / float64(len(consecutiveFailedPayloadTags))) * 100)
+	ta.BlockerScoreReasons = append(ta.BlockerScoreReasons,
+		fmt.Sprintf("failed in %d/%d of current rejected payload streak", failedInStreak, len(consecutiveFailedPayloadTags)))
+	if ta.BlockerScore >= s.Weights.StreakOverrideMinimum && failedInStreakPercentage >= ta.BlockerScore {
+		ta.BlockerScore = failedInStreakPercentage
+	}
+
+	if signals := ta.BlockerSignals; signals != nil {
+		s.scoreSignal(ta, signals.BlockingJob, s.Weights.BlockingJob, "failed in a blocking job")
+		s.scoreSignal(ta, signals.OpenTriage, s.Weights.OpenTriage, "has an open triage")
+		s.scoreSignal(ta, signals.IntentionalRegression, s.Weights.IntentionalRegression, "is an intentional regression")
+		s.scoreSignal(ta, signals.PeriodicFailures > 0, s.Weights.PeriodicFailures,
+			fmt.Sprintf("failed %d times in periodic jobs outside of payloads", signals.PeriodicFailures))
+	}
+	ta.BlockerScore = int(math.Max(0, math.Min(float64(ta.BlockerScore), 100)))
+}
+
+func (s *WeightedBlockerScorer) scoreSignal(ta *apitype.TestFailureAnalysis, present bool, weight int, reason string) {
+	if !present || weight == 0 {
+		return
+	}
+	ta.BlockerScore += weight
+	ta.BlockerScoreReasons = append(ta.BlockerScoreReasons, fmt.Sprintf("%s (%+d)", reason, weight))
+}
+
+// loadBlockerSignals looks up the weighted signals for the failing tests of a release's payloads. Periodic failures
+// are counted since the given time.
+func loadBlockerSignals(dbc *db.DB, weights BlockerWeights, release string, payloadTags []string, failures []*apitype.TestFailureAnalysis, since time.Time) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	if weights.BlockingJob == 0 && weights.OpenTriage == 0 && weights.IntentionalRegression == 0 && weights.PeriodicFailures == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(failures))
+	for _, ta := range failures {
+		names = append(names, ta.Name)
+	}
+
+	var blockingJobs []string
+	if weights.BlockingJob != 0 && len(payloadTags) > 0 {
+		res := dbc.DB.Table("release_job_runs").
+			Joins("JOIN release_tags ON release_tags.id = release_job_runs.release_tag_id").
+			Where("release_tags.release_tag IN ?", payloadTags).
+			Where("release_job_runs.kind = ?", "Blocking").
+			Distinct().Pluck("release_job_runs.job_name", &blockingJobs)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+	blocking := map[string]bool{}
+	for _, job := range blockingJobs {
+		blocking[job] = true
+	}
+
+	var triaged []string
+	if weights.OpenTriage != 0 {
+		res := dbc.DB.Table("test_regressions").
+			Joins("JOIN triage_regressions ON triage_regressions.test_regression_id = test_regressions.id").
+			Joins("JOIN triages ON triages.id = triage_regressions.triage_id").
+			Where("test_regressions.release = ?", release).
+			Where("test_regressions.test_name IN ?", names).
+			Where("triages.resolved IS NULL").
+			Distinct().Pluck("test_regressions.test_name", &triaged)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+
+	var allowed []string
+	if weights.IntentionalRegression != 0 {
+		res := dbc.DB.Table("regression_allowances").
+			Where("release = ? AND test_name IN ?", release, names).
+			Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+			Distinct().Pluck("test_name", &allowed)
+		if res.Error != nil {
+			return res.Error
+		}
+		for _, ir := range regressionallowances.EmbeddedIntentionalRegressions()[release] {
+			allowed = append(allowed, ir.TestName)
+		}
+	}
+
+	var periodicFailures []testFailureCount
+	if weights.PeriodicFailures != 0 {
+		res := dbc.DB.Raw(`SELECT t.name, COUNT(*) AS failures
+			FROM prow_job_run_tests pjrt
+			JOIN tests t ON t.id = pjrt.test_id
+			JOIN prow_job_runs pjr ON pjr.id = pjrt.prow_job_run_id
+			JOIN prow_jobs pj ON pj.id = pjr.prow_job_id
+			WHERE t.name IN ? AND pjrt.status = ? AND pj.release = ? AND pj.name LIKE 'periodic-%' AND pjr.timestamp >= ?
+			AND NOT EXISTS (SELECT 1 FROM release_job_runs rjr WHERE rjr.prow_job_run_id = pjr.id)
+			GROUP BY t.name`, names, sippyprocessingv1.TestStatusFailure, release, since).Scan(&periodicFailures)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+
+	setBlockerSignals(failures, blocking, triaged, allowed, periodicFailuresByName(periodicFailures))
+	return nil
+}
+
+type testFailureCount struct {
+	Name     string
+	Failures int
+}
+
+func periodicFailuresByName(rows []testFailureCount) map[string]int {
+	byName := map[string]int{}
+	for _, r := range rows {
+		byName[r.Name] = r.Failures
+	}
+	return byName
+}
+
+func setBlockerSignals(failures []*apitype.TestFailureAnalysis, blockingJobs map[string]bool, triaged, allowed []string, periodicFailures map[string]int) {
+	triagedSet := map[string]bool{}
+	for _, name := range triaged {
+		triagedSet[name] = true
+	}
+	allowedSet := map[string]bool{}
+	for _, name := range allowed {
+		allowedSet[name] = true
+	}
+	for _, ta := range failures {
+		signals := &apitype.BlockerSignals{
+			OpenTriage:            triagedSet[ta.Name],
+			IntentionalRegression: allowedSet[ta.Name],
+			PeriodicFailures:      periodicFailures[ta.Name],
+		}
+		for _, fp := range ta.FailedPayloads {
+			for _, job := range fp.FailedJobs {
+				if blockingJobs[job] {
+					signals.BlockingJob = true
+				}
+			}
+		}
+		ta.BlockerSignals = signals
+	}
+}
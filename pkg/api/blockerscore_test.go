@@ -0,0 +1,119 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	v1 "github.com/openshift/sippy/pkg/apis/config/v1"
+)
+
+func failedIn(payloads ...string) map[string]*apitype.FailedPayload {
+	failed := map[string]*apitype.FailedPayload{}
+	for _, p := range payloads {
+		failed[p] = &apitype.FailedPayload{}
+	}
+	return failed
+}
+
+func TestWeightedBlockerScorer(t *testing.T) {
+	streak := []string{"p5", "p4", "p3", "p2", "p1"}
+	tests := []struct {
+		name           string
+		weights        BlockerWeights
+		streak         []string
+		failedPayloads []string
+		signals        *apitype.BlockerSignals
+		expectedScore  int
+		expectedReason string
+	}{
+		{
+			name:           "accepted payload",
+			weights:        DefaultBlockerWeights(),
+			failedPayloads: []string{"p1"},
+			expectedScore:  0,
+			expectedReason: "most recent payload was Accepted, test may be failing intermittently but cannot be fully blocking",
+		},
+		{
+			name:           "consecutive failures",
+			weights:        DefaultBlockerWeights(),
+			streak:         streak,
+			failedPayloads: []string{"p5", "p4"},
+			expectedScore:  50,
+			expectedReason: "failed in 2 most recent rejected payloads",
+		},
+		{
+			name:           "streak percentage override",
+			weights:        DefaultBlockerWeights(),
+			streak:         streak,
+			failedPayloads: []string{"p5", "p4", "p2", "p1"},
+			expectedScore:  80,
+			expectedReason: "failed in 4/5 of current rejected payload streak",
+		},
+		{
+			name:           "unweighted signals are ignored",
+			weights:        DefaultBlockerWeights(),
+			streak:         streak,
+			failedPayloads: []string{"p5"},
+			signals:        &apitype.BlockerSignals{BlockingJob: true},
+			expectedScore:  25,
+			expectedReason: "failed in 1 most recent rejected payloads",
+		},
+		{
+			name:           "weighted signals",
+			weights:        BlockerWeights{ConsecutiveFailure: 25, StreakOverrideMinimum: 50, BlockingJob: 10, IntentionalRegression: -50},
+			streak:         streak,
+			failedPayloads: []string{"p5"},
+			signals:        &apitype.BlockerSignals{BlockingJob: true, IntentionalRegression: true},
+			expectedScore:  0,
+			expectedReason: "is an intentional regression (-50)",
+		},
+		{
+			name:           "score is capped",
+			weights:        BlockerWeights{ConsecutiveFailure: 50, StreakOverrideMinimum: 50, PeriodicFailures: 20},
+			streak:         streak,
+			failedPayloads: []string{"p5", "p4"},
+			signals:        &apitype.BlockerSignals{PeriodicFailures: 3},
+			expectedScore:  100,
+			expectedReason: "failed 3 times in periodic jobs outside of payloads (+20)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := &apitype.TestFailureAnalysis{FailedPayloads: failedIn(tt.failedPayloads...), BlockerSignals: tt.signals}
+			scorer := &WeightedBlockerScorer{Weights: tt.weights}
+			scorer.Score(tt.streak, ta)
+			assert.Equal(t, tt.expectedScore, ta.BlockerScore)
+			assert.Contains(t, ta.BlockerScoreReasons, tt.expectedReason)
+			assert.NotContains(t, ta.BlockerScoreReasons, "failed in a blocking job (+0)")
+		})
+	}
+}
+
+func TestResolveBlockerWeights(t *testing.T) {
+	ten, five, zero := 10, 5, 0
+	cfg := &v1.SippyConfig{BlockerScoring: v1.BlockerScoringConfig{
+		Default: v1.BlockerScoreWeights{BlockingJob: &ten},
+		Streams: map[string]v1.BlockerScoreWeights{
+			"nightly":      {OpenTriage: &five},
+			"4.22-nightly": {BlockingJob: &zero},
+		},
+	}}
+
+	assert.Equal(t, DefaultBlockerWeights(), ResolveBlockerWeights(nil, "4.22", "nightly"))
+	assert.Equal(t, BlockerWeights{ConsecutiveFailure: 25, StreakOverrideMinimum: 50, BlockingJob: 10}, ResolveBlockerWeights(cfg, "4.22", "ci"))
+	assert.Equal(t, BlockerWeights{ConsecutiveFailure: 25, StreakOverrideMinimum: 50, BlockingJob: 10, OpenTriage: 5}, ResolveBlockerWeights(cfg, "4.21", "nightly"))
+	assert.Equal(t, BlockerWeights{ConsecutiveFailure: 25, StreakOverrideMinimum: 50, OpenTriage: 5}, ResolveBlockerWeights(cfg, "4.22", "nightly"),
+		"release stream weights win, including explicit zeros")
+}
+
+func TestSetBlockerSignals(t *testing.T) {
+	failures := []*apitype.TestFailureAnalysis{
+		{Name: "a", FailedPayloads: map[string]*apitype.FailedPayload{"p1": {FailedJobs: []string{"informing-job", "blocking-job"}}}},
+		{Name: "b", FailedPayloads: map[string]*apitype.FailedPayload{"p1": {FailedJobs: []string{"informing-job"}}}},
+	}
+	setBlockerSignals(failures, map[string]bool{"blocking-job": true}, []string{"b"}, []string{"a"}, map[string]int{"b": 4})
+	assert.Equal(t, &apitype.BlockerSignals{BlockingJob: true, IntentionalRegression: true}, failures[0].BlockerSignals)
+	assert.Equal(t, &apitype.BlockerSignals{OpenTriage: true, PeriodicFailures: 4}, failures[1].BlockerSignals)
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/openshift/sippy/pkg/apis/cache"
+	"github.com/openshift/sippy/pkg/util/singleflight"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -89,6 +92,7 @@ func GetDataFromCacheOrGenerate[T any](
 
 		refreshRecent := cacheOptions.RefreshRecent && !hasStableData
 		if !cacheOptions.ForceRefresh && !refreshRecent {
+			// entries written without stale-while-revalidate (e.g. by cache priming jobs) are always usable as-is
 			if res, err := c.Get(ctx, string(cacheKey), cacheDuration); err == nil {
 				logrus.WithFields(logrus.Fields{
 					"key":  string(cacheKey),
@@ -102,22 +106,152 @@ func GetDataFromCacheOrGenerate[T any](
 			} else if strings.Contains(err.Error(), "connection refused") {
 				logrus.WithError(err).Fatalf("redis URL specified but got connection refused, exiting due to cost issues in this configuration")
 			}
+
+			if cacheOptions.StaleWhileRevalidate > 0 {
+				if entry, ok := getSWREntry[T](ctx, c, cacheKey, cacheDuration+cacheOptions.StaleWhileRevalidate); ok {
+					if time.Now().Before(entry.Expires) {
+						logrus.WithFields(logrus.Fields{
+							"key":  string(cacheKey),
+							"type": reflect.TypeOf(defaultVal).String(),
+						}).Infof("cache hit")
+						return entry.Val, nil
+					}
+					age := time.Since(entry.Timestamp)
+					logrus.WithFields(logrus.Fields{
+						"key": string(cacheKey),
+						"age": age,
+					}).Infof("serving stale cache entry while revalidating")
+					recordStale(ctx, age)
+					revalidateInBackground(ctx, c, cacheOptions, cacheKey, cacheDuration, generateFn)
+					return entry.Val, nil
+				}
+			}
 			logrus.WithFields(logrus.Fields{
 				"key": string(cacheKey),
 			}).Infof("cache miss")
 		}
 
 		// Cache has missed or we're deliberately refreshing the data:
-		result, errs := generateFn(ctx)
-		if len(errs) == 0 && !cacheOptions.SkipCacheWrites {
-			CacheSet(ctx, c, result, cacheKey, cacheDuration)
-		}
-		return result, errs
+		return coalesce(ctx, cacheKey, func(ctx context.Context) (T, []error) {
+			result, errs := generateFn(ctx)
+			if len(errs) == 0 && !cacheOptions.SkipCacheWrites {
+				cacheSetEntry(ctx, c, cacheOptions, result, cacheKey, cacheDuration)
+			}
+			return result, errs
+		})
 	}
 
 	return generateFn(ctx)
 }
 
+// swrKeyPrefix marks entries written with stale-while-revalidate, which wrap the value with its generation time and
+// outlive its expiry. They are kept apart from the normal key so readers that don't opt in (and cache priming jobs)
+// never see the wrapper.
+const swrKeyPrefix = "swr:"
+
+// revalidateTimeout bounds a shared or background regeneration, which is no longer tied to the request that started it.
+const revalidateTimeout = 30 * time.Minute
+
+// revalidating holds the cache keys with a background regeneration in flight, so concurrent requests for the same
+// stale entry start only one.
+var revalidating sync.Map
+
+// swrEntry is a value cached for stale-while-revalidate. It is fresh until Expires, then may be served stale until
+// the cache drops it.
+type swrEntry[T any] struct {
+	Val       T
+	Timestamp time.Time // when the value was generated
+	Expires   time.Time
+}
+
+// CacheStatus records whether GetDataFromCacheOrGenerate served stale data while handling a request.
+type CacheStatus struct {
+	lock  sync.Mutex
+	stale bool
+	age   time.Duration
+}
+
+// Stale reports whether any stale data was served, and the age of the oldest.
+func (s *CacheStatus) Stale() (bool, time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.stale, s.age
+}
+
+type cacheStatusKey struct{}
+
+// WithCacheStatus returns a context in which cache lookups record whether they served stale data, so a handler
+// can tell its client.
+func WithCacheStatus(ctx context.Context) (context.Context, *CacheStatus) {
+	status := &CacheStatus{}
+	return context.WithValue(ctx, cacheStatusKey{}, status), status
+}
+
+func recordStale(ctx context.Context, age time.Duration) {
+	status, ok := ctx.Value(cacheStatusKey{}).(*CacheStatus)
+	if !ok || status == nil {
+		return
+	}
+	status.lock.Lock()
+	defer status.lock.Unlock()
+	status.stale = true
+	if age > status.age {
+		status.age = age
+	}
+}
+
+func getSWREntry[T any](ctx context.Context, c cache.Cache, cacheKey []byte, swrDuration time.Duration) (swrEntry[T], bool) {
+	var entry swrEntry[T]
+	res, err := c.Get(ctx, swrKeyPrefix+string(cacheKey), swrDuration)
+	if err != nil || res == nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(res, &entry); err != nil {
+		logrus.WithError(err).Warnf("failed to unmarshal stale-while-revalidate cache item.  cacheKey=%s", cacheKey)
+		return entry, false
+	}
+	return entry, true
+}
+
+func revalidateInBackground[T any](ctx context.Context, c cache.Cache, cacheOptions cache.RequestOptions, cacheKey []byte,
+	cacheDuration time.Duration, generateFn func(context.Context) (T, []error)) {
+	key := string(cacheKey)
+	if _, inFlight := revalidating.LoadOrStore(key, struct{}{}); inFlight {
+		return
+	}
+
+	// the request will finish long before regeneration does, so keep its values but not its cancellation, and
+	// don't let the regeneration report into the request's cache status
+	bgCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), revalidateTimeout)
+	bgCtx = context.WithValue(bgCtx, cacheStatusKey{}, (*CacheStatus)(nil))
+	go func() {
+		defer cancel()
+		defer revalidating.Delete(key)
+		before := time.Now()
+		result, errs := generateFn(bgCtx)
+		if len(errs) > 0 {
+			logrus.WithField("key", key).Warnf("background revalidation failed: %v", errs)
+			return
+		}
+		if !cacheOptions.SkipCacheWrites {
+			cacheSetEntry(bgCtx, c, cacheOptions, result, cacheKey, cacheDuration)
+		}
+		logrus.WithField("key", key).Infof("background revalidation completed in %s", time.Since(before))
+	}()
+}
+
+// cacheSetEntry stores result under the normal key, or when stale-while-revalidate is enabled as a single entry that
+// records its generation time and lives for the stale window past its expiry.
+func cacheSetEntry[T any](ctx context.Context, c cache.Cache, cacheOptions cache.RequestOptions, result T, cacheKey []byte, cacheDuration time.Duration) {
+	if cacheOptions.StaleWhileRevalidate <= 0 {
+		CacheSet(ctx, c, result, cacheKey, cacheDuration)
+		return
+	}
+	now := time.Now().UTC()
+	CacheSet(ctx, c, swrEntry[T]{Val: result, Timestamp: now, Expires: now.Add(cacheDuration)},
+		[]byte(swrKeyPrefix+string(cacheKey)), cacheDuration+cacheOptions.StaleWhileRevalidate)
+}
+
 func CacheSet[T any](ctx context.Context, c cache.Cache, result T, cacheKey []byte, cacheDuration time.Duration) {
 	cr, err := json.Marshal(result)
 	if err == nil {
@@ -226,15 +360,118 @@ func GetDataFromCacheOrMatview[T any](ctx context.Context,
 
 	// Cache missed or refresh invalidated the data, so generate it.
 	logrus.Debugf("cache duration set to %s or approx %s for key %s", cacheDuration, time.Now().Add(cacheDuration).Format(time.RFC3339), cacheKey)
-	result, errs := generateFn(ctx)
-	if len(errs) == 0 {
-		cacheVal.Val = result
-		cacheVal.Timestamp = time.Now().UTC()
-		CacheSet(ctx, cacheClient, cacheVal, cacheKey, cacheDuration)
+	return coalesce(ctx, cacheKey, func(ctx context.Context) (T, []error) {
+		result, errs := generateFn(ctx)
+		if len(errs) == 0 {
+			cacheVal.Val = result
+			cacheVal.Timestamp = time.Now().UTC()
+			CacheSet(ctx, cacheClient, cacheVal, cacheKey, cacheDuration)
+		}
+		return result, errs
+	})
+}
+
+// generations coalesces concurrent generation for the same cache key, so identical requests that all miss the
+// cache share one set of (often BigQuery) queries rather than each running their own.
+var generations singleflight.Group[any]
+
+type generated[T any] struct {
+	val  T
+	errs []error
+}
+
+// coalesce runs generateFn, or waits for an identical in-flight generation and shares its result. The generation
+// keeps the values of whichever request started it but not its cancellation, so that request going away does not
+// fail the others waiting on it.
+func coalesce[T any](ctx context.Context, cacheKey []byte, generateFn func(context.Context) (T, []error)) (T, []error) {
+	res, shared, _ := generations.Do(string(cacheKey), func() (any, error) {
+		genCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), revalidateTimeout)
+		defer cancel()
+		val, errs := generateFn(genCtx)
+		return generated[T]{val: val, errs: errs}, nil
+	})
+	out, ok := res.(generated[T])
+	if !ok {
+		// the in-flight call panicked, or (improbably) produced a different type under the same key
+		return generateFn(ctx)
+	}
+	if shared {
+		// callers commonly adjust what they get back (e.g. component report post-analysis), so don't hand out
+		// the same value twice
+		out.val = copyViaJSON(out.val)
+	}
+	return out.val, out.errs
+}
+
+// copyViaJSON deep copies anything we cache, which must already survive a round trip through JSON.
+func copyViaJSON[T any](val T) T {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return val
 	}
-	return result, errs
+	var cp T
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return val
+	}
+	return cp
 }
 
 func RefreshMatviewKey(matview string) string {
 	return "matview_refreshed:" + matview
 }
+
+// CacheEntries reports cache contents for the cache admin API and CLI.
+type CacheEntries struct {
+	Entries   []CacheEntry      `json:"entries"`
+	TotalSize int64             `json:"total_size"`
+	Links     map[string]string `json:"links,omitempty"`
+}
+
+type CacheEntry struct {
+	cache.Entry
+	// TTLSeconds is the time left before the entry expires, omitted when it does not expire.
+	TTLSeconds int64             `json:"ttl_seconds,omitempty"`
+	Links      map[string]string `json:"links,omitempty"`
+}
+
+// NewCacheEntries summarizes entries as of now. When baseURL is set, each entry links to the API that deletes it.
+func NewCacheEntries(entries []cache.Entry, now time.Time, baseURL string) CacheEntries {
+	result := CacheEntries{Entries: make([]CacheEntry, 0, len(entries))}
+	for _, entry := range entries {
+		ce := CacheEntry{Entry: entry, TTLSeconds: int64(entry.TTL(now).Seconds())}
+		if baseURL != "" {
+			ce.Links = map[string]string{"delete": baseURL + "/api/cache?key=" + url.QueryEscape(entry.Key)}
+		}
+		result.TotalSize += entry.Size
+		result.Entries = append(result.Entries, ce)
+	}
+	return result
+}
+
+// DeleteCacheEntries deletes each entry along with any stale-while-revalidate entry for the same key, so purged data
+// is not served afterward. It returns the keys deleted before any failure.
+func DeleteCacheEntries(ctx context.Context, c cache.Cache, entries []cache.Entry) ([]string, error) {
+	swrEntries, err := c.List(ctx, swrKeyPrefix)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list stale-while-revalidate cache entries")
+	}
+	swrKeys := make(map[string]bool, len(swrEntries))
+	for _, entry := range swrEntries {
+		swrKeys[entry.Key] = true
+	}
+
+	deleted := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys := []string{entry.Key}
+		if swrKeys[swrKeyPrefix+entry.Key] {
+			keys = append(keys, swrKeyPrefix+entry.Key)
+		}
+		for _, key := range keys {
+			if err := c.Delete(ctx, key); err != nil {
+				return deleted, errors.WithMessagef(err, "failed to delete cache key %s", key)
+			}
+			deleted = append(deleted, key)
+		}
+	}
+	return deleted, nil
+}
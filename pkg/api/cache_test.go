@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -23,6 +26,7 @@ func init() {
 // mockCache is a test cache that properly returns errors on miss,
 // records durations passed to Set, and can simulate errors.
 type mockCache struct {
+	lock         sync.Mutex
 	store        map[string][]byte
 	setDurations map[string]time.Duration
 	setCalls     int
@@ -39,6 +43,8 @@ func newMockCache() *mockCache {
 }
 
 func (m *mockCache) Get(_ context.Context, key string, duration time.Duration) ([]byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.getCalls++
 	if m.getErr != nil {
 		return nil, m.getErr
@@ -51,6 +57,8 @@ func (m *mockCache) Get(_ context.Context, key string, duration time.Duration) (
 }
 
 func (m *mockCache) Set(_ context.Context, key string, content []byte, duration time.Duration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.setCalls++
 	if m.setErr != nil {
 		return m.setErr
@@ -60,6 +68,25 @@ func (m *mockCache) Set(_ context.Context, key string, content []byte, duration
 	return nil
 }
 
+func (m *mockCache) List(_ context.Context, prefix string) ([]cache.Entry, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var entries []cache.Entry
+	for key, content := range m.store {
+		if strings.HasPrefix(key, prefix) {
+			entries = append(entries, cache.Entry{Key: key, Size: int64(len(content))})
+		}
+	}
+	return entries, nil
+}
+
+func (m *mockCache) Delete(_ context.Context, key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.store, key)
+	return nil
+}
+
 type testResult struct {
 	Value string `json:"value"`
 }
@@ -723,3 +750,223 @@ func TestGetDataFromCacheOrMatview_DifferentMatviews(t *testing.T) {
 	assert.Equal(t, "cached-2d", result2d.Value, "2d cache should not be invalidated")
 	assert.Equal(t, 0, gen2dCalls)
 }
+
+func TestNewCacheEntries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(90 * time.Second)
+	result := NewCacheEntries([]cache.Entry{
+		{Key: "ComponentReport~{\"a\":1}", Size: 10, ExpiresAt: &expiresAt},
+		{Key: "matview_refreshed:foo", Size: 5},
+	}, now, "https://sippy.example.com")
+
+	assert.Equal(t, int64(15), result.TotalSize)
+	require.Len(t, result.Entries, 2)
+	assert.Equal(t, int64(90), result.Entries[0].TTLSeconds)
+	assert.Equal(t, "https://sippy.example.com/api/cache?key=ComponentReport~%7B%22a%22%3A1%7D", result.Entries[0].Links["delete"])
+	assert.Equal(t, int64(0), result.Entries[1].TTLSeconds)
+}
+
+func TestDeleteCacheEntries(t *testing.T) {
+	mc := newMockCache()
+	mc.store["a"] = []byte("1")
+	mc.store["b"] = []byte("2")
+	mc.store["c"] = []byte("3")
+	mc.store[swrKeyPrefix+"a"] = []byte("1")
+	mc.store[swrKeyPrefix+"c"] = []byte("3")
+
+	deleted, err := DeleteCacheEntries(context.TODO(), mc, []cache.Entry{{Key: "a"}, {Key: "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", swrKeyPrefix + "a", "b"}, deleted, "stale-while-revalidate entries are purged with their keys")
+	assert.Equal(t, map[string][]byte{"c": []byte("3"), swrKeyPrefix + "c": []byte("3")}, mc.store)
+}
+
+// storeSWREntry caches val for stale-while-revalidate as generated age ago, with the given expiry.
+func storeSWREntry(t *testing.T, mc *mockCache, spec CacheSpec, val testResult, age, expiry time.Duration) {
+	cacheKey, err := spec.GetCacheKey()
+	require.NoError(t, err)
+	generated := time.Now().Add(-age)
+	data, err := json.Marshal(swrEntry[testResult]{Val: val, Timestamp: generated, Expires: generated.Add(expiry)})
+	require.NoError(t, err)
+	mc.store[swrKeyPrefix+string(cacheKey)] = data
+}
+
+func waitForRevalidation(t *testing.T, spec CacheSpec) {
+	cacheKey, err := spec.GetCacheKey()
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, inFlight := revalidating.Load(string(cacheKey))
+		return !inFlight
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// TestGetDataFromCacheOrGenerate_StaleWhileRevalidate verifies that an expired entry is served immediately, marked
+// stale, while a background regeneration replaces it with a single fresh entry.
+func TestGetDataFromCacheOrGenerate_StaleWhileRevalidate(t *testing.T) {
+	mc := newMockCache()
+	spec := NewCacheSpec(testCacheKey{Query: "swr"}, "prefix~", nil)
+	storeSWREntry(t, mc, spec, testResult{Value: "stale"}, 2*time.Hour, time.Hour)
+
+	var generateCalls int
+	opts := cache.RequestOptions{Expiry: time.Hour, StaleWhileRevalidate: 2 * time.Hour}
+	ctx, status := WithCacheStatus(context.Background())
+	result, errs := GetDataFromCacheOrGenerate(ctx, mc, opts, spec,
+		makeGenerateFn(testResult{Value: "fresh"}, &generateCalls), testResult{})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, "stale", result.Value, "should return the stale value without waiting")
+	stale, age := status.Stale()
+	assert.True(t, stale)
+	assert.InDelta(t, (2 * time.Hour).Seconds(), age.Seconds(), 60)
+
+	waitForRevalidation(t, spec)
+	assert.Equal(t, 1, generateCalls)
+	cacheKey, _ := spec.GetCacheKey()
+	assert.NotContains(t, mc.store, string(cacheKey), "the value is stored only once")
+	assert.Equal(t, 3*time.Hour, mc.setDurations[swrKeyPrefix+string(cacheKey)])
+
+	// the next request finds the fresh entry
+	ctx, status = WithCacheStatus(context.Background())
+	result, _ = GetDataFromCacheOrGenerate(ctx, mc, opts, spec,
+		makeGenerateFn(testResult{Value: "unused"}, &generateCalls), testResult{})
+	assert.Equal(t, "fresh", result.Value)
+	stale, _ = status.Stale()
+	assert.False(t, stale)
+}
+
+// TestGetDataFromCacheOrGenerate_StaleWhileRevalidateDedupes verifies concurrent requests for the same stale entry
+// start only one background regeneration.
+func TestGetDataFromCacheOrGenerate_StaleWhileRevalidateDedupes(t *testing.T) {
+	mc := newMockCache()
+	spec := NewCacheSpec(testCacheKey{Query: "swr-dedupe"}, "prefix~", nil)
+	storeSWREntry(t, mc, spec, testResult{Value: "stale"}, 90*time.Minute, time.Hour)
+
+	release := make(chan struct{})
+	var generateCalls atomic.Int32
+	generateFn := func(_ context.Context) (testResult, []error) {
+		generateCalls.Add(1)
+		<-release
+		return testResult{Value: "fresh"}, nil
+	}
+	opts := cache.RequestOptions{Expiry: time.Hour, StaleWhileRevalidate: time.Hour}
+	for i := 0; i < 3; i++ {
+		result, errs := GetDataFromCacheOrGenerate(context.Background(), mc, opts, spec, generateFn, testResult{})
+		assert.Empty(t, errs)
+		assert.Equal(t, "stale", result.Value)
+	}
+	close(release)
+	waitForRevalidation(t, spec)
+	assert.Equal(t, int32(1), generateCalls.Load())
+}
+
+// TestGetDataFromCacheOrGenerate_StaleWhileRevalidateMiss verifies a request with nothing cached generates
+// synchronously and stores a single entry that later requests read fresh.
+func TestGetDataFromCacheOrGenerate_StaleWhileRevalidateMiss(t *testing.T) {
+	mc := newMockCache()
+	spec := NewCacheSpec(testCacheKey{Query: "swr-miss"}, "prefix~", nil)
+
+	var generateCalls int
+	ctx, status := WithCacheStatus(context.Background())
+	result, errs := GetDataFromCacheOrGenerate(ctx, mc,
+		cache.RequestOptions{Expiry: time.Hour, StaleWhileRevalidate: time.Hour}, spec,
+		makeGenerateFn(testResult{Value: "generated"}, &generateCalls), testResult{})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, "generated", result.Value)
+	assert.Equal(t, 1, generateCalls)
+	stale, _ := status.Stale()
+	assert.False(t, stale)
+
+	cacheKey, _ := spec.GetCacheKey()
+	assert.Len(t, mc.store, 1, "the value is stored only once")
+	entry, ok := getSWREntry[testResult](context.Background(), mc, cacheKey, 2*time.Hour)
+	require.True(t, ok)
+	assert.Equal(t, "generated", entry.Val.Value)
+
+	ctx, status = WithCacheStatus(context.Background())
+	result, _ = GetDataFromCacheOrGenerate(ctx, mc,
+		cache.RequestOptions{Expiry: time.Hour, StaleWhileRevalidate: time.Hour}, spec,
+		makeGenerateFn(testResult{Value: "unused"}, &generateCalls), testResult{})
+	assert.Equal(t, "generated", result.Value)
+	assert.Equal(t, 1, generateCalls, "an unexpired entry is a cache hit")
+	stale, _ = status.Stale()
+	assert.False(t, stale)
+}
+
+// TestGetDataFromCacheOrGenerate_StaleWhileRevalidateReadsPrimedEntry verifies an entry written without
+// stale-while-revalidate, as the cache priming jobs do, is still used by readers that opt in.
+func TestGetDataFromCacheOrGenerate_StaleWhileRevalidateReadsPrimedEntry(t *testing.T) {
+	mc := newMockCache()
+	spec := NewCacheSpec(testCacheKey{Query: "swr-primed"}, "prefix~", nil)
+	cacheKey, err := spec.GetCacheKey()
+	require.NoError(t, err)
+	mc.store[string(cacheKey)] = []byte(`{"value":"primed"}`)
+
+	var generateCalls int
+	result, errs := GetDataFromCacheOrGenerate(context.Background(), mc,
+		cache.RequestOptions{Expiry: time.Hour, StaleWhileRevalidate: time.Hour}, spec,
+		makeGenerateFn(testResult{Value: "unused"}, &generateCalls), testResult{})
+	assert.Empty(t, errs)
+	assert.Equal(t, "primed", result.Value)
+	assert.Equal(t, 0, generateCalls)
+}
+
+// TestGetDataFromCacheOrGenerate_CoalescesConcurrentMisses verifies that concurrent requests missing the cache for
+// the same key share one generation, and each get their own copy of the result.
+func TestGetDataFromCacheOrGenerate_CoalescesConcurrentMisses(t *testing.T) {
+	mc := newMockCache()
+	spec := NewCacheSpec(testCacheKey{Query: "coalesce"}, "prefix~", nil)
+	cacheKey, err := spec.GetCacheKey()
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	var generateCalls atomic.Int32
+	generateFn := func(_ context.Context) (testResult, []error) {
+		generateCalls.Add(1)
+		<-release
+		return testResult{Value: "generated"}, nil
+	}
+
+	const requests = 4
+	results := make([]testResult, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var errs []error
+			results[i], errs = GetDataFromCacheOrGenerate(context.Background(), mc, cache.RequestOptions{}, spec, generateFn, testResult{})
+			assert.Empty(t, errs)
+		}(i)
+	}
+	// hold the first generation until every other request is waiting on it
+	require.Eventually(t, func() bool {
+		return generations.Waiters(string(cacheKey)) == requests-1
+	}, 5*time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), generateCalls.Load())
+	assert.Equal(t, 1, mc.setCalls, "only the generating request should write the cache")
+	for _, result := range results {
+		assert.Equal(t, "generated", result.Value)
+	}
+}
+
+// TestGetDataFromCacheOrGenerate_CoalesceIgnoresCallerCancellation verifies that a shared generation is not cut short
+// when the request that started it is cancelled.
+func TestGetDataFromCacheOrGenerate_CoalesceIgnoresCallerCancellation(t *testing.T) {
+	mc := newMockCache()
+	spec := NewCacheSpec(testCacheKey{Query: "coalesce-cancel"}, "prefix~", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	generateFn := func(genCtx context.Context) (testResult, []error) {
+		if err := genCtx.Err(); err != nil {
+			return testResult{}, []error{err}
+		}
+		return testResult{Value: "generated"}, nil
+	}
+	result, errs := GetDataFromCacheOrGenerate(ctx, mc, cache.RequestOptions{}, spec, generateFn, testResult{})
+	assert.Empty(t, errs)
+	assert.Equal(t, "generated", result.Value)
+}
@@ -0,0 +1,184 @@
+package componentreadiness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	sippyapi "github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/regressionallowances"
+)
+
+const (
+	allowanceLink          = "%s/api/component_readiness/allowances/%d"
+	allowanceAuditLogsLink = "%s/api/component_readiness/allowances/%d/audit"
+)
+
+// ListRegressionAllowances lists allowances, optionally limited to a release. Expired allowances are
+// only included when requested.
+func ListRegressionAllowances(dbc *db.DB, release string, includeExpired bool, req *http.Request) ([]models.RegressionAllowance, error) {
+	allowances := []models.RegressionAllowance{}
+	q := dbc.DB.Order("release DESC, test_name, id")
+	if release != "" {
+		q = q.Where("release = ?", release)
+	}
+	if !includeExpired {
+		q = q.Where("expires_at IS NULL OR expires_at > ?", time.Now())
+	}
+	if res := q.Find(&allowances); res.Error != nil {
+		log.WithError(res.Error).Error("error listing regression allowances")
+		return nil, res.Error
+	}
+	for i := range allowances {
+		injectAllowanceHATEOASLinks(&allowances[i], sippyapi.GetBaseURL(req))
+	}
+	return allowances, nil
+}
+
+func GetRegressionAllowance(dbc *db.DB, id int, req *http.Request) (*models.RegressionAllowance, error) {
+	allowance := &models.RegressionAllowance{}
+	res := dbc.DB.First(allowance, id)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.WithError(res.Error).Errorf("error looking up regression allowance: %d", id)
+		return nil, res.Error
+	}
+	injectAllowanceHATEOASLinks(allowance, sippyapi.GetBaseURL(req))
+	return allowance, nil
+}
+
+// validateRegressionAllowance applies the same rules as the allowances compiled into sippy, plus the
+// constraints of the stored record. Small changes in logic for create vs update are controlled by the update param.
+func validateRegressionAllowance(allowance models.RegressionAllowance, update bool) error {
+	if !update && allowance.ID > 0 {
+		return fmt.Errorf("cannot specify an id for a new regression allowance, one will be autogenerated")
+	}
+	if update && allowance.ID == 0 {
+		return fmt.Errorf("must specify an id for a regression allowance update")
+	}
+	if allowance.Release == "" {
+		return fmt.Errorf("release must be specified")
+	}
+	if allowance.Release == allowance.PreviousRelease {
+		return fmt.Errorf("previous_release must differ from release")
+	}
+	return regressionallowances.ValidateIntentionalRegression(regressionallowances.FromAllowance(allowance))
+}
+
+// CreateRegressionAllowance stores a new allowance. dbc must carry models.CurrentUserKey in its context for the audit log.
+func CreateRegressionAllowance(dbc *gorm.DB, allowance models.RegressionAllowance, req *http.Request) (models.RegressionAllowance, error) {
+	if err := validateRegressionAllowance(allowance, false); err != nil {
+		log.WithError(err).Error("error validating regression allowance")
+		return allowance, err
+	}
+	// normalize variants the same way the importer does so lookups and uniqueness are consistent
+	allowance.Variants = regressionallowances.ToAllowance(allowance.Release, regressionallowances.FromAllowance(allowance)).Variants
+	allowance.CreatedAt = time.Time{}
+	allowance.UpdatedAt = time.Time{}
+
+	if res := dbc.Create(&allowance); res.Error != nil {
+		log.WithError(res.Error).Error("error creating regression allowance")
+		return allowance, res.Error
+	}
+	log.WithField("allowanceID", allowance.ID).Info("regression allowance created")
+	injectAllowanceHATEOASLinks(&allowance, sippyapi.GetBaseURL(req))
+	return allowance, nil
+}
+
+// UpdateRegressionAllowance replaces an existing allowance. dbc must carry models.CurrentUserKey in its context.
+func UpdateRegressionAllowance(dbc *gorm.DB, allowance models.RegressionAllowance, req *http.Request) (models.RegressionAllowance, error) {
+	if err := validateRegressionAllowance(allowance, true); err != nil {
+		log.WithError(err).Error("error validating regression allowance")
+		return allowance, err
+	}
+	allowance.Variants = regressionallowances.ToAllowance(allowance.Release, regressionallowances.FromAllowance(allowance)).Variants
+
+	existing := models.RegressionAllowance{}
+	if res := dbc.First(&existing, allowance.ID); res.Error != nil {
+		log.WithError(res.Error).Errorf("error looking up existing regression allowance: %d", allowance.ID)
+		return allowance, res.Error
+	}
+	allowance.CreatedAt = existing.CreatedAt
+
+	if res := dbc.Save(&allowance); res.Error != nil {
+		log.WithError(res.Error).Error("error updating regression allowance")
+		return allowance, res.Error
+	}
+	injectAllowanceHATEOASLinks(&allowance, sippyapi.GetBaseURL(req))
+	return allowance, nil
+}
+
+// DeleteRegressionAllowance removes an allowance. dbc must carry models.CurrentUserKey in its context.
+func DeleteRegressionAllowance(dbc *gorm.DB, id int) error {
+	existing := &models.RegressionAllowance{}
+	if res := dbc.First(existing, id).Delete(existing); res.Error != nil {
+		return fmt.Errorf("error deleting regression allowance: %v", res.Error)
+	}
+	return nil
+}
+
+// RegressionAllowanceAuditLog is a single change to an allowance, with the record before and after.
+type RegressionAllowanceAuditLog struct {
+	Operation string                      `json:"operation"`
+	User      string                      `json:"user"`
+	CreatedAt time.Time                   `json:"created_at"`
+	Old       *models.RegressionAllowance `json:"old,omitempty"`
+	New       *models.RegressionAllowance `json:"new,omitempty"`
+	Links     map[string]string           `json:"links"`
+}
+
+func GetRegressionAllowanceAuditLogs(dbc *gorm.DB, id int, req *http.Request) ([]RegressionAllowanceAuditLog, error) {
+	var auditLogs []models.AuditLog
+	res := dbc.Where("table_name = 'regression_allowances' and row_id = ?", id).Order("created_at DESC").Find(&auditLogs)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	baseURL := sippyapi.GetBaseURL(req)
+	result := make([]RegressionAllowanceAuditLog, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		entry := RegressionAllowanceAuditLog{
+			Operation: auditLog.Operation,
+			User:      auditLog.User,
+			CreatedAt: auditLog.CreatedAt,
+			Links:     map[string]string{"allowance": fmt.Sprintf(allowanceLink, baseURL, id)},
+		}
+		var err error
+		if entry.Old, err = unmarshalAuditedAllowance(auditLog.OldData); err != nil {
+			return nil, err
+		}
+		if entry.New, err = unmarshalAuditedAllowance(auditLog.NewData); err != nil {
+			return nil, err
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+func unmarshalAuditedAllowance(data []byte) (*models.RegressionAllowance, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	allowance := &models.RegressionAllowance{}
+	if err := json.Unmarshal(data, allowance); err != nil {
+		return nil, fmt.Errorf("error unmarshalling audited regression allowance: %w", err)
+	}
+	return allowance, nil
+}
+
+// injectAllowanceHATEOASLinks adds restful links clients can follow for this allowance record.
+func injectAllowanceHATEOASLinks(allowance *models.RegressionAllowance, baseURL string) {
+	allowance.Links = map[string]string{
+		"self":       fmt.Sprintf(allowanceLink, baseURL, allowance.ID),
+		"audit_logs": fmt.Sprintf(allowanceAuditLogsLink, baseURL, allowance.ID),
+	}
+}
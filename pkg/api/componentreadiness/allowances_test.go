@@ -0,0 +1,51 @@
+package componentreadiness
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+func TestValidateRegressionAllowance(t *testing.T) {
+	valid := models.RegressionAllowance{
+		Release:       "4.19",
+		TestID:        "openshift-tests:abc123",
+		TestName:      "[sig-node] some test",
+		JiraComponent: "Node",
+		Variants: []string{
+			"Architecture:amd64", "FeatureSet:default", "Installer:ipi", "Network:ovn",
+			"Platform:aws", "Suite:unknown", "Topology:ha", "Upgrade:none", "LayeredProduct:none",
+		},
+		PreviousRelease:           "4.18",
+		PreviousSuccesses:         100,
+		RegressedSuccesses:        90,
+		RegressedFailures:         10,
+		JiraBug:                   "https://issues.redhat.com/browse/OCPBUGS-1",
+		ReasonToAllowInsteadOfFix: "fix is too risky this late",
+	}
+	assert.NoError(t, validateRegressionAllowance(valid, false))
+
+	tests := []struct {
+		name   string
+		update bool
+		modify func(a *models.RegressionAllowance)
+	}{
+		{name: "id on create", modify: func(a *models.RegressionAllowance) { a.ID = 5 }},
+		{name: "no id on update", update: true, modify: func(a *models.RegressionAllowance) {}},
+		{name: "missing release", modify: func(a *models.RegressionAllowance) { a.Release = "" }},
+		{name: "same previous release", modify: func(a *models.RegressionAllowance) { a.PreviousRelease = "4.19" }},
+		{name: "missing reason", modify: func(a *models.RegressionAllowance) { a.ReasonToAllowInsteadOfFix = "" }},
+		{name: "no regression", modify: func(a *models.RegressionAllowance) { a.RegressedFailures = 0 }},
+		{name: "missing variant", modify: func(a *models.RegressionAllowance) { a.Variants = a.Variants[1:] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid
+			a.Variants = append([]string{}, valid.Variants...)
+			tt.modify(&a)
+			assert.Error(t, validateRegressionAllowance(a, tt.update))
+		})
+	}
+}
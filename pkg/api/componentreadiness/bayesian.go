@@ -0,0 +1,184 @@
+package componentreadiness
+
+import (
+	"fmt"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/testdetails"
+)
+
+// number of slices used when integrating over the base posterior; fine enough that the probability
+// is stable to well under a tenth of a percent even for tests with tens of thousands of runs.
+const bayesianIntegrationSteps = 2000
+
+// buildBayesianTestStats compares sample and base pass rates using beta-binomial posteriors. Unlike Fisher's
+// Exact test, which swings between significant and not as a few runs come and go on low-sample tests, the
+// posterior probability of regression moves gradually as evidence accumulates.
+func (c *ComponentReportGenerator) buildBayesianTestStats(testStats *testdetails.TestComparison, logger *log.Entry) {
+	testStats.Comparison = crtest.Bayesian
+
+	opts := c.ReqOptions.AdvancedOption
+	if testStats.SampleStats.Total() == 0 {
+		testStats.ReportStatus = crtest.MissingSample
+		if opts.IgnoreMissing {
+			testStats.ReportStatus = crtest.NotSignificant
+		}
+		testStats.Explanations = append(testStats.Explanations, explanationNoRegression)
+		return
+	}
+	if testStats.BaseStats == nil || testStats.BaseStats.Total() == 0 {
+		testStats.ReportStatus = crtest.MissingBasis
+		return
+	}
+
+	samplePass := testStats.SampleStats.Passes(opts.FlakeAsFailure)
+	sampleFail := testStats.SampleStats.Total() - samplePass
+	basePass := testStats.BaseStats.Passes(opts.FlakeAsFailure)
+	baseFail := testStats.BaseStats.Total() - basePass
+	effectivePityFactor := float64(opts.PityFactor) + testStats.PityAdjustment
+	effectiveMinimumFailure := opts.MinimumFailure + testStats.MinimumFailureAdjustment
+	if effectiveMinimumFailure < 0 {
+		effectiveMinimumFailure = 0
+	}
+
+	credibleLevel := float64(testStats.RequiredConfidence) / 100
+	analysis := bayesianComparison(basePass, baseFail, samplePass, sampleFail, effectivePityFactor/100, credibleLevel)
+	testStats.Bayesian = &analysis
+	logger.Debugf("computed Bayesian info: regression probability %v, improvement probability %v",
+		analysis.ProbabilityOfRegression, analysis.ProbabilityOfImprovement)
+
+	testStats.ReportStatus = crtest.NotSignificant
+	if effectiveMinimumFailure != 0 && sampleFail < effectiveMinimumFailure {
+		return
+	}
+
+	basisPassPercentage := float64(basePass) / float64(testStats.BaseStats.Total())
+	samplePassPercentage := float64(samplePass) / float64(testStats.SampleStats.Total())
+	switch {
+	case analysis.ProbabilityOfRegression >= credibleLevel:
+		testStats.ReportStatus = getRegressionStatus(basisPassPercentage, samplePassPercentage)
+		testStats.Explanations = append(testStats.Explanations,
+			fmt.Sprintf("%s regression detected.", crtest.StringForStatus(testStats.ReportStatus)),
+			fmt.Sprintf("Bayesian probability of a regression: %.2f%%.", analysis.ProbabilityOfRegression*100),
+			fmt.Sprintf("Test pass rate dropped from %.2f%% to %.2f%%.",
+				testStats.BaseStats.SuccessRate*float64(100),
+				testStats.SampleStats.SuccessRate*float64(100)),
+			fmt.Sprintf("%.0f%% credible intervals for the pass rate: base %.2f%%-%.2f%%, sample %.2f%%-%.2f%%.",
+				credibleLevel*100,
+				analysis.BaseCredibleInterval.Lower*100, analysis.BaseCredibleInterval.Upper*100,
+				analysis.SampleCredibleInterval.Lower*100, analysis.SampleCredibleInterval.Upper*100))
+	case analysis.ProbabilityOfImprovement >= credibleLevel:
+		testStats.ReportStatus = crtest.SignificantImprovement
+	}
+}
+
+// bayesianComparison computes the posterior comparison of pass rates using uniform Beta(1, 1) priors.
+// regressionMargin is how far (as a fraction) the sample pass rate must fall below the base pass rate to count.
+func bayesianComparison(basePass, baseFail, samplePass, sampleFail int, regressionMargin, credibleLevel float64) testdetails.BayesianAnalysis {
+	baseA, baseB := float64(basePass)+1, float64(baseFail)+1
+	sampleA, sampleB := float64(samplePass)+1, float64(sampleFail)+1
+	tail := (1 - credibleLevel) / 2
+	return testdetails.BayesianAnalysis{
+		ProbabilityOfRegression:  probabilityExceeds(baseA, baseB, sampleA, sampleB, regressionMargin),
+		ProbabilityOfImprovement: probabilityExceeds(sampleA, sampleB, baseA, baseB, 0),
+		CredibleLevel:            credibleLevel,
+		BaseCredibleInterval: testdetails.CredibleInterval{
+			Lower: betaQuantile(tail, baseA, baseB),
+			Upper: betaQuantile(1-tail, baseA, baseB),
+		},
+		SampleCredibleInterval: testdetails.CredibleInterval{
+			Lower: betaQuantile(tail, sampleA, sampleB),
+			Upper: betaQuantile(1-tail, sampleA, sampleB),
+		},
+	}
+}
+
+// probabilityExceeds returns P(Y - X > margin) for independent Y ~ Beta(yA, yB) and X ~ Beta(xA, xB),
+// integrating the CDF of X over the bulk of Y's distribution.
+func probabilityExceeds(yA, yB, xA, xB, margin float64) float64 {
+	mean := yA / (yA + yB)
+	stddev := math.Sqrt(yA * yB / ((yA + yB) * (yA + yB) * (yA + yB + 1)))
+	lo := math.Max(0, mean-12*stddev)
+	hi := math.Min(1, mean+12*stddev)
+
+	step := (hi - lo) / bayesianIntegrationSteps
+	prob := 0.0
+	prevCDF := regularizedIncompleteBeta(lo, yA, yB)
+	for i := 1; i <= bayesianIntegrationSteps; i++ {
+		y := lo + float64(i)*step
+		cdf := regularizedIncompleteBeta(y, yA, yB)
+		prob += (cdf - prevCDF) * regularizedIncompleteBeta(y-step/2-margin, xA, xB)
+		prevCDF = cdf
+	}
+	return math.Min(1, math.Max(0, prob))
+}
+
+// betaQuantile inverts the Beta(a, b) CDF by bisection.
+func betaQuantile(p, a, b float64) float64 {
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if regularizedIncompleteBeta(mid, a, b) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// regularizedIncompleteBeta is the Beta(a, b) CDF at x, evaluated with the usual continued fraction.
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log1p(-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 500
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	clamp := func(v float64) float64 {
+		if math.Abs(v) < tiny {
+			return tiny
+		}
+		return v
+	}
+
+	c := 1.0
+	d := 1 / clamp(1-(a+b)*x/(a+1))
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		numerator := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 / clamp(1+numerator*d)
+		c = clamp(1 + numerator/c)
+		h *= d * c
+
+		numerator = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 / clamp(1+numerator*d)
+		c = clamp(1 + numerator/c)
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,80 @@
+package componentreadiness
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/testdetails"
+)
+
+func TestRegularizedIncompleteBeta(t *testing.T) {
+	// Beta(1, 1) is uniform
+	assert.InDelta(t, 0.3, regularizedIncompleteBeta(0.3, 1, 1), 1e-9)
+	// Beta(2, 1) has CDF x^2
+	assert.InDelta(t, 0.49, regularizedIncompleteBeta(0.7, 2, 1), 1e-9)
+	// symmetric distributions are centered on one half
+	assert.InDelta(t, 0.5, regularizedIncompleteBeta(0.5, 40, 40), 1e-9)
+	assert.InDelta(t, 0.7, betaQuantile(regularizedIncompleteBeta(0.7, 15, 4), 15, 4), 1e-9)
+}
+
+func TestBayesianComparison(t *testing.T) {
+	same := bayesianComparison(95, 5, 95, 5, 0, 0.95)
+	assert.InDelta(t, 0.5, same.ProbabilityOfRegression, 0.01)
+	assert.InDelta(t, 0.5, same.ProbabilityOfImprovement, 0.01)
+	assert.Less(t, same.BaseCredibleInterval.Lower, 0.95)
+	assert.Greater(t, same.BaseCredibleInterval.Upper, 0.95)
+
+	regressed := bayesianComparison(990, 10, 80, 20, 0.05, 0.95)
+	assert.Greater(t, regressed.ProbabilityOfRegression, 0.99)
+	assert.Less(t, regressed.ProbabilityOfImprovement, 0.01)
+	assert.Less(t, regressed.SampleCredibleInterval.Upper, regressed.BaseCredibleInterval.Lower)
+
+	// a margin makes a small drop insignificant
+	small := bayesianComparison(990, 10, 970, 30, 0.05, 0.95)
+	assert.Less(t, small.ProbabilityOfRegression, 0.01)
+}
+
+func Test_componentReportGenerator_buildBayesianTestStats(t *testing.T) {
+	tests := []struct {
+		name          string
+		sampleSuccess int
+		sampleFailure int
+		baseSuccess   int
+		baseFailure   int
+		minFail       int
+		expected      crtest.Status
+	}{
+		{name: "no regression", sampleSuccess: 97, sampleFailure: 3, baseSuccess: 970, baseFailure: 30, expected: crtest.NotSignificant},
+		{name: "significant regression", sampleSuccess: 88, sampleFailure: 12, baseSuccess: 990, baseFailure: 10, expected: crtest.SignificantRegression},
+		{name: "extreme regression", sampleSuccess: 5, sampleFailure: 10, baseSuccess: 990, baseFailure: 10, expected: crtest.ExtremeRegression},
+		{name: "under minimum failures", sampleSuccess: 5, sampleFailure: 2, baseSuccess: 990, baseFailure: 10, minFail: 3, expected: crtest.NotSignificant},
+		{name: "improvement", sampleSuccess: 1000, sampleFailure: 0, baseSuccess: 900, baseFailure: 100, expected: crtest.SignificantImprovement},
+		{name: "missing sample", baseSuccess: 900, baseFailure: 100, expected: crtest.MissingSample},
+		{name: "missing basis", sampleSuccess: 10, expected: crtest.MissingBasis},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ComponentReportGenerator{}
+			c.ReqOptions.AdvancedOption.ComparisonMode = crtest.Bayesian
+			c.ReqOptions.AdvancedOption.Confidence = 95
+			c.ReqOptions.AdvancedOption.PityFactor = 5
+			c.ReqOptions.AdvancedOption.MinimumFailure = tt.minFail
+
+			testAnalysis := &testdetails.TestComparison{
+				SampleStats: testdetails.ReleaseStats{Stats: crtest.Stats{SuccessCount: tt.sampleSuccess, FailureCount: tt.sampleFailure}},
+				BaseStats:   &testdetails.ReleaseStats{Stats: crtest.Stats{SuccessCount: tt.baseSuccess, FailureCount: tt.baseFailure}},
+			}
+			c.assessComponentStatus(testAnalysis, logrus.NewEntry(logrus.New()))
+			assert.Equal(t, tt.expected, testAnalysis.ReportStatus)
+			assert.Equal(t, crtest.Bayesian, testAnalysis.Comparison)
+			assert.Nil(t, testAnalysis.FisherExact)
+			if tt.expected <= crtest.SignificantRegression {
+				assert.NotNil(t, testAnalysis.Bayesian)
+				assert.Contains(t, testAnalysis.Explanations[1], "Bayesian probability of a regression")
+			}
+		})
+	}
+}
@@ -87,6 +87,7 @@ func GetComponentReport(
 		return report, []error{err}
 	}
 
+	reqOptions, reportCacheOption := reportCacheOptions(reqOptions)
 	generator := NewComponentReportGenerator(provider, reqOptions, dbc, releaseConfigs, baseURL)
 
 	if os.Getenv("DEV_MODE") == "1" {
@@ -103,7 +104,7 @@ func GetComponentReport(
 
 	report, errs = api.GetDataFromCacheOrGenerate[crtype.ComponentReport](
 		ctx,
-		generator.getCache(), generator.ReqOptions.CacheOption,
+		generator.getCache(), reportCacheOption,
 		api.NewCacheSpec(generator.GetCacheKey(ctx), ComponentReportCacheKeyPrefix, nil),
 		generator.GenerateReport,
 		crtype.ComponentReport{})
@@ -119,6 +120,15 @@ func GetComponentReport(
 	return report, []error{}
 }
 
+// reportCacheOptions splits the cache options for looking up a report from those for the queries that generate it.
+// Only the report itself may be served stale while revalidating; otherwise a regenerated report could be built from
+// stale query results and cached as fresh.
+func reportCacheOptions(reqOptions reqopts.RequestOptions) (reqopts.RequestOptions, cache.RequestOptions) {
+	reportCacheOption := reqOptions.CacheOption
+	reqOptions.CacheOption.StaleWhileRevalidate = 0
+	return reqOptions, reportCacheOption
+}
+
 // PostAnalysis runs the PostAnalysis method for all middleware on this component report.
 // This is done outside the caching mechanism so we can load fresh data from our db (which is fast and cheap),
 // and inject it into an expensive / slow report without recalculating everything.
@@ -187,6 +197,8 @@ type GeneratorCacheKey struct {
 	AdvancedOption reqopts.Advanced
 	TestFilters    reqopts.TestFilters
 	TestIDOptions  []reqopts.TestIdentification
+	// ExcludedJobRunLabels is only set when the labels differ from the defaults, so existing keys are unchanged.
+	ExcludedJobRunLabels *[]string `json:",omitempty"`
 }
 
 // GetCacheKey creates a cache key using the generator properties that we want included for uniqueness in what
@@ -230,6 +242,8 @@ func (c *ComponentReportGenerator) GetCacheKey(ctx context.Context) GeneratorCac
 	if len(cacheKey.TestFilters.Capabilities) > 0 { // should already be, but just in case
 		sort.Strings(cacheKey.TestFilters.Capabilities)
 	}
+	cacheKey.AdvancedOption.ExcludedJobRunLabels = nil
+	cacheKey.ExcludedJobRunLabels = c.ReqOptions.AdvancedOption.ExcludedJobRunLabelsCacheKey()
 
 	return cacheKey
 }
@@ -285,7 +299,7 @@ func (c *ComponentReportGenerator) initializeMiddleware() {
 	} else {
 		log.Warnf("no db connection provided, skipping regressiontracker middleware")
 	}
-	c.middlewares = append(c.middlewares, regressionallowances2.NewRegressionAllowancesMiddleware(c.ReqOptions, c.releaseConfigs))
+	c.middlewares = append(c.middlewares, regressionallowances2.NewRegressionAllowancesMiddleware(c.dbc, c.ReqOptions, c.releaseConfigs))
 
 	// Initialize LinkInjector middleware
 	linkInjector := linkinjector.NewLinkInjectorMiddleware(c.ReqOptions, c.baseURL)
@@ -580,11 +594,12 @@ func updateCellStatus(
 func initTestAnalysisStruct(
 	testStats *testdetails.TestComparison,
 	reqOptions reqopts.RequestOptions,
+	testID string,
 	sampleStatus crstatus.TestStatus,
 	baseStatus *crstatus.TestStatus) {
 
-	// Default to required confidence from request, middleware may adjust later.
-	testStats.RequiredConfidence = reqOptions.AdvancedOption.Confidence
+	// Default to required confidence from request or the view's overrides for this test, middleware may adjust later.
+	applyThresholds(testStats, reqOptions.AdvancedOption, sampleStatus.Component, sampleStatus.Capabilities, testID)
 
 	testStats.SampleStats = testdetails.ReleaseStats{
 		Release: reqOptions.SampleRelease.Name,
@@ -602,6 +617,21 @@ func initTestAnalysisStruct(
 	}
 }
 
+// thresholdOverrideAdjustment is recorded on tests whose thresholds were overridden by the view.
+const thresholdOverrideAdjustment = "threshold-override"
+
+// applyThresholds sets the required confidence for a test, and expresses any overridden pity factor or minimum
+// failure count as adjustments, since the analysis applies those on top of the request's advanced options.
+func applyThresholds(testStats *testdetails.TestComparison, opts reqopts.Advanced, component string, capabilities []string, testID string) {
+	thresholds := opts.ThresholdsFor(component, capabilities, testID)
+	testStats.RequiredConfidence = thresholds.Confidence
+	if thresholds.Overridden {
+		testStats.PityAdjustment = float64(thresholds.PityFactor - opts.PityFactor)
+		testStats.MinimumFailureAdjustment = thresholds.MinimumFailure - opts.MinimumFailure
+		testStats.AddAdjustment(thresholdOverrideAdjustment)
+	}
+}
+
 func (c *ComponentReportGenerator) generateComponentTestReport(basisStatusMap, sampleStatusMap map[string]crstatus.TestStatus) (crtype.ComponentReport, error) {
 	// aggregatedStatus is the aggregated status based on the requested rows and columns
 	aggregatedStatus := map[crtest.RowIdentification]map[crtest.ColumnID]cellStatus{}
@@ -634,9 +664,9 @@ func (c *ComponentReportGenerator) generateComponentTestReport(basisStatusMap, s
 		} else {
 			// Initialize the test analysis before we start passing it around to the middleware
 			if basisThere {
-				initTestAnalysisStruct(&cellReport, c.ReqOptions, sampleStatus, &basisStatus)
+				initTestAnalysisStruct(&cellReport, c.ReqOptions, testKey.TestID, sampleStatus, &basisStatus)
 			} else {
-				initTestAnalysisStruct(&cellReport, c.ReqOptions, sampleStatus, nil)
+				initTestAnalysisStruct(&cellReport, c.ReqOptions, testKey.TestID, sampleStatus, nil)
 			}
 
 			// Give middleware a chance to adjust parameters prior to analysis
@@ -757,7 +787,7 @@ func getRegressionStatus(basisPassPercentage, samplePassPercentage float64) crte
 
 // TODO: this will eventually become the analyze step on a Middleware, or possibly a separate
 // set of objects relating to analysis, as there's not a lot of overlap between the analyzers
-// (fishers, pass rate, bayes (future)) and the middlewares (fallback, intentional regressions,
+// (fishers, pass rate, bayes) and the middlewares (fallback, intentional regressions,
 // cross variant compare, rarely run jobs, etc.)
 func (c *ComponentReportGenerator) assessComponentStatus(testStats *testdetails.TestComparison, logger *log.Entry) {
 	// Catch unset required confidence, typically unit tests
@@ -781,6 +811,11 @@ func (c *ComponentReportGenerator) assessComponentStatus(testStats *testdetails.
 		return
 	}
 
+	if opts.ComparisonMode == crtest.Bayesian {
+		c.buildBayesianTestStats(testStats, logger)
+		return
+	}
+
 	// Otherwise we fall back to default behavior of Fishers Exact test:
 	c.buildFisherExactTestStats(testStats, logger)
 }
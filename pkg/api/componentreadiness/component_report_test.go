@@ -2,6 +2,7 @@
 package componentreadiness
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/testdetails"
+	"github.com/openshift/sippy/pkg/apis/cache"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
@@ -1860,3 +1862,100 @@ func Test_componentReportGenerator_assessComponentStatus(t *testing.T) {
 
 // TestCopyIncludeVariantsAndRemoveOverrides moved to dataprovider/bigquery package
 // where the function now lives.
+
+func Test_applyThresholds(t *testing.T) {
+	minimumFailure := 25
+	confidence := 99
+	opts := reqopts.Advanced{
+		Confidence:     95,
+		PityFactor:     5,
+		MinimumFailure: 3,
+		ThresholdOverrides: []reqopts.ThresholdOverride{
+			{Component: "Storage", Confidence: &confidence, MinimumFailure: &minimumFailure},
+		},
+	}
+	c := &ComponentReportGenerator{ReqOptions: reqopts.RequestOptions{AdvancedOption: opts}}
+	newAnalysis := func() *testdetails.TestComparison {
+		return &testdetails.TestComparison{
+			SampleStats: testdetails.ReleaseStats{Stats: crtest.Stats{SuccessCount: 80, FailureCount: 20}},
+			BaseStats:   &testdetails.ReleaseStats{Stats: crtest.Stats{SuccessCount: 98, FailureCount: 2}},
+		}
+	}
+
+	regular := newAnalysis()
+	applyThresholds(regular, opts, "Networking", nil, "test-1")
+	assert.Equal(t, 95, regular.RequiredConfidence)
+	assert.Empty(t, regular.Adjustments)
+	c.assessComponentStatus(regular, logrus.NewEntry(logrus.New()))
+	assert.Equal(t, crtest.ExtremeRegression, regular.ReportStatus)
+
+	overridden := newAnalysis()
+	applyThresholds(overridden, opts, "Storage", nil, "test-1")
+	assert.Equal(t, 99, overridden.RequiredConfidence)
+	assert.Equal(t, 22, overridden.MinimumFailureAdjustment)
+	assert.Zero(t, overridden.PityAdjustment)
+	assert.Equal(t, []string{thresholdOverrideAdjustment}, overridden.Adjustments)
+	c.assessComponentStatus(overridden, logrus.NewEntry(logrus.New()))
+	assert.Equal(t, crtest.NotSignificant, overridden.ReportStatus, "20 failures is under the overridden minimum")
+}
+
+func Test_internalGenerateTestDetailsReport_excludedJobRuns(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	row := func(id string, hours int, passed bool, labels ...string) crstatus.TestJobRunRows {
+		r := crstatus.TestJobRunRows{ProwJob: "job-a", ProwJobRunID: id, StartTime: start.Add(time.Duration(hours) * time.Hour), JobLabels: labels}
+		r.TotalCount = 1
+		if passed {
+			r.SuccessCount = 1
+		}
+		return r
+	}
+	baseStatus := map[string][]crstatus.TestJobRunRows{"job-a": {row("b1", 0, true), row("b2", 1, false, "InfraFailure")}}
+	sampleStatus := map[string][]crstatus.TestJobRunRows{
+		"job-a": {row("s1", 3, true), row("s2", 2, false, "ClusterDNSFlake", "InfraFailure"), row("s2", 2, false, "ClusterDNSFlake", "InfraFailure")},
+		"job-b": {row("s3", 4, false, "InfraFailure")},
+	}
+	testID := reqopts.TestIdentification{TestID: "test-1"}
+
+	c := &ComponentReportGenerator{ReqOptions: reqopts.RequestOptions{
+		SampleRelease:  reqopts.Release{Name: "4.20"},
+		AdvancedOption: reqopts.Advanced{Confidence: 95},
+	}}
+	report := c.internalGenerateTestDetailsReport("4.19", nil, nil, baseStatus, sampleStatus, testID)
+	assert.Equal(t, []testdetails.ExcludedJobRun{
+		{Release: "4.20", JobName: "job-a", JobRunID: "s2", StartTime: start.Add(2 * time.Hour), Label: "InfraFailure"},
+		{Release: "4.20", JobName: "job-a", JobRunID: "s3", StartTime: start.Add(4 * time.Hour), Label: "InfraFailure"},
+		{Release: "4.19", JobName: "job-a", JobRunID: "b2", StartTime: start.Add(time.Hour), Label: "InfraFailure"},
+	}, report.ExcludedJobRuns)
+	assert.Equal(t, 1, report.Analyses[0].SampleStats.SuccessCount+report.Analyses[0].SampleStats.FailureCount)
+	assert.Len(t, report.Analyses[0].JobStats, 1, "jobs with only excluded runs are dropped")
+
+	c.ReqOptions.AdvancedOption.ExcludedJobRunLabels = []string{}
+	report = c.internalGenerateTestDetailsReport("4.19", nil, nil, baseStatus, sampleStatus, testID)
+	assert.Empty(t, report.ExcludedJobRuns)
+	assert.Equal(t, 4, report.Analyses[0].SampleStats.SuccessCount+report.Analyses[0].SampleStats.FailureCount)
+}
+
+func TestGetCacheKey_excludedJobRunLabels(t *testing.T) {
+	keyFor := func(labels []string) string {
+		c := &ComponentReportGenerator{ReqOptions: reqopts.RequestOptions{AdvancedOption: reqopts.Advanced{ExcludedJobRunLabels: labels}}}
+		b, err := json.Marshal(c.GetCacheKey(context.TODO()))
+		assert.NoError(t, err)
+		return string(b)
+	}
+	assert.Equal(t, keyFor(nil), keyFor([]string{"InfraFailure"}))
+	assert.NotContains(t, keyFor(nil), "ExcludedJobRunLabels")
+	assert.NotEqual(t, keyFor(nil), keyFor([]string{}))
+	assert.Equal(t, keyFor([]string{"ClusterDNSFlake", "InfraFailure"}), keyFor([]string{"InfraFailure", "ClusterDNSFlake"}))
+}
+
+func TestReportCacheOptions(t *testing.T) {
+	reqOptions := reqopts.RequestOptions{CacheOption: cache.RequestOptions{
+		CRTimeRoundingFactor: 4 * time.Hour,
+		StaleWhileRevalidate: 2 * time.Hour,
+	}}
+	queryOptions, reportCacheOption := reportCacheOptions(reqOptions)
+	assert.Equal(t, 2*time.Hour, reportCacheOption.StaleWhileRevalidate, "the report may be served stale")
+	assert.Zero(t, queryOptions.CacheOption.StaleWhileRevalidate, "queries generating the report must not be")
+	assert.Equal(t, 4*time.Hour, queryOptions.CacheOption.CRTimeRoundingFactor)
+	assert.Equal(t, 2*time.Hour, reqOptions.CacheOption.StaleWhileRevalidate, "the caller's options are unchanged")
+}
@@ -25,8 +25,7 @@ import (
 )
 
 const (
-	DefaultJunitTable        = "junit"
-	jobRunAnnotationToIgnore = "InfraFailure"
+	DefaultJunitTable = "junit"
 
 	// normalJobNameCol simply uses the prow job name for regular (non-pull-request) component reports.
 	normalJobNameCol = `
@@ -261,11 +260,31 @@ func buildKeyTestFilterClause(tableAlias string) string {
 //   - Then successes (success_val > 0)
 //   - Then failures
 //
+// Job runs carrying any of excludedJobRunLabels are left out entirely.
+//
 // If keyTestNames is provided, additional CTEs are created to identify jobs with failed key tests
 // based on the DEDUPED data. This ensures key test filtering uses the same deduplicated results.
-func buildCRQueryCTEs(dataset, junitTable, jobNameQueryPortion, jobRunAnnotationToIgnore string, keyTestNames []string) (string, []bigquery.QueryParameter) {
+func buildCRQueryCTEs(dataset, junitTable, jobNameQueryPortion string, excludedJobRunLabels, keyTestNames []string) (string, []bigquery.QueryParameter) {
 	var commonParams []bigquery.QueryParameter
 
+	excludedJoin, excludedFilter := "", ""
+	if len(excludedJobRunLabels) > 0 {
+		excludedJoin = fmt.Sprintf(`
+			LEFT JOIN (
+				SELECT DISTINCT prowjob_build_id
+				FROM %s.job_labels
+				WHERE prowjob_start >= DATETIME(@From)
+				AND prowjob_start < DATETIME(@To)
+				AND label IN UNNEST(@ExcludedJobRunLabels)
+			) excluded_runs ON junit.prowjob_build_id = excluded_runs.prowjob_build_id`, dataset)
+		excludedFilter = `
+			AND excluded_runs.prowjob_build_id IS NULL`
+		commonParams = append(commonParams, bigquery.QueryParameter{
+			Name:  "ExcludedJobRunLabels",
+			Value: excludedJobRunLabels,
+		})
+	}
+
 	// Create the deduped_testcases CTE - this is the source of truth for all subsequent CTEs
 	dedupedCTE := fmt.Sprintf(`deduped_testcases_with_rownum AS (
 			SELECT
@@ -297,21 +316,15 @@ func buildCRQueryCTEs(dataset, junitTable, jobNameQueryPortion, jobRunAnnotation
 			INNER JOIN %s.jobs  jobs ON
 				junit.prowjob_build_id = jobs.prowjob_build_id
 				AND jobs.prowjob_start >= DATETIME(@From)
-				AND jobs.prowjob_start < DATETIME(@To)
-			LEFT JOIN %s.job_labels job_labels ON
-				junit.prowjob_build_id = job_labels.prowjob_build_id
-				AND job_labels.prowjob_start >= DATETIME(@From)
-				AND job_labels.prowjob_start < DATETIME(@To)
-				AND job_labels.label = '%s'
+				AND jobs.prowjob_start < DATETIME(@To)%s
 			WHERE modified_time >= DATETIME(@From)
 			AND modified_time < DATETIME(@To)
-			AND skipped = false
-			AND job_labels.label IS NULL
+			AND skipped = false%s
 		),
 		deduped_testcases AS (
 			SELECT * FROM deduped_testcases_with_rownum WHERE row_num = 1
 		)`,
-		jobNameQueryPortion, dataset, junitTable, dataset, dataset, jobRunAnnotationToIgnore)
+		jobNameQueryPortion, dataset, junitTable, dataset, excludedJoin, excludedFilter)
 
 	// Always create the component mapping CTE
 	componentMappingCTE := fmt.Sprintf(`,
@@ -404,7 +417,8 @@ func BuildComponentReportQuery(
 	// TODO: last_failure here explicitly uses success_val not adjusted_success_val, this ensures we
 	// show the last time the test failed, not flaked. if you enable the flakes as failures feature (which is
 	// non default today), the last failure time will be wrong which can impact things like failed fix detection.
-	withClause, commonParams := buildCRQueryCTEs(client.Dataset, junitTable, jobNameQueryPortion, jobRunAnnotationToIgnore, reqOptions.AdvancedOption.KeyTestNames)
+	withClause, commonParams := buildCRQueryCTEs(client.Dataset, junitTable, jobNameQueryPortion,
+		reqOptions.AdvancedOption.JobRunLabelsToExclude(), reqOptions.AdvancedOption.KeyTestNames)
 
 	queryString := fmt.Sprintf(`%s
 					SELECT
@@ -559,8 +573,9 @@ func buildTestDetailsQuery(
 		groupByVariants += fmt.Sprintf("jv_%s.variant_value,\n", v)
 	}
 
-	// Build WITH clause with key test filtering if configured
-	withClause, commonParams := buildCRQueryCTEs(client.Dataset, junitTable, jobNameQueryPortion, jobRunAnnotationToIgnore, c.AdvancedOption.KeyTestNames)
+	// Build WITH clause with key test filtering if configured. Runs with excluded labels are kept here and
+	// filtered out by the test details report, so it can show which runs were excluded and why.
+	withClause, commonParams := buildCRQueryCTEs(client.Dataset, junitTable, jobNameQueryPortion, nil, c.AdvancedOption.KeyTestNames)
 
 	jobLabelsJoin := fmt.Sprintf(`LEFT JOIN (
 						SELECT prowjob_build_id, STRING_AGG(DISTINCT label, ',' ORDER BY label) AS job_labels
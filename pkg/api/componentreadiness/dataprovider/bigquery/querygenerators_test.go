@@ -263,7 +263,8 @@ func TestBuildComponentReportQuery_WithAndWithoutExclusiveTests(t *testing.T) {
 			DBGroupBy:       sets.NewString(),
 			IncludeVariants: map[string][]string{},
 		},
-		AdvancedOption: reqopts.Advanced{},
+		// exclude no job run labels so only the key test parameters are present
+		AdvancedOption: reqopts.Advanced{ExcludedJobRunLabels: []string{}},
 	}
 
 	// Query without key tests
@@ -322,3 +323,52 @@ func TestBuildComponentReportQuery_WithAndWithoutExclusiveTests(t *testing.T) {
 			"KeyTestNames parameter should contain the test names array")
 	}
 }
+
+func TestBuildComponentReportQuery_ExcludedJobRunLabels(t *testing.T) {
+	mockClient := &bqcachedclient.Client{Dataset: "test_dataset"}
+	allJobVariants := crtest.JobVariants{Variants: map[string][]string{"Platform": {"aws"}}}
+
+	tests := []struct {
+		name     string
+		excluded []string
+		expected []string
+	}{
+		{
+			name:     "defaults exclude infra failures",
+			expected: []string{"InfraFailure"},
+		},
+		{
+			name:     "configured labels replace defaults",
+			excluded: []string{"InfraFailure", "ClusterDNSFlake"},
+			expected: []string{"InfraFailure", "ClusterDNSFlake"},
+		},
+		{
+			name:     "empty list excludes nothing",
+			excluded: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqOptions := reqopts.RequestOptions{
+				VariantOption:  reqopts.Variants{DBGroupBy: sets.NewString("Platform")},
+				AdvancedOption: reqopts.Advanced{ExcludedJobRunLabels: tt.excluded},
+			}
+			query, _, params := BuildComponentReportQuery(mockClient, reqOptions, allJobVariants, map[string][]string{}, DefaultJunitTable, false)
+
+			var found interface{}
+			for _, p := range params {
+				if p.Name == "ExcludedJobRunLabels" {
+					found = p.Value
+				}
+			}
+			if len(tt.expected) == 0 {
+				assert.NotContains(t, query, "excluded_runs")
+				assert.Nil(t, found)
+				return
+			}
+			assert.Contains(t, query, "label IN UNNEST(@ExcludedJobRunLabels)")
+			assert.Contains(t, query, "AND excluded_runs.prowjob_build_id IS NULL")
+			assert.Equal(t, tt.expected, found)
+		})
+	}
+}
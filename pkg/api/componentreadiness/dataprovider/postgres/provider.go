@@ -0,0 +1,389 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+
+	apiPkg "github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/componentreadiness/dataprovider"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crstatus"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
+	apiCache "github.com/openshift/sippy/pkg/apis/cache"
+	v1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/util/param"
+	"github.com/openshift/sippy/pkg/util/sets"
+)
+
+var _ dataprovider.DataProvider = &PostgresProvider{}
+
+// PostgresProvider implements dataprovider.DataProvider using the prow job run and test
+// tables in the sippy postgres database, allowing component readiness to run without BigQuery.
+// Test ownership (component and capabilities) comes from the test_ownerships table.
+type PostgresProvider struct {
+	dbc   *db.DB
+	cache apiCache.Cache
+}
+
+func NewPostgresProvider(dbc *db.DB, cache apiCache.Cache) *PostgresProvider {
+	return &PostgresProvider{dbc: dbc, cache: cache}
+}
+
+func (p *PostgresProvider) Cache() apiCache.Cache {
+	return p.cache
+}
+
+// --- TestStatusQuerier ---
+
+func (p *PostgresProvider) QueryBaseTestStatus(ctx context.Context, reqOptions reqopts.RequestOptions,
+	allJobVariants crtest.JobVariants) (map[string]crstatus.TestStatus, []error) {
+
+	generator := NewBaseQueryGenerator(p.dbc, reqOptions, allJobVariants)
+	result, errs := apiPkg.GetDataFromCacheOrGenerate[crstatus.ReportTestStatus](
+		ctx, p.cache, reqOptions.CacheOption,
+		apiPkg.NewCacheSpec(generator, "PostgresBaseTestStatus~", &reqOptions.BaseRelease.End),
+		generator.QueryTestStatus, crstatus.ReportTestStatus{})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result.BaseStatus, nil
+}
+
+func (p *PostgresProvider) QuerySampleTestStatus(ctx context.Context, reqOptions reqopts.RequestOptions,
+	allJobVariants crtest.JobVariants,
+	includeVariants map[string][]string,
+	start, end time.Time) (map[string]crstatus.TestStatus, []error) {
+
+	generator := NewSampleQueryGenerator(p.dbc, reqOptions, allJobVariants, includeVariants, start, end)
+	result, errs := apiPkg.GetDataFromCacheOrGenerate[crstatus.ReportTestStatus](
+		ctx, p.cache, reqOptions.CacheOption,
+		apiPkg.NewCacheSpec(generator, "PostgresSampleTestStatus~", &end),
+		generator.QueryTestStatus, crstatus.ReportTestStatus{})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result.SampleStatus, nil
+}
+
+// --- TestDetailsQuerier ---
+
+func (p *PostgresProvider) QueryBaseJobRunTestStatus(ctx context.Context, reqOptions reqopts.RequestOptions,
+	allJobVariants crtest.JobVariants) (map[string][]crstatus.TestJobRunRows, []error) {
+
+	generator := NewBaseTestDetailsQueryGenerator(
+		log.WithField("func", "QueryBaseJobRunTestStatus"),
+		p.dbc, reqOptions, allJobVariants,
+		reqOptions.BaseRelease.Name, reqOptions.BaseRelease.Start, reqOptions.BaseRelease.End,
+		reqOptions.TestIDOptions)
+
+	result, errs := apiPkg.GetDataFromCacheOrGenerate[crstatus.TestJobRunStatuses](
+		ctx, p.cache, reqOptions.CacheOption,
+		apiPkg.NewCacheSpec(generator, "PostgresBaseJobRunTestStatus~", &reqOptions.BaseRelease.End),
+		generator.QueryTestStatus, crstatus.TestJobRunStatuses{})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result.BaseStatus, nil
+}
+
+func (p *PostgresProvider) QuerySampleJobRunTestStatus(ctx context.Context, reqOptions reqopts.RequestOptions,
+	allJobVariants crtest.JobVariants,
+	includeVariants map[string][]string,
+	start, end time.Time) (map[string][]crstatus.TestJobRunRows, []error) {
+
+	generator := NewSampleTestDetailsQueryGenerator(p.dbc, reqOptions, allJobVariants, includeVariants, start, end)
+	result, errs := apiPkg.GetDataFromCacheOrGenerate[crstatus.TestJobRunStatuses](
+		ctx, p.cache, reqOptions.CacheOption,
+		apiPkg.NewCacheSpec(generator, "PostgresSampleJobRunTestStatus~", &end),
+		generator.QueryTestStatus, crstatus.TestJobRunStatuses{})
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result.SampleStatus, nil
+}
+
+// --- MetadataQuerier ---
+
+func (p *PostgresProvider) QueryJobVariants(ctx context.Context) (crtest.JobVariants, []error) {
+	variants := crtest.JobVariants{Variants: map[string][]string{}}
+
+	type variantRow struct {
+		VariantName  string
+		VariantValue string
+	}
+	var rows []variantRow
+	res := p.dbc.DB.WithContext(ctx).Raw(`
+		SELECT DISTINCT variant_name, variant_value FROM (
+			SELECT split_part(variant, ':', 1) AS variant_name, substr(variant, strpos(variant, ':') + 1) AS variant_value
+			FROM prow_jobs, unnest(prow_jobs.variants) AS variant
+			WHERE split_part(variant, ':', 1) != 'Release'
+			UNION
+			SELECT 'Release', release FROM prow_jobs
+		) v
+		WHERE variant_value != ''
+		ORDER BY variant_name, variant_value`).Scan(&rows)
+	if res.Error != nil {
+		log.WithError(res.Error).Error("error querying variants from postgres")
+		return variants, []error{res.Error}
+	}
+
+	for _, row := range rows {
+		variants.Variants[row.VariantName] = append(variants.Variants[row.VariantName], row.VariantValue)
+	}
+	floatVariants := sets.NewString("FromRelease", "FromReleaseMajor", "FromReleaseMinor", "Release", "ReleaseMajor", "ReleaseMinor")
+	for name, values := range variants.Variants {
+		if floatVariants.Has(name) {
+			sort.Slice(values, func(i, j int) bool {
+				return compareVersions(values[i], values[j]) < 0
+			})
+		}
+	}
+	return variants, nil
+}
+
+// QueryReleaseDates returns a time range for each release. Releases in postgres carry no GA date,
+// so the ranges are open-ended.
+func (p *PostgresProvider) QueryReleaseDates(ctx context.Context, _ reqopts.RequestOptions) ([]crtest.ReleaseTimeRange, []error) {
+	releases, err := p.QueryReleases(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+	timeRanges := []crtest.ReleaseTimeRange{}
+	for _, release := range releases {
+		timeRanges = append(timeRanges, crtest.ReleaseTimeRange{Release: release.Release})
+	}
+	return timeRanges, nil
+}
+
+// QueryReleases derives the known releases from the prow jobs recorded in postgres, newest first,
+// each pointing to the next older release as its previous release.
+func (p *PostgresProvider) QueryReleases(ctx context.Context) ([]v1.Release, error) {
+	var names []string
+	res := p.dbc.DB.WithContext(ctx).Raw(`SELECT DISTINCT release FROM prow_jobs WHERE release != ''`).Scan(&names)
+	if res.Error != nil {
+		log.WithError(res.Error).Error("error querying releases from postgres")
+		return nil, res.Error
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return compareVersions(names[i], names[j]) > 0
+	})
+
+	releases := make([]v1.Release, 0, len(names))
+	for i, name := range names {
+		release := v1.Release{
+			Release: name,
+			Capabilities: map[v1.ReleaseCapability]bool{
+				v1.ComponentReadinessCap: true,
+				v1.SippyClassicCap:       true,
+			},
+		}
+		if i+1 < len(names) {
+			release.PreviousRelease = names[i+1]
+		}
+		releases = append(releases, release)
+	}
+	return releases, nil
+}
+
+// QueryUniqueVariantValues returns the distinct values for a variant over the past 60 days of job runs.
+// The field names are the bigquery junit columns (e.g. platform, network, arch, upgrade), which are mapped
+// onto the equivalent prow job variants; the nested "variants" field returns every variant value.
+func (p *PostgresProvider) QueryUniqueVariantValues(ctx context.Context, field string, nested bool) ([]string, error) {
+	variantFilter := ""
+	var args []interface{}
+	if !nested {
+		variantName, ok := junitColumnVariants[field]
+		if !ok {
+			return nil, fmt.Errorf("unsupported variant field %q", field)
+		}
+		variantFilter = "AND split_part(variant, ':', 1) = ?"
+		args = append(args, variantName)
+	}
+
+	var names []string
+	res := p.dbc.DB.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT DISTINCT substr(variant, strpos(variant, ':') + 1) AS name
+		FROM prow_jobs, unnest(prow_jobs.variants) AS variant
+		WHERE EXISTS (
+			SELECT 1 FROM prow_job_runs
+			WHERE prow_job_runs.prow_job_id = prow_jobs.id
+			AND prow_job_runs.timestamp > NOW() - INTERVAL '60 days'
+		)
+		%s
+		ORDER BY name`, variantFilter), args...).Scan(&names)
+	if res.Error != nil {
+		log.WithError(res.Error).Error("error querying variant values from postgres")
+		return nil, res.Error
+	}
+	return names, nil
+}
+
+// junitColumnVariants maps the bigquery junit column names callers ask for onto prow job variant names.
+var junitColumnVariants = map[string]string{
+	"platform": "Platform",
+	"network":  "Network",
+	"arch":     "Architecture",
+	"upgrade":  "Upgrade",
+}
+
+// --- JobQuerier ---
+
+func (p *PostgresProvider) QueryJobRuns(ctx context.Context, reqOptions reqopts.RequestOptions,
+	allJobVariants crtest.JobVariants,
+	release string, start, end time.Time) (map[string]dataprovider.JobRunStats, error) {
+
+	params := map[string]interface{}{
+		"From":    start,
+		"To":      end,
+		"Release": release,
+	}
+
+	variantFilters := ""
+	includeVariants := reqOptions.VariantOption.IncludeVariants
+	for _, group := range sortedKeys(includeVariants) {
+		cleanGroup := param.Cleanse(group)
+		paramName := fmt.Sprintf("variantGroup_%s", cleanGroup)
+		variantFilters += fmt.Sprintf(" AND (jv_%s.variant_value IN @%s)", cleanGroup, paramName)
+		params[paramName] = includeVariants[group]
+	}
+
+	queryString := fmt.Sprintf(`WITH %s
+		SELECT
+			prow_jobs.name AS job_name,
+			COUNT(DISTINCT prow_job_runs.id) AS total_runs,
+			COUNT(DISTINCT prow_job_runs.id) FILTER (WHERE prow_job_runs.succeeded) AS successful_runs
+		FROM prow_job_runs
+		INNER JOIN prow_jobs ON prow_jobs.id = prow_job_runs.prow_job_id
+		%s
+		WHERE prow_job_runs.timestamp >= @From
+			AND prow_job_runs.timestamp < @To
+			AND prow_job_runs.deleted_at IS NULL
+			AND prow_jobs.release = @Release
+			AND (prow_jobs.name LIKE 'periodic-%%' OR prow_jobs.name LIKE 'release-%%' OR prow_jobs.name LIKE 'aggregator-%%')
+			%s
+		GROUP BY prow_jobs.name
+		ORDER BY prow_jobs.name
+	`, jobVariantsCTE, buildVariantJoins(crtest.JobVariants{Variants: includeVariants}, "prow_jobs.name"), variantFilters)
+
+	type jobRunRow struct {
+		JobName        string
+		TotalRuns      int
+		SuccessfulRuns int
+	}
+	var rows []jobRunRow
+	if res := p.dbc.DB.WithContext(ctx).Raw(queryString, params).Scan(&rows); res.Error != nil {
+		return nil, fmt.Errorf("error executing view jobs query: %w", res.Error)
+	}
+
+	results := map[string]dataprovider.JobRunStats{}
+	for _, row := range rows {
+		passRate := 0.0
+		if row.TotalRuns > 0 {
+			passRate = float64(row.SuccessfulRuns) / float64(row.TotalRuns) * 100
+		}
+		results[row.JobName] = dataprovider.JobRunStats{
+			JobName:        row.JobName,
+			TotalRuns:      row.TotalRuns,
+			SuccessfulRuns: row.SuccessfulRuns,
+			PassRate:       passRate,
+		}
+	}
+	return results, nil
+}
+
+func (p *PostgresProvider) QueryJobVariantValues(ctx context.Context, jobNames []string,
+	variantKeys []string) (map[string]map[string]string, error) {
+	results := map[string]map[string]string{}
+	if len(jobNames) == 0 {
+		return results, nil
+	}
+
+	variantsByJob, err := p.lookupVariants(ctx, jobNames)
+	if err != nil {
+		return nil, fmt.Errorf("error querying job variant values: %w", err)
+	}
+	wanted := sets.NewString(variantKeys...)
+	for jobName, variants := range variantsByJob {
+		for name, value := range variants {
+			if !wanted.Has(name) {
+				continue
+			}
+			if results[jobName] == nil {
+				results[jobName] = map[string]string{}
+			}
+			results[jobName][name] = value
+		}
+	}
+	return results, nil
+}
+
+func (p *PostgresProvider) LookupJobVariants(ctx context.Context, jobName string) (map[string]string, error) {
+	variantsByJob, err := p.lookupVariants(ctx, []string{jobName})
+	if err != nil {
+		return nil, fmt.Errorf("error querying job variants: %w", err)
+	}
+	if variants, ok := variantsByJob[jobName]; ok {
+		return variants, nil
+	}
+	return map[string]string{}, nil
+}
+
+// lookupVariants returns the variants for each of the given jobs, including the job's release as the Release variant.
+func (p *PostgresProvider) lookupVariants(ctx context.Context, jobNames []string) (map[string]map[string]string, error) {
+	var rows []struct {
+		Name     string
+		Release  string
+		Variants pq.StringArray
+	}
+	if res := p.dbc.DB.WithContext(ctx).Raw(`SELECT name, release, variants FROM prow_jobs WHERE name IN ?`, jobNames).Scan(&rows); res.Error != nil {
+		return nil, res.Error
+	}
+
+	results := map[string]map[string]string{}
+	for _, row := range rows {
+		variants := map[string]string{}
+		for _, variant := range row.Variants {
+			name, value, ok := strings.Cut(variant, ":")
+			if !ok {
+				continue
+			}
+			variants[name] = value
+		}
+		if row.Release != "" {
+			variants["Release"] = row.Release
+		}
+		results[row.Name] = variants
+	}
+	return results, nil
+}
+
+// compareVersions orders release-like strings (e.g. 4.9 < 4.10 < 5.0) numerically where possible.
+func compareVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aVal, aErr := strconv.ParseInt(aParts[i], 10, 32)
+		bVal, bErr := strconv.ParseInt(bParts[i], 10, 32)
+		if aErr != nil || bErr != nil {
+			if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+				return c
+			}
+			continue
+		}
+		if aVal != bVal {
+			if aVal < bVal {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(aParts) - len(bParts)
+}
@@ -0,0 +1,824 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api/componentreadiness/utils"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crstatus"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/util/param"
+)
+
+const (
+	// jobVariantsCTE flattens the "Name:Value" variants stored on prow_jobs into rows shaped like the
+	// bigquery job_variants table, adding the job's release as the Release variant.
+	jobVariantsCTE = `job_variants AS (
+			SELECT
+				prow_jobs.name AS job_name,
+				split_part(variant, ':', 1) AS variant_name,
+				substr(variant, strpos(variant, ':') + 1) AS variant_value
+			FROM prow_jobs, unnest(prow_jobs.variants) AS variant
+			WHERE split_part(variant, ':', 1) != 'Release'
+			UNION ALL
+			SELECT prow_jobs.name, 'Release', prow_jobs.release
+			FROM prow_jobs
+		)`
+
+	// normalJobNameCol simply uses the prow job name for regular (non-pull-request) component reports.
+	normalJobNameCol = `
+				prow_jobs.name AS variant_registry_job_name,
+`
+	// pullRequestDynamicJobNameCol is used for pull-request component reports and will use the releaseJobName
+	// annotation for /payload jobs if it exists, otherwise the normal prow job name. This is done as /payload
+	// jobs get custom job names which will not have variants recorded.
+	pullRequestDynamicJobNameCol = `
+				COALESCE((
+					SELECT split_part(prow_job_run_annotations.value, ',', 1)
+					FROM prow_job_run_annotations
+					WHERE prow_job_run_annotations.prow_job_run_id = prow_job_runs.id
+					AND prow_job_run_annotations.key = 'releaseJobName'
+					AND prow_job_run_annotations.deleted_at IS NULL
+					LIMIT 1
+				), prow_jobs.name) AS variant_registry_job_name,
+`
+)
+
+type baseQueryGenerator struct {
+	dbc         *db.DB
+	allVariants crtest.JobVariants
+	ReqOptions  reqopts.RequestOptions
+}
+
+func NewBaseQueryGenerator(
+	dbc *db.DB,
+	reqOptions reqopts.RequestOptions,
+	allVariants crtest.JobVariants) baseQueryGenerator {
+	return baseQueryGenerator{
+		dbc:         dbc,
+		allVariants: allVariants,
+		ReqOptions:  reqOptions,
+	}
+}
+
+func (b *baseQueryGenerator) QueryTestStatus(ctx context.Context) (crstatus.ReportTestStatus, []error) {
+	commonQuery, groupByQuery, params := BuildComponentReportQuery(b.ReqOptions, b.allVariants, b.ReqOptions.VariantOption.IncludeVariants, false)
+
+	baseString := commonQuery + ` AND jv_Release.variant_value = @BaseRelease`
+	params["From"] = b.ReqOptions.BaseRelease.Start
+	params["To"] = b.ReqOptions.BaseRelease.End
+	params["BaseRelease"] = b.ReqOptions.BaseRelease.Name
+
+	baseStatus, errs := fetchTestStatusResults(ctx, b.dbc, baseString+groupByQuery, params)
+	return crstatus.ReportTestStatus{BaseStatus: baseStatus}, errs
+}
+
+type sampleQueryGenerator struct {
+	dbc         *db.DB
+	allVariants crtest.JobVariants
+	ReqOptions  reqopts.RequestOptions
+	// IncludeVariants is separate from ReqOptions as the caller sometimes has to modify them.
+	IncludeVariants map[string][]string
+
+	Start time.Time
+	End   time.Time
+}
+
+func NewSampleQueryGenerator(
+	dbc *db.DB,
+	reqOptions reqopts.RequestOptions,
+	allVariants crtest.JobVariants,
+	includeVariants map[string][]string,
+	start, end time.Time) sampleQueryGenerator {
+	return sampleQueryGenerator{
+		dbc:             dbc,
+		ReqOptions:      reqOptions,
+		allVariants:     allVariants,
+		IncludeVariants: includeVariants,
+		Start:           start,
+		End:             end,
+	}
+}
+
+func (s *sampleQueryGenerator) QueryTestStatus(ctx context.Context) (crstatus.ReportTestStatus, []error) {
+	commonQuery, groupByQuery, params := BuildComponentReportQuery(s.ReqOptions, s.allVariants, s.IncludeVariants, true)
+
+	sampleString := commonQuery + sampleReleaseFilter(s.ReqOptions, "junit_data", params)
+	params["From"] = s.Start
+	params["To"] = s.End
+
+	sampleStatus, errs := fetchTestStatusResults(ctx, s.dbc, sampleString+groupByQuery, params)
+	return crstatus.ReportTestStatus{SampleStatus: sampleStatus}, errs
+}
+
+// sampleReleaseFilter limits the sample to the requested release, or to the job runs of a pull request or
+// set of payloads when those options are set. As a side effect it adds the needed parameters.
+func sampleReleaseFilter(reqOptions reqopts.RequestOptions, tableAlias string, params map[string]interface{}) string {
+	var filter string
+	// Only set sample release when PR and payload options are not set
+	if reqOptions.SampleRelease.PullRequestOptions == nil && reqOptions.SampleRelease.PayloadOptions == nil {
+		filter += ` AND jv_Release.variant_value = @SampleRelease`
+		params["SampleRelease"] = reqOptions.SampleRelease.Name
+	}
+	if prOpts := reqOptions.SampleRelease.PullRequestOptions; prOpts != nil {
+		filter += fmt.Sprintf(` AND %s.prow_job_run_id IN (
+						SELECT prow_job_run_prow_pull_requests.prow_job_run_id
+						FROM prow_job_run_prow_pull_requests
+						INNER JOIN prow_pull_requests ON prow_pull_requests.id = prow_job_run_prow_pull_requests.prow_pull_request_id
+						WHERE prow_pull_requests.org = @Org AND prow_pull_requests.repo = @Repo AND prow_pull_requests.number::text = @PRNumber)`,
+			tableAlias)
+		params["Org"] = prOpts.Org
+		params["Repo"] = prOpts.Repo
+		params["PRNumber"] = prOpts.PRNumber
+	}
+	if payloadOpts := reqOptions.SampleRelease.PayloadOptions; payloadOpts != nil {
+		filter += fmt.Sprintf(` AND %s.prow_job_run_id IN (
+						SELECT release_job_runs.prow_job_run_id
+						FROM release_job_runs
+						INNER JOIN release_tags ON release_tags.id::text = release_job_runs.release_tag_id
+						WHERE release_tags.release_tag IN @Tags)`,
+			tableAlias)
+		params["Tags"] = payloadOpts.Tags
+	}
+	return filter
+}
+
+// buildPriorityCaseStatement generates a SQL CASE statement that assigns priority based on test position in the list.
+// Lower index = higher priority. This is used to ensure when multiple key tests appear in the same job,
+// only the highest priority one is counted.
+func buildPriorityCaseStatement(keyTestNames []string, params map[string]interface{}) string {
+	var caseStatements []string
+	for i, testName := range keyTestNames {
+		paramName := fmt.Sprintf("TestName%d", i)
+		caseStatements = append(caseStatements, fmt.Sprintf("WHEN test_name = @%s THEN %d", paramName, i))
+		params[paramName] = testName
+	}
+	caseStatements = append(caseStatements, fmt.Sprintf("ELSE %d", len(keyTestNames)))
+	return strings.Join(caseStatements, "\n\t\t\t\t\t\t\t")
+}
+
+// buildKeyTestFilterClause returns the WHERE clause for filtering by key tests with priority.
+// It excludes other failing tests from jobs with failed key tests, but keeps passing/flaky tests.
+func buildKeyTestFilterClause(tableAlias string) string {
+	return fmt.Sprintf(`
+					AND (
+						-- Include tests from jobs that don't have any failed key tests
+						%s.prow_job_run_id NOT IN (SELECT prow_job_run_id FROM jobs_with_highest_priority_test)
+						-- Or include the highest priority key test from jobs that have failed key tests
+						OR EXISTS (
+							SELECT 1 FROM jobs_with_highest_priority_test j
+							WHERE j.prow_job_run_id = %s.prow_job_run_id
+							AND j.test_name = %s.test_name
+						)
+						-- Or include non-failing tests (successful or flaky) from jobs with failed key tests
+						OR (
+							%s.prow_job_run_id IN (SELECT prow_job_run_id FROM jobs_with_highest_priority_test)
+							AND (%s.adjusted_success_val > 0 OR %s.adjusted_flake_count > 0)
+						)
+					)`, tableAlias, tableAlias, tableAlias, tableAlias, tableAlias, tableAlias)
+}
+
+// buildCRQueryCTEs builds the WITH clause for the component readiness queries.
+//
+// Unlike the bigquery junit table, prow_job_run_tests is already deduplicated by the prow loader (a test that
+// both failed and passed in a run is recorded once as a flake), so junit_data only has to translate statuses.
+// Job runs carrying any of excludedJobRunLabels are left out entirely.
+func buildCRQueryCTEs(jobNameQueryPortion string, excludedJobRunLabels, keyTestNames []string, params map[string]interface{}) string {
+	excludedFilter := ""
+	if len(excludedJobRunLabels) > 0 {
+		excludedFilter = `
+			AND NOT (COALESCE(prow_job_runs.labels, '{}') && CAST(@ExcludedJobRunLabels AS text[]))`
+		params["ExcludedJobRunLabels"] = pq.StringArray(excludedJobRunLabels)
+	}
+
+	ctes := fmt.Sprintf(`WITH %s,
+		junit_data AS (
+			SELECT
+				prow_job_run_tests.prow_job_run_id,
+%s
+				prow_job_runs.url AS prowjob_url,
+				prow_job_runs.timestamp AS prowjob_start,
+				prow_job_runs.labels AS job_labels,
+				prow_job_runs.test_failures,
+				prow_job_run_tests.test_id,
+				prow_job_run_tests.suite_id,
+				tests.name AS test_name,
+				prow_job_run_tests.status,
+				CASE WHEN prow_job_run_tests.status = 1 THEN 1 ELSE 0 END AS adjusted_success_val,
+				CASE WHEN prow_job_run_tests.status = 13 THEN 1 ELSE 0 END AS adjusted_flake_count
+			FROM prow_job_run_tests
+			INNER JOIN prow_job_runs ON prow_job_runs.id = prow_job_run_tests.prow_job_run_id
+			INNER JOIN prow_jobs ON prow_jobs.id = prow_job_runs.prow_job_id
+			INNER JOIN tests ON tests.id = prow_job_run_tests.test_id
+			WHERE prow_job_run_tests.created_at >= @From
+			AND prow_job_runs.timestamp >= @From
+			AND prow_job_runs.timestamp < @To
+			AND prow_job_run_tests.deleted_at IS NULL
+			AND prow_job_runs.deleted_at IS NULL
+			AND prow_job_run_tests.status IN (1, 12, 13)%s
+		)`, jobVariantsCTE, jobNameQueryPortion, excludedFilter)
+
+	if len(keyTestNames) > 0 {
+		caseStatement := buildPriorityCaseStatement(keyTestNames, params)
+		ctes += fmt.Sprintf(`,
+		key_test_priorities AS (
+			SELECT
+				prow_job_run_id,
+				test_name,
+				-- Find the index/priority of each test (lower index = higher priority)
+				CASE
+				%s
+				END AS test_priority
+			FROM junit_data
+			WHERE test_name IN @KeyTestNames
+			AND adjusted_success_val = 0
+			AND adjusted_flake_count = 0
+		),
+		jobs_with_highest_priority_test AS (
+			SELECT
+				prow_job_run_id,
+				test_name
+			FROM key_test_priorities
+			WHERE test_priority = (
+				SELECT MIN(test_priority)
+				FROM key_test_priorities ep2
+				WHERE ep2.prow_job_run_id = key_test_priorities.prow_job_run_id
+			)
+		)`, caseStatement)
+		params["KeyTestNames"] = keyTestNames
+	}
+
+	return ctes
+}
+
+// buildVariantJoins joins job_variants once per known variant, exposing each as jv_[VariantName].
+func buildVariantJoins(allJobVariants crtest.JobVariants, jobNameCol string) string {
+	joinVariants := ""
+	for _, variant := range sortedKeys(allJobVariants.Variants) {
+		v := param.Cleanse(variant)
+		joinVariants += fmt.Sprintf("LEFT JOIN job_variants jv_%s ON %s = jv_%s.job_name AND jv_%s.variant_name = '%s'\n",
+			v, jobNameCol, v, v, v)
+	}
+	return joinVariants
+}
+
+// buildGroupByVariants returns the select and group by portions for the requested DBGroupBy variants.
+// Column aliases are quoted as variants are camelcase, and we want them back like: variant_Architecture
+func buildGroupByVariants(reqOptions reqopts.RequestOptions) (string, string) {
+	selectVariants := ""
+	groupByVariants := ""
+	for _, v := range reqOptions.VariantOption.DBGroupBy.List() {
+		v = param.Cleanse(v)
+		selectVariants += fmt.Sprintf("jv_%s.variant_value AS \"variant_%s\",\n", v, v)
+		groupByVariants += fmt.Sprintf("jv_%s.variant_value,\n", v)
+	}
+	return selectVariants, groupByVariants
+}
+
+// BuildComponentReportQuery returns the common query for the higher level summary component summary, mirroring
+// the bigquery implementation. If key test names are configured in the view's advanced options, when any of
+// these tests fail in a job, all other test failures in that job are excluded from regression analysis.
+func BuildComponentReportQuery(
+	reqOptions reqopts.RequestOptions,
+	allJobVariants crtest.JobVariants,
+	includeVariants map[string][]string,
+	isSample bool,
+) (string, string, map[string]interface{}) {
+	params := map[string]interface{}{}
+	selectVariants, groupByVariants := buildGroupByVariants(reqOptions)
+
+	jobNameQueryPortion := normalJobNameCol
+	if reqOptions.SampleRelease.PullRequestOptions != nil && isSample {
+		jobNameQueryPortion = pullRequestDynamicJobNameCol
+	}
+	withClause := buildCRQueryCTEs(jobNameQueryPortion, reqOptions.AdvancedOption.JobRunLabelsToExclude(),
+		reqOptions.AdvancedOption.KeyTestNames, params)
+
+	// WARNING: returning additional columns from this query will require explicit parsing in scanRowToTestStatus
+	queryString := fmt.Sprintf(`%s
+					SELECT
+						(array_agg(junit_data.test_name ORDER BY junit_data.prowjob_start DESC))[1] AS test_name,
+						(array_agg(cm.suite ORDER BY junit_data.prowjob_start DESC))[1] AS test_suite,
+						cm.unique_id AS test_id,
+						%s
+						COUNT(*) AS total_count,
+						SUM(junit_data.adjusted_success_val) AS success_count,
+						SUM(junit_data.adjusted_flake_count) AS flake_count,
+						MAX(CASE WHEN junit_data.status = 12 THEN junit_data.prowjob_start ELSE NULL END) AS last_failure,
+						MAX(cm.component) AS component,
+						MAX(cm.capabilities) AS capabilities
+					FROM junit_data
+					INNER JOIN test_ownerships cm ON junit_data.test_id = cm.test_id AND junit_data.suite_id = cm.suite_id AND cm.deleted_at IS NULL
+`, withClause, selectVariants)
+
+	queryString += buildVariantJoins(allJobVariants, "junit_data.variant_registry_job_name")
+
+	queryString += `WHERE cm.staff_approved_obsolete = false
+						AND (junit_data.variant_registry_job_name LIKE 'periodic-%' OR junit_data.variant_registry_job_name LIKE 'release-%' OR junit_data.variant_registry_job_name LIKE 'aggregator-%')`
+
+	if len(reqOptions.AdvancedOption.KeyTestNames) > 0 {
+		queryString += buildKeyTestFilterClause("junit_data")
+	}
+	if reqOptions.AdvancedOption.IgnoreDisruption {
+		queryString += ` AND NOT ('Disruption' = ANY(COALESCE(cm.capabilities, '{}')))`
+	}
+
+	variantGroups := includeVariants
+	// potentially cross-compare variants for the sample
+	if isSample && len(reqOptions.VariantOption.VariantCrossCompare) > 0 {
+		// Merge CompareVariants into includeVariants (don't replace entirely)
+		variantGroups = make(map[string][]string)
+		for k, v := range includeVariants {
+			variantGroups[k] = v
+		}
+		for k, v := range reqOptions.VariantOption.CompareVariants {
+			variantGroups[k] = v
+		}
+	}
+
+	for _, group := range sortedKeys(variantGroups) {
+		group = param.Cleanse(group)
+		paramName := fmt.Sprintf("variantGroup_%s", group)
+		queryString += fmt.Sprintf(" AND (jv_%s.variant_value IN @%s)", group, paramName)
+		params[paramName] = variantGroups[group]
+	}
+
+	// filter by test properties
+	if len(reqOptions.Capabilities) > 0 {
+		// include if there is any intersection between the capabilities filter and test capabilities
+		queryString += ` AND cm.capabilities && CAST(@Capabilities AS text[])`
+		params["Capabilities"] = pq.StringArray(reqOptions.Capabilities)
+	}
+	if isSample && len(reqOptions.Lifecycles) > 0 {
+		// prow_job_run_tests does not record a lifecycle, every test is treated as "blocking" just as
+		// the bigquery implementation does for a missing lifecycle.
+		queryString += ` AND 'blocking' IN @Lifecycles`
+		params["Lifecycles"] = reqOptions.Lifecycles
+	}
+
+	// In this context, a component report, multiple test ID options should not be specified. Thus
+	// here we assume just one for the filtering purposes here.
+	if len(reqOptions.TestIDOptions) == 1 {
+		for _, group := range sortedKeys(reqOptions.TestIDOptions[0].RequestedVariants) {
+			group = param.Cleanse(group)
+			paramName := fmt.Sprintf("ReqVariant_%s", group)
+			queryString += fmt.Sprintf(` AND jv_%s.variant_value = @%s`, group, paramName)
+			params[paramName] = reqOptions.TestIDOptions[0].RequestedVariants[group]
+		}
+		if reqOptions.TestIDOptions[0].Capability != "" {
+			queryString += ` AND @Capability = ANY(cm.capabilities)`
+			params["Capability"] = reqOptions.TestIDOptions[0].Capability
+		}
+		if reqOptions.TestIDOptions[0].TestID != "" {
+			queryString += ` AND cm.unique_id = @TestId`
+			params["TestId"] = reqOptions.TestIDOptions[0].TestID
+		}
+	}
+
+	groupString := fmt.Sprintf(`
+					GROUP BY
+						%s
+						cm.unique_id `, groupByVariants)
+
+	return queryString, groupString, params
+}
+
+// buildTestDetailsQuery returns the query for specific test + variant combos, including job run data.
+// This is for the bottom level most specific pages in component readiness.
+func buildTestDetailsQuery(
+	testIDOpts []reqopts.TestIdentification,
+	c reqopts.RequestOptions,
+	allJobVariants crtest.JobVariants,
+	includeVariants map[string][]string,
+	isSample bool) (string, string, map[string]interface{}) {
+
+	params := map[string]interface{}{}
+	selectVariants, groupByVariants := buildGroupByVariants(c)
+
+	jobNameQueryPortion := normalJobNameCol
+	if c.SampleRelease.PullRequestOptions != nil && isSample {
+		jobNameQueryPortion = pullRequestDynamicJobNameCol
+	}
+	// Runs with excluded labels are kept here and filtered out by the test details report, so it can show which
+	// runs were excluded and why.
+	withClause := buildCRQueryCTEs(jobNameQueryPortion, nil, c.AdvancedOption.KeyTestNames, params)
+
+	queryString := fmt.Sprintf(`%s
+					SELECT
+						cm.unique_id AS test_id,
+						MAX(junit.test_name) AS test_name,
+						MAX(cm.suite) AS test_suite,
+						%s
+						MAX(junit.variant_registry_job_name) AS prowjob_name,
+						MAX(cm.jira_component) AS jira_component,
+						MAX(cm.jira_component_id) AS jira_component_id,
+						COUNT(*) AS total_count,
+						MAX(junit.prowjob_url) AS prowjob_url,
+						junit.prow_job_run_id AS prowjob_run_id,
+						MAX(junit.prowjob_start) AS prowjob_start,
+						SUM(junit.adjusted_success_val) AS success_count,
+						SUM(junit.adjusted_flake_count) AS flake_count,
+						MAX(junit.job_labels) AS job_labels,
+						MAX(junit.test_failures) AS job_run_test_failure_count,
+						'blocking' AS lifecycle
+					FROM junit_data junit
+					INNER JOIN test_ownerships cm ON junit.test_id = cm.test_id AND junit.suite_id = cm.suite_id AND cm.deleted_at IS NULL
+`, withClause, selectVariants)
+
+	queryString += buildVariantJoins(allJobVariants, "junit.variant_registry_job_name")
+
+	queryString += `
+					WHERE
+						(junit.variant_registry_job_name LIKE 'periodic-%' OR junit.variant_registry_job_name LIKE 'release-%' OR junit.variant_registry_job_name LIKE 'aggregator-%')
+						AND
+`
+	queryString += "("
+	for i, testIDOption := range testIDOpts {
+		queryString = addTestFilters(testIDOption, i, queryString, c, includeVariants, params)
+	}
+	queryString += ")"
+
+	if len(c.AdvancedOption.KeyTestNames) > 0 {
+		queryString += buildKeyTestFilterClause("junit")
+	}
+
+	if isSample {
+		queryString += filterByCrossCompareVariants(c.VariantOption.VariantCrossCompare, c.VariantOption.CompareVariants, params)
+	} else {
+		queryString += filterByCrossCompareVariants(c.VariantOption.VariantCrossCompare, includeVariants, params)
+		queryString += ` AND jv_Release.variant_value = @BaseRelease`
+	}
+
+	groupString := fmt.Sprintf(`
+					GROUP BY
+						%s
+						junit.prow_job_run_id,
+						cm.unique_id
+					ORDER BY
+						MAX(junit.prowjob_start) `, groupByVariants)
+
+	return queryString, groupString, params
+}
+
+// addTestFilters adds the where clause limiting to one test and variants combo.
+func addTestFilters(
+	testIDOption reqopts.TestIdentification,
+	index int,
+	queryString string,
+	c reqopts.RequestOptions,
+	includeVariants map[string][]string,
+	params map[string]interface{}) string {
+
+	if index > 0 {
+		queryString += " OR "
+	}
+
+	testIDParam := fmt.Sprintf("TestID%d", index)
+	queryString += fmt.Sprintf("(cm.unique_id = @%s", testIDParam)
+	params[testIDParam] = testIDOption.TestID
+
+	for _, key := range sortedKeys(includeVariants) {
+		// only add in include variants that aren't part of the requested or cross-compared variants
+		if _, ok := testIDOption.RequestedVariants[key]; ok {
+			continue
+		}
+		if slices.Contains(c.VariantOption.VariantCrossCompare, key) {
+			continue
+		}
+
+		group := param.Cleanse(key)
+		paramName := fmt.Sprintf("IncludeVariants%d_%s", index, group)
+		queryString += fmt.Sprintf(` AND jv_%s.variant_value IN @%s`, group, paramName)
+		params[paramName] = includeVariants[key]
+	}
+
+	for _, group := range sortedKeys(testIDOption.RequestedVariants) {
+		group = param.Cleanse(group)
+		paramName := fmt.Sprintf("RequestedVariant%d_%s", index, group)
+		queryString += fmt.Sprintf(` AND jv_%s.variant_value = @%s`, group, paramName)
+		params[paramName] = testIDOption.RequestedVariants[group]
+	}
+	queryString += `)
+`
+	return queryString
+}
+
+// filterByCrossCompareVariants adds the where clause for any variants being cross-compared (which are not included in RequestedVariants).
+// As a side effect, it also adds any necessary parameters for the clause.
+func filterByCrossCompareVariants(crossCompare []string, variantGroups map[string][]string, params map[string]interface{}) (whereClause string) {
+	if len(variantGroups) == 0 {
+		return
+	}
+	sort.StringSlice(crossCompare).Sort()
+	for _, group := range crossCompare {
+		if variants := variantGroups[group]; len(variants) > 0 {
+			group = param.Cleanse(group)
+			paramName := "CrossVariants" + group
+			whereClause += fmt.Sprintf(` AND jv_%s.variant_value IN @%s`, group, paramName)
+			params[paramName] = variants
+		}
+	}
+	return
+}
+
+func fetchTestStatusResults(ctx context.Context, dbc *db.DB, query string, params map[string]interface{}) (map[string]crstatus.TestStatus, []error) {
+	errs := []error{}
+	status := map[string]crstatus.TestStatus{}
+
+	rows, err := dbc.DB.WithContext(ctx).Raw(query, params).Rows()
+	if err != nil {
+		log.WithError(err).Error("error querying test status from postgres")
+		return status, append(errs, err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return status, append(errs, err)
+	}
+	for rows.Next() {
+		testIDStr, testStatus, err := scanRowToTestStatus(rows, cols)
+		if err != nil {
+			scanErr := errors.Wrap(err, "error deserializing row from postgres")
+			log.Error(scanErr.Error())
+			errs = append(errs, scanErr)
+			continue
+		}
+		status[testIDStr] = testStatus
+	}
+	if err := rows.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return status, errs
+}
+
+// scanRowToTestStatus scans a single row into a testID string and matching status.
+// This is where we handle the dynamic variant_ columns, parsing these into a map on the test identification.
+func scanRowToTestStatus(rows *sql.Rows, cols []string) (string, crstatus.TestStatus, error) {
+	tid := crtest.KeyWithVariants{Variants: map[string]string{}}
+	cts := crstatus.TestStatus{}
+
+	var lastFailure sql.NullTime
+	var capabilities pq.StringArray
+	variants := make([]sql.NullString, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i, col := range cols {
+		switch {
+		case col == "test_id":
+			dest[i] = &tid.TestID
+		case col == "test_name":
+			dest[i] = &cts.TestName
+		case col == "test_suite":
+			dest[i] = &cts.TestSuite
+		case col == "total_count":
+			dest[i] = &cts.TotalCount
+		case col == "success_count":
+			dest[i] = &cts.SuccessCount
+		case col == "flake_count":
+			dest[i] = &cts.FlakeCount
+		case col == "last_failure":
+			dest[i] = &lastFailure
+		case col == "component":
+			dest[i] = &cts.Component
+		case col == "capabilities":
+			dest[i] = &capabilities
+		case strings.HasPrefix(col, "variant_"):
+			dest[i] = &variants[i]
+		default:
+			log.Warnf("ignoring column in query: %s", col)
+			dest[i] = new(interface{})
+		}
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", cts, err
+	}
+
+	if lastFailure.Valid {
+		cts.LastFailure = lastFailure.Time.UTC()
+	}
+	cts.Capabilities = capabilities
+	for i, col := range cols {
+		if strings.HasPrefix(col, "variant_") && variants[i].Valid {
+			tid.Variants[col[len("variant_"):]] = variants[i].String
+		}
+	}
+
+	return tid.KeyOrDie(), cts, nil
+}
+
+// sortedKeys is a helper that sorts the keys of a variant group map for consistent ordering.
+func sortedKeys[T any](it map[string]T) []string {
+	keys := make([]string, 0, len(it))
+	for k := range it {
+		keys = append(keys, k)
+	}
+	sort.StringSlice(keys).Sort()
+	return keys
+}
+
+// baseTestDetailsQueryGenerator generates the query we use for the basis on the test details page.
+type baseTestDetailsQueryGenerator struct {
+	logger         log.FieldLogger
+	dbc            *db.DB
+	ReqOptions     reqopts.RequestOptions
+	allJobVariants crtest.JobVariants
+	BaseRelease    string
+	BaseStart      time.Time
+	BaseEnd        time.Time
+	TestIDOpts     []reqopts.TestIdentification
+}
+
+func NewBaseTestDetailsQueryGenerator(logger log.FieldLogger, dbc *db.DB,
+	reqOptions reqopts.RequestOptions,
+	allJobVariants crtest.JobVariants,
+	baseRelease string, baseStart time.Time, baseEnd time.Time,
+	testIDOpts []reqopts.TestIdentification) *baseTestDetailsQueryGenerator {
+
+	return &baseTestDetailsQueryGenerator{
+		logger:         logger,
+		dbc:            dbc,
+		ReqOptions:     reqOptions,
+		allJobVariants: allJobVariants,
+		BaseRelease:    baseRelease,
+		BaseStart:      baseStart,
+		BaseEnd:        baseEnd,
+		TestIDOpts:     testIDOpts,
+	}
+}
+
+func (b *baseTestDetailsQueryGenerator) QueryTestStatus(ctx context.Context) (crstatus.TestJobRunStatuses, []error) {
+	commonQuery, groupByQuery, params := buildTestDetailsQuery(
+		b.TestIDOpts,
+		b.ReqOptions,
+		b.allJobVariants,
+		b.ReqOptions.VariantOption.IncludeVariants, false)
+	params["From"] = b.BaseStart
+	params["To"] = b.BaseEnd
+	params["BaseRelease"] = b.BaseRelease
+
+	baseStatus, errs := fetchJobRunTestStatusResults(ctx, b.logger, b.dbc, commonQuery+groupByQuery, params)
+	return crstatus.TestJobRunStatuses{BaseStatus: baseStatus}, errs
+}
+
+// sampleTestDetailsQueryGenerator generates the query we use for the sample on the test details page.
+type sampleTestDetailsQueryGenerator struct {
+	allJobVariants  crtest.JobVariants
+	dbc             *db.DB
+	ReqOptions      reqopts.RequestOptions
+	IncludeVariants map[string][]string
+
+	Start time.Time
+	End   time.Time
+}
+
+func NewSampleTestDetailsQueryGenerator(
+	dbc *db.DB,
+	reqOptions reqopts.RequestOptions,
+	allJobVariants crtest.JobVariants,
+	includeVariants map[string][]string,
+	start, end time.Time) *sampleTestDetailsQueryGenerator {
+	return &sampleTestDetailsQueryGenerator{
+		allJobVariants:  allJobVariants,
+		dbc:             dbc,
+		ReqOptions:      reqOptions,
+		IncludeVariants: includeVariants,
+		Start:           start,
+		End:             end,
+	}
+}
+
+func (s *sampleTestDetailsQueryGenerator) QueryTestStatus(ctx context.Context) (crstatus.TestJobRunStatuses, []error) {
+	commonQuery, groupByQuery, params := buildTestDetailsQuery(
+		s.ReqOptions.TestIDOptions,
+		s.ReqOptions,
+		s.allJobVariants,
+		s.IncludeVariants, true)
+
+	sampleString := commonQuery + sampleReleaseFilter(s.ReqOptions, "junit", params)
+	params["From"] = s.Start
+	params["To"] = s.End
+
+	sampleStatus, errs := fetchJobRunTestStatusResults(ctx, log.WithField("generator", "SampleQuery"), s.dbc, sampleString+groupByQuery, params)
+	return crstatus.TestJobRunStatuses{SampleStatus: sampleStatus}, errs
+}
+
+func fetchJobRunTestStatusResults(ctx context.Context, logger log.FieldLogger, dbc *db.DB, query string, params map[string]interface{}) (map[string][]crstatus.TestJobRunRows, []error) {
+	errs := []error{}
+	status := map[string][]crstatus.TestJobRunRows{}
+
+	rows, err := dbc.DB.WithContext(ctx).Raw(query, params).Rows()
+	if err != nil {
+		logger.WithError(err).Error("error querying job run test status from postgres")
+		return status, append(errs, err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return status, append(errs, err)
+	}
+	for rows.Next() {
+		jobRunTestStatusRow, err := scanRowToJobRunTestReportStatus(rows, cols)
+		if err != nil {
+			scanErr := errors.Wrap(err, "error deserializing row from postgres")
+			logger.Error(scanErr.Error())
+			errs = append(errs, scanErr)
+			continue
+		}
+		prowName := utils.NormalizeProwJobName(jobRunTestStatusRow.ProwJob)
+		status[prowName] = append(status[prowName], jobRunTestStatusRow)
+	}
+	if err := rows.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return status, errs
+}
+
+// scanRowToJobRunTestReportStatus scans a single row into a job run test status.
+// This is where we handle the dynamic variant_ columns, parsing these into a map on the test identification.
+func scanRowToJobRunTestReportStatus(rows *sql.Rows, cols []string) (crstatus.TestJobRunRows, error) {
+	cts := crstatus.TestJobRunRows{
+		TestKey: crtest.KeyWithVariants{Variants: map[string]string{}},
+	}
+
+	var runID int64
+	var prowJobURL, jiraComponent sql.NullString
+	var jiraComponentID sql.NullInt64
+	var jobLabels pq.StringArray
+	variants := make([]sql.NullString, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i, col := range cols {
+		switch {
+		case col == "total_count":
+			dest[i] = &cts.TotalCount
+		case col == "success_count":
+			dest[i] = &cts.SuccessCount
+		case col == "flake_count":
+			dest[i] = &cts.FlakeCount
+		case col == "prowjob_name":
+			dest[i] = &cts.ProwJob
+		case col == "prowjob_run_id":
+			dest[i] = &runID
+		case col == "prowjob_url":
+			dest[i] = &prowJobURL
+		case col == "prowjob_start":
+			dest[i] = &cts.StartTime
+		case col == "test_id":
+			dest[i] = &cts.TestKey.TestID
+		case col == "test_name":
+			dest[i] = &cts.TestName
+		case col == "jira_component":
+			dest[i] = &jiraComponent
+		case col == "jira_component_id":
+			dest[i] = &jiraComponentID
+		case col == "job_labels":
+			dest[i] = &jobLabels
+		case col == "job_run_test_failure_count":
+			dest[i] = &cts.TestFailures
+		case col == "lifecycle":
+			dest[i] = &cts.Lifecycle
+		case strings.HasPrefix(col, "variant_"):
+			dest[i] = &variants[i]
+		default:
+			dest[i] = new(interface{})
+		}
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return cts, err
+	}
+
+	cts.ProwJobRunID = strconv.FormatInt(runID, 10)
+	cts.ProwJobURL = prowJobURL.String
+	cts.StartTime = cts.StartTime.UTC()
+	cts.JiraComponent = jiraComponent.String
+	if jiraComponentID.Valid {
+		cts.JiraComponentID = new(big.Rat).SetInt64(jiraComponentID.Int64)
+	}
+	if len(jobLabels) > 0 {
+		cts.JobLabels = jobLabels
+	}
+	for i, col := range cols {
+		if strings.HasPrefix(col, "variant_") && variants[i].Valid {
+			cts.TestKey.Variants[col[len("variant_"):]] = variants[i].String
+		}
+	}
+
+	// Serialize the test key once only so we don't have to keep recalculating
+	cts.TestKeyStr = cts.TestKey.KeyOrDie()
+
+	return cts, nil
+}
@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
+	"github.com/openshift/sippy/pkg/util/sets"
+)
+
+var testJobVariants = crtest.JobVariants{
+	Variants: map[string][]string{
+		"Platform": {"aws", "gcp"},
+		"Network":  {"sdn", "ovn"},
+		"Release":  {"4.21", "4.22"},
+	},
+}
+
+func TestBuildComponentReportQuery(t *testing.T) {
+	baseReqOptions := reqopts.RequestOptions{
+		VariantOption: reqopts.Variants{
+			ColumnGroupBy: sets.NewString("Platform"),
+			DBGroupBy:     sets.NewString("Network", "Platform"),
+		},
+	}
+
+	tests := []struct {
+		name            string
+		reqOptions      func(reqopts.RequestOptions) reqopts.RequestOptions
+		includeVariants map[string][]string
+		isSample        bool
+		contains        []string
+		notContains     []string
+		params          map[string]interface{}
+	}{
+		{
+			name:       "defaults group by variants and exclude infra failures",
+			reqOptions: func(o reqopts.RequestOptions) reqopts.RequestOptions { return o },
+			contains: []string{
+				`jv_Network.variant_value AS "variant_Network"`,
+				`jv_Platform.variant_value AS "variant_Platform"`,
+				"LEFT JOIN job_variants jv_Release ON junit_data.variant_registry_job_name = jv_Release.job_name AND jv_Release.variant_name = 'Release'",
+				"NOT (COALESCE(prow_job_runs.labels, '{}') && CAST(@ExcludedJobRunLabels AS text[]))",
+				"prow_jobs.name AS variant_registry_job_name",
+				"LIKE 'periodic-%'",
+			},
+			notContains: []string{"jobs_with_highest_priority_test", "'Disruption'", "@Lifecycles"},
+			params:      map[string]interface{}{"ExcludedJobRunLabels": pq.StringArray{"InfraFailure"}},
+		},
+		{
+			name: "configured job run labels replace the default",
+			reqOptions: func(o reqopts.RequestOptions) reqopts.RequestOptions {
+				o.AdvancedOption.ExcludedJobRunLabels = []string{"InfraFailure", "ClusterDNSFlake"}
+				return o
+			},
+			contains: []string{"CAST(@ExcludedJobRunLabels AS text[])"},
+			params:   map[string]interface{}{"ExcludedJobRunLabels": pq.StringArray{"InfraFailure", "ClusterDNSFlake"}},
+		},
+		{
+			name: "empty job run labels exclude nothing",
+			reqOptions: func(o reqopts.RequestOptions) reqopts.RequestOptions {
+				o.AdvancedOption.ExcludedJobRunLabels = []string{}
+				return o
+			},
+			notContains: []string{"@ExcludedJobRunLabels"},
+		},
+		{
+			name: "key tests, disruption and capabilities",
+			reqOptions: func(o reqopts.RequestOptions) reqopts.RequestOptions {
+				o.AdvancedOption.KeyTestNames = []string{"install should succeed: overall", "[sig-cluster-lifecycle] Cluster completes upgrade"}
+				o.AdvancedOption.IgnoreDisruption = true
+				o.Capabilities = []string{"Upgrade"}
+				return o
+			},
+			contains: []string{
+				"key_test_priorities AS",
+				"WHEN test_name = @TestName1 THEN 1",
+				"junit_data.prow_job_run_id NOT IN (SELECT prow_job_run_id FROM jobs_with_highest_priority_test)",
+				"NOT ('Disruption' = ANY(COALESCE(cm.capabilities, '{}')))",
+				"cm.capabilities && CAST(@Capabilities AS text[])",
+			},
+			params: map[string]interface{}{
+				"TestName0":    "install should succeed: overall",
+				"KeyTestNames": []string{"install should succeed: overall", "[sig-cluster-lifecycle] Cluster completes upgrade"},
+				"Capabilities": pq.StringArray{"Upgrade"},
+			},
+		},
+		{
+			name: "sample cross-compare merges compare variants",
+			reqOptions: func(o reqopts.RequestOptions) reqopts.RequestOptions {
+				o.VariantOption.VariantCrossCompare = []string{"Network"}
+				o.VariantOption.CompareVariants = map[string][]string{"Network": {"sdn"}}
+				o.Lifecycles = []string{"blocking"}
+				return o
+			},
+			includeVariants: map[string][]string{"Network": {"ovn"}, "Platform": {"aws"}},
+			isSample:        true,
+			contains: []string{
+				"AND (jv_Network.variant_value IN @variantGroup_Network)",
+				"AND (jv_Platform.variant_value IN @variantGroup_Platform)",
+				"AND 'blocking' IN @Lifecycles",
+			},
+			params: map[string]interface{}{
+				"variantGroup_Network":  []string{"sdn"},
+				"variantGroup_Platform": []string{"aws"},
+			},
+		},
+		{
+			name: "pull request sample uses release job name annotation",
+			reqOptions: func(o reqopts.RequestOptions) reqopts.RequestOptions {
+				o.SampleRelease.PullRequestOptions = &reqopts.PullRequest{Org: "openshift", Repo: "origin", PRNumber: "123"}
+				return o
+			},
+			isSample: true,
+			contains: []string{"prow_job_run_annotations.key = 'releaseJobName'"},
+		},
+		{
+			name: "single test id option filters by test and requested variants",
+			reqOptions: func(o reqopts.RequestOptions) reqopts.RequestOptions {
+				o.TestIDOptions = []reqopts.TestIdentification{{
+					TestID:            "ourtests:123",
+					Capability:        "Install",
+					RequestedVariants: map[string]string{"Platform": "gcp"},
+				}}
+				return o
+			},
+			contains: []string{
+				"AND jv_Platform.variant_value = @ReqVariant_Platform",
+				"AND @Capability = ANY(cm.capabilities)",
+				"AND cm.unique_id = @TestId",
+			},
+			params: map[string]interface{}{"ReqVariant_Platform": "gcp", "TestId": "ourtests:123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, groupBy, params := BuildComponentReportQuery(tt.reqOptions(baseReqOptions), testJobVariants, tt.includeVariants, tt.isSample)
+			for _, s := range tt.contains {
+				assert.Contains(t, query, s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, query, s)
+			}
+			for k, v := range tt.params {
+				assert.Equal(t, v, params[k], "param %s", k)
+			}
+			assert.Contains(t, groupBy, "jv_Network.variant_value,")
+			assert.Contains(t, groupBy, "cm.unique_id")
+		})
+	}
+}
+
+func TestSampleReleaseFilter(t *testing.T) {
+	tests := []struct {
+		name          string
+		sampleRelease reqopts.Release
+		contains      []string
+		notContains   []string
+	}{
+		{
+			name:          "release",
+			sampleRelease: reqopts.Release{Name: "4.22"},
+			contains:      []string{"jv_Release.variant_value = @SampleRelease"},
+			notContains:   []string{"prow_pull_requests", "release_tags"},
+		},
+		{
+			name:          "pull request",
+			sampleRelease: reqopts.Release{Name: "4.22", PullRequestOptions: &reqopts.PullRequest{Org: "openshift", Repo: "origin", PRNumber: "1"}},
+			contains:      []string{"junit.prow_job_run_id IN (", "prow_pull_requests.number::text = @PRNumber"},
+			notContains:   []string{"@SampleRelease"},
+		},
+		{
+			name:          "payload",
+			sampleRelease: reqopts.Release{Name: "4.22", PayloadOptions: &reqopts.Payload{Tags: []string{"4.22.0-0.nightly-2025-01-01-000000"}}},
+			contains:      []string{"release_tags.release_tag IN @Tags"},
+			notContains:   []string{"@SampleRelease"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]interface{}{}
+			filter := sampleReleaseFilter(reqopts.RequestOptions{SampleRelease: tt.sampleRelease}, "junit", params)
+			for _, s := range tt.contains {
+				assert.Contains(t, filter, s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, filter, s)
+			}
+		})
+	}
+}
+
+func TestBuildTestDetailsQuery(t *testing.T) {
+	reqOptions := reqopts.RequestOptions{
+		VariantOption: reqopts.Variants{
+			DBGroupBy:           sets.NewString("Network", "Platform"),
+			VariantCrossCompare: []string{"Network"},
+			CompareVariants:     map[string][]string{"Network": {"sdn"}},
+		},
+	}
+	testIDOpts := []reqopts.TestIdentification{
+		{TestID: "ourtests:1", RequestedVariants: map[string]string{"Platform": "aws"}},
+		{TestID: "ourtests:2", RequestedVariants: map[string]string{"Platform": "gcp"}},
+	}
+	includeVariants := map[string][]string{"Network": {"ovn"}, "Platform": {"aws", "gcp"}, "Release": {"4.22"}}
+
+	query, groupBy, params := buildTestDetailsQuery(testIDOpts, reqOptions, testJobVariants, includeVariants, false)
+	assert.Contains(t, query, "(cm.unique_id = @TestID0 AND jv_Release.variant_value IN @IncludeVariants0_Release AND jv_Platform.variant_value = @RequestedVariant0_Platform)")
+	assert.Contains(t, query, " OR (cm.unique_id = @TestID1")
+	assert.Contains(t, query, "AND jv_Network.variant_value IN @CrossVariantsNetwork")
+	assert.Contains(t, query, "AND jv_Release.variant_value = @BaseRelease")
+	assert.Equal(t, "ourtests:2", params["TestID1"])
+	assert.Equal(t, []string{"ovn"}, params["CrossVariantsNetwork"], "base cross-compares against the included variants")
+	assert.Contains(t, groupBy, "junit.prow_job_run_id")
+	assert.NotContains(t, query, "@ExcludedJobRunLabels", "excluded runs are filtered by the report so they can be listed")
+
+	query, _, params = buildTestDetailsQuery(testIDOpts, reqOptions, testJobVariants, includeVariants, true)
+	assert.NotContains(t, query, "@BaseRelease")
+	assert.Equal(t, []string{"sdn"}, params["CrossVariantsNetwork"], "sample cross-compares against the compare variants")
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Negative(t, compareVersions("4.9", "4.10"))
+	assert.Positive(t, compareVersions("5.0", "4.22"))
+	assert.Zero(t, compareVersions("4.22", "4.22"))
+	assert.Negative(t, compareVersions("4.22", "4.22.1"))
+}
@@ -0,0 +1,286 @@
+package componentreadiness
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	crtype "github.com/openshift/sippy/pkg/apis/api/componentreport"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/junit"
+)
+
+// ExportFormat is a serialization of a component report for consumers other than the UI.
+type ExportFormat string
+
+const (
+	ExportJSON     ExportFormat = "json"
+	ExportCSV      ExportFormat = "csv"
+	ExportMarkdown ExportFormat = "markdown"
+	ExportJUnit    ExportFormat = "junit"
+)
+
+var ExportFormats = []ExportFormat{ExportJSON, ExportCSV, ExportMarkdown, ExportJUnit}
+
+// ParseExportFormat validates a requested format, defaulting to JSON.
+func ParseExportFormat(format string) (ExportFormat, error) {
+	if format == "" {
+		return ExportJSON, nil
+	}
+	for _, f := range ExportFormats {
+		if string(f) == format {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("invalid format %q, must be one of %v", format, ExportFormats)
+}
+
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportCSV:
+		return "text/csv; charset=utf-8"
+	case ExportMarkdown:
+		return "text/markdown; charset=utf-8"
+	case ExportJUnit:
+		return "application/xml; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// FileExtension is used when writing reports to files.
+func (f ExportFormat) FileExtension() string {
+	switch f {
+	case ExportMarkdown:
+		return "md"
+	case ExportJUnit:
+		return "xml"
+	}
+	return string(f)
+}
+
+// WriteReport serializes a report in the given format. The title heads the markdown summary and names the junit
+// suites, typically the view name.
+func WriteReport(w io.Writer, format ExportFormat, report crtype.ComponentReport, title string) error {
+	switch format {
+	case ExportCSV:
+		return WriteReportCSV(w, report)
+	case ExportMarkdown:
+		return WriteReportMarkdown(w, report, title)
+	case ExportJUnit:
+		return WriteReportJUnit(w, report, title)
+	}
+	return json.NewEncoder(w).Encode(report)
+}
+
+var reportCSVHeader = []string{
+	"component", "capability", "test_id", "test_name", "test_suite", "variants", "status", "status_name", "comparison",
+	"sample_release", "sample_success", "sample_failure", "sample_flake", "sample_pass_rate",
+	"base_release", "base_success", "base_failure", "base_flake", "base_pass_rate",
+	"fisher_exact", "last_failure", "regression_id", "regression_opened", "test_details",
+}
+
+// WriteReportCSV writes one row per regressed test and variant combination.
+func WriteReportCSV(w io.Writer, report crtype.ComponentReport) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(reportCSVHeader); err != nil {
+		return err
+	}
+	for _, t := range regressedTests(report) {
+		record := []string{
+			t.Component, t.Capability, t.TestID, t.TestName, t.TestSuite, variantsString(t.Variants),
+			strconv.Itoa(int(t.ReportStatus)), statusName(t.ReportStatus), string(t.Comparison),
+			t.SampleStats.Release, strconv.Itoa(t.SampleStats.SuccessCount), strconv.Itoa(t.SampleStats.FailureCount),
+			strconv.Itoa(t.SampleStats.FlakeCount), formatRate(t.SampleStats.SuccessRate),
+			"", "", "", "", "",
+			"", "", "", "", t.Links["test_details"],
+		}
+		if t.BaseStats != nil {
+			record[14] = t.BaseStats.Release
+			record[15] = strconv.Itoa(t.BaseStats.SuccessCount)
+			record[16] = strconv.Itoa(t.BaseStats.FailureCount)
+			record[17] = strconv.Itoa(t.BaseStats.FlakeCount)
+			record[18] = formatRate(t.BaseStats.SuccessRate)
+		}
+		if t.FisherExact != nil {
+			record[19] = strconv.FormatFloat(*t.FisherExact, 'g', 6, 64)
+		}
+		if t.LastFailure != nil {
+			record[20] = t.LastFailure.UTC().Format(time.RFC3339)
+		}
+		if t.Regression != nil {
+			record[21] = strconv.FormatUint(uint64(t.Regression.ID), 10)
+			record[22] = t.Regression.Opened.UTC().Format(time.RFC3339)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteReportMarkdown writes a per component summary followed by the regressed tests, for release status emails.
+func WriteReportMarkdown(w io.Writer, report crtype.ComponentReport, title string) error {
+	type componentSummary struct {
+		cells, regressed, triaged int
+	}
+	summaries := map[string]*componentSummary{}
+	var components []string
+	for _, row := range report.Rows {
+		s, ok := summaries[row.Component]
+		if !ok {
+			s = &componentSummary{}
+			summaries[row.Component] = s
+			components = append(components, row.Component)
+		}
+		for _, col := range row.Columns {
+			if regressed(col.Status) {
+				s.cells++
+			}
+			for _, t := range col.RegressedTests {
+				if !regressed(t.ReportStatus) {
+					continue
+				}
+				s.regressed++
+				if t.ReportStatus == crtest.ExtremeTriagedRegression || t.ReportStatus == crtest.SignificantTriagedRegression {
+					s.triaged++
+				}
+			}
+		}
+	}
+	sort.Strings(components)
+
+	var sb strings.Builder
+	if title != "" {
+		fmt.Fprintf(&sb, "# %s\n\n", title)
+	}
+	if report.GeneratedAt != nil {
+		fmt.Fprintf(&sb, "Generated at %s.\n\n", report.GeneratedAt.UTC().Format(time.RFC3339))
+	}
+	sb.WriteString("## Components\n\n| Component | Regressed cells | Regressed tests | Triaged |\n| --- | --- | --- | --- |\n")
+	for _, c := range components {
+		s := summaries[c]
+		if s.cells == 0 && s.regressed == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "| %s | %d | %d | %d |\n", markdownEscape(c), s.cells, s.regressed, s.triaged)
+	}
+
+	sb.WriteString("\n## Regressed tests\n\n| Component | Test | Variants | Status | Sample pass rate | Base pass rate |\n| --- | --- | --- | --- | --- | --- |\n")
+	for _, t := range regressedTests(report) {
+		name := markdownEscape(t.TestName)
+		if link := t.Links["test_details"]; link != "" {
+			name = fmt.Sprintf("[%s](%s)", name, link)
+		}
+		base := "-"
+		if t.BaseStats != nil {
+			base = formatPercent(t.BaseStats.SuccessRate)
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s | %s |\n", markdownEscape(t.Component), name,
+			markdownEscape(variantsString(t.Variants)), statusName(t.ReportStatus), formatPercent(t.SampleStats.SuccessRate), base)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteReportJUnit writes a suite per component with a test case per regressed test and variant combination.
+// Untriaged regressions fail, while triaged and fixed regressions are skipped, so CI tooling can gate on them.
+func WriteReportJUnit(w io.Writer, report crtype.ComponentReport, title string) error {
+	suites := &junit.TestSuites{}
+	byComponent := map[string]*junit.TestSuite{}
+	for _, t := range regressedTests(report) {
+		suite, ok := byComponent[t.Component]
+		if !ok {
+			suite = &junit.TestSuite{Name: strings.TrimSpace(title + " " + t.Component)}
+			byComponent[t.Component] = suite
+			suites.Suites = append(suites.Suites, suite)
+		}
+		tc := &junit.TestCase{
+			Name:      fmt.Sprintf("%s [%s]", t.TestName, variantsString(t.Variants)),
+			Classname: t.Capability,
+		}
+		message := fmt.Sprintf("%s: sample pass rate %s", statusName(t.ReportStatus), formatPercent(t.SampleStats.SuccessRate))
+		if t.BaseStats != nil {
+			message += fmt.Sprintf(", base pass rate %s in %s", formatPercent(t.BaseStats.SuccessRate), t.BaseStats.Release)
+		}
+		switch t.ReportStatus {
+		case crtest.ExtremeTriagedRegression, crtest.SignificantTriagedRegression, crtest.FixedRegression:
+			tc.SkipMessage = &junit.SkipMessage{Message: message}
+			suite.NumSkipped++
+		default:
+			tc.FailureOutput = &junit.FailureOutput{Message: message, Output: strings.Join(t.Explanations, "\n")}
+			suite.NumFailed++
+		}
+		suite.NumTests++
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(suites)
+}
+
+// regressedTests flattens the regressed tests of a report in a stable order.
+func regressedTests(report crtype.ComponentReport) []crtype.ReportTestSummary {
+	var tests []crtype.ReportTestSummary
+	for _, row := range report.Rows {
+		for _, col := range row.Columns {
+			tests = append(tests, col.RegressedTests...)
+		}
+	}
+	sort.SliceStable(tests, func(i, j int) bool {
+		a, b := tests[i], tests[j]
+		if a.Component != b.Component {
+			return a.Component < b.Component
+		}
+		if a.TestName != b.TestName {
+			return a.TestName < b.TestName
+		}
+		return variantsString(a.Variants) < variantsString(b.Variants)
+	})
+	return tests
+}
+
+func variantsString(variants map[string]string) string {
+	parts := make([]string, 0, len(variants))
+	for k, v := range variants {
+		parts = append(parts, k+":"+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+func statusName(status crtest.Status) string {
+	switch status {
+	case crtest.NotSignificant:
+		return "NotSignificant"
+	case crtest.MissingBasis:
+		return "MissingBasis"
+	case crtest.MissingBasisAndSample:
+		return "MissingBasisAndSample"
+	case crtest.SignificantImprovement:
+		return "SignificantImprovement"
+	}
+	return crtest.StringForStatus(status)
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', 4, 64)
+}
+
+func formatPercent(rate float64) string {
+	return strconv.FormatFloat(rate*100, 'f', 2, 64) + "%"
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
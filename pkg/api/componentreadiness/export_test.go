@@ -0,0 +1,112 @@
+package componentreadiness
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	crtype "github.com/openshift/sippy/pkg/apis/api/componentreport"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/testdetails"
+	"github.com/openshift/sippy/pkg/apis/junit"
+)
+
+func exportTestReport() crtype.ComponentReport {
+	aws := map[string]string{"Platform": "aws", "Network": "ovn"}
+	test := func(name string, status crtest.Status) crtype.ReportTestSummary {
+		return crtype.ReportTestSummary{
+			Identification: crtest.Identification{
+				RowIdentification:    crtest.RowIdentification{Component: "etcd", Capability: "Operator", TestID: name + "-id", TestName: name},
+				ColumnIdentification: crtest.ColumnIdentification{Variants: aws},
+			},
+			TestComparison: testdetails.TestComparison{
+				ReportStatus: status,
+				SampleStats: testdetails.ReleaseStats{Release: "4.20",
+					Stats: crtest.Stats{SuccessCount: 8, FailureCount: 2, SuccessRate: 0.8}},
+				BaseStats: &testdetails.ReleaseStats{Release: "4.19",
+					Stats: crtest.Stats{SuccessCount: 99, FailureCount: 1, SuccessRate: 0.99}},
+				Links: map[string]string{"test_details": "https://sippy/test_details?id=" + name},
+			},
+		}
+	}
+	return crtype.ComponentReport{Rows: []crtype.ReportRow{
+		{
+			RowIdentification: crtest.RowIdentification{Component: "etcd"},
+			Columns: []crtype.ReportColumn{{
+				ColumnIdentification: crtest.ColumnIdentification{Variants: aws},
+				Status:               crtest.ExtremeRegression,
+				RegressedTests: []crtype.ReportTestSummary{
+					test("b | piped", crtest.SignificantTriagedRegression),
+					test("a", crtest.ExtremeRegression),
+				},
+			}},
+		},
+		{
+			RowIdentification: crtest.RowIdentification{Component: "network"},
+			Columns:           []crtype.ReportColumn{{Status: crtest.NotSignificant}},
+		},
+	}}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	f, err := ParseExportFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, ExportJSON, f)
+	f, err = ParseExportFormat("markdown")
+	require.NoError(t, err)
+	assert.Equal(t, "md", f.FileExtension())
+	_, err = ParseExportFormat("xlsx")
+	assert.Error(t, err)
+}
+
+func TestWriteReportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, ExportCSV, exportTestReport(), ""))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, reportCSVHeader, records[0])
+	row := map[string]string{}
+	for i, h := range records[0] {
+		row[h] = records[1][i]
+	}
+	assert.Equal(t, "a", row["test_name"])
+	assert.Equal(t, "Network:ovn Platform:aws", row["variants"])
+	assert.Equal(t, "Extreme", row["status_name"])
+	assert.Equal(t, "0.8000", row["sample_pass_rate"])
+	assert.Equal(t, "4.19", row["base_release"])
+	assert.Equal(t, "https://sippy/test_details?id=a", row["test_details"])
+}
+
+func TestWriteReportMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, ExportMarkdown, exportTestReport(), "4.20-main"))
+
+	md := buf.String()
+	assert.Contains(t, md, "# 4.20-main\n")
+	assert.Contains(t, md, "| etcd | 1 | 2 | 1 |\n")
+	assert.NotContains(t, md, "| network |", "components without regressions are left out of the summary")
+	assert.Contains(t, md, `[b \| piped](https://sippy/test_details?id=b | piped)`)
+	assert.Contains(t, md, "| 80.00% | 99.00% |")
+}
+
+func TestWriteReportJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, ExportJUnit, exportTestReport(), "4.20-main"))
+
+	suites := junit.TestSuites{}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	require.Len(t, suites.Suites, 1)
+	suite := suites.Suites[0]
+	assert.Equal(t, "4.20-main etcd", suite.Name)
+	assert.Equal(t, uint(2), suite.NumTests)
+	assert.Equal(t, uint(1), suite.NumFailed)
+	assert.Equal(t, uint(1), suite.NumSkipped)
+	require.NotNil(t, suite.TestCases[0].FailureOutput, "untriaged regressions fail")
+	require.NotNil(t, suite.TestCases[1].SkipMessage, "triaged regressions are skipped")
+}
@@ -12,17 +12,30 @@ import (
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/testdetails"
 	v1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
+	"github.com/openshift/sippy/pkg/db"
 	"github.com/openshift/sippy/pkg/regressionallowances"
 	log "github.com/sirupsen/logrus"
 )
 
 var _ middleware.Middleware = &RegressionAllowances{}
 
-func NewRegressionAllowancesMiddleware(reqOptions reqopts.RequestOptions, releaseConfigs []v1.Release) *RegressionAllowances {
+// adjustmentName marks test comparisons whose base stats or analysis parameters came from an intentional
+// regression allowance.
+const adjustmentName = "regression-allowance"
+
+// NewRegressionAllowancesMiddleware reads allowances from the database when one is provided, falling back to the
+// allowances compiled into sippy for releases with none stored; without a database only the compiled-in allowances
+// apply. Changes apply to reports generated after them; cached reports keep the allowances they were generated with
+// until their cache entries expire or are invalidated.
+func NewRegressionAllowancesMiddleware(dbc *db.DB, reqOptions reqopts.RequestOptions, releaseConfigs []v1.Release) *RegressionAllowances {
+	getter := regressionallowances.IntentionalRegressionFor
+	if dbc != nil {
+		getter = regressionallowances.NewDBLookup(dbc).IntentionalRegressionFor
+	}
 	return &RegressionAllowances{
 		log:                  log.WithField("middleware", "RegressionAllowances"),
 		reqOptions:           reqOptions,
-		regressionGetterFunc: regressionallowances.IntentionalRegressionFor,
+		regressionGetterFunc: getter,
 		releaseConfigs:       releaseConfigs,
 	}
 }
@@ -104,6 +117,7 @@ func (r *RegressionAllowances) matchBaseRegression(testID crtest.Identification,
 		} else if overrideTestStats.Total() > 0 { // only override if there is history to override with
 			testStats.BaseStats.Release = baseRegressionPreviousRelease
 			testStats.BaseStats.Stats = overrideTestStats
+			testStats.AddAdjustment(adjustmentName)
 			r.log.Infof("BaseRegression - PreviousPassPercentage overrides baseStats.  Release: %s, Successes: %d, Flakes: %d",
 				baseRegressionPreviousRelease, baseStats.SuccessCount, baseStats.FlakeCount)
 		}
@@ -128,6 +142,7 @@ func (r *RegressionAllowances) adjustAnalysisParameters(testStats *testdetails.T
 			testStats.Explanations = append(testStats.Explanations,
 				fmt.Sprintf("Intentional regression applied to allow a %.1f%% pass rate: %q %s",
 					requiredSuccessRate, ir.ReasonToAllowInsteadOfFix, ir.JiraBug))
+			testStats.AddAdjustment(adjustmentName)
 		}
 	} else {
 		// for regressions on existing tests, adjust what Fisher's Exact will consider a pass
@@ -143,6 +158,7 @@ func (r *RegressionAllowances) adjustAnalysisParameters(testStats *testdetails.T
 				testStats.Explanations = append(testStats.Explanations,
 					fmt.Sprintf("Intentional regression applied to allow a %.1f%% pass rate: %q %s",
 						regressedPassPercentage*100, ir.ReasonToAllowInsteadOfFix, ir.JiraBug))
+				testStats.AddAdjustment(adjustmentName)
 			}
 		}
 	}
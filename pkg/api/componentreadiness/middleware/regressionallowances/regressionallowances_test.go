@@ -96,6 +96,7 @@ func Test_PreAnalysis(t *testing.T) {
 		regressionGetter func(releaseString string, variant crtest.ColumnIdentification, testID string) *regressionallowances.IntentionalRegression
 		testStatus       *testdetails.TestComparison
 		expectedStatus   *testdetails.TestComparison
+		adjusted         bool
 	}{
 		{
 			name:             "swap base stats using regression allowance",
@@ -104,6 +105,7 @@ func Test_PreAnalysis(t *testing.T) {
 			regressionGetter: regressionGetter,
 			testStatus:       buildTestStatus(100, 75, 0, "4.18"),
 			expectedStatus:   buildTestStatus(100, 100, 0, "4.17"),
+			adjusted:         true,
 		},
 		{
 			name:             "do not swap base stats for regression allowance if fallback is active",
@@ -136,6 +138,7 @@ func Test_PreAnalysis(t *testing.T) {
 			regressionGetter: regressionGetter,
 			testStatus:       buildTestStatus2(100, 99, 1, "4.18", "4.19", 20, 0, 0),
 			expectedStatus:   buildTestStatus2(100, 99, 1, "4.18", "4.19", 20, 10, 0),
+			adjusted:         true,
 		},
 		{
 			name:             "sample stats with regression allowance and no basis should adjust pass rate",
@@ -145,15 +148,19 @@ func Test_PreAnalysis(t *testing.T) {
 			// note that the base stats are used only to detect that there was no basis, and sample stats are not used at all in the adjustment
 			testStatus:     buildTestStatus2(0, 0, 0, "4.18", "4.19", 0, 0, 0),
 			expectedStatus: buildTestStatus2(0, 0, 0, "4.18", "4.19", 0, 0, -5),
+			adjusted:       true,
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			rfb := NewRegressionAllowancesMiddleware(test.reqOpts, releaseConfigs)
+			rfb := NewRegressionAllowancesMiddleware(nil, test.reqOpts, releaseConfigs)
 			rfb.regressionGetterFunc = test.regressionGetter
 			err := rfb.PreAnalysis(test.testKey, test.testStatus)
 			assert.NoError(t, err)
 			test.testStatus.Explanations = nil // ignore explanations generated in the test
+			if test.adjusted {
+				test.expectedStatus.Adjustments = []string{adjustmentName}
+			}
 			maskFLOPError(&test.expectedStatus.RequiredPassRateAdjustment, &test.testStatus.RequiredPassRateAdjustment)
 			maskFLOPError(&test.expectedStatus.PityAdjustment, &test.testStatus.PityAdjustment)
 			assert.Equal(t, *test.expectedStatus, *test.testStatus)
@@ -30,6 +30,10 @@ const (
 	// an open regression. The goal is to adjust this to a smaller value so that we will this test more strict than the ones
 	// without open regressions.
 	openRegressionMinimumFailureAdjustment = -1
+
+	// adjustmentName marks test comparisons whose thresholds were relaxed for an open regression, or whose status
+	// was changed by the regression's triages.
+	adjustmentName = "regression-tracker"
 )
 
 var _ middleware.Middleware = &RegressionTracker{}
@@ -100,9 +104,11 @@ func (r *RegressionTracker) PreAnalysis(testKey crtest.Identification, testStats
 			//
 			// ie. if the request was for 95% confidence, but we see that a test has an open regression (meaning at some point recently
 			// we were over 95% certain of a regression), we're going to only require 90% certainty to mark that test red.
-			testStats.RequiredConfidence = r.reqOptions.AdvancedOption.Confidence - openRegressionConfidenceAdjustment
-			testStats.PityAdjustment = openRegressionPityAdjustment
-			testStats.MinimumFailureAdjustment = openRegressionMinimumFailureAdjustment
+			// Relative to what the comparison was initialized with, which may be a view's override for this test.
+			testStats.RequiredConfidence -= openRegressionConfidenceAdjustment
+			testStats.PityAdjustment += openRegressionPityAdjustment
+			testStats.MinimumFailureAdjustment += openRegressionMinimumFailureAdjustment
+			testStats.AddAdjustment(adjustmentName)
 		}
 	}
 	return nil
@@ -152,20 +158,24 @@ func (r *RegressionTracker) PostAnalysis(testKey crtest.Identification, testStat
 				testStats.Explanations = append(testStats.Explanations, fmt.Sprintf(
 					"Regression is triaged, and believed fixed as of %s, but failures have been observed as recently as %s.",
 					lastResolution.Format(time.RFC3339), testStats.LastFailure.Format(time.RFC3339)))
+				testStats.AddAdjustment(adjustmentName)
 			case allTriagesResolved:
 				// claimed fixed, no failures since resolution date
 				testStats.ReportStatus = crtest.FixedRegression
 				testStats.Explanations = append(testStats.Explanations, fmt.Sprintf(
 					"Regression is triaged and believed fixed as of %s.",
 					lastResolution.Format(time.RFC3339)))
+				testStats.AddAdjustment(adjustmentName)
 			case testStats.ReportStatus == crtest.SignificantRegression:
 				testStats.ReportStatus = crtest.SignificantTriagedRegression
 				testStats.Explanations = append(testStats.Explanations,
 					"Regression has been triaged to one or more bugs.")
+				testStats.AddAdjustment(adjustmentName)
 			case testStats.ReportStatus == crtest.ExtremeRegression:
 				testStats.ReportStatus = crtest.ExtremeTriagedRegression
 				testStats.Explanations = append(testStats.Explanations,
 					"Extreme regression has been triaged to one or more bugs.")
+				testStats.AddAdjustment(adjustmentName)
 			}
 		}
 	}
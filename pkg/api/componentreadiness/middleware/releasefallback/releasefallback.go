@@ -29,6 +29,9 @@ const (
 
 var _ middleware.Middleware = &ReleaseFallback{}
 
+// adjustmentName marks test comparisons whose base stats were swapped for a prior release's.
+const adjustmentName = "release-fallback"
+
 func NewReleaseFallbackMiddleware(
 	provider dataprovider.DataProvider,
 	reqOptions reqopts.RequestOptions,
@@ -160,6 +163,7 @@ func (r *ReleaseFallback) PreAnalysis(testKey crtest.Identification, testStats *
 	// Add an explanation for the user why we fell back for the final release data:
 	if swappedExplanation != "" {
 		testStats.Explanations = append(testStats.Explanations, swappedExplanation)
+		testStats.AddAdjustment(adjustmentName)
 	}
 
 	return nil
@@ -340,6 +344,8 @@ type fallbackTestQueryReleasesGeneratorCacheKey struct {
 	CRTimeRoundingFactor time.Duration
 	// KeyTestNames affects the BuildComponentReportQuery results via filtering logic
 	KeyTestNames []string
+	// ExcludedJobRunLabels filters job runs out of the same query, only set when they differ from the defaults
+	ExcludedJobRunLabels *[]string `json:",omitempty"`
 }
 
 // getCacheKey creates a cache key using the generator properties that we want included for uniqueness in what
@@ -353,6 +359,7 @@ func (f *fallbackTestQueryReleasesGenerator) getCacheKey() fallbackTestQueryRele
 		VariantDBGroupBy:     f.ReqOptions.VariantOption.DBGroupBy,
 		CRTimeRoundingFactor: f.ReqOptions.CacheOption.CRTimeRoundingFactor,
 		KeyTestNames:         f.ReqOptions.AdvancedOption.KeyTestNames,
+		ExcludedJobRunLabels: f.ReqOptions.AdvancedOption.ExcludedJobRunLabelsCacheKey(),
 	}
 }
 
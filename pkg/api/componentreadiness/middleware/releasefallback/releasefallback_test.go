@@ -107,7 +107,7 @@ func Test_PreAnalysis(t *testing.T) {
 				},
 			},
 			testStats:      buildTestStats(100, 93, release419, nil),
-			expectedStatus: buildTestStats(100, 95, release418, []string{"Overrode base stats (0.9300) using release 4.18 (0.9500)"}),
+			expectedStatus: adjusted(buildTestStats(100, 95, release418, []string{"Overrode base stats (0.9300) using release 4.18 (0.9500)"})),
 		},
 		{
 			name:    "fallback twice to prior release",
@@ -120,7 +120,7 @@ func Test_PreAnalysis(t *testing.T) {
 				},
 			},
 			testStats:      buildTestStats(100, 93, release419, nil),
-			expectedStatus: buildTestStats(100, 98, release417, []string{"Overrode base stats (0.9500) using release 4.17 (0.9800)"}),
+			expectedStatus: adjusted(buildTestStats(100, 98, release417, []string{"Overrode base stats (0.9500) using release 4.17 (0.9800)"})),
 		},
 		{
 			name:    "fallback once to two releases ago",
@@ -133,7 +133,7 @@ func Test_PreAnalysis(t *testing.T) {
 				},
 			},
 			testStats:      buildTestStats(100, 97, release419, nil),
-			expectedStatus: buildTestStats(100, 98, release417, []string{"Overrode base stats (0.9700) using release 4.17 (0.9800)"}),
+			expectedStatus: adjusted(buildTestStats(100, 98, release417, []string{"Overrode base stats (0.9700) using release 4.17 (0.9800)"})),
 		},
 		{
 			name:    "don't fallback to prior release",
@@ -172,6 +172,12 @@ func Test_PreAnalysis(t *testing.T) {
 		})
 	}
 }
+
+func adjusted(tc *testdetails.TestComparison) *testdetails.TestComparison {
+	tc.AddAdjustment(adjustmentName)
+	return tc
+}
+
 func TestCalculateFallbackReleases(t *testing.T) {
 	start419 := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
 	end419 := time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)
@@ -0,0 +1,56 @@
+package componentreadiness
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	sippyapi "github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+const regressionHistoryLink = "%s/api/component_readiness/regressions/%d/history"
+
+// RegressionHistory is the daily timeline of how a regressed test compared against its basis.
+type RegressionHistory struct {
+	RegressionID uint                        `json:"regression_id"`
+	Snapshots    []models.RegressionSnapshot `json:"snapshots"`
+	Links        map[string]string           `json:"links"`
+}
+
+// GetRegressionHistory returns the snapshots recorded for a regression, oldest first, optionally limited to one
+// view. Returns nil if the regression does not exist.
+func GetRegressionHistory(dbc *db.DB, id int, viewName string, req *http.Request) (*RegressionHistory, error) {
+	regression := &models.TestRegression{}
+	if res := dbc.DB.Select("id").First(regression, id); res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.WithError(res.Error).Errorf("error looking up regression record: %d", id)
+		return nil, res.Error
+	}
+
+	history := &RegressionHistory{
+		RegressionID: regression.ID,
+		Snapshots:    []models.RegressionSnapshot{},
+	}
+	q := dbc.DB.Where("test_regression_id = ?", regression.ID)
+	if viewName != "" {
+		q = q.Where("view_name = ?", viewName)
+	}
+	if res := q.Order("date").Order("view_name").Find(&history.Snapshots); res.Error != nil {
+		log.WithError(res.Error).Errorf("error listing snapshots for regression: %d", id)
+		return nil, res.Error
+	}
+
+	baseURL := sippyapi.GetBaseURL(req)
+	history.Links = map[string]string{
+		"self":       fmt.Sprintf(regressionHistoryLink, baseURL, regression.ID),
+		"regression": fmt.Sprintf(regressionLink, baseURL, regression.ID),
+	}
+	return history, nil
+}
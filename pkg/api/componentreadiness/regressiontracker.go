@@ -3,6 +3,7 @@ package componentreadiness
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -15,7 +16,10 @@ import (
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/testdetails"
 	"github.com/openshift/sippy/pkg/db"
 	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/webhooks"
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const (
@@ -41,15 +45,19 @@ type RegressionStore interface {
 	UpsertRegressionView(regressionID uint, viewName string) error
 	// DeactivateRolledOffViews sets active=false on regression_views rows for regressions that have rolled off a view.
 	DeactivateRolledOffViews(regressionIDs []uint, activeViewMap map[uint][]string) error
+	// RecordSnapshot upserts today's snapshot of how the regressed test compared in the view.
+	RecordSnapshot(regressionID uint, viewName string, regTest crtype.ReportTestSummary) error
 }
 
 type PostgresRegressionStore struct {
 	dbc        *db.DB
 	jiraClient *jira.Client
+	// webhookBaseURL is the sippy URL event links point to.
+	webhookBaseURL string
 }
 
-func NewPostgresRegressionStore(dbc *db.DB, jiraClient *jira.Client) RegressionStore {
-	return &PostgresRegressionStore{dbc: dbc, jiraClient: jiraClient}
+func NewPostgresRegressionStore(dbc *db.DB, jiraClient *jira.Client, webhookBaseURL string) RegressionStore {
+	return &PostgresRegressionStore{dbc: dbc, jiraClient: jiraClient, webhookBaseURL: webhookBaseURL}
 }
 
 func (prs *PostgresRegressionStore) ListCurrentRegressionsForRelease(release string) ([]*models.TestRegression, error) {
@@ -95,13 +103,80 @@ func (prs *PostgresRegressionStore) OpenRegression(view crview.View, newRegresse
 		return &models.TestRegression{}, res.Error
 	}
 	log.Infof("opened a new regression: %v", newRegression)
+	prs.notify(webhooks.NewRegressionEvent(webhooks.RegressionOpened, *newRegression, view.Name))
 	return newRegression, nil
 
 }
 
+// UpdateRegression saves the regression, notifying webhook subscribers if it was closed, reopened, or has failed
+// for the first time since a triage it belongs to was resolved.
 func (prs *PostgresRegressionStore) UpdateRegression(reg *models.TestRegression) error {
+	var stored models.TestRegression
+	found := true
+	if res := prs.dbc.DB.Select("id", "closed", "last_failure").First(&stored, reg.ID); res.Error != nil {
+		if !errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("error looking up regression %d: %w", reg.ID, res.Error)
+		}
+		found = false
+	}
 	res := prs.dbc.DB.Save(&reg)
-	return res.Error
+	if res.Error != nil || !found {
+		return res.Error
+	}
+
+	if eventType, ok := regressionTransition(stored, *reg); ok {
+		prs.notify(webhooks.NewRegressionEvent(eventType, *reg))
+	}
+	if failedSince(stored, *reg) {
+		prs.notifyFailedAfterResolution(reg, stored.LastFailure)
+	}
+	return nil
+}
+
+// regressionTransition returns the lifecycle event for a stored regression being updated, if it closed or reopened.
+func regressionTransition(stored, updated models.TestRegression) (webhooks.EventType, bool) {
+	switch {
+	case stored.Closed.Valid && !updated.Closed.Valid:
+		return webhooks.RegressionReopened, true
+	case !stored.Closed.Valid && updated.Closed.Valid:
+		return webhooks.RegressionClosed, true
+	}
+	return "", false
+}
+
+// failedSince reports whether the updated regression has failed since the stored regression's last failure.
+func failedSince(stored, updated models.TestRegression) bool {
+	return updated.LastFailure.Valid && (!stored.LastFailure.Valid || updated.LastFailure.Time.After(stored.LastFailure.Time))
+}
+
+// notifyFailedAfterResolution sends an event for each resolved triage of the regression whose resolution time
+// falls between the previous and the new last failure.
+func (prs *PostgresRegressionStore) notifyFailedAfterResolution(reg *models.TestRegression, previousFailure sql.NullTime) {
+	var triages []models.Triage
+	q := prs.dbc.DB.Table("triages").
+		Joins("JOIN triage_regressions ON triage_regressions.triage_id = triages.id").
+		Where("triage_regressions.test_regression_id = ?", reg.ID).
+		Where("triages.resolved IS NOT NULL AND triages.resolved < ?", reg.LastFailure.Time)
+	if previousFailure.Valid {
+		q = q.Where("triages.resolved >= ?", previousFailure.Time)
+	}
+	if res := q.Select("triages.*").Find(&triages); res.Error != nil {
+		log.WithError(res.Error).Errorf("error looking up resolved triages for regression %d", reg.ID)
+		return
+	}
+	for _, triage := range triages {
+		event := webhooks.NewRegressionEvent(webhooks.RegressionFailedAfterResolution, *reg)
+		event.Triage = webhooks.NewTriageEvent(webhooks.RegressionFailedAfterResolution, triage).Triage
+		prs.notify(event)
+	}
+}
+
+// notify queues webhook events. Failures are logged rather than returned so that webhooks never hold up
+// regression tracking.
+func (prs *PostgresRegressionStore) notify(events ...webhooks.Event) {
+	if err := webhooks.Notify(prs.dbc.DB, prs.webhookBaseURL, events...); err != nil {
+		log.WithError(err).Error("error queuing webhook events")
+	}
 }
 
 func (prs *PostgresRegressionStore) MergeJobRuns(regressionID uint, jobRuns []models.RegressionJobRun) error {
@@ -148,6 +223,53 @@ func (prs *PostgresRegressionStore) DeactivateRolledOffViews(regressionIDs []uin
 	return nil
 }
 
+func (prs *PostgresRegressionStore) RecordSnapshot(regressionID uint, viewName string, regTest crtype.ReportTestSummary) error {
+	snapshot := NewRegressionSnapshot(regressionID, viewName, time.Now(), regTest)
+	res := prs.dbc.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "test_regression_id"}, {Name: "view_name"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns(snapshotUpdateColumns),
+	}).Create(&snapshot)
+	if res.Error != nil {
+		return fmt.Errorf("error recording snapshot for regression %d in view %s: %w", regressionID, viewName, res.Error)
+	}
+	return nil
+}
+
+var snapshotUpdateColumns = []string{
+	"updated_at", "status", "comparison", "base_release",
+	"base_success_count", "base_failure_count", "base_flake_count", "base_success_rate",
+	"sample_success_count", "sample_failure_count", "sample_flake_count", "sample_success_rate",
+	"pass_rate_delta", "fisher_exact", "adjustments", "explanations",
+}
+
+// NewRegressionSnapshot captures the comparison for a regressed test on the day of the given time.
+func NewRegressionSnapshot(regressionID uint, viewName string, now time.Time, regTest crtype.ReportTestSummary) models.RegressionSnapshot {
+	now = now.UTC()
+	snapshot := models.RegressionSnapshot{
+		TestRegressionID:   regressionID,
+		ViewName:           viewName,
+		Date:               time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		Status:             int(regTest.ReportStatus),
+		Comparison:         string(regTest.Comparison),
+		SampleSuccessCount: regTest.SampleStats.SuccessCount,
+		SampleFailureCount: regTest.SampleStats.FailureCount,
+		SampleFlakeCount:   regTest.SampleStats.FlakeCount,
+		SampleSuccessRate:  regTest.SampleStats.SuccessRate,
+		FisherExact:        regTest.FisherExact,
+		Adjustments:        regTest.Adjustments,
+		Explanations:       regTest.Explanations,
+	}
+	if regTest.BaseStats != nil {
+		snapshot.BaseRelease = regTest.BaseStats.Release
+		snapshot.BaseSuccessCount = regTest.BaseStats.SuccessCount
+		snapshot.BaseFailureCount = regTest.BaseStats.FailureCount
+		snapshot.BaseFlakeCount = regTest.BaseStats.FlakeCount
+		snapshot.BaseSuccessRate = regTest.BaseStats.SuccessRate
+		snapshot.PassRateDelta = regTest.SampleStats.SuccessRate - regTest.BaseStats.SuccessRate
+	}
+	return snapshot
+}
+
 // ResolveTriages sets the resolution time on any triages that no longer have active regressions
 // It only does so when all the regressions have been closed for at least regressionHysteresisDays (5) days
 func (prs *PostgresRegressionStore) ResolveTriages() error {
@@ -198,6 +320,7 @@ func (prs *PostgresRegressionStore) ResolveTriages() error {
 		}
 
 		ReportTriageResolved(prs.jiraClient, triage)
+		prs.notify(webhooks.NewTriageEvent(webhooks.TriageResolved, triage))
 		log.Infof("Resolved triage %d with resolution time %v", triage.ID, triage.Resolved.Time)
 	}
 
@@ -321,6 +444,10 @@ func SyncRegressionsForReport(
 					"test": regTest.TestName,
 				}).Debugf("reusing already opened regression: %v", openReg)
 			}
+			if err := backend.RecordSnapshot(openReg.ID, view.Name, regTest); err != nil {
+				// snapshots only feed regression history, so don't let one stop tracking
+				rLog.WithError(err).Error("error recording regression snapshot")
+			}
 			activeRegressions = append(activeRegressions, openReg)
 		} else {
 			openedRegs++
@@ -331,6 +458,9 @@ func SyncRegressionsForReport(
 				rLog.WithError(err).Errorf("error opening new regression for: %v", regTest)
 				return nil, fmt.Errorf("error opening new regression: %v: %w", regTest, err)
 			}
+			if err := backend.RecordSnapshot(newReg.ID, view.Name, regTest); err != nil {
+				rLog.WithError(err).Error("error recording regression snapshot")
+			}
 			activeRegressions = append(activeRegressions, newReg)
 			rLog.Infof("new regression opened with id: %d", newReg.ID)
 		}
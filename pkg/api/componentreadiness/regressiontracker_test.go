@@ -1,12 +1,15 @@
 package componentreadiness
 
 import (
+	"database/sql"
 	"testing"
 	"time"
 
+	crtype "github.com/openshift/sippy/pkg/apis/api/componentreport"
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/testdetails"
 	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/webhooks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -187,3 +190,87 @@ func TestFailedJobRunsFromTestDetails_StartTimePreserved(t *testing.T) {
 	require.Len(t, runs, 1)
 	assert.Equal(t, startTime, runs[0].StartTime)
 }
+
+func TestNewRegressionSnapshot(t *testing.T) {
+	pValue := 0.001
+	regTest := crtype.ReportTestSummary{
+		TestComparison: testdetails.TestComparison{
+			ReportStatus: crtest.SignificantRegression,
+			Comparison:   crtest.FisherExact,
+			SampleStats: testdetails.ReleaseStats{
+				Release: "4.22",
+				Stats:   crtest.Stats{SuccessCount: 80, FailureCount: 20, FlakeCount: 2, SuccessRate: 0.8},
+			},
+			BaseStats: &testdetails.ReleaseStats{
+				Release: "4.20",
+				Stats:   crtest.Stats{SuccessCount: 950, FailureCount: 50, SuccessRate: 0.95},
+			},
+			FisherExact:  &pValue,
+			Explanations: []string{"Overrode base stats (0.9300) using release 4.20 (0.9500)"},
+			Adjustments:  []string{"release-fallback"},
+		},
+	}
+
+	snapshot := NewRegressionSnapshot(7, "4.22-main", time.Date(2026, 4, 10, 23, 30, 0, 0, time.FixedZone("EDT", -4*3600)), regTest)
+	assert.Equal(t, uint(7), snapshot.TestRegressionID)
+	assert.Equal(t, "4.22-main", snapshot.ViewName)
+	assert.Equal(t, time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC), snapshot.Date, "date should be the UTC day")
+	assert.Equal(t, int(crtest.SignificantRegression), snapshot.Status)
+	assert.Equal(t, "4.20", snapshot.BaseRelease)
+	assert.Equal(t, 950, snapshot.BaseSuccessCount)
+	assert.Equal(t, 20, snapshot.SampleFailureCount)
+	assert.InDelta(t, -0.15, snapshot.PassRateDelta, 0.0001)
+	assert.Equal(t, &pValue, snapshot.FisherExact)
+	assert.Equal(t, []string{"release-fallback"}, []string(snapshot.Adjustments))
+
+	regTest.BaseStats = nil
+	snapshot = NewRegressionSnapshot(7, "4.22-main", time.Now(), regTest)
+	assert.Empty(t, snapshot.BaseRelease)
+	assert.Zero(t, snapshot.PassRateDelta)
+}
+
+func TestRegressionTransition(t *testing.T) {
+	closedAt := sql.NullTime{Valid: true, Time: time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name      string
+		stored    sql.NullTime
+		updated   sql.NullTime
+		wantEvent webhooks.EventType
+	}{
+		{name: "stays open", stored: sql.NullTime{}, updated: sql.NullTime{}},
+		{name: "closes", stored: sql.NullTime{}, updated: closedAt, wantEvent: webhooks.RegressionClosed},
+		{name: "reopens", stored: closedAt, updated: sql.NullTime{}, wantEvent: webhooks.RegressionReopened},
+		{name: "stays closed", stored: closedAt, updated: closedAt},
+		{name: "stays closed with a new close time", stored: closedAt,
+			updated: sql.NullTime{Valid: true, Time: closedAt.Time.AddDate(0, 0, 1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventType, ok := regressionTransition(models.TestRegression{Closed: tt.stored}, models.TestRegression{Closed: tt.updated})
+			assert.Equal(t, tt.wantEvent != "", ok)
+			assert.Equal(t, tt.wantEvent, eventType)
+		})
+	}
+}
+
+func TestFailedSince(t *testing.T) {
+	failedAt := sql.NullTime{Valid: true, Time: time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)}
+	later := sql.NullTime{Valid: true, Time: failedAt.Time.Add(time.Hour)}
+	tests := []struct {
+		name    string
+		stored  sql.NullTime
+		updated sql.NullTime
+		want    bool
+	}{
+		{name: "never failed", stored: sql.NullTime{}, updated: sql.NullTime{}},
+		{name: "first failure", stored: sql.NullTime{}, updated: failedAt, want: true},
+		{name: "new failure", stored: failedAt, updated: later, want: true},
+		{name: "same failure", stored: failedAt, updated: failedAt},
+		{name: "older failure", stored: later, updated: failedAt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, failedSince(models.TestRegression{LastFailure: tt.stored}, models.TestRegression{LastFailure: tt.updated}))
+		})
+	}
+}
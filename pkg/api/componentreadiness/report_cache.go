@@ -0,0 +1,80 @@
+package componentreadiness
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crview"
+	"github.com/openshift/sippy/pkg/apis/cache"
+)
+
+// ViewCacheEntries finds the cached component and test details reports generated for a view, e.g. to purge them
+// after a bad data load. Cache keys hold the resolved request options rather than the view name, so entries are
+// matched on the view's releases and variant options; requests that override those will not match.
+func ViewCacheEntries(ctx context.Context, c cache.Cache, view crview.View) ([]cache.Entry, error) {
+	var matched []cache.Entry
+	for _, prefix := range []string{ComponentReportCacheKeyPrefix, TestDetailsReportCacheKeyPrefix} {
+		entries, err := c.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if cacheKeyMatchesView(entry.Key, view) {
+				matched = append(matched, entry)
+			}
+		}
+	}
+	return matched, nil
+}
+
+func cacheKeyMatchesView(key string, view crview.View) bool {
+	var keyJSON string
+	var ok bool
+	for _, prefix := range []string{ComponentReportCacheKeyPrefix, TestDetailsReportCacheKeyPrefix} {
+		if keyJSON, ok = strings.CutPrefix(key, prefix); ok {
+			break
+		}
+	}
+	if !ok {
+		return false
+	}
+	var cacheKey GeneratorCacheKey
+	if err := json.Unmarshal([]byte(keyJSON), &cacheKey); err != nil {
+		return false
+	}
+
+	return cacheKey.BaseRelease.Name == view.BaseRelease.Name &&
+		cacheKey.SampleRelease.Name == view.SampleRelease.Name &&
+		cacheKey.VariantOption.ColumnGroupBy.Equal(view.VariantOptions.ColumnGroupBy) &&
+		variantMapsEqual(cacheKey.VariantOption.IncludeVariants, view.VariantOptions.IncludeVariants) &&
+		variantMapsEqual(cacheKey.VariantOption.CompareVariants, view.VariantOptions.CompareVariants)
+}
+
+// variantMapsEqual compares variant selections ignoring value order and treating missing and empty as the same.
+func variantMapsEqual(a, b map[string][]string) bool {
+	normalize := func(m map[string][]string) map[string][]string {
+		result := map[string][]string{}
+		for k, vals := range m {
+			if len(vals) == 0 {
+				continue
+			}
+			sorted := slices.Clone(vals)
+			sort.Strings(sorted)
+			result[k] = sorted
+		}
+		return result
+	}
+	na, nb := normalize(a), normalize(b)
+	if len(na) != len(nb) {
+		return false
+	}
+	for k, vals := range na {
+		if !slices.Equal(vals, nb[k]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package componentreadiness
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crview"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
+	"github.com/openshift/sippy/pkg/util"
+	"github.com/openshift/sippy/pkg/util/sets"
+)
+
+func TestViewCacheEntries(t *testing.T) {
+	view := crview.View{
+		Name:          "4.20-main",
+		BaseRelease:   reqopts.RelativeRelease{Release: reqopts.Release{Name: "4.19"}},
+		SampleRelease: reqopts.RelativeRelease{Release: reqopts.Release{Name: "4.20"}},
+		VariantOptions: reqopts.Variants{
+			ColumnGroupBy:   sets.NewString("Platform", "Network"),
+			IncludeVariants: map[string][]string{"Platform": {"aws", "gcp"}, "Network": {"ovn"}},
+		},
+	}
+	keyFor := func(prefix string, key GeneratorCacheKey) string {
+		b, err := json.Marshal(key)
+		require.NoError(t, err)
+		return prefix + string(b)
+	}
+	matching := GeneratorCacheKey{
+		BaseRelease:   reqopts.Release{Name: "4.19"},
+		SampleRelease: reqopts.Release{Name: "4.20"},
+		VariantOption: reqopts.Variants{
+			ColumnGroupBy:   sets.NewString("Network", "Platform"),
+			IncludeVariants: map[string][]string{"Platform": {"gcp", "aws"}, "Network": {"ovn"}, "Topology": {}},
+			CompareVariants: map[string][]string{},
+		},
+		TestIDOptions: []reqopts.TestIdentification{{TestID: "test1"}},
+	}
+	otherRelease := matching
+	otherRelease.SampleRelease = reqopts.Release{Name: "4.21"}
+	otherVariants := matching
+	otherVariants.VariantOption.IncludeVariants = map[string][]string{"Platform": {"aws"}, "Network": {"ovn"}}
+
+	c := &util.PseudoCache{Cache: map[string][]byte{
+		keyFor(ComponentReportCacheKeyPrefix, matching):      []byte("report"),
+		keyFor(TestDetailsReportCacheKeyPrefix, matching):    []byte("details"),
+		keyFor(ComponentReportCacheKeyPrefix, otherRelease):  []byte("report"),
+		keyFor(ComponentReportCacheKeyPrefix, otherVariants): []byte("report"),
+		keyFor("TestVariants~", matching):                    []byte("variants"),
+		ComponentReportCacheKeyPrefix + "not json":           []byte("report"),
+	}}
+
+	entries, err := ViewCacheEntries(context.TODO(), c, view)
+	require.NoError(t, err)
+	keys := []string{}
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	assert.ElementsMatch(t, []string{
+		keyFor(ComponentReportCacheKeyPrefix, matching),
+		keyFor(TestDetailsReportCacheKeyPrefix, matching),
+	}, keys)
+}
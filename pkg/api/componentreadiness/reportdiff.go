@@ -0,0 +1,225 @@
+package componentreadiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/openshift/sippy/pkg/api/componentreadiness/dataprovider"
+	"github.com/openshift/sippy/pkg/api/componentreadiness/utils"
+	crtype "github.com/openshift/sippy/pkg/apis/api/componentreport"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crview"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
+	"github.com/openshift/sippy/pkg/apis/cache"
+	v1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/util"
+)
+
+const (
+	reportDiffLink = "%s/api/component_readiness/report_diff?%s"
+	reportLink     = "%s/api/component_readiness?%s"
+)
+
+// ErrInvalidDiffTime is returned by GetReportDiff when from or to cannot be parsed, as opposed to failing to
+// generate either report.
+var ErrInvalidDiffTime = errors.New("invalid report diff time")
+
+// ReportDiff describes what changed in a view's component report between two sample end dates.
+type ReportDiff struct {
+	View string    `json:"view"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// NewlyRegressed and Cleared are the cells that started or stopped being regressed, StatusChanged those that
+	// otherwise changed status.
+	NewlyRegressed []CellDiff `json:"newly_regressed"`
+	Cleared        []CellDiff `json:"cleared"`
+	StatusChanged  []CellDiff `json:"status_changed"`
+	// Tests are the tests listed in either report whose status or sample pass rate changed.
+	Tests []TestDiff        `json:"tests"`
+	Links map[string]string `json:"links,omitempty"`
+}
+
+// CellDiff is a report cell, a row and column, whose status changed. Cells missing from a report are treated as
+// not significant.
+type CellDiff struct {
+	crtest.RowIdentification
+	crtest.ColumnIdentification
+	FromStatus crtest.Status `json:"from_status"`
+	ToStatus   crtest.Status `json:"to_status"`
+}
+
+// TestDiff is the change in a single test's status and sample pass rate. A pass rate is only known when the test
+// was listed in that report.
+type TestDiff struct {
+	crtest.Identification
+	FromStatus    crtest.Status `json:"from_status"`
+	ToStatus      crtest.Status `json:"to_status"`
+	FromPassRate  *float64      `json:"from_pass_rate,omitempty"`
+	ToPassRate    *float64      `json:"to_pass_rate,omitempty"`
+	PassRateDelta *float64      `json:"pass_rate_delta,omitempty"`
+}
+
+// GetReportDiff generates, or loads from cache, the view's report with its sample window moved to end at from and
+// at to, and diffs the two. The times accept the same formats as views, such as "now-1d", "ga" or RFC3339.
+func GetReportDiff(
+	ctx context.Context,
+	provider dataprovider.DataProvider,
+	dbc *db.DB,
+	view crview.View,
+	releases []v1.Release,
+	cacheOpts cache.RequestOptions,
+	from, to string,
+	baseURL string,
+) (*ReportDiff, error) {
+	viewOpts, err := utils.ViewRequestOptions(releases, view, cacheOpts)
+	if err != nil {
+		return nil, err
+	}
+	sampleRelease := viewOpts.SampleRelease
+	fromEnd, err := util.ParseCRReleaseTime(releases, sampleRelease.Name, from, false, nil, cacheOpts.CRTimeRoundingFactor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from time %q in wrong format: %v", ErrInvalidDiffTime, from, err)
+	}
+	toEnd, err := util.ParseCRReleaseTime(releases, sampleRelease.Name, to, false, nil, cacheOpts.CRTimeRoundingFactor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to time %q in wrong format: %v", ErrInvalidDiffTime, to, err)
+	}
+
+	reports := make([]crtype.ComponentReport, 2)
+	samples := make([]reqopts.Release, 2)
+	for i, end := range []time.Time{fromEnd, toEnd} {
+		// keep the view's sample window length so the two reports are comparable
+		samples[i] = sampleRelease
+		samples[i].Start = end.Add(-sampleRelease.End.Sub(sampleRelease.Start))
+		samples[i].End = end
+		opts := viewOpts
+		opts.SampleRelease = samples[i]
+		report, errs := GetComponentReport(ctx, provider, dbc, opts, baseURL)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("error generating report ending %s: %v", end.Format(time.RFC3339), errs)
+		}
+		reports[i] = report
+	}
+
+	diff := DiffComponentReports(reports[0], reports[1])
+	diff.View = view.Name
+	diff.From = fromEnd
+	diff.To = toEnd
+	diff.Links = map[string]string{
+		"self":        fmt.Sprintf(reportDiffLink, baseURL, url.Values{"view": {view.Name}, "from": {from}, "to": {to}}.Encode()),
+		"from_report": fmt.Sprintf(reportLink, baseURL, reportParams(view.Name, samples[0])),
+		"to_report":   fmt.Sprintf(reportLink, baseURL, reportParams(view.Name, samples[1])),
+	}
+	return &diff, nil
+}
+
+func reportParams(viewName string, sample reqopts.Release) string {
+	return url.Values{
+		"view":            {viewName},
+		"sampleStartTime": {sample.Start.UTC().Format(time.RFC3339)},
+		"sampleEndTime":   {sample.End.UTC().Format(time.RFC3339)},
+	}.Encode()
+}
+
+// DiffComponentReports compares two reports for the same view, cell by cell and test by test.
+func DiffComponentReports(from, to crtype.ComponentReport) ReportDiff {
+	diff := ReportDiff{NewlyRegressed: []CellDiff{}, Cleared: []CellDiff{}, StatusChanged: []CellDiff{}, Tests: []TestDiff{}}
+
+	fromCells, fromTests := indexReport(from)
+	toCells, toTests := indexReport(to)
+
+	for _, key := range unionKeys(fromCells, toCells) {
+		cell := CellDiff{FromStatus: crtest.NotSignificant, ToStatus: crtest.NotSignificant}
+		if c, ok := fromCells[key]; ok {
+			cell.RowIdentification, cell.ColumnIdentification, cell.FromStatus = c.row, c.column, c.status
+		}
+		if c, ok := toCells[key]; ok {
+			cell.RowIdentification, cell.ColumnIdentification, cell.ToStatus = c.row, c.column, c.status
+		}
+		switch {
+		case cell.FromStatus == cell.ToStatus:
+		case !regressed(cell.FromStatus) && regressed(cell.ToStatus):
+			diff.NewlyRegressed = append(diff.NewlyRegressed, cell)
+		case regressed(cell.FromStatus) && !regressed(cell.ToStatus):
+			diff.Cleared = append(diff.Cleared, cell)
+		default:
+			diff.StatusChanged = append(diff.StatusChanged, cell)
+		}
+	}
+
+	for _, key := range unionKeys(fromTests, toTests) {
+		test := TestDiff{FromStatus: crtest.NotSignificant, ToStatus: crtest.NotSignificant}
+		if t, ok := fromTests[key]; ok {
+			test.Identification, test.FromStatus = t.Identification, t.ReportStatus
+			rate := t.SampleStats.SuccessRate
+			test.FromPassRate = &rate
+		}
+		if t, ok := toTests[key]; ok {
+			test.Identification, test.ToStatus = t.Identification, t.ReportStatus
+			rate := t.SampleStats.SuccessRate
+			test.ToPassRate = &rate
+		}
+		if test.FromPassRate != nil && test.ToPassRate != nil {
+			delta := *test.ToPassRate - *test.FromPassRate
+			test.PassRateDelta = &delta
+			if delta == 0 && test.FromStatus == test.ToStatus {
+				continue
+			}
+		}
+		diff.Tests = append(diff.Tests, test)
+	}
+	return diff
+}
+
+// regressed matches the statuses the regression tracker considers open.
+func regressed(status crtest.Status) bool {
+	return status < crtest.FixedRegression
+}
+
+type reportCell struct {
+	row    crtest.RowIdentification
+	column crtest.ColumnIdentification
+	status crtest.Status
+}
+
+func indexReport(report crtype.ComponentReport) (map[string]reportCell, map[string]crtype.ReportTestSummary) {
+	cells := map[string]reportCell{}
+	tests := map[string]crtype.ReportTestSummary{}
+	for _, row := range report.Rows {
+		for _, col := range row.Columns {
+			cells[cellKey(row.RowIdentification, col.Variants)] = reportCell{
+				row:    row.RowIdentification,
+				column: col.ColumnIdentification,
+				status: col.Status,
+			}
+			for _, t := range col.RegressedTests {
+				tests[crtest.KeyWithVariants{TestID: t.TestID, Variants: t.Variants}.KeyOrDie()] = t
+			}
+		}
+	}
+	return cells, tests
+}
+
+func cellKey(row crtest.RowIdentification, variants map[string]string) string {
+	return fmt.Sprintf("%s|%s|%s", row.Component, row.Capability,
+		crtest.KeyWithVariants{TestID: row.TestID, Variants: variants}.KeyOrDie())
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
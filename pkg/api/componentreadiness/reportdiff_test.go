@@ -0,0 +1,84 @@
+package componentreadiness
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	crtype "github.com/openshift/sippy/pkg/apis/api/componentreport"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/testdetails"
+)
+
+func TestDiffComponentReports(t *testing.T) {
+	aws := map[string]string{"Platform": "aws"}
+	gcp := map[string]string{"Platform": "gcp"}
+	regressedTest := func(testID string, variants map[string]string, status crtest.Status, passRate float64) crtype.ReportTestSummary {
+		return crtype.ReportTestSummary{
+			Identification: crtest.Identification{
+				RowIdentification:    crtest.RowIdentification{Component: "etcd", TestID: testID},
+				ColumnIdentification: crtest.ColumnIdentification{Variants: variants},
+			},
+			TestComparison: testdetails.TestComparison{
+				ReportStatus: status,
+				SampleStats:  testdetails.ReleaseStats{Stats: crtest.Stats{SuccessRate: passRate}},
+			},
+		}
+	}
+	column := func(variants map[string]string, status crtest.Status, tests ...crtype.ReportTestSummary) crtype.ReportColumn {
+		return crtype.ReportColumn{ColumnIdentification: crtest.ColumnIdentification{Variants: variants}, Status: status, RegressedTests: tests}
+	}
+	row := func(component string, columns ...crtype.ReportColumn) crtype.ReportRow {
+		return crtype.ReportRow{RowIdentification: crtest.RowIdentification{Component: component}, Columns: columns}
+	}
+
+	from := crtype.ComponentReport{Rows: []crtype.ReportRow{
+		row("etcd",
+			column(aws, crtest.SignificantRegression, regressedTest("t1", aws, crtest.SignificantRegression, 0.9), regressedTest("t2", aws, crtest.ExtremeRegression, 0.5)),
+			column(gcp, crtest.NotSignificant)),
+		row("network", column(aws, crtest.SignificantRegression)),
+	}}
+	to := crtype.ComponentReport{Rows: []crtype.ReportRow{
+		row("etcd",
+			column(aws, crtest.ExtremeRegression, regressedTest("t1", aws, crtest.ExtremeRegression, 0.7), regressedTest("t2", aws, crtest.ExtremeRegression, 0.5)),
+			column(gcp, crtest.SignificantRegression, regressedTest("t3", gcp, crtest.SignificantRegression, 0.8))),
+		row("network", column(aws, crtest.SignificantTriagedRegression)),
+		row("storage", column(aws, crtest.MissingSample)),
+	}}
+
+	diff := DiffComponentReports(from, to)
+
+	require.Len(t, diff.NewlyRegressed, 1)
+	assert.Equal(t, "etcd", diff.NewlyRegressed[0].Component)
+	assert.Equal(t, gcp, diff.NewlyRegressed[0].Variants)
+	assert.Equal(t, crtest.NotSignificant, diff.NewlyRegressed[0].FromStatus)
+
+	assert.Empty(t, diff.Cleared, "triaged regressions are still regressed")
+
+	require.Len(t, diff.StatusChanged, 3)
+	statuses := map[string][2]crtest.Status{}
+	for _, c := range diff.StatusChanged {
+		statuses[c.Component] = [2]crtest.Status{c.FromStatus, c.ToStatus}
+	}
+	assert.Equal(t, map[string][2]crtest.Status{
+		"etcd":    {crtest.SignificantRegression, crtest.ExtremeRegression},
+		"network": {crtest.SignificantRegression, crtest.SignificantTriagedRegression},
+		"storage": {crtest.NotSignificant, crtest.MissingSample},
+	}, statuses)
+
+	require.Len(t, diff.Tests, 2, "unchanged tests are left out")
+	byID := map[string]TestDiff{}
+	for _, td := range diff.Tests {
+		byID[td.TestID] = td
+	}
+	assert.InDelta(t, -0.2, *byID["t1"].PassRateDelta, 0.0001)
+	assert.Equal(t, crtest.ExtremeRegression, byID["t1"].ToStatus)
+	assert.Nil(t, byID["t3"].FromPassRate)
+	assert.Nil(t, byID["t3"].PassRateDelta)
+	assert.Equal(t, 0.8, *byID["t3"].ToPassRate)
+
+	cleared := DiffComponentReports(to, from)
+	require.Len(t, cleared.Cleared, 1)
+	assert.Equal(t, gcp, cleared.Cleared[0].Variants)
+}
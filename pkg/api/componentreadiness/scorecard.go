@@ -0,0 +1,188 @@
+package componentreadiness
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm/clause"
+
+	sippyapi "github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/componentreadiness/dataprovider"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crview"
+	v1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+const (
+	burndownLink        = "%s/api/component_readiness/burndown?%s"
+	viewRegressionsLink = "%s/api/component_readiness/regressions?%s"
+)
+
+// ReadinessBurndown is the series of daily scorecards for a view over its release's development window.
+type ReadinessBurndown struct {
+	View    string `json:"view"`
+	Release string `json:"release"`
+	// BranchCut and GA bound the series, taken from the release's development start and GA dates. Either may
+	// be unknown, in which case the series is open ended on that side.
+	BranchCut  *time.Time                  `json:"branch_cut,omitempty"`
+	GA         *time.Time                  `json:"ga,omitempty"`
+	Scorecards []models.ReadinessScorecard `json:"scorecards"`
+	Links      map[string]string           `json:"links"`
+}
+
+// RecordReadinessScorecard computes and upserts today's scorecard for a regression tracking view.
+func RecordReadinessScorecard(dbc *db.DB, view crview.View, now time.Time) error {
+	var regressions []models.TestRegression
+	res := dbc.DB.Preload("Triages").Preload("Views").
+		Joins("JOIN regression_views rv ON rv.test_regression_id = test_regressions.id").
+		Where("rv.view_name = ?", view.Name).
+		Find(&regressions)
+	if res.Error != nil {
+		return fmt.Errorf("error listing regressions for view %s: %w", view.Name, res.Error)
+	}
+
+	scorecard := NewReadinessScorecard(view.Name, view.SampleRelease.Name, now, regressions)
+	res = dbc.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "view_name"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at", "release", "open_regressions", "triaged_regressions", "untriaged_regressions",
+			"mean_hours_to_triage", "mean_hours_to_close", "components",
+		}),
+	}).Create(&scorecard)
+	if res.Error != nil {
+		return fmt.Errorf("error recording scorecard for view %s: %w", view.Name, res.Error)
+	}
+	return nil
+}
+
+// NewReadinessScorecard rolls up the regressions seen in a view as of the day of the given time. Regressions count
+// as open while they are unclosed and still active in the view.
+func NewReadinessScorecard(viewName, release string, now time.Time, regressions []models.TestRegression) models.ReadinessScorecard {
+	now = now.UTC()
+	scorecard := models.ReadinessScorecard{
+		ViewName: viewName,
+		Release:  release,
+		Date:     time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	components := map[string]*models.ComponentScorecard{}
+	var triageHours, closeHours []float64
+	for _, r := range regressions {
+		triaged := len(r.Triages) > 0
+		if triaged {
+			firstTriage := r.Triages[0].CreatedAt
+			for _, t := range r.Triages[1:] {
+				if t.CreatedAt.Before(firstTriage) {
+					firstTriage = t.CreatedAt
+				}
+			}
+			triageHours = append(triageHours, max(firstTriage.Sub(r.Opened).Hours(), 0))
+		}
+		if r.Closed.Valid {
+			closeHours = append(closeHours, max(r.Closed.Time.Sub(r.Opened).Hours(), 0))
+			continue
+		}
+		if !activeInView(r, viewName) {
+			continue
+		}
+
+		c, ok := components[r.Component]
+		if !ok {
+			c = &models.ComponentScorecard{Component: r.Component}
+			components[r.Component] = c
+		}
+		scorecard.OpenRegressions++
+		c.Open++
+		if triaged {
+			scorecard.TriagedRegressions++
+			c.Triaged++
+		} else {
+			scorecard.UntriagedRegressions++
+			c.Untriaged++
+		}
+	}
+	scorecard.MeanHoursToTriage = mean(triageHours)
+	scorecard.MeanHoursToClose = mean(closeHours)
+
+	scorecard.Components = models.ComponentScorecards{}
+	for _, c := range components {
+		scorecard.Components = append(scorecard.Components, *c)
+	}
+	sort.Slice(scorecard.Components, func(i, j int) bool {
+		return scorecard.Components[i].Component < scorecard.Components[j].Component
+	})
+	return scorecard
+}
+
+func activeInView(r models.TestRegression, viewName string) bool {
+	for _, v := range r.Views {
+		if v.ViewName == viewName {
+			return v.Active
+		}
+	}
+	return false
+}
+
+func mean(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	m := sum / float64(len(values))
+	return &m
+}
+
+// GetReadinessBurndown returns the recorded scorecards for a view between its release's branch cut and GA.
+func GetReadinessBurndown(ctx context.Context, dbc *db.DB, provider dataprovider.DataProvider, view crview.View, req *http.Request) (*ReadinessBurndown, error) {
+	burndown := &ReadinessBurndown{
+		View:       view.Name,
+		Release:    view.SampleRelease.Name,
+		Scorecards: []models.ReadinessScorecard{},
+	}
+	if provider != nil {
+		releases, err := provider.QueryReleases(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error querying releases: %w", err)
+		}
+		burndown.BranchCut, burndown.GA = releaseDevelopmentWindow(releases, burndown.Release)
+	}
+
+	q := dbc.DB.Where("view_name = ?", view.Name)
+	if burndown.BranchCut != nil {
+		q = q.Where("date >= ?", burndown.BranchCut.Format(time.DateOnly))
+	}
+	if burndown.GA != nil {
+		q = q.Where("date <= ?", burndown.GA.Format(time.DateOnly))
+	}
+	if res := q.Order("date").Find(&burndown.Scorecards); res.Error != nil {
+		log.WithError(res.Error).Errorf("error listing scorecards for view %s", view.Name)
+		return nil, res.Error
+	}
+
+	baseURL := sippyapi.GetBaseURL(req)
+	params := url.Values{"view": {view.Name}}.Encode()
+	burndown.Links = map[string]string{
+		"self":        fmt.Sprintf(burndownLink, baseURL, params),
+		"regressions": fmt.Sprintf(viewRegressionsLink, baseURL, params),
+	}
+	return burndown, nil
+}
+
+// releaseDevelopmentWindow returns the development start (branch cut) and GA dates of a release, when known.
+func releaseDevelopmentWindow(releases []v1.Release, release string) (*time.Time, *time.Time) {
+	for _, r := range releases {
+		if r.Release == release {
+			return r.DevelopmentStartDate, r.GADate
+		}
+	}
+	return nil, nil
+}
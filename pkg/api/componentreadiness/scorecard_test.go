@@ -0,0 +1,75 @@
+package componentreadiness
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+func TestNewReadinessScorecard(t *testing.T) {
+	opened := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 4, 10, 15, 0, 0, 0, time.UTC)
+	active := []models.RegressionView{{ViewName: "4.22-main", Active: true}}
+	inactive := []models.RegressionView{{ViewName: "4.22-main", Active: false}}
+
+	regressions := []models.TestRegression{
+		{ID: 1, Component: "etcd", Opened: opened, Views: active,
+			Triages: []models.Triage{{CreatedAt: opened.Add(48 * time.Hour)}, {CreatedAt: opened.Add(12 * time.Hour)}}},
+		{ID: 2, Component: "etcd", Opened: opened, Views: active},
+		{ID: 3, Component: "networking", Opened: opened, Views: active},
+		// closed regressions count towards time to close, and time to triage if triaged, but are not open
+		{ID: 4, Component: "networking", Opened: opened, Views: inactive,
+			Closed:  sql.NullTime{Valid: true, Time: opened.Add(72 * time.Hour)},
+			Triages: []models.Triage{{CreatedAt: opened.Add(36 * time.Hour)}}},
+		// rolled off this view but still open in another
+		{ID: 5, Component: "etcd", Opened: opened, Views: inactive},
+	}
+
+	scorecard := NewReadinessScorecard("4.22-main", "4.22", now, regressions)
+	assert.Equal(t, time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC), scorecard.Date)
+	assert.Equal(t, 3, scorecard.OpenRegressions)
+	assert.Equal(t, 1, scorecard.TriagedRegressions)
+	assert.Equal(t, 2, scorecard.UntriagedRegressions)
+	require.NotNil(t, scorecard.MeanHoursToTriage)
+	assert.InDelta(t, 24.0, *scorecard.MeanHoursToTriage, 0.001, "earliest triage of each regression is used")
+	require.NotNil(t, scorecard.MeanHoursToClose)
+	assert.InDelta(t, 72.0, *scorecard.MeanHoursToClose, 0.001)
+	assert.Equal(t, models.ComponentScorecards{
+		{Component: "etcd", Open: 2, Triaged: 1, Untriaged: 1},
+		{Component: "networking", Open: 1, Untriaged: 1},
+	}, scorecard.Components)
+
+	empty := NewReadinessScorecard("4.22-main", "4.22", now, nil)
+	assert.Nil(t, empty.MeanHoursToTriage)
+	assert.Nil(t, empty.MeanHoursToClose)
+	assert.Empty(t, empty.Components)
+}
+
+func TestReleaseDevelopmentWindow(t *testing.T) {
+	develStart := time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)
+	ga := time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC)
+	releases := []v1.Release{
+		{Release: "4.23"},
+		{Release: "4.22", DevelopmentStartDate: &develStart, GADate: &ga},
+	}
+
+	branchCut, gaDate := releaseDevelopmentWindow(releases, "4.22")
+	require.NotNil(t, branchCut)
+	require.NotNil(t, gaDate)
+	assert.Equal(t, develStart, *branchCut, "the series starts at branch cut, not a window before GA")
+	assert.Equal(t, ga, *gaDate)
+
+	branchCut, gaDate = releaseDevelopmentWindow(releases, "4.23")
+	assert.Nil(t, branchCut)
+	assert.Nil(t, gaDate, "unreleased versions have an open ended series")
+
+	branchCut, gaDate = releaseDevelopmentWindow(releases, "4.99")
+	assert.Nil(t, branchCut)
+	assert.Nil(t, gaDate)
+}
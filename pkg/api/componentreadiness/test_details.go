@@ -26,6 +26,7 @@ import (
 )
 
 func GetTestDetails(ctx context.Context, provider dataprovider.DataProvider, dbc *db.DB, reqOptions reqopts.RequestOptions, releases []v1.Release, baseURL string) (testdetails.Report, []error) {
+	reqOptions, reportCacheOption := reportCacheOptions(reqOptions)
 	generator := NewComponentReportGenerator(provider, reqOptions, dbc, releases, baseURL)
 	if os.Getenv("DEV_MODE") == "1" {
 		return generator.GenerateTestDetailsReport(ctx)
@@ -34,7 +35,7 @@ func GetTestDetails(ctx context.Context, provider dataprovider.DataProvider, dbc
 	report, errs := api.GetDataFromCacheOrGenerate[testdetails.Report](
 		ctx,
 		generator.getCache(),
-		generator.ReqOptions.CacheOption,
+		reportCacheOption,
 		api.NewCacheSpec(generator.GetCacheKey(ctx), TestDetailsReportCacheKeyPrefix, nil),
 		generator.GenerateTestDetailsReport,
 		testdetails.Report{})
@@ -248,6 +249,11 @@ func (c *ComponentReportGenerator) GenerateDetailsReportForTest(
 		// Inject the override report stats into the first position on the main report,
 		// which callers will interpret as the authoritative report in the event multiple are returned
 		report.Analyses = append([]testdetails.Analysis{baseOverrideReport.Analyses[0]}, report.Analyses...)
+		for _, run := range overrideReport.ExcludedJobRuns {
+			if run.Release == testIDOption.BaseOverrideRelease {
+				report.ExcludedJobRuns = append(report.ExcludedJobRuns, run)
+			}
+		}
 	}
 
 	if !allowUnregressedReports {
@@ -437,10 +443,12 @@ func (c *ComponentReportGenerator) internalGenerateTestDetailsReport(
 		},
 	}
 
+	baseStatus, baseExcluded := c.excludeLabeledJobRuns(baseStatus, baseRelease)
+	sampleStatus, sampleExcluded := c.excludeLabeledJobRuns(sampleStatus, c.ReqOptions.SampleRelease.Name)
 	totalBase, totalSample, report, result, lastFailure := c.summarizeRecordedTestStats(baseStatus, sampleStatus, testKey)
+	result.ExcludedJobRuns = append(sampleExcluded, baseExcluded...)
 
 	testStats := testdetails.TestComparison{
-		RequiredConfidence: c.ReqOptions.AdvancedOption.Confidence,
 		SampleStats: testdetails.ReleaseStats{
 			Release: c.ReqOptions.SampleRelease.Name,
 			Start:   &c.ReqOptions.SampleRelease.Start,
@@ -454,6 +462,11 @@ func (c *ComponentReportGenerator) internalGenerateTestDetailsReport(
 			Stats:   totalBase,
 		},
 	}
+	var capabilities []string
+	if testIDOption.Capability != "" {
+		capabilities = []string{testIDOption.Capability}
+	}
+	applyThresholds(&testStats, c.ReqOptions.AdvancedOption, testIDOption.Component, capabilities, testIDOption.TestID)
 	if !lastFailure.IsZero() {
 		testStats.LastFailure = &lastFailure
 	}
@@ -471,6 +484,41 @@ func (c *ComponentReportGenerator) internalGenerateTestDetailsReport(
 	return result
 }
 
+// excludeLabeledJobRuns drops the job runs carrying an excluded label, returning the runs that remain by job and
+// the excluded runs ordered by start time.
+func (c *ComponentReportGenerator) excludeLabeledJobRuns(
+	status map[string][]crstatus.TestJobRunRows, release string,
+) (map[string][]crstatus.TestJobRunRows, []testdetails.ExcludedJobRun) {
+	kept := map[string][]crstatus.TestJobRunRows{}
+	var excluded []testdetails.ExcludedJobRun
+	seen := sets.NewString()
+	for job, rows := range status {
+		for _, row := range rows {
+			label, ok := c.ReqOptions.AdvancedOption.ExcludedJobRunLabel(row.JobLabels)
+			if !ok {
+				kept[job] = append(kept[job], row)
+				continue
+			}
+			if seen.Has(row.ProwJobRunID) {
+				continue
+			}
+			seen.Insert(row.ProwJobRunID)
+			excluded = append(excluded, testdetails.ExcludedJobRun{
+				Release:   release,
+				JobName:   row.ProwJob,
+				JobURL:    row.ProwJobURL,
+				JobRunID:  row.ProwJobRunID,
+				StartTime: row.StartTime,
+				Label:     label,
+			})
+		}
+	}
+	sort.Slice(excluded, func(i, j int) bool {
+		return excluded[i].StartTime.Before(excluded[j].StartTime)
+	})
+	return kept, excluded
+}
+
 // go through all the job runs that had a test and summarize the results
 func (c *ComponentReportGenerator) summarizeRecordedTestStats(
 	baseStatus, sampleStatus map[string][]crstatus.TestJobRunRows, testKey crtest.Identification,
@@ -501,8 +549,14 @@ func (c *ComponentReportGenerator) summarizeRecordedTestStats(
 		// determine the statistical significance to report in the job stats
 		sFail, sPass := jobStats.SampleStats.FailPassWithFlakes(faf)
 		bFail, bPass := jobStats.BaseStats.FailPassWithFlakes(faf)
-		_, _, r, _ := fet.FisherExactTest(sFail, sPass, bFail, bPass)
-		jobStats.Significant = r < 1-float64(c.ReqOptions.AdvancedOption.Confidence)/100
+		if c.ReqOptions.AdvancedOption.ComparisonMode == crtest.Bayesian {
+			confidence := float64(c.ReqOptions.AdvancedOption.Confidence) / 100
+			analysis := bayesianComparison(bPass, bFail, sPass, sFail, float64(c.ReqOptions.AdvancedOption.PityFactor)/100, confidence)
+			jobStats.Significant = analysis.ProbabilityOfRegression >= confidence || analysis.ProbabilityOfImprovement >= confidence
+		} else {
+			_, _, r, _ := fet.FisherExactTest(sFail, sPass, bFail, bPass)
+			jobStats.Significant = r < 1-float64(c.ReqOptions.AdvancedOption.Confidence)/100
+		}
 
 		report.JobStats = append(report.JobStats, jobStats)
 	}
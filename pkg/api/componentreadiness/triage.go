@@ -22,6 +22,7 @@ import (
 	"github.com/openshift/sippy/pkg/db"
 	"github.com/openshift/sippy/pkg/db/models"
 	"github.com/openshift/sippy/pkg/db/query"
+	"github.com/openshift/sippy/pkg/webhooks"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -106,6 +107,7 @@ func CreateTriage(dbc *gorm.DB, jiraClient *jira.Client, triage models.Triage, r
 	log.WithField("triageID", triage.ID).Info("triage record created")
 	injectHATEOASLinks(&triage, sippyapi.GetBaseURL(req))
 	ReportTriageAddedForJira(jiraClient, triage, req)
+	notifyTriage(dbc, req, webhooks.NewTriageEvent(webhooks.TriageCreated, triage))
 	return triage, nil
 }
 
@@ -291,9 +293,22 @@ func UpdateTriage(dbc *gorm.DB, jiraClient *jira.Client, triage models.Triage, r
 	if existingTriage.URL != triage.URL {
 		ReportTriageAddedForJira(jiraClient, triage, req)
 	}
+
+	events := []webhooks.Event{webhooks.NewTriageEvent(webhooks.TriageUpdated, triage)}
+	if !existingTriage.Resolved.Valid && triage.Resolved.Valid {
+		events = append(events, webhooks.NewTriageEvent(webhooks.TriageResolved, triage))
+	}
+	notifyTriage(dbc, req, events...)
 	return triage, nil
 }
 
+// notifyTriage queues webhook events for a triage change; the change has already been saved, so errors are only logged.
+func notifyTriage(dbc *gorm.DB, req *http.Request, events ...webhooks.Event) {
+	if err := webhooks.Notify(dbc, sippyapi.GetBaseURL(req), events...); err != nil {
+		log.WithError(err).Error("error queuing triage webhook events")
+	}
+}
+
 func DeleteTriage(dbc *gorm.DB, id int) error {
 	existingTriage := &models.Triage{}
 	res := dbc.First(existingTriage, id).Delete(existingTriage)
@@ -796,7 +811,8 @@ func injectHATEOASLinks(triage *models.Triage, baseURL string) {
 // Per-view test_details links use composite keys: test_details:<view_name>.
 func InjectRegressionHATEOASLinks(regression *models.TestRegression, views []crview.View, releases []v1.Release, crTimeRoundingFactor time.Duration, baseAPIURL, baseFrontendURL string) {
 	regression.Links = map[string]string{
-		"self": fmt.Sprintf(regressionLink, baseAPIURL, regression.ID),
+		"self":    fmt.Sprintf(regressionLink, baseAPIURL, regression.ID),
+		"history": fmt.Sprintf(regressionHistoryLink, baseAPIURL, regression.ID),
 	}
 
 	for _, rv := range regression.Views {
@@ -769,6 +769,7 @@ func TestInjectRegressionHATEOASLinks(t *testing.T) {
 			InjectRegressionHATEOASLinks(tt.regression, views, releases, 0, "http://api", "http://frontend")
 
 			assert.Contains(t, tt.regression.Links, "self")
+			assert.Contains(t, tt.regression.Links, "history")
 
 			if tt.expectNoDetails {
 				for key := range tt.regression.Links {
@@ -782,7 +783,7 @@ func TestInjectRegressionHATEOASLinks(t *testing.T) {
 				// Count test_details links matches expected count
 				detailsCount := 0
 				for key := range tt.regression.Links {
-					if key != "self" {
+					if key != "self" && key != "history" {
 						detailsCount++
 					}
 				}
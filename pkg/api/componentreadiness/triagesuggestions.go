@@ -0,0 +1,285 @@
+package componentreadiness
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	sippyapi "github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/db/query"
+)
+
+const (
+	triagesLink = "%s/api/component_readiness/triages"
+
+	// minJobRunOverlap is the fraction of the smaller regression's job runs that must also have failed the other
+	// regression's test for the two to be considered the same problem.
+	minJobRunOverlap = 0.5
+	// minOutputSimilarity is the jaccard similarity of normalized output tokens above which two failures are
+	// considered to be the same message.
+	minOutputSimilarity = 0.7
+	// maxOutputsPerRegression bounds the pairwise output comparisons for regressions with many failures.
+	maxOutputsPerRegression = 10
+	maxRawOutputLength      = 4000
+	maxSampleOutputLength   = 200
+)
+
+// TriageSuggestion proposes a single triage for a group of untriaged regressions that fail in the same job runs
+// with similar output, as a single bad change often regresses many tests and variants at once.
+type TriageSuggestion struct {
+	Regressions []models.TestRegression `json:"regressions"`
+	Components  []string                `json:"components"`
+	// SharedJobRuns is the number of job runs in which at least two of the regressions failed.
+	SharedJobRuns int `json:"shared_job_runs"`
+	// OutputSimilarity is the lowest similarity of the failure output that linked the regressions together.
+	OutputSimilarity     float64           `json:"output_similarity"`
+	SampleOutput         string            `json:"sample_output"`
+	SuggestedDescription string            `json:"suggested_description"`
+	Links                map[string]string `json:"links"`
+}
+
+// GetTriageSuggestions clusters the open, untriaged regressions for a release into triage suggestions, largest first.
+func GetTriageSuggestions(dbc *db.DB, release string, req *http.Request) ([]TriageSuggestion, error) {
+	openRegressions, err := query.ListOpenRegressions(dbc, release)
+	if err != nil {
+		return nil, err
+	}
+	var untriaged []*models.TestRegression
+	var ids []uint
+	for _, r := range openRegressions {
+		if len(r.Triages) == 0 {
+			untriaged = append(untriaged, r)
+			ids = append(ids, r.ID)
+		}
+	}
+	outputs, err := query.ListRegressionFailureOutputs(dbc, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := ClusterRegressions(untriaged, outputs)
+	baseURL := sippyapi.GetBaseURL(req)
+	for i := range suggestions {
+		suggestions[i].Links = map[string]string{
+			"create_triage": fmt.Sprintf(triagesLink, baseURL),
+		}
+		for j := range suggestions[i].Regressions {
+			r := &suggestions[i].Regressions[j]
+			r.Links = map[string]string{
+				"self":    fmt.Sprintf(regressionLink, baseURL, r.ID),
+				"history": fmt.Sprintf(regressionHistoryLink, baseURL, r.ID),
+			}
+		}
+	}
+	return suggestions, nil
+}
+
+type regressionFailures struct {
+	regression *models.TestRegression
+	jobRuns    sets.Set[string]
+	// outputs are the distinct normalized failure outputs, with their tokens for comparison.
+	outputs []string
+	tokens  []sets.Set[string]
+}
+
+// ClusterRegressions groups regressions that failed in overlapping job runs with similar normalized output.
+// Links are transitive, and only groups of two or more regressions are returned.
+func ClusterRegressions(regressions []*models.TestRegression, outputs []query.RegressionFailureOutput) []TriageSuggestion {
+	failures := make([]*regressionFailures, len(regressions))
+	index := map[uint]int{}
+	for i, r := range regressions {
+		failures[i] = &regressionFailures{regression: r, jobRuns: sets.New[string]()}
+		index[r.ID] = i
+	}
+	for _, o := range outputs {
+		i, ok := index[o.RegressionID]
+		if !ok {
+			continue
+		}
+		f := failures[i]
+		f.jobRuns.Insert(o.ProwJobRunID)
+		normalized := NormalizeFailureOutput(o.Output)
+		if normalized == "" || len(f.outputs) >= maxOutputsPerRegression || slices.Contains(f.outputs, normalized) {
+			continue
+		}
+		f.outputs = append(f.outputs, normalized)
+		f.tokens = append(f.tokens, sets.New(strings.Fields(normalized)...))
+	}
+
+	parent := make([]int, len(failures))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	type link struct {
+		from       int
+		similarity float64
+	}
+	var links []link
+	for i := range failures {
+		for j := i + 1; j < len(failures); j++ {
+			if similarity, linked := linkFailures(failures[i], failures[j]); linked {
+				parent[find(j)] = find(i)
+				links = append(links, link{from: i, similarity: similarity})
+			}
+		}
+	}
+	minSimilarity := map[int]float64{}
+	for _, l := range links {
+		root := find(l.from)
+		if s, ok := minSimilarity[root]; !ok || l.similarity < s {
+			minSimilarity[root] = l.similarity
+		}
+	}
+
+	groups := map[int][]*regressionFailures{}
+	var roots []int
+	for i := range failures {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], failures[i])
+	}
+
+	suggestions := []TriageSuggestion{}
+	for _, root := range roots {
+		if len(groups[root]) < 2 {
+			continue
+		}
+		suggestions = append(suggestions, newTriageSuggestion(groups[root], minSimilarity[root]))
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return len(suggestions[i].Regressions) > len(suggestions[j].Regressions)
+	})
+	return suggestions
+}
+
+// linkFailures reports whether two regressions look like the same problem, and how similar their output was.
+func linkFailures(a, b *regressionFailures) (float64, bool) {
+	smaller := min(a.jobRuns.Len(), b.jobRuns.Len())
+	if smaller == 0 {
+		return 0, false
+	}
+	if float64(a.jobRuns.Intersection(b.jobRuns).Len())/float64(smaller) < minJobRunOverlap {
+		return 0, false
+	}
+	var best float64
+	for _, ta := range a.tokens {
+		for _, tb := range b.tokens {
+			best = max(best, jaccard(ta, tb))
+		}
+	}
+	return best, best >= minOutputSimilarity
+}
+
+func jaccard(a, b sets.Set[string]) float64 {
+	union := a.Union(b).Len()
+	if union == 0 {
+		return 0
+	}
+	return float64(a.Intersection(b).Len()) / float64(union)
+}
+
+func newTriageSuggestion(group []*regressionFailures, similarity float64) TriageSuggestion {
+	suggestion := TriageSuggestion{OutputSimilarity: similarity}
+	components := sets.New[string]()
+	runCounts := map[string]int{}
+	outputCounts := map[string]int{}
+	for _, f := range group {
+		suggestion.Regressions = append(suggestion.Regressions, *f.regression)
+		if f.regression.Component != "" {
+			components.Insert(f.regression.Component)
+		}
+		for run := range f.jobRuns {
+			runCounts[run]++
+		}
+		for _, o := range f.outputs {
+			outputCounts[o]++
+		}
+	}
+	for _, count := range runCounts {
+		if count > 1 {
+			suggestion.SharedJobRuns++
+		}
+	}
+	suggestion.Components = sets.List(components)
+
+	// The output shared by the most regressions best describes the group, ties going to the shortest.
+	candidates := slices.Collect(maps.Keys(outputCounts))
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if outputCounts[a] != outputCounts[b] {
+			return outputCounts[a] > outputCounts[b]
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	if len(candidates) > 0 {
+		suggestion.SampleOutput = candidates[0]
+	}
+	summary := suggestion.SampleOutput
+	if len(summary) > maxSampleOutputLength {
+		summary = truncateOnRune(summary, maxSampleOutputLength) + "..."
+	}
+	suggestion.SampleOutput = summary
+
+	scope := "tests"
+	if len(suggestion.Components) > 0 {
+		scope = strings.Join(suggestion.Components, ", ")
+	}
+	suggestion.SuggestedDescription = fmt.Sprintf("%d regressions in %s failing in %d shared job runs with: %s",
+		len(group), scope, suggestion.SharedJobRuns, summary)
+	return suggestion
+}
+
+var failureOutputReplacements = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`), "<uuid>"},
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[t ]\d{2}:\d{2}:\d{2}(\.\d+)?(z|[+-]\d{2}:?\d{2})?`), "<time>"},
+	{regexp.MustCompile(`\d{1,3}(\.\d{1,3}){3}(:\d+)?`), "<ip>"},
+	{regexp.MustCompile(`\b[0-9a-f]{8,}\b`), "<hex>"},
+	{regexp.MustCompile(`\d+(\.\d+)?`), "<n>"},
+	{regexp.MustCompile(`\s+`), " "},
+}
+
+// NormalizeFailureOutput strips the run specific details such as times, addresses and identifiers from test
+// output so the same failure in different job runs compares equal.
+func NormalizeFailureOutput(output string) string {
+	output = truncateOnRune(output, maxRawOutputLength)
+	output = strings.ToLower(output)
+	for _, r := range failureOutputReplacements {
+		output = r.re.ReplaceAllString(output, r.replacement)
+	}
+	return strings.TrimSpace(output)
+}
+
+// truncateOnRune shortens s to at most n bytes without splitting a multi-byte character.
+func truncateOnRune(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
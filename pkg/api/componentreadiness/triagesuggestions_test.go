@@ -0,0 +1,75 @@
+package componentreadiness
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/db/query"
+)
+
+func TestNormalizeFailureOutput(t *testing.T) {
+	a := NormalizeFailureOutput("2026-04-10T12:00:01Z  pod etcd-0 on 10.0.1.15:2379 failed: uid 3f2b8c1e-1111-2222-3333-444455556666 after 31.5s")
+	b := NormalizeFailureOutput("2026-04-11T08:13:59Z pod etcd-2 on 10.0.3.7:2379 failed: uid 9a9a9a9a-1111-2222-3333-444455556666 after 12s")
+	assert.Equal(t, a, b)
+	assert.Equal(t, "<time> pod etcd-<n> on <ip> failed: uid <uuid> after <n>s", a)
+}
+
+func TestTruncateOnRune(t *testing.T) {
+	assert.Equal(t, "short", truncateOnRune("short", 10))
+	assert.Equal(t, "abc", truncateOnRune("abcdef", 3))
+	// "é" is two bytes, so cutting at 2 would split it
+	assert.Equal(t, "a", truncateOnRune("aé", 2))
+
+	long := strings.Repeat("→", maxSampleOutputLength)
+	truncated := truncateOnRune(long, maxSampleOutputLength)
+	assert.True(t, utf8.ValidString(truncated))
+	assert.LessOrEqual(t, len(truncated), maxSampleOutputLength)
+}
+
+func TestClusterRegressions(t *testing.T) {
+	regressions := []*models.TestRegression{
+		{ID: 1, Component: "etcd", TestName: "etcd is available"},
+		{ID: 2, Component: "kube-apiserver", TestName: "apiserver is available"},
+		{ID: 3, Component: "etcd", TestName: "etcd is available"},
+		{ID: 4, Component: "networking", TestName: "pods can reach each other"},
+		{ID: 5, Component: "etcd", TestName: "etcd defrag succeeds"},
+	}
+	quorumLost := "etcdserver: leader changed on 10.0.%d.1, quorum lost"
+	outputs := []query.RegressionFailureOutput{
+		{RegressionID: 1, ProwJobRunID: "100", Output: fmt.Sprintf(quorumLost, 1)},
+		{RegressionID: 1, ProwJobRunID: "101", Output: fmt.Sprintf(quorumLost, 2)},
+		{RegressionID: 2, ProwJobRunID: "100", Output: fmt.Sprintf(quorumLost, 3)},
+		{RegressionID: 2, ProwJobRunID: "101", Output: ""},
+		// linked to 2, and so to 1, through shared runs and output
+		{RegressionID: 3, ProwJobRunID: "101", Output: fmt.Sprintf(quorumLost, 4)},
+		{RegressionID: 3, ProwJobRunID: "102", Output: fmt.Sprintf(quorumLost, 5)},
+		// same runs, different failure
+		{RegressionID: 4, ProwJobRunID: "100", Output: "dial tcp: i/o timeout reaching service endpoints"},
+		// same failure, different runs
+		{RegressionID: 5, ProwJobRunID: "200", Output: fmt.Sprintf(quorumLost, 6)},
+		// outputs for regressions not being clustered are ignored
+		{RegressionID: 99, ProwJobRunID: "100", Output: fmt.Sprintf(quorumLost, 7)},
+	}
+
+	suggestions := ClusterRegressions(regressions, outputs)
+	require.Len(t, suggestions, 1)
+	s := suggestions[0]
+	var ids []uint
+	for _, r := range s.Regressions {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []uint{1, 2, 3}, ids)
+	assert.Equal(t, []string{"etcd", "kube-apiserver"}, s.Components)
+	assert.Equal(t, 2, s.SharedJobRuns)
+	assert.InDelta(t, 1.0, s.OutputSimilarity, 0.0001)
+	assert.Equal(t, "etcdserver: leader changed on <ip>, quorum lost", s.SampleOutput)
+	assert.Contains(t, s.SuggestedDescription, "3 regressions in etcd, kube-apiserver")
+
+	assert.Empty(t, ClusterRegressions(regressions[3:], outputs))
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -156,6 +157,12 @@ func ParseComponentReportRequest(
 		if len(req.URL.Query()["keyTestName"]) > 0 {
 			opts.AdvancedOption.KeyTestNames = advOpts.KeyTestNames
 		}
+		if req.URL.Query().Get("comparisonMode") != "" {
+			opts.AdvancedOption.ComparisonMode = advOpts.ComparisonMode
+		}
+		if req.URL.Query().Has("excludedJobRunLabel") {
+			opts.AdvancedOption.ExcludedJobRunLabels = advOpts.ExcludedJobRunLabels
+		}
 	} else {
 		opts.AdvancedOption = advOpts
 	}
@@ -259,6 +266,28 @@ func GetViewReleaseOptions(
 	return opts, nil
 }
 
+// ViewRequestOptions returns the request options for a view's report as of now, as if only the view was requested.
+func ViewRequestOptions(releases []v1.Release, view crview.View, cacheOpts cache.RequestOptions) (reqopts.RequestOptions, error) {
+	baseRelease, err := GetViewReleaseOptions(releases, "basis", view.BaseRelease, cacheOpts.CRTimeRoundingFactor)
+	if err != nil {
+		return reqopts.RequestOptions{}, err
+	}
+	sampleRelease, err := GetViewReleaseOptions(releases, "sample", view.SampleRelease, cacheOpts.CRTimeRoundingFactor)
+	if err != nil {
+		return reqopts.RequestOptions{}, err
+	}
+	return reqopts.RequestOptions{
+		ViewName:       view.Name,
+		BaseRelease:    baseRelease,
+		SampleRelease:  sampleRelease,
+		VariantOption:  view.VariantOptions,
+		AdvancedOption: view.AdvancedOptions,
+		TestFilters:    view.TestFilters,
+		TestIDOptions:  []reqopts.TestIdentification{view.TestIDOption},
+		CacheOption:    cacheOpts,
+	}, nil
+}
+
 func parsePROptions(req *http.Request) *reqopts.PullRequest {
 	pro := reqopts.PullRequest{
 		Org:      param.SafeRead(req, "samplePROrg"),
@@ -428,9 +457,32 @@ func parseAdvancedOptions(req *http.Request) (advancedOption reqopts.Advanced, e
 	// all other test failures in that job are excluded from regression analysis
 	advancedOption.KeyTestNames = req.URL.Query()["keyTestName"]
 
+	// Parse labels of job runs to exclude, replacing the defaults. An empty value excludes nothing.
+	if labels, ok := req.URL.Query()["excludedJobRunLabel"]; ok {
+		advancedOption.ExcludedJobRunLabels = []string{}
+		for _, label := range labels {
+			if label != "" {
+				advancedOption.ExcludedJobRunLabels = append(advancedOption.ExcludedJobRunLabels, label)
+			}
+		}
+	}
+
+	advancedOption.ComparisonMode, err = parseComparisonMode(req)
 	return
 }
 
+// parseComparisonMode reads the statistical comparison to use for tests with basis data; empty means Fisher's Exact.
+func parseComparisonMode(req *http.Request) (crtest.Comparison, error) {
+	if req.URL.Query().Get("comparisonMode") == "" {
+		return "", nil
+	}
+	mode := crtest.Comparison(param.SafeRead(req, "comparisonMode"))
+	if slices.Contains(crtest.ComparisonModes(), mode) {
+		return mode, nil
+	}
+	return "", fmt.Errorf("comparisonMode must be one of: %s, %s", crtest.FisherExact, crtest.Bayesian)
+}
+
 func parseDateRange(allReleases []v1.Release, req *http.Request,
 	releaseOpts reqopts.Release,
 	startName string, endName string,
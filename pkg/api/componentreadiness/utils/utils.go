@@ -176,6 +176,17 @@ func addAdvancedOptionsParams(params url.Values, advancedOptions reqopts.Advance
 	params.Add("ignoreMissing", strconv.FormatBool(advancedOptions.IgnoreMissing))
 	params.Add("flakeAsFailure", strconv.FormatBool(advancedOptions.FlakeAsFailure))
 	params.Add("includeMultiReleaseAnalysis", strconv.FormatBool(advancedOptions.IncludeMultiReleaseAnalysis))
+	if advancedOptions.ComparisonMode != "" {
+		params.Add("comparisonMode", string(advancedOptions.ComparisonMode))
+	}
+	if advancedOptions.ExcludedJobRunLabels != nil {
+		if len(advancedOptions.ExcludedJobRunLabels) == 0 {
+			params.Add("excludedJobRunLabel", "")
+		}
+		for _, label := range advancedOptions.ExcludedJobRunLabels {
+			params.Add("excludedJobRunLabel", label)
+		}
+	}
 }
 
 // addVariantOptionsParams adds variant options to URL parameters
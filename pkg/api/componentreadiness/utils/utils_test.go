@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/crview"
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
 	v1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
@@ -182,6 +183,27 @@ func TestGenerateTestDetailsURL(t *testing.T) {
 		assert.NotContains(t, url, "testBasisRelease")
 	})
 
+	t.Run("URL generation with bayesian comparison mode", func(t *testing.T) {
+		advancedOptions := testView.AdvancedOptions
+		advancedOptions.ComparisonMode = crtest.Bayesian
+		url, err := GenerateTestDetailsURL(
+			"openshift-tests:abc123",
+			"https://sippy.example.com",
+			"", // viewName
+			getBaseReleaseOpts(),
+			getSampleReleaseOpts(),
+			advancedOptions,
+			testView.VariantOptions,
+			reqopts.TestFilters{},
+			"",
+			"",
+			[]string{"Architecture:amd64", "Platform:aws"},
+			"",
+		)
+		require.NoError(t, err)
+		assert.Contains(t, url, "comparisonMode=bayesian")
+	})
+
 	t.Run("URL generation with release fallback", func(t *testing.T) {
 		url, err := GenerateTestDetailsURL(
 			"openshift-tests:abc123",
@@ -605,4 +627,19 @@ func TestGenerateTestDetailsURL(t *testing.T) {
 		assert.Contains(t, url, "sampleEndTime=")
 	})
 
+	t.Run("excluded job run labels are only included when configured", func(t *testing.T) {
+		generate := func(labels []string) string {
+			advancedOptions := testView.AdvancedOptions
+			advancedOptions.ExcludedJobRunLabels = labels
+			url, err := GenerateTestDetailsURL("test-id", "https://sippy.example.com", "", getBaseReleaseOpts(), getSampleReleaseOpts(),
+				advancedOptions, testView.VariantOptions, reqopts.TestFilters{}, "", "", nil, "")
+			require.NoError(t, err)
+			return url
+		}
+		assert.NotContains(t, generate(nil), "excludedJobRunLabel")
+		assert.Contains(t, generate([]string{}), "excludedJobRunLabel=&")
+		url := generate([]string{"InfraFailure", "ClusterDNSFlake"})
+		assert.Contains(t, url, "excludedJobRunLabel=InfraFailure")
+		assert.Contains(t, url, "excludedJobRunLabel=ClusterDNSFlake")
+	})
 }
@@ -0,0 +1,160 @@
+package componentreadiness
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	sippyapi "github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/webhooks"
+)
+
+const (
+	webhookLink           = "%s/api/component_readiness/webhooks/%d"
+	webhookDeliveriesLink = "%s/api/component_readiness/webhooks/%d/deliveries"
+
+	maxWebhookDeliveries = 100
+)
+
+func ListWebhookSubscriptions(dbc *db.DB, req *http.Request) ([]models.WebhookSubscription, error) {
+	subscriptions := []models.WebhookSubscription{}
+	if res := dbc.DB.Order("id").Find(&subscriptions); res.Error != nil {
+		log.WithError(res.Error).Error("error listing webhook subscriptions")
+		return nil, res.Error
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+		injectWebhookHATEOASLinks(&subscriptions[i], sippyapi.GetBaseURL(req))
+	}
+	return subscriptions, nil
+}
+
+func GetWebhookSubscription(dbc *db.DB, id int, req *http.Request) (*models.WebhookSubscription, error) {
+	subscription := &models.WebhookSubscription{}
+	res := dbc.DB.First(subscription, id)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.WithError(res.Error).Errorf("error looking up webhook subscription: %d", id)
+		return nil, res.Error
+	}
+	subscription.Secret = ""
+	injectWebhookHATEOASLinks(subscription, sippyapi.GetBaseURL(req))
+	return subscription, nil
+}
+
+func validateWebhookSubscription(subscription models.WebhookSubscription, update bool, allowedHosts []string) error {
+	if !update && subscription.ID > 0 {
+		return fmt.Errorf("cannot specify an id for a new webhook subscription, one will be autogenerated")
+	}
+	if update && subscription.ID == 0 {
+		return fmt.Errorf("must specify an id for a webhook subscription update")
+	}
+	if subscription.Name == "" {
+		return fmt.Errorf("name is required for a webhook subscription")
+	}
+	if err := webhooks.ValidateURL(subscription.URL, allowedHosts); err != nil {
+		return err
+	}
+	for _, t := range subscription.EventTypes {
+		if !webhooks.ValidEventType(t) {
+			return fmt.Errorf("invalid event type %q, must be one of %v", t, webhooks.EventTypes)
+		}
+	}
+	for _, v := range subscription.Variants {
+		if name, value, ok := strings.Cut(v, ":"); !ok || name == "" || value == "" {
+			return fmt.Errorf("invalid variant %q, expected Name:Value", v)
+		}
+	}
+	return nil
+}
+
+// CreateWebhookSubscription stores a new subscription, generating a signing secret if none was given. The
+// returned subscription is the only time the secret is shown. Its URL must be on one of allowedHosts.
+func CreateWebhookSubscription(dbc *gorm.DB, subscription models.WebhookSubscription, user string, allowedHosts []string, req *http.Request) (models.WebhookSubscription, error) {
+	if err := validateWebhookSubscription(subscription, false, allowedHosts); err != nil {
+		log.WithError(err).Error("error validating webhook subscription")
+		return subscription, err
+	}
+	if subscription.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return subscription, fmt.Errorf("error generating webhook secret: %w", err)
+		}
+		subscription.Secret = hex.EncodeToString(secret)
+	}
+	subscription.CreatedBy = user
+	subscription.CreatedAt = time.Time{}
+	subscription.UpdatedAt = time.Time{}
+
+	if res := dbc.Create(&subscription); res.Error != nil {
+		log.WithError(res.Error).Error("error creating webhook subscription")
+		return subscription, res.Error
+	}
+	log.WithField("subscriptionID", subscription.ID).Info("webhook subscription created")
+	injectWebhookHATEOASLinks(&subscription, sippyapi.GetBaseURL(req))
+	return subscription, nil
+}
+
+// UpdateWebhookSubscription replaces an existing subscription, keeping its secret unless a new one is given.
+func UpdateWebhookSubscription(dbc *gorm.DB, subscription models.WebhookSubscription, allowedHosts []string, req *http.Request) (models.WebhookSubscription, error) {
+	if err := validateWebhookSubscription(subscription, true, allowedHosts); err != nil {
+		log.WithError(err).Error("error validating webhook subscription")
+		return subscription, err
+	}
+	existing := models.WebhookSubscription{}
+	if res := dbc.First(&existing, subscription.ID); res.Error != nil {
+		log.WithError(res.Error).Errorf("error looking up existing webhook subscription: %d", subscription.ID)
+		return subscription, res.Error
+	}
+	subscription.CreatedAt = existing.CreatedAt
+	subscription.CreatedBy = existing.CreatedBy
+	if subscription.Secret == "" {
+		subscription.Secret = existing.Secret
+	}
+
+	if res := dbc.Save(&subscription); res.Error != nil {
+		log.WithError(res.Error).Error("error updating webhook subscription")
+		return subscription, res.Error
+	}
+	subscription.Secret = ""
+	injectWebhookHATEOASLinks(&subscription, sippyapi.GetBaseURL(req))
+	return subscription, nil
+}
+
+func DeleteWebhookSubscription(dbc *gorm.DB, id int) error {
+	existing := &models.WebhookSubscription{}
+	if res := dbc.First(existing, id).Delete(existing); res.Error != nil {
+		return fmt.Errorf("error deleting webhook subscription: %v", res.Error)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the most recent deliveries for a subscription, to help debug an endpoint.
+func ListWebhookDeliveries(dbc *db.DB, id int) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	res := dbc.DB.Where("subscription_id = ?", id).Order("id DESC").Limit(maxWebhookDeliveries).Find(&deliveries)
+	if res.Error != nil {
+		log.WithError(res.Error).Errorf("error listing deliveries for webhook subscription: %d", id)
+		return nil, res.Error
+	}
+	return deliveries, nil
+}
+
+// injectWebhookHATEOASLinks adds restful links clients can follow for this webhook subscription.
+func injectWebhookHATEOASLinks(subscription *models.WebhookSubscription, baseURL string) {
+	subscription.Links = map[string]string{
+		"self":       fmt.Sprintf(webhookLink, baseURL, subscription.ID),
+		"deliveries": fmt.Sprintf(webhookDeliveriesLink, baseURL, subscription.ID),
+	}
+}
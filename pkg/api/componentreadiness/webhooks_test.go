@@ -0,0 +1,41 @@
+package componentreadiness
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+func TestValidateWebhookSubscription(t *testing.T) {
+	allowedHosts := []string{"example.com"}
+	valid := models.WebhookSubscription{
+		Name:       "etcd alerts",
+		URL:        "https://chat.example.com/hooks/etcd",
+		EventTypes: pq.StringArray{"regression.opened", "triage.resolved"},
+		Variants:   pq.StringArray{"Platform:aws"},
+	}
+	assert.NoError(t, validateWebhookSubscription(valid, false, allowedHosts))
+
+	withID := valid
+	withID.ID = 4
+	assert.Error(t, validateWebhookSubscription(withID, false, allowedHosts))
+	assert.NoError(t, validateWebhookSubscription(withID, true, allowedHosts))
+	assert.Error(t, validateWebhookSubscription(valid, true, allowedHosts), "updates need an id")
+
+	for name, modify := range map[string]func(*models.WebhookSubscription){
+		"missing name":       func(s *models.WebhookSubscription) { s.Name = "" },
+		"relative url":       func(s *models.WebhookSubscription) { s.URL = "/hooks/etcd" },
+		"unsupported scheme": func(s *models.WebhookSubscription) { s.URL = "ftp://example.com/hooks" },
+		"plain http":         func(s *models.WebhookSubscription) { s.URL = "http://chat.example.com/hooks/etcd" },
+		"host not allowed":   func(s *models.WebhookSubscription) { s.URL = "https://10.0.0.1/hooks/etcd" },
+		"unknown event":      func(s *models.WebhookSubscription) { s.EventTypes = pq.StringArray{"regression.exploded"} },
+		"malformed variant":  func(s *models.WebhookSubscription) { s.Variants = pq.StringArray{"aws"} },
+	} {
+		sub := valid
+		modify(&sub)
+		assert.Error(t, validateWebhookSubscription(sub, false, allowedHosts), name)
+	}
+}
@@ -0,0 +1,323 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	fischer "github.com/glycerine/golang-fisher-exact"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
+	bqclient "github.com/openshift/sippy/pkg/bigquery"
+	"github.com/openshift/sippy/pkg/bigquery/bqlabel"
+)
+
+const (
+	DisruptionRegressed      = "regressed"
+	DisruptionImproved       = "improved"
+	DisruptionNotSignificant = "not_significant"
+	DisruptionInsufficient   = "insufficient_data"
+
+	// minimumDisruptionJobRuns is how many job runs each window needs before a backend's comparison is tested for
+	// significance.
+	minimumDisruptionJobRuns = 10
+
+	defaultDisruptionConfidence = 95
+
+	disruptionComparisonLink = "%s/api/disruption/compare?%s"
+)
+
+// GetDisruptionComparisonFromBigQuery compares disruption per backend between the base and sample releases of the
+// request options, for job runs matching its included variants.
+func GetDisruptionComparisonFromBigQuery(ctx context.Context, client *bqclient.Client, opts reqopts.RequestOptions, baseURL string) (apitype.DisruptionComparison, []error) {
+	generator := disruptionComparisonGenerator{
+		client:          client,
+		View:            opts.ViewName,
+		Base:            disruptionWindow(opts.BaseRelease),
+		Sample:          disruptionWindow(opts.SampleRelease),
+		IncludeVariants: opts.VariantOption.IncludeVariants,
+		Confidence:      opts.AdvancedOption.Confidence,
+	}
+	if generator.Confidence == 0 {
+		generator.Confidence = defaultDisruptionConfidence
+	}
+
+	comparison, errs := GetDataFromCacheOrGenerate[apitype.DisruptionComparison](ctx, client.Cache, opts.CacheOption,
+		NewCacheSpec(generator, "DisruptionComparison~", &generator.Sample.End), generator.GenerateComparison, apitype.DisruptionComparison{})
+	if len(errs) > 0 {
+		return comparison, errs
+	}
+	comparison.Links = map[string]string{"self": fmt.Sprintf(disruptionComparisonLink, baseURL, generator.queryParams().Encode())}
+	return comparison, nil
+}
+
+func disruptionWindow(release reqopts.Release) apitype.DisruptionWindow {
+	return apitype.DisruptionWindow{Release: release.Name, Start: release.Start, End: release.End}
+}
+
+type disruptionComparisonGenerator struct {
+	client          *bqclient.Client
+	View            string
+	Base            apitype.DisruptionWindow
+	Sample          apitype.DisruptionWindow
+	IncludeVariants map[string][]string
+	Confidence      int
+}
+
+// disruptionStatsRow is the disruption of one backend in a window. DisruptionSeconds has the disruption of each job
+// run, for testing whether its distribution changed.
+type disruptionStatsRow struct {
+	BackendName        string    `bigquery:"backend_name"`
+	JobRuns            int       `bigquery:"job_runs"`
+	RunsWithDisruption int       `bigquery:"runs_with_disruption"`
+	P50                float64   `bigquery:"p50"`
+	P95                float64   `bigquery:"p95"`
+	P99                float64   `bigquery:"p99"`
+	DisruptionSeconds  []float64 `bigquery:"disruption_seconds"`
+}
+
+func (c *disruptionComparisonGenerator) GenerateComparison(ctx context.Context) (apitype.DisruptionComparison, []error) {
+	before := time.Now()
+	base, err := c.queryDisruptionStats(ctx, c.Base)
+	if err != nil {
+		return apitype.DisruptionComparison{}, []error{err}
+	}
+	sample, err := c.queryDisruptionStats(ctx, c.Sample)
+	if err != nil {
+		return apitype.DisruptionComparison{}, []error{err}
+	}
+	log.Infof("Disruption comparison fetched from bigquery in %s with %d base and %d sample backends", time.Since(before), len(base), len(sample))
+
+	return apitype.DisruptionComparison{
+		View:            c.View,
+		Base:            c.Base,
+		Sample:          c.Sample,
+		IncludeVariants: c.IncludeVariants,
+		Confidence:      c.Confidence,
+		Backends:        compareDisruptionStats(base, sample, c.Confidence),
+	}, nil
+}
+
+func (c *disruptionComparisonGenerator) queryDisruptionStats(ctx context.Context, window apitype.DisruptionWindow) ([]disruptionStatsRow, error) {
+	params := []bigquery.QueryParameter{
+		{Name: "Release", Value: window.Release},
+		{Name: "From", Value: window.Start},
+		{Name: "To", Value: window.End},
+	}
+	joins := fmt.Sprintf("LEFT JOIN %s.job_variants jv_Release ON jobs.JobName = jv_Release.job_name AND jv_Release.variant_name = 'Release'\n", c.client.Dataset)
+	var filters string
+	for _, name := range sortedVariantNames(c.IncludeVariants) {
+		if name == "Release" {
+			continue
+		}
+		paramName := "Variant" + strings.ReplaceAll(name, "-", "_")
+		joins += fmt.Sprintf("LEFT JOIN %s.job_variants jv_%s ON jobs.JobName = jv_%s.job_name AND jv_%s.variant_name = '%s'\n",
+			c.client.Dataset, name, name, name, name)
+		filters += fmt.Sprintf(" AND jv_%s.variant_value IN UNNEST(@%s)", name, paramName)
+		params = append(params, bigquery.QueryParameter{Name: paramName, Value: c.IncludeVariants[name]})
+	}
+
+	queryString := fmt.Sprintf(`
+		SELECT
+			disruption.BackendName AS backend_name,
+			COUNT(*) AS job_runs,
+			COUNTIF(disruption.DisruptionSeconds > 0) AS runs_with_disruption,
+			APPROX_QUANTILES(disruption.DisruptionSeconds, 100)[OFFSET(50)] AS p50,
+			APPROX_QUANTILES(disruption.DisruptionSeconds, 100)[OFFSET(95)] AS p95,
+			APPROX_QUANTILES(disruption.DisruptionSeconds, 100)[OFFSET(99)] AS p99,
+			ARRAY_AGG(CAST(IFNULL(disruption.DisruptionSeconds, 0) AS FLOAT64)) AS disruption_seconds
+		FROM openshift-ci-data-analysis.ci_data.BackendDisruption disruption
+		JOIN openshift-ci-data-analysis.ci_data.BackendDisruption_JobRuns jobs ON disruption.JobRunName = jobs.Name
+		%s
+		WHERE jobs.StartTime >= @From AND jobs.StartTime < @To
+			AND jv_Release.variant_value = @Release%s
+		GROUP BY backend_name`, joins, filters)
+
+	query := c.client.Query(ctx, bqlabel.DisruptionComparison, queryString)
+	query.Parameters = params
+	it, err := query.Read(ctx)
+	if err != nil {
+		log.WithError(err).Error("error querying disruption comparison from bigquery")
+		return nil, err
+	}
+
+	var rows []disruptionStatsRow
+	for {
+		r := disruptionStatsRow{}
+		err := it.Next(&r)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			log.WithError(err).Error("error parsing disruption comparison row from bigquery")
+			return nil, err
+		}
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+func (c *disruptionComparisonGenerator) queryParams() url.Values {
+	if c.View != "" {
+		return url.Values{"view": {c.View}}
+	}
+	params := url.Values{
+		"baseRelease":     {c.Base.Release},
+		"baseStartTime":   {c.Base.Start.UTC().Format(time.RFC3339)},
+		"baseEndTime":     {c.Base.End.UTC().Format(time.RFC3339)},
+		"sampleRelease":   {c.Sample.Release},
+		"sampleStartTime": {c.Sample.Start.UTC().Format(time.RFC3339)},
+		"sampleEndTime":   {c.Sample.End.UTC().Format(time.RFC3339)},
+		"confidence":      {fmt.Sprint(c.Confidence)},
+	}
+	for _, name := range sortedVariantNames(c.IncludeVariants) {
+		for _, v := range c.IncludeVariants[name] {
+			params.Add("includeVariant", name+":"+v)
+		}
+	}
+	return params
+}
+
+// compareDisruptionStats pairs the backends seen in either window, testing whether the disruption rate, the fraction
+// of job runs with any disruption, or the distribution of disruption seconds per job run changed significantly. When
+// the seconds changed, their direction decides the status. Regressions are listed first.
+func compareDisruptionStats(base, sample []disruptionStatsRow, confidence int) []apitype.DisruptionBackendComparison {
+	byBackend := map[string]*apitype.DisruptionBackendComparison{}
+	baseSeconds, sampleSeconds := map[string][]float64{}, map[string][]float64{}
+	get := func(name string) *apitype.DisruptionBackendComparison {
+		if _, ok := byBackend[name]; !ok {
+			byBackend[name] = &apitype.DisruptionBackendComparison{BackendName: name}
+		}
+		return byBackend[name]
+	}
+	for _, r := range base {
+		get(r.BackendName).Base = r.stats()
+		baseSeconds[r.BackendName] = r.DisruptionSeconds
+	}
+	for _, r := range sample {
+		get(r.BackendName).Sample = r.stats()
+		sampleSeconds[r.BackendName] = r.DisruptionSeconds
+	}
+
+	alpha := 1 - float64(confidence)/100
+	comparisons := make([]apitype.DisruptionBackendComparison, 0, len(byBackend))
+	for _, c := range byBackend {
+		c.P50Delta = c.Sample.P50 - c.Base.P50
+		c.P95Delta = c.Sample.P95 - c.Base.P95
+		c.P99Delta = c.Sample.P99 - c.Base.P99
+		c.Status = DisruptionInsufficient
+		if c.Base.JobRuns >= minimumDisruptionJobRuns && c.Sample.JobRuns >= minimumDisruptionJobRuns {
+			_, _, _, twoSided := fischer.FisherExactTest(c.Sample.RunsWithDisruption, c.Sample.JobRuns-c.Sample.RunsWithDisruption,
+				c.Base.RunsWithDisruption, c.Base.JobRuns-c.Base.RunsWithDisruption)
+			c.RateFisherExact = twoSided
+			sampleHigher, secondsP := mannWhitneyU(sampleSeconds[c.BackendName], baseSeconds[c.BackendName])
+			c.SecondsMannWhitney = secondsP
+			c.Significant = c.RateFisherExact < alpha || c.SecondsMannWhitney < alpha
+			c.Status = DisruptionNotSignificant
+			if c.Significant {
+				regressed := sampleHigher
+				if c.SecondsMannWhitney >= alpha {
+					sampleRate := float64(c.Sample.RunsWithDisruption) / float64(c.Sample.JobRuns)
+					baseRate := float64(c.Base.RunsWithDisruption) / float64(c.Base.JobRuns)
+					regressed = sampleRate > baseRate
+				}
+				if regressed {
+					c.Status = DisruptionRegressed
+				} else {
+					c.Status = DisruptionImproved
+				}
+			}
+		}
+		comparisons = append(comparisons, *c)
+	}
+
+	statusOrder := map[string]int{DisruptionRegressed: 0, DisruptionImproved: 1, DisruptionNotSignificant: 2, DisruptionInsufficient: 3}
+	sort.Slice(comparisons, func(i, j int) bool {
+		a, b := comparisons[i], comparisons[j]
+		if statusOrder[a.Status] != statusOrder[b.Status] {
+			return statusOrder[a.Status] < statusOrder[b.Status]
+		}
+		if a.P95Delta != b.P95Delta {
+			return a.P95Delta > b.P95Delta
+		}
+		return a.BackendName < b.BackendName
+	})
+	return comparisons
+}
+
+// mannWhitneyU tests whether the values of a and b come from the same distribution, returning whether a tends to be
+// higher and the two-sided p-value. It uses the normal approximation with tie and continuity corrections, which
+// suits the job run counts compared here.
+func mannWhitneyU(a, b []float64) (bool, float64) {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 == 0 || n2 == 0 {
+		return false, 1
+	}
+	type value struct {
+		v     float64
+		fromA bool
+	}
+	values := make([]value, 0, len(a)+len(b))
+	for _, v := range a {
+		values = append(values, value{v: v, fromA: true})
+	}
+	for _, v := range b {
+		values = append(values, value{v: v})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].v < values[j].v })
+
+	// tied values share the average of the ranks they span
+	var rankSumA, ties float64
+	for i := 0; i < len(values); {
+		j := i
+		for j < len(values) && values[j].v == values[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if values[k].fromA {
+				rankSumA += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	n := n1 + n2
+	u := rankSumA - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return false, 1
+	}
+	z := math.Max(math.Abs(u-mean)-0.5, 0) / sigma
+	return u > mean, math.Erfc(z / math.Sqrt2)
+}
+
+func (r disruptionStatsRow) stats() apitype.DisruptionStats {
+	return apitype.DisruptionStats{
+		JobRuns:            r.JobRuns,
+		RunsWithDisruption: r.RunsWithDisruption,
+		P50:                r.P50,
+		P95:                r.P95,
+		P99:                r.P99,
+	}
+}
+
+func sortedVariantNames(variants map[string][]string) []string {
+	names := make([]string, 0, len(variants))
+	for name := range variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+)
+
+// disruptionRuns is the disruption seconds of jobRuns job runs, disrupted of which saw the given seconds.
+func disruptionRuns(jobRuns, disrupted int, seconds float64) []float64 {
+	runs := make([]float64, jobRuns)
+	for i := 0; i < disrupted; i++ {
+		runs[i] = seconds
+	}
+	return runs
+}
+
+func TestCompareDisruptionStats(t *testing.T) {
+	base := []disruptionStatsRow{
+		{BackendName: "kube-api-new-connections", JobRuns: 200, RunsWithDisruption: 10, P50: 0, P95: 1, P99: 3, DisruptionSeconds: disruptionRuns(200, 10, 1)},
+		{BackendName: "ingress-to-console-new-connections", JobRuns: 200, RunsWithDisruption: 60, P50: 0, P95: 8, P99: 20, DisruptionSeconds: disruptionRuns(200, 60, 8)},
+		{BackendName: "oauth-api-reused-connections", JobRuns: 200, RunsWithDisruption: 20, P95: 2, DisruptionSeconds: disruptionRuns(200, 20, 2)},
+		{BackendName: "cache-api-new-connections", JobRuns: 5, RunsWithDisruption: 0, DisruptionSeconds: disruptionRuns(5, 0, 0)},
+		{BackendName: "image-registry-new-connections", JobRuns: 200, RunsWithDisruption: 100, P50: 1, P95: 1, P99: 1, DisruptionSeconds: disruptionRuns(200, 100, 1)},
+	}
+	sample := []disruptionStatsRow{
+		{BackendName: "kube-api-new-connections", JobRuns: 150, RunsWithDisruption: 60, P50: 1, P95: 12, P99: 30, DisruptionSeconds: disruptionRuns(150, 60, 12)},
+		{BackendName: "ingress-to-console-new-connections", JobRuns: 150, RunsWithDisruption: 5, P95: 1, P99: 2, DisruptionSeconds: disruptionRuns(150, 5, 1)},
+		{BackendName: "oauth-api-reused-connections", JobRuns: 150, RunsWithDisruption: 16, P95: 3, DisruptionSeconds: disruptionRuns(150, 16, 2)},
+		{BackendName: "cache-api-new-connections", JobRuns: 150, RunsWithDisruption: 90, P95: 40, DisruptionSeconds: disruptionRuns(150, 90, 40)},
+		{BackendName: "image-registry-new-connections", JobRuns: 150, RunsWithDisruption: 75, P50: 30, P95: 30, P99: 30, DisruptionSeconds: disruptionRuns(150, 75, 30)},
+	}
+
+	comparisons := compareDisruptionStats(base, sample, 95)
+	require.Len(t, comparisons, 5)
+
+	assert.Equal(t, "image-registry-new-connections", comparisons[0].BackendName)
+	assert.Equal(t, DisruptionRegressed, comparisons[0].Status, "same disruption rate, but longer disruption")
+	assert.Greater(t, comparisons[0].RateFisherExact, 0.05)
+	assert.Less(t, comparisons[0].SecondsMannWhitney, 0.05)
+
+	assert.Equal(t, "kube-api-new-connections", comparisons[1].BackendName)
+	assert.Equal(t, DisruptionRegressed, comparisons[1].Status)
+	assert.True(t, comparisons[1].Significant)
+	assert.Equal(t, 11.0, comparisons[1].P95Delta)
+	assert.Equal(t, 27.0, comparisons[1].P99Delta)
+
+	assert.Equal(t, "ingress-to-console-new-connections", comparisons[2].BackendName)
+	assert.Equal(t, DisruptionImproved, comparisons[2].Status)
+
+	assert.Equal(t, "oauth-api-reused-connections", comparisons[3].BackendName)
+	assert.Equal(t, DisruptionNotSignificant, comparisons[3].Status)
+	assert.False(t, comparisons[3].Significant)
+
+	assert.Equal(t, "cache-api-new-connections", comparisons[4].BackendName)
+	assert.Equal(t, DisruptionInsufficient, comparisons[4].Status, "too few base job runs to test")
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	higher, p := mannWhitneyU([]float64{1, 2, 3}, []float64{4, 5, 6})
+	assert.False(t, higher)
+	assert.InDelta(t, 0.081, p, 0.001)
+
+	higher, p = mannWhitneyU([]float64{4, 5, 6}, []float64{1, 2, 3})
+	assert.True(t, higher)
+	assert.InDelta(t, 0.081, p, 0.001)
+
+	_, p = mannWhitneyU(disruptionRuns(20, 0, 0), disruptionRuns(20, 0, 0))
+	assert.Equal(t, 1.0, p, "no disruption in either window")
+}
+
+func TestDisruptionComparisonQueryParams(t *testing.T) {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	generator := disruptionComparisonGenerator{
+		Base:            apitype.DisruptionWindow{Release: "4.21", Start: start, End: start.AddDate(0, 0, 14)},
+		Sample:          apitype.DisruptionWindow{Release: "4.22", Start: start.AddDate(0, 1, 0), End: start.AddDate(0, 1, 7)},
+		IncludeVariants: map[string][]string{"Platform": {"aws", "gcp"}, "Network": {"ovn"}},
+		Confidence:      95,
+	}
+	params := generator.queryParams()
+	assert.Equal(t, []string{"Network:ovn", "Platform:aws", "Platform:gcp"}, params["includeVariant"])
+	assert.Equal(t, "2026-10-01T00:00:00Z", params.Get("sampleStartTime"))
+
+	generator.View = "4.22-main"
+	assert.Equal(t, "view=4.22-main", generator.queryParams().Encode(), "views link by name")
+}
@@ -411,18 +411,7 @@ func JobRunRiskAnalysis(
 	}
 
 	// Pre-load test bugs as well:
-	if len(jobRun.Tests) <= maxFailuresToFullyAnalyze {
-		for i, tr := range jobRun.Tests {
-			bugs, err := query.LoadBugsForTest(dbc, tr.Test.Name, true)
-			if err != nil {
-				logger.WithError(err).Errorf("Error evaluating bugs for prow job: %d, test name: %s", jobRun.ProwJob.ID, tr.Test.Name)
-			} else {
-				logger.Debugf("Found %d bugs for test '%s'", len(bugs), tr.Test.Name)
-				tr.Test.Bugs = bugs
-				jobRun.Tests[i] = tr
-			}
-		}
-	}
+	loadFailedTestBugs(dbc, jobRun, logger)
 
 	return runJobRunAnalysis(ctx,
 		bqc, jobRun, compareRelease, historicalCount, neverStableJob, jobNames, logger,
@@ -432,6 +421,23 @@ func JobRunRiskAnalysis(
 	)
 }
 
+// loadFailedTestBugs loads the bugs of each failed test, unless there are too many failures to fully analyze.
+func loadFailedTestBugs(dbc *db.DB, jobRun *models.ProwJobRun, logger *log.Entry) {
+	if len(jobRun.Tests) > maxFailuresToFullyAnalyze {
+		return
+	}
+	for i, tr := range jobRun.Tests {
+		bugs, err := query.LoadBugsForTest(dbc, tr.Test.Name, true)
+		if err != nil {
+			logger.WithError(err).Errorf("Error evaluating bugs for prow job: %d, test name: %s", jobRun.ProwJob.ID, tr.Test.Name)
+		} else {
+			logger.Debugf("Found %d bugs for test '%s'", len(bugs), tr.Test.Name)
+			tr.Test.Bugs = bugs
+			jobRun.Tests[i] = tr
+		}
+	}
+}
+
 // testResultsByJobNameFunc is used for injecting db responses in unit tests.
 type testResultsByJobNameFunc func(testName string, jobNames []string) (*apitype.Test, error)
 
@@ -44,13 +44,14 @@ func (m *lineMatcher) PostProcessMatch(fullArtifact JobRunArtifact) JobRunArtifa
 		Truncated: clMatches.Truncated,
 	}
 
-	// if we have more matches than requested, trim them down
-	if len(clMatches.Matches) > m.maxFileMatches {
-		clMatches.Matches = clMatches.Matches[:m.maxFileMatches]
+	// if we have more matches than requested, trim them down (without modifying the full artifact, which may be shared)
+	matches := clMatches.Matches
+	if len(matches) > m.maxFileMatches {
+		matches = matches[:m.maxFileMatches]
 		trimmed.Truncated = true
 	}
 	// if we have more context lines than requested, trim them down
-	for _, match := range clMatches.Matches {
+	for _, match := range matches {
 		beforeLines := match.Before
 		if len(beforeLines) > m.contextBefore {
 			beforeLines = beforeLines[len(beforeLines)-m.contextBefore:]
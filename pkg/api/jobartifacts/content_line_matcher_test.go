@@ -119,4 +119,8 @@ func TestPostProcessMatch(t *testing.T) {
 	assert.Equal(t, []string{"line before 3\n"}, processedMatches.Matches[1].Before)
 	assert.Equal(t, "another test line\n", processedMatches.Matches[1].Match)
 	assert.Equal(t, []string{"line after 3\n"}, processedMatches.Matches[1].After)
+
+	// the full artifact may be shared between coalesced queries, so it must be left intact
+	assert.Len(t, fullArtifact.MatchedContent.ContentLineMatches.Matches, 3)
+	assert.False(t, fullArtifact.MatchedContent.ContentLineMatches.Truncated)
 }
@@ -11,6 +11,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"github.com/openshift/sippy/pkg/util"
+	"github.com/openshift/sippy/pkg/util/singleflight"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -30,6 +31,8 @@ type Manager struct {
 	jobRunWorkers   *sync.WaitGroup
 	artifactChan    chan artifactRequest
 	artifactWorkers *sync.WaitGroup
+	// jobRuns coalesces concurrent scans of the same job run for the same query
+	jobRuns singleflight.Group[JobRun]
 }
 
 // NewManager creates a new Manager with a pool of workers to process job run artifact queries
@@ -36,6 +36,32 @@ type JobArtifactQuery struct {
 func (q *JobArtifactQuery) queryJobArtifacts(ctx context.Context, jobRunID int64, mgr *Manager, logger *log.Entry) (JobRun, error) {
 	logger = logger.WithField("func", "queryJobArtifacts").WithField("job_run_id", jobRunID)
 
+	// identical queries for the same run share one scan; the cache key leaves out what post-processing trims, so
+	// queries differing only in match limits share it too. The shared scan must not end when the caller that
+	// started it goes away, so it gets its own timeout instead of that caller's cancellation.
+	jobRunResponse, _, err := mgr.jobRuns.Do(q.CacheKeyForJobRun(jobRunID), func() (JobRun, error) {
+		scanCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), artifactQueryTimeout)
+		defer cancel()
+		return q.scanJobArtifacts(scanCtx, jobRunID, mgr, logger)
+	})
+	if err != nil {
+		return jobRunResponse, err
+	}
+
+	// post-process matches down to what was actually requested, into a new slice since the scan result is shared
+	if q.ContentMatcher != nil {
+		artifacts := make([]JobRunArtifact, len(jobRunResponse.Artifacts))
+		for i := range jobRunResponse.Artifacts {
+			artifacts[i] = q.ContentMatcher.PostProcessMatch(jobRunResponse.Artifacts[i])
+		}
+		jobRunResponse.Artifacts = artifacts
+	}
+
+	return jobRunResponse, nil
+}
+
+// scanJobArtifacts finds and scans the run's matching artifacts, reusing and filling out any cached result.
+func (q *JobArtifactQuery) scanJobArtifacts(ctx context.Context, jobRunID int64, mgr *Manager, logger *log.Entry) (JobRun, error) {
 	// check the cache first
 	jobRunResponse, err := q.GetCachedJobRun(ctx, jobRunID)
 	if err != nil { // cache miss, look up the job run from scratch
@@ -66,14 +92,7 @@ func (q *JobArtifactQuery) queryJobArtifacts(ctx context.Context, jobRunID int64
 		jobRunResponse.Artifacts = append(completedArtifacts, newArtifacts...)
 	}
 
-	// cache the response and then post-process matches down to what was actually requested
 	_ = q.SetJobRunCache(ctx, jobRunID, jobRunResponse)
-	if q.ContentMatcher != nil {
-		for i := range jobRunResponse.Artifacts {
-			jobRunResponse.Artifacts[i] = q.ContentMatcher.PostProcessMatch(jobRunResponse.Artifacts[i])
-		}
-	}
-
 	return jobRunResponse, nil
 }
 
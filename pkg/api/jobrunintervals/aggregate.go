@@ -0,0 +1,365 @@
+package jobrunintervals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api"
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/apis/cache"
+	"github.com/openshift/sippy/pkg/db"
+)
+
+const (
+	// DefaultAggregateRuns and MaxAggregateRuns bound how many job runs have their intervals loaded from GCS.
+	DefaultAggregateRuns = 25
+	MaxAggregateRuns     = 100
+
+	// aggregateWorkers is how many job runs have their intervals loaded at once.
+	aggregateWorkers = 5
+
+	// runSummaryCacheExpiry is long as the intervals of a finished job run do not change.
+	runSummaryCacheExpiry = 7 * 24 * time.Hour
+
+	e2eTestSource   = "E2ETest"
+	e2eFailedStatus = "Failed"
+
+	aggregateLink = "%s/api/jobs/runs/intervals/aggregate?%s"
+	intervalsLink = "%s/api/jobs/runs/intervals?%s"
+)
+
+// errNoIntervalFiles is returned for a job run without interval files, which may not have been uploaded yet. Such runs
+// are skipped rather than cached as having no intervals.
+var errNoIntervalFiles = errors.New("no interval files found")
+
+// stableLocatorKeys are the locator keys that identify the same thing across job runs. Other keys, such as pods and
+// nodes, are named differently in every run and would prevent intervals from being grouped.
+var stableLocatorKeys = []string{"clusteroperator", "backend-disruption-name", "connection", "namespace", "alert"}
+
+// AggregateOptions select the job runs whose intervals are aggregated. Runs must match the release, one of the job
+// names if any are given, and all the variants.
+type AggregateOptions struct {
+	Release  string
+	JobNames []string
+	Variants []string
+	Start    time.Time
+	End      time.Time
+	MaxRuns  int
+	// FailedOnly limits the runs to those that failed.
+	FailedOnly bool
+	// Sources limits the aggregates to intervals from these sources, all sources are included if empty.
+	Sources []string
+	// IntervalFile is the interval file to load from each run, defaulting to the spyglass file.
+	IntervalFile string
+}
+
+// intervalKey identifies a kind of interval across job runs.
+type intervalKey struct {
+	Source      string
+	LocatorType string
+	Locator     string
+	Condition   string
+	Reason      string
+}
+
+// intervalGroup holds the intervals of one kind in a single job run.
+type intervalGroup struct {
+	Key             intervalKey
+	Durations       []float64
+	FailureOverlaps int
+}
+
+// runIntervalSummary is what is cached for each job run, as interval files are large and slow to load from GCS.
+type runIntervalSummary struct {
+	Groups []intervalGroup
+}
+
+type runSummaryCacheKey struct {
+	ProwJobRunID int64
+	IntervalFile string
+}
+
+type aggregateRunRow struct {
+	ID        int64
+	JobName   string
+	URL       string
+	Timestamp time.Time
+	Failed    bool
+}
+
+// AggregateJobRunIntervals loads the intervals of the most recent job runs matching the options and reports, for each
+// kind of interval, how often it appears, how long it typically lasts, and how often it overlaps with failed tests.
+// Each run's intervals are summarized and cached, so later requests only load runs not seen before.
+func AggregateJobRunIntervals(ctx context.Context, gcsClient *storage.Client, dbc *db.DB, cacheClient cache.Cache,
+	gcsBucket string, opts AggregateOptions, req *http.Request) (*apitype.IntervalAggregateReport, error) {
+	logger := log.WithFields(log.Fields{"func": "AggregateJobRunIntervals", "release": opts.Release})
+
+	runs, err := findAggregateRuns(dbc, opts)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("aggregating intervals from %d job runs", len(runs))
+
+	summaries := make([]*runIntervalSummary, len(runs))
+	errs := make([]error, len(runs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < aggregateWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				summaries[i], errs[i] = loadRunIntervalSummary(ctx, gcsClient, dbc, cacheClient, gcsBucket, runs[i].ID,
+					opts.IntervalFile, logger.WithField("jobRunID", runs[i].ID))
+			}
+		}()
+	}
+	for i := range runs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	baseURL := api.GetBaseURL(req)
+	report := &apitype.IntervalAggregateReport{
+		Release:  opts.Release,
+		JobNames: opts.JobNames,
+		Variants: opts.Variants,
+		Start:    opts.Start,
+		End:      opts.End,
+		Runs:     []apitype.IntervalAggregateRun{},
+	}
+	var loaded []runIntervalSummary
+	for i, run := range runs {
+		params := url.Values{"prow_job_run_id": {fmt.Sprint(run.ID)}}
+		if opts.IntervalFile != "" {
+			params.Set("file", opts.IntervalFile)
+		}
+		r := apitype.IntervalAggregateRun{
+			ProwJobRunID: run.ID,
+			JobName:      run.JobName,
+			URL:          run.URL,
+			Timestamp:    run.Timestamp,
+			Failed:       run.Failed,
+			Links:        map[string]string{"intervals": fmt.Sprintf(intervalsLink, baseURL, params.Encode())},
+		}
+		if errors.Is(errs[i], errNoIntervalFiles) {
+			logger.Infof("skipping job run %d with no interval files", run.ID)
+			r.Error = errs[i].Error()
+		} else if errs[i] != nil {
+			logger.WithError(errs[i]).Warnf("unable to load intervals for job run %d", run.ID)
+			r.Error = errs[i].Error()
+		} else {
+			loaded = append(loaded, *summaries[i])
+		}
+		report.Runs = append(report.Runs, r)
+	}
+	report.RunsAnalyzed = len(loaded)
+	report.Aggregates = aggregateRunSummaries(loaded, opts.Sources)
+	report.Links = map[string]string{"self": fmt.Sprintf(aggregateLink, baseURL, req.URL.RawQuery)}
+	return report, nil
+}
+
+func findAggregateRuns(dbc *db.DB, opts AggregateOptions) ([]aggregateRunRow, error) {
+	q := dbc.DB.Table("prow_job_runs").
+		Select("prow_job_runs.id, prow_jobs.name AS job_name, prow_job_runs.url, prow_job_runs.timestamp, prow_job_runs.failed").
+		Joins("JOIN prow_jobs ON prow_jobs.id = prow_job_runs.prow_job_id").
+		Where("prow_jobs.release = ?", opts.Release).
+		Where("prow_job_runs.timestamp >= ? AND prow_job_runs.timestamp < ?", opts.Start, opts.End)
+	if len(opts.JobNames) > 0 {
+		q = q.Where("prow_jobs.name IN ?", opts.JobNames)
+	}
+	for _, v := range opts.Variants {
+		q = q.Where("? = ANY(prow_jobs.variants)", v)
+	}
+	if opts.FailedOnly {
+		q = q.Where("prow_job_runs.failed")
+	}
+	var runs []aggregateRunRow
+	res := q.Order("prow_job_runs.timestamp DESC").Limit(opts.MaxRuns).Scan(&runs)
+	return runs, res.Error
+}
+
+func loadRunIntervalSummary(ctx context.Context, gcsClient *storage.Client, dbc *db.DB, cacheClient cache.Cache,
+	gcsBucket string, jobRunID int64, intervalFile string, logger *log.Entry) (*runIntervalSummary, error) {
+	generate := func(context.Context) (runIntervalSummary, []error) {
+		intervals, err := JobRunIntervals(gcsClient, dbc, jobRunID, gcsBucket, "", intervalFile, logger)
+		if err != nil {
+			return runIntervalSummary{}, []error{err}
+		}
+		if len(intervals.IntervalFilesAvailable) == 0 {
+			return runIntervalSummary{}, []error{errNoIntervalFiles}
+		}
+		return summarizeRunIntervals(intervals.Items), nil
+	}
+	summary, errs := api.GetDataFromCacheOrGenerate[runIntervalSummary](ctx, cacheClient,
+		cache.RequestOptions{Expiry: runSummaryCacheExpiry},
+		api.NewCacheSpec(runSummaryCacheKey{ProwJobRunID: jobRunID, IntervalFile: intervalFile}, "IntervalSummary~", nil),
+		generate, runIntervalSummary{})
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return &summary, nil
+}
+
+// summarizeRunIntervals groups a job run's intervals by kind, recording their durations and how many overlapped a
+// failed test. Test intervals are only used to find when tests failed, as test results are reported elsewhere.
+func summarizeRunIntervals(intervals []apitype.EventInterval) runIntervalSummary {
+	var failures [][2]time.Time
+	for _, i := range intervals {
+		if i.Source == e2eTestSource && i.StructuredMessage.Annotations["status"] == e2eFailedStatus && i.From != nil {
+			failures = append(failures, intervalSpan(i))
+		}
+	}
+
+	groups := map[intervalKey]*intervalGroup{}
+	var order []intervalKey
+	for _, i := range intervals {
+		if i.Source == e2eTestSource {
+			continue
+		}
+		key := intervalKey{
+			Source:      i.Source,
+			LocatorType: i.StructuredLocator.Type,
+			Locator:     stableLocator(i.StructuredLocator),
+			Condition:   i.StructuredMessage.Annotations["condition"],
+			Reason:      i.StructuredMessage.Reason,
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &intervalGroup{Key: key, Durations: []float64{}}
+			groups[key] = group
+			order = append(order, key)
+		}
+		if i.From == nil {
+			continue
+		}
+		span := intervalSpan(i)
+		group.Durations = append(group.Durations, span[1].Sub(span[0]).Seconds())
+		for _, f := range failures {
+			if overlaps(span, f) {
+				group.FailureOverlaps++
+				break
+			}
+		}
+	}
+
+	summary := runIntervalSummary{Groups: make([]intervalGroup, 0, len(order))}
+	for _, key := range order {
+		summary.Groups = append(summary.Groups, *groups[key])
+	}
+	return summary
+}
+
+// aggregateRunSummaries combines the per run summaries into statistics for each kind of interval, most widespread
+// first.
+func aggregateRunSummaries(summaries []runIntervalSummary, sources []string) []apitype.IntervalAggregate {
+	allowed := map[string]bool{}
+	for _, s := range sources {
+		allowed[s] = true
+	}
+
+	aggregates := map[intervalKey]*apitype.IntervalAggregate{}
+	durations := map[intervalKey][]float64{}
+	for _, summary := range summaries {
+		for _, group := range summary.Groups {
+			if len(allowed) > 0 && !allowed[group.Key.Source] {
+				continue
+			}
+			agg, ok := aggregates[group.Key]
+			if !ok {
+				agg = &apitype.IntervalAggregate{
+					Source:      group.Key.Source,
+					LocatorType: group.Key.LocatorType,
+					Locator:     group.Key.Locator,
+					Condition:   group.Key.Condition,
+					Reason:      group.Key.Reason,
+				}
+				aggregates[group.Key] = agg
+			}
+			agg.Runs++
+			agg.Occurrences += len(group.Durations)
+			agg.FailureOverlaps += group.FailureOverlaps
+			if group.FailureOverlaps > 0 {
+				agg.RunsWithFailureOverlap++
+			}
+			durations[group.Key] = append(durations[group.Key], group.Durations...)
+		}
+	}
+
+	result := make([]apitype.IntervalAggregate, 0, len(aggregates))
+	for key, agg := range aggregates {
+		if len(summaries) > 0 {
+			agg.RunPercentage = round(float64(agg.Runs) / float64(len(summaries)) * 100)
+		}
+		d := durations[key]
+		sort.Float64s(d)
+		agg.MedianDuration = round(percentile(d, 50))
+		agg.P95Duration = round(percentile(d, 95))
+		if len(d) > 0 {
+			agg.MaxDuration = round(d[len(d)-1])
+		}
+		result = append(result, *agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Runs != b.Runs {
+			return a.Runs > b.Runs
+		}
+		if a.Occurrences != b.Occurrences {
+			return a.Occurrences > b.Occurrences
+		}
+		return strings.Join([]string{a.Source, a.LocatorType, a.Locator, a.Condition, a.Reason}, "\x00") <
+			strings.Join([]string{b.Source, b.LocatorType, b.Locator, b.Condition, b.Reason}, "\x00")
+	})
+	return result
+}
+
+func stableLocator(locator apitype.Locator) string {
+	var parts []string
+	for _, k := range stableLocatorKeys {
+		if v, ok := locator.Keys[k]; ok {
+			parts = append(parts, k+"/"+v)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// intervalSpan returns the start and end of an interval with a start time. Intervals without an end are instants.
+func intervalSpan(i apitype.EventInterval) [2]time.Time {
+	if i.To == nil || i.To.Before(*i.From) {
+		return [2]time.Time{*i.From, *i.From}
+	}
+	return [2]time.Time{*i.From, *i.To}
+}
+
+func overlaps(a, b [2]time.Time) bool {
+	return !a[0].After(b[1]) && !b[0].After(a[1])
+}
+
+// percentile uses the nearest rank method on sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
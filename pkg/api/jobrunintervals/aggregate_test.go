@@ -0,0 +1,69 @@
+package jobrunintervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+)
+
+func interval(source, reason string, keys map[string]string, from, to int, annotations map[string]string) apitype.EventInterval {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	f := start.Add(time.Duration(from) * time.Second)
+	t := start.Add(time.Duration(to) * time.Second)
+	return apitype.EventInterval{
+		Source:            source,
+		StructuredLocator: apitype.Locator{Type: "Disruption", Keys: keys},
+		StructuredMessage: apitype.Message{Reason: reason, Annotations: annotations},
+		From:              &f,
+		To:                &t,
+	}
+}
+
+func TestAggregateRunIntervals(t *testing.T) {
+	apiBackend := map[string]string{"backend-disruption-name": "kube-api-new-connections", "connection": "new", "pod": "ignored-1"}
+	failedTest := map[string]string{"status": "Failed"}
+
+	run1 := summarizeRunIntervals([]apitype.EventInterval{
+		interval("Disruption", "DisruptionBegan", apiBackend, 100, 110, nil),
+		interval("Disruption", "DisruptionBegan", apiBackend, 500, 530, nil),
+		interval("E2ETest", "", map[string]string{"e2e-test": "failing test"}, 505, 600, failedTest),
+		interval("E2ETest", "", map[string]string{"e2e-test": "passing test"}, 90, 200, map[string]string{"status": "Passed"}),
+	})
+	require.Len(t, run1.Groups, 1, "test intervals are not aggregated")
+	assert.Equal(t, "backend-disruption-name/kube-api-new-connections connection/new", run1.Groups[0].Key.Locator)
+	assert.Equal(t, []float64{10, 30}, run1.Groups[0].Durations)
+	assert.Equal(t, 1, run1.Groups[0].FailureOverlaps)
+
+	otherPod := map[string]string{"backend-disruption-name": "kube-api-new-connections", "connection": "new", "pod": "ignored-2"}
+	run2 := summarizeRunIntervals([]apitype.EventInterval{
+		interval("Disruption", "DisruptionBegan", otherPod, 0, 20, nil),
+		interval("OperatorState", "", map[string]string{"clusteroperator": "etcd"}, 0, 60, map[string]string{"condition": "Degraded"}),
+	})
+	run3 := summarizeRunIntervals(nil)
+
+	aggregates := aggregateRunSummaries([]runIntervalSummary{run1, run2, run3}, nil)
+	require.Len(t, aggregates, 2)
+	disruption := aggregates[0]
+	assert.Equal(t, "Disruption", disruption.Source)
+	assert.Equal(t, 2, disruption.Runs, "intervals from different pods group together")
+	assert.Equal(t, 3, disruption.Occurrences)
+	assert.Equal(t, 66.7, disruption.RunPercentage)
+	assert.Equal(t, 20.0, disruption.MedianDuration)
+	assert.Equal(t, 30.0, disruption.P95Duration)
+	assert.Equal(t, 30.0, disruption.MaxDuration)
+	assert.Equal(t, 1, disruption.FailureOverlaps)
+	assert.Equal(t, 1, disruption.RunsWithFailureOverlap)
+
+	operator := aggregates[1]
+	assert.Equal(t, "clusteroperator/etcd", operator.Locator)
+	assert.Equal(t, "Degraded", operator.Condition)
+	assert.Equal(t, 33.3, operator.RunPercentage)
+
+	filtered := aggregateRunSummaries([]runIntervalSummary{run1, run2, run3}, []string{"OperatorState"})
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "OperatorState", filtered[0].Source)
+}
@@ -0,0 +1,190 @@
+package jobrunscan
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// LabelExpression is a compiled compound matcher for symptoms of matcher type "label_expression". The expression
+// supports only label identifiers (true when the label is applied), the literals true and false, !, &&, || and
+// parentheses, with ! binding tightest and && before ||.
+type LabelExpression struct {
+	source string
+	root   exprNode
+}
+
+// CompileLabelExpression parses the match_string of a symptom of matcher type "label_expression".
+func CompileLabelExpression(expr string) (*LabelExpression, error) {
+	tokens, err := tokenizeLabelExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty label expression")
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q at position %d in label expression", p.tokens[p.pos].text, p.tokens[p.pos].offset)
+	}
+	return &LabelExpression{source: expr, root: root}, nil
+}
+
+// Eval reports whether the expression holds for the given set of applied label IDs.
+func (e *LabelExpression) Eval(labels map[string]bool) bool {
+	return e.root.eval(labels)
+}
+
+func (e *LabelExpression) String() string {
+	return e.source
+}
+
+type exprNode interface {
+	eval(labels map[string]bool) bool
+}
+
+type exprIdent string
+
+func (n exprIdent) eval(labels map[string]bool) bool { return labels[string(n)] }
+
+type exprLiteral bool
+
+func (n exprLiteral) eval(map[string]bool) bool { return bool(n) }
+
+type exprNot struct{ operand exprNode }
+
+func (n exprNot) eval(labels map[string]bool) bool { return !n.operand.eval(labels) }
+
+type exprBinary struct {
+	and         bool
+	left, right exprNode
+}
+
+func (n exprBinary) eval(labels map[string]bool) bool {
+	if n.and {
+		return n.left.eval(labels) && n.right.eval(labels)
+	}
+	return n.left.eval(labels) || n.right.eval(labels)
+}
+
+type exprToken struct {
+	text   string
+	offset int
+}
+
+func tokenizeLabelExpression(expr string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == '!':
+			tokens = append(tokens, exprToken{text: string(r), offset: i})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("unexpected %q at position %d in label expression", string(r), i)
+			}
+			tokens = append(tokens, exprToken{text: string([]rune{r, r}), offset: i})
+			i += 2
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, exprToken{text: string(runes[start:i]), offset: start})
+		default:
+			return nil, fmt.Errorf("unsupported character %q at position %d in label expression", string(r), i)
+		}
+	}
+	return tokens, nil
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos].text
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = exprBinary{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = exprBinary{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.peek() == "!" {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return exprNot{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of label expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.text {
+	case "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis for position %d in label expression", tok.offset)
+		}
+		p.pos++
+		return node, nil
+	case "true":
+		return exprLiteral(true), nil
+	case "false":
+		return exprLiteral(false), nil
+	}
+	if !ValidIdentifierRegex.MatchString(tok.text) {
+		return nil, fmt.Errorf("unexpected %q at position %d in label expression", tok.text, tok.offset)
+	}
+	return exprIdent(tok.text), nil
+}
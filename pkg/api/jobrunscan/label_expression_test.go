@@ -0,0 +1,43 @@
+package jobrunscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelExpression(t *testing.T) {
+	labels := map[string]bool{"DNSTimeout": true, "InfraFailure": true}
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{expr: "DNSTimeout", expected: true},
+		{expr: "OperatorError", expected: false},
+		{expr: "DNSTimeout && !OperatorError", expected: true},
+		{expr: "DNSTimeout && OperatorError", expected: false},
+		{expr: "OperatorError || InfraFailure", expected: true},
+		{expr: "!(DNSTimeout || OperatorError)", expected: false},
+		{expr: "OperatorError || DNSTimeout && InfraFailure", expected: true},
+		{expr: "(OperatorError || DNSTimeout) && !InfraFailure", expected: false},
+		{expr: "true && !false", expected: true},
+		{expr: "!!DNSTimeout", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := CompileLabelExpression(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expr.Eval(labels))
+		})
+	}
+}
+
+func TestLabelExpressionErrors(t *testing.T) {
+	for _, expr := range []string{"", "A &&", "A & B", "(A || B", "A B", "A == B", "1Label", "A | B", ")"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := CompileLabelExpression(expr)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package jobrunscan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/storage"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/openshift/sippy/pkg/api/jobartifacts"
+	"github.com/openshift/sippy/pkg/apis/cache"
+	v1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
+	bqclient "github.com/openshift/sippy/pkg/bigquery"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/db/models/jobrunscan"
+)
+
+const (
+	scannerSourceTool = "sippy symptom-scanner"
+	jobLabelsTable    = "job_labels"
+	scannerUser       = "sippy"
+)
+
+// ErrJobRunNotFound is returned when asked to scan a job run that is not in the database.
+var ErrJobRunNotFound = errors.New("job run not found")
+
+// SymptomMatch records a symptom that matched a job run and the labels it contributes.
+type SymptomMatch struct {
+	SymptomID   string   `json:"symptom_id"`
+	LabelIDs    []string `json:"label_ids"`
+	ArtifactURL string   `json:"artifact_url,omitempty"`
+	MatchedText string   `json:"matched_text,omitempty"`
+}
+
+// JobRunScanResult is the outcome of evaluating all applicable symptoms against a job run.
+type JobRunScanResult struct {
+	JobRunID int64          `json:"job_run_id"`
+	Matches  []SymptomMatch `json:"matches"`
+	// NewLabels are the labels this scan added to the job run
+	NewLabels []string `json:"new_labels"`
+	// Labels are all labels on the job run after the scan
+	Labels []string `json:"labels"`
+	// Applied is false for dry runs, or when there was nothing new to write
+	Applied bool     `json:"applied"`
+	Errors  []string `json:"errors,omitempty"`
+	// a non-final result ran into timeouts scanning artifacts; retrying could find more matches
+	IsFinal bool              `json:"is_final"`
+	Links   map[string]string `json:"links,omitempty"`
+}
+
+// SymptomScanner evaluates symptom definitions against job runs and records matches as job run labels.
+type SymptomScanner struct {
+	dbc      *db.DB
+	bqClient *bqclient.Client // optional; when set, labels are also written to the BigQuery job_labels table
+	bucket   *storage.BucketHandle
+	cache    cache.Cache
+	manager  *jobartifacts.Manager
+	releases []v1.Release
+}
+
+func NewSymptomScanner(dbc *db.DB, bqClient *bqclient.Client, bucket *storage.BucketHandle, cacheClient cache.Cache,
+	manager *jobartifacts.Manager, releases []v1.Release) *SymptomScanner {
+	return &SymptomScanner{
+		dbc:      dbc,
+		bqClient: bqClient,
+		bucket:   bucket,
+		cache:    cacheClient,
+		manager:  manager,
+		releases: releases,
+	}
+}
+
+// ScanJobRun evaluates every applicable symptom against the job run. Simple symptoms are matched against
+// the job run's artifacts first; "label_expression" symptoms are then evaluated against the labels applied so far (including
+// labels already on the job run) until no more labels are added. Unless dryRun is set, new labels are written.
+func (s *SymptomScanner) ScanJobRun(ctx context.Context, jobRunID int64, dryRun bool) (*JobRunScanResult, error) {
+	var jobRun models.ProwJobRun
+	res := s.dbc.DB.WithContext(ctx).Preload("ProwJob").First(&jobRun, jobRunID)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrJobRunNotFound
+		}
+		return nil, res.Error
+	}
+
+	var symptoms []jobrunscan.Symptom
+	if res := s.dbc.DB.WithContext(ctx).Order("id").Find(&symptoms); res.Error != nil {
+		return nil, fmt.Errorf("error listing symptoms: %w", res.Error)
+	}
+
+	release := findRelease(s.releases, jobRun.ProwJob.Release)
+	result := &JobRunScanResult{JobRunID: jobRunID, IsFinal: true}
+	var simple, compound []jobrunscan.Symptom
+	for _, symptom := range symptoms {
+		if !SymptomApplies(symptom, jobRun.Timestamp, jobRun.ProwJob.Release, release) {
+			continue
+		}
+		switch symptom.MatcherType {
+		case jobrunscan.MatcherTypeLabelExpression:
+			compound = append(compound, symptom)
+		case jobrunscan.MatcherTypeCEL:
+			// stored before the API rejected it; CEL is not evaluated
+			result.Errors = append(result.Errors, fmt.Sprintf("symptom %s: matcher_type cel is not supported", symptom.ID))
+		default:
+			simple = append(simple, symptom)
+		}
+	}
+
+	labels := map[string]bool{}
+	for _, label := range jobRun.Labels {
+		labels[label] = true
+	}
+
+	for _, symptom := range simple {
+		match, final, err := s.matchArtifacts(ctx, jobRunID, symptom)
+		if !final {
+			result.IsFinal = false
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("symptom %s: %v", symptom.ID, err))
+			continue
+		}
+		if match != nil {
+			result.Matches = append(result.Matches, *match)
+			for _, label := range symptom.LabelIDs {
+				labels[label] = true
+			}
+		}
+	}
+
+	matches, errs := evaluateCompoundSymptoms(compound, labels)
+	result.Matches = append(result.Matches, matches...)
+	result.Errors = append(result.Errors, errs...)
+
+	existing := map[string]bool{}
+	for _, label := range jobRun.Labels {
+		existing[label] = true
+	}
+	for label := range labels {
+		result.Labels = append(result.Labels, label)
+		if !existing[label] {
+			result.NewLabels = append(result.NewLabels, label)
+		}
+	}
+	sort.Strings(result.Labels)
+	sort.Strings(result.NewLabels)
+
+	if dryRun || len(result.NewLabels) == 0 {
+		return result, nil
+	}
+	if err := s.applyLabels(ctx, &jobRun, result); err != nil {
+		return result, err
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// matchArtifacts checks a simple symptom against the job run's artifacts, returning the first match if any.
+func (s *SymptomScanner) matchArtifacts(ctx context.Context, jobRunID int64, symptom jobrunscan.Symptom) (*SymptomMatch, bool, error) {
+	query := &jobartifacts.JobArtifactQuery{
+		GcsBucket: s.bucket,
+		DbClient:  s.dbc,
+		Cache:     s.cache,
+		JobRunIDs: []int64{jobRunID},
+		PathGlob:  symptom.FilePattern,
+	}
+	switch symptom.MatcherType {
+	case jobrunscan.MatcherTypeString:
+		query.ContentMatcher = jobartifacts.NewStringMatcher(symptom.MatchString, 0, 0, 1)
+	case jobrunscan.MatcherTypeRegex:
+		re, err := regexp.Compile(symptom.MatchString)
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid regex: %w", err)
+		}
+		query.ContentMatcher = jobartifacts.NewRegexMatcher(re, 0, 0, 1)
+	case jobrunscan.MatcherTypeFile:
+		// file existence only
+	default:
+		return nil, true, fmt.Errorf("unsupported matcher_type: %s", symptom.MatcherType)
+	}
+
+	response := s.manager.Query(ctx, query)
+	if len(response.Errors) > 0 {
+		return nil, response.IsFinal, errors.New(response.Errors[0].Error)
+	}
+	for _, run := range response.JobRuns {
+		for _, artifact := range run.Artifacts {
+			if artifact.Error != "" {
+				continue
+			}
+			if query.ContentMatcher == nil {
+				return newSymptomMatch(symptom, artifact.ArtifactURL, ""), response.IsFinal, nil
+			}
+			if text, matched := artifact.Matched(); matched {
+				return newSymptomMatch(symptom, artifact.ArtifactURL, text), response.IsFinal, nil
+			}
+		}
+	}
+	return nil, response.IsFinal, nil
+}
+
+func newSymptomMatch(symptom jobrunscan.Symptom, artifactURL, text string) *SymptomMatch {
+	return &SymptomMatch{
+		SymptomID:   symptom.ID,
+		LabelIDs:    symptom.LabelIDs,
+		ArtifactURL: artifactURL,
+		MatchedText: text,
+	}
+}
+
+// evaluateCompoundSymptoms repeatedly evaluates the label expressions of "label_expression" symptoms against the label set, adding the labels of
+// each that matches, until a pass adds nothing; this lets compound symptoms build on each other regardless of order.
+func evaluateCompoundSymptoms(symptoms []jobrunscan.Symptom, labels map[string]bool) ([]SymptomMatch, []string) {
+	var matches []SymptomMatch
+	var errs []string
+	expressions := map[string]*LabelExpression{}
+	for _, symptom := range symptoms {
+		expr, err := CompileLabelExpression(symptom.MatchString)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("symptom %s: %v", symptom.ID, err))
+			continue
+		}
+		expressions[symptom.ID] = expr
+	}
+
+	matched := map[string]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, symptom := range symptoms {
+			expr, ok := expressions[symptom.ID]
+			if !ok || matched[symptom.ID] || !expr.Eval(labels) {
+				continue
+			}
+			matched[symptom.ID] = true
+			matches = append(matches, *newSymptomMatch(symptom, "", ""))
+			for _, label := range symptom.LabelIDs {
+				if !labels[label] {
+					labels[label] = true
+					changed = true
+				}
+			}
+		}
+	}
+	return matches, errs
+}
+
+// SymptomApplies reports whether a symptom's applicability filters allow it to be evaluated against a job run
+// started at the given time for the given release. release may be nil if the release is not known to sippy,
+// in which case status and product filters cannot be satisfied.
+func SymptomApplies(symptom jobrunscan.Symptom, jobRunStart time.Time, releaseName string, release *v1.Release) bool {
+	if symptom.ValidFrom != nil && jobRunStart.Before(*symptom.ValidFrom) {
+		return false
+	}
+	if symptom.ValidUntil != nil && !jobRunStart.Before(*symptom.ValidUntil) {
+		return false
+	}
+	if len(symptom.FilterReleases) > 0 && !contains(symptom.FilterReleases, releaseName) {
+		return false
+	}
+	if len(symptom.FilterReleaseStatuses) > 0 && (release == nil || !contains(symptom.FilterReleaseStatuses, release.Status)) {
+		return false
+	}
+	if len(symptom.FilterProducts) > 0 && (release == nil || !contains(symptom.FilterProducts, release.Product)) {
+		return false
+	}
+	return true
+}
+
+func contains(values pq.StringArray, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func findRelease(releases []v1.Release, name string) *v1.Release {
+	for i := range releases {
+		if releases[i].Release == name {
+			return &releases[i]
+		}
+	}
+	return nil
+}
+
+// applyLabels adds the new labels to the job run in postgres, and to the BigQuery job_labels table
+// when configured so the labels are seen by everything that reads job run labels from there.
+func (s *SymptomScanner) applyLabels(ctx context.Context, jobRun *models.ProwJobRun, result *JobRunScanResult) error {
+	res := s.dbc.DB.WithContext(ctx).Exec(
+		"UPDATE prow_job_runs SET labels = ARRAY(SELECT DISTINCT unnest(COALESCE(labels, '{}') || CAST(? AS text[])) ORDER BY 1) WHERE id = ?",
+		pq.StringArray(result.NewLabels), jobRun.ID)
+	if res.Error != nil {
+		return fmt.Errorf("error applying labels to job run %d: %w", jobRun.ID, res.Error)
+	}
+	log.WithField("jobRunID", jobRun.ID).Infof("applied labels %v from symptom scan", result.NewLabels)
+
+	if s.bqClient == nil {
+		return nil
+	}
+	rows := jobRunLabelRows(jobRun, result, civil.DateTimeOf(time.Now()))
+	inserter := s.bqClient.BQ.Dataset(s.bqClient.Dataset).Table(jobLabelsTable).Inserter()
+	if err := inserter.Put(ctx, rows); err != nil {
+		return fmt.Errorf("error writing job labels to bigquery for job run %d: %w", jobRun.ID, err)
+	}
+	return nil
+}
+
+// jobRunLabelRows builds a job_labels row for each new label, attributed to the first symptom that applied it.
+func jobRunLabelRows(jobRun *models.ProwJobRun, result *JobRunScanResult, now civil.DateTime) []models.JobRunLabel {
+	isNew := map[string]bool{}
+	for _, label := range result.NewLabels {
+		isNew[label] = true
+	}
+	var rows []models.JobRunLabel
+	for _, match := range result.Matches {
+		for _, label := range match.LabelIDs {
+			if !isNew[label] {
+				continue
+			}
+			isNew[label] = false
+			comment, _ := json.Marshal(map[string]SymptomMatch{"symptom_scanner_v1": match})
+			rows = append(rows, models.JobRunLabel{
+				ID:         strconv.FormatUint(uint64(jobRun.ID), 10),
+				StartTime:  civil.DateTimeOf(jobRun.Timestamp),
+				Label:      label,
+				Comment:    string(comment),
+				User:       scannerUser,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+				SourceTool: scannerSourceTool,
+				SymptomID:  match.SymptomID,
+				URL:        jobRun.URL,
+			})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,93 @@
+package jobrunscan
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/db/models/jobrunscan"
+)
+
+func TestSymptomApplies(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+	after := start.Add(time.Hour)
+	release := &v1.Release{Release: "4.20", Status: "Development", Product: "OCP"}
+
+	tests := []struct {
+		name     string
+		filters  jobrunscan.ApplicabilityFilters
+		release  *v1.Release
+		expected bool
+	}{
+		{name: "no filters", expected: true},
+		{name: "within window", filters: jobrunscan.ApplicabilityFilters{ValidFrom: &before, ValidUntil: &after}, expected: true},
+		{name: "not yet valid", filters: jobrunscan.ApplicabilityFilters{ValidFrom: &after}, expected: false},
+		{name: "expired", filters: jobrunscan.ApplicabilityFilters{ValidUntil: &start}, expected: false},
+		{name: "release matches", filters: jobrunscan.ApplicabilityFilters{FilterReleases: pq.StringArray{"4.19", "4.20"}}, release: release, expected: true},
+		{name: "release does not match", filters: jobrunscan.ApplicabilityFilters{FilterReleases: pq.StringArray{"4.19"}}, release: release, expected: false},
+		{name: "status matches", filters: jobrunscan.ApplicabilityFilters{FilterReleaseStatuses: pq.StringArray{"Development"}}, release: release, expected: true},
+		{name: "status does not match", filters: jobrunscan.ApplicabilityFilters{FilterReleaseStatuses: pq.StringArray{"Full Support"}}, release: release, expected: false},
+		{name: "product matches", filters: jobrunscan.ApplicabilityFilters{FilterProducts: pq.StringArray{"OKD", "OCP"}}, release: release, expected: true},
+		{name: "product with unknown release", filters: jobrunscan.ApplicabilityFilters{FilterProducts: pq.StringArray{"OCP"}}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symptom := jobrunscan.Symptom{ApplicabilityFilters: tt.filters}
+			assert.Equal(t, tt.expected, SymptomApplies(symptom, start, "4.20", tt.release))
+		})
+	}
+}
+
+func labelExpressionSymptom(id, expr string, labels ...string) jobrunscan.Symptom {
+	return jobrunscan.Symptom{SymptomContent: jobrunscan.SymptomContent{
+		ID: id, MatcherType: jobrunscan.MatcherTypeLabelExpression, MatchString: expr, LabelIDs: labels,
+	}}
+}
+
+func TestEvaluateCompoundSymptoms(t *testing.T) {
+	symptoms := []jobrunscan.Symptom{
+		// depends on a label applied by a later symptom
+		labelExpressionSymptom("Chained", "Compound && !Unrelated", "Chained"),
+		labelExpressionSymptom("Compound", "DNSTimeout && InfraFailure", "Compound"),
+		labelExpressionSymptom("NoMatch", "Unrelated", "Other"),
+		labelExpressionSymptom("Broken", "DNSTimeout &&", "Other"),
+	}
+	labels := map[string]bool{"DNSTimeout": true, "InfraFailure": true}
+
+	matches, errs := evaluateCompoundSymptoms(symptoms, labels)
+	assert.Len(t, errs, 1)
+	var ids []string
+	for _, m := range matches {
+		ids = append(ids, m.SymptomID)
+	}
+	assert.ElementsMatch(t, []string{"Compound", "Chained"}, ids)
+	assert.True(t, labels["Compound"])
+	assert.True(t, labels["Chained"])
+	assert.False(t, labels["Other"])
+}
+
+func TestJobRunLabelRows(t *testing.T) {
+	jobRun := &models.ProwJobRun{URL: "https://prow/run/1", Timestamp: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+	jobRun.ID = 1234
+	result := &JobRunScanResult{
+		Matches: []SymptomMatch{
+			{SymptomID: "First", LabelIDs: []string{"Existing", "DNSTimeout"}},
+			{SymptomID: "Second", LabelIDs: []string{"DNSTimeout", "Compound"}},
+		},
+		NewLabels: []string{"Compound", "DNSTimeout"},
+	}
+	rows := jobRunLabelRows(jobRun, result, civil.DateTimeOf(time.Now()))
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "1234", rows[0].ID)
+	assert.Equal(t, "DNSTimeout", rows[0].Label)
+	assert.Equal(t, "First", rows[0].SymptomID)
+	assert.Equal(t, "Compound", rows[1].Label)
+	assert.Equal(t, "Second", rows[1].SymptomID)
+	assert.Equal(t, scannerSourceTool, rows[1].SourceTool)
+}
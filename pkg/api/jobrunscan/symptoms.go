@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 
 	sippyapi "github.com/openshift/sippy/pkg/api"
 	"github.com/openshift/sippy/pkg/db"
@@ -28,25 +29,37 @@ func validateSymptom(dbc *gorm.DB, symptom jobrunscan.Symptom) error {
 
 	// Validate matcher_type enum
 	validMatcherTypes := map[string]bool{
-		jobrunscan.MatcherTypeString: true,
-		jobrunscan.MatcherTypeRegex:  true,
-		jobrunscan.MatcherTypeFile:   true,
-		jobrunscan.MatcherTypeCEL:    true,
+		jobrunscan.MatcherTypeString:          true,
+		jobrunscan.MatcherTypeRegex:           true,
+		jobrunscan.MatcherTypeFile:            true,
+		jobrunscan.MatcherTypeLabelExpression: true,
+	}
+	if symptom.MatcherType == jobrunscan.MatcherTypeCEL {
+		return fmt.Errorf("matcher_type cel is not supported, use label_expression to combine label IDs with !, &&, || and parentheses")
 	}
 	if !validMatcherTypes[symptom.MatcherType] {
-		return fmt.Errorf("invalid matcher_type: %s (must be one of: string, regex, none, cel)", symptom.MatcherType)
+		return fmt.Errorf("invalid matcher_type: %s (must be one of: string, regex, none, label_expression)", symptom.MatcherType)
 	}
 
 	// Validate required fields based on matcher_type
-	if symptom.MatcherType != jobrunscan.MatcherTypeCEL {
-		// Non-CEL matchers require file_pattern
+	if symptom.MatcherType != jobrunscan.MatcherTypeLabelExpression {
+		// Artifact matchers require file_pattern
 		if symptom.FilePattern == "" {
 			return fmt.Errorf("file_pattern is required for matcher_type: %s", symptom.MatcherType)
 		}
 	} else {
-		// CEL matchers require match_string
+		// Label expressions require match_string
 		if symptom.MatchString == "" {
-			return fmt.Errorf("match_string is required for matcher_type: cel")
+			return fmt.Errorf("match_string is required for matcher_type: label_expression")
+		}
+		if _, err := CompileLabelExpression(symptom.MatchString); err != nil {
+			return fmt.Errorf("invalid match_string for matcher_type label_expression: %v", err)
+		}
+	}
+
+	if symptom.MatcherType == jobrunscan.MatcherTypeRegex {
+		if _, err := regexp.Compile(symptom.MatchString); err != nil {
+			return fmt.Errorf("invalid match_string for matcher_type regex: %v", err)
 		}
 	}
 
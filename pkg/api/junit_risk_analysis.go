@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/apis/cache"
+	"github.com/openshift/sippy/pkg/apis/junit"
+	sippyprocessingv1 "github.com/openshift/sippy/pkg/apis/sippyprocessing/v1"
+	"github.com/openshift/sippy/pkg/bigquery"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/testidentification"
+)
+
+// UploadedJUnitJobName names the job of uploaded JUnit results in their risk analysis, as they did not run in prow.
+const UploadedJUnitJobName = "uploaded-junit"
+
+// JUnitRiskAnalysis analyzes the failures in JUnit results from outside of prow, such as partner CI, against the
+// release's jobs with the given variants, as returned by ImportantJobVariants. There is no job history for uploaded
+// results, so they are never flagged as having incomplete tests.
+func JUnitRiskAnalysis(
+	ctx context.Context, logger *log.Entry,
+	dbc *db.DB, bqc *bigquery.Client, cacheClient cache.Cache,
+	suites *junit.TestSuites, release string, variants []string,
+) (apitype.ProwJobRunRiskAnalysis, error) {
+	logger = logger.WithField("func", "JUnitRiskAnalysis")
+	jobRun := jobRunFromJUnit(suites, release, variants)
+
+	jobNames, totalJobRuns, err := findVariantMatchJobNames(dbc, jobRun.ProwJob.Variants, release)
+	if err != nil {
+		return apitype.ProwJobRunRiskAnalysis{}, err
+	}
+	if totalJobRuns < 20 {
+		// go back to the prior release and get more jobs to compare against
+		releases, err := GetReleases(ctx, bqc, false)
+		if err != nil {
+			logger.WithError(err).Error("Failed to get releases for prior release lookup")
+		}
+		for _, r := range releases {
+			if r.Release == release && r.PreviousRelease != "" {
+				priorJobNames, _, err := findVariantMatchJobNames(dbc, jobRun.ProwJob.Variants, r.PreviousRelease)
+				if err != nil {
+					return apitype.ProwJobRunRiskAnalysis{}, err
+				}
+				jobNames = append(jobNames, priorJobNames...)
+				break
+			}
+		}
+	}
+	logger.Infof("Found %d job(s) matching variants %v", len(jobNames), jobRun.ProwJob.Variants)
+
+	loadFailedTestBugs(dbc, jobRun, logger)
+
+	return runJobRunAnalysis(ctx,
+		bqc, jobRun, release, 0, false, jobNames, logger,
+		jobNamesTestResultFunc(dbc),
+		variantsTestResultFunc(ctx, dbc, cacheClient),
+		false,
+	)
+}
+
+// jobRunFromJUnit builds a transient job run holding the failed tests of the JUnit results. As when loading job runs,
+// a test that both passed and failed in a suite is a flake rather than a failure.
+func jobRunFromJUnit(suites *junit.TestSuites, release string, variants []string) *models.ProwJobRun {
+	statuses := map[string]sippyprocessingv1.TestStatus{}
+	var order []models.ProwJobRunTest
+	var collect func(suiteName string, suite *junit.TestSuite)
+	collect = func(suiteName string, suite *junit.TestSuite) {
+		for _, tc := range suite.TestCases {
+			if tc.SkipMessage != nil || testidentification.IsIgnoredTest(tc.Name) {
+				continue
+			}
+			status := sippyprocessingv1.TestStatusSuccess
+			if tc.FailureOutput != nil {
+				status = sippyprocessingv1.TestStatusFailure
+			}
+			key := fmt.Sprintf("%s.%s", suiteName, tc.Name)
+			existing, ok := statuses[key]
+			switch {
+			case !ok:
+				statuses[key] = status
+				order = append(order, models.ProwJobRunTest{Test: models.Test{Name: tc.Name}, Suite: models.Suite{Name: suiteName}})
+			case existing != status && existing != sippyprocessingv1.TestStatusFlake:
+				statuses[key] = sippyprocessingv1.TestStatusFlake
+			}
+		}
+		for _, child := range suite.Children {
+			collect(suiteName, child)
+		}
+	}
+	for _, suite := range suites.Suites {
+		collect(suite.Name, suite)
+	}
+
+	jobRun := &models.ProwJobRun{
+		ProwJob:   models.ProwJob{Name: UploadedJUnitJobName, Release: release, Variants: variants},
+		TestCount: len(order),
+	}
+	for _, t := range order {
+		status := statuses[fmt.Sprintf("%s.%s", t.Suite.Name, t.Test.Name)]
+		if status == sippyprocessingv1.TestStatusFailure {
+			t.Status = int(status)
+			jobRun.Tests = append(jobRun.Tests, t)
+		}
+	}
+	jobRun.Failed = len(jobRun.Tests) > 0
+	jobRun.Succeeded = !jobRun.Failed
+	return jobRun
+}
+
+// ImportantJobVariants validates "Name:value" variants against those sippy stores for jobs, and orders them as it
+// stores them, so they compare equal to the variants of matching jobs. At least one variant is required, as without
+// any there are no jobs to compare against.
+func ImportantJobVariants(variants []string) ([]string, error) {
+	supported := testidentification.ImportantVariants()
+	byName := map[string]string{}
+	for _, v := range variants {
+		name, value, ok := strings.Cut(v, ":")
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("variant %q must be of the form Name:value", v)
+		}
+		if !slices.Contains(supported, name) {
+			return nil, fmt.Errorf("variant %q is not one of the supported variants %s", name, strings.Join(supported, ", "))
+		}
+		byName[name] = v
+	}
+	var important []string
+	for _, name := range supported {
+		if v, ok := byName[name]; ok {
+			important = append(important, v)
+		}
+	}
+	if len(important) == 0 {
+		return nil, fmt.Errorf("at least one variant is required, from %s", strings.Join(supported, ", "))
+	}
+	return important, nil
+}
+
+// findVariantMatchJobNames finds the release's jobs having all the given variants, and counts their runs.
+func findVariantMatchJobNames(dbc *db.DB, variants []string, release string) ([]string, int, error) {
+	if len(variants) == 0 {
+		return nil, 0, nil
+	}
+	var jobs []models.ProwJob
+	res := dbc.DB.Where("release = ? AND variants @> ?", release, pq.StringArray(variants)).Find(&jobs)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+	if len(jobs) == 0 {
+		return nil, 0, nil
+	}
+
+	jobNames := make([]string, 0, len(jobs))
+	jobIDs := make([]uint, 0, len(jobs))
+	for _, job := range jobs {
+		jobNames = append(jobNames, job.Name)
+		jobIDs = append(jobIDs, job.ID)
+	}
+	var runs int64
+	res = dbc.DB.Model(&models.ProwJobRun{}).Where("prow_job_id IN ?", jobIDs).Count(&runs)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+	return jobNames, int(runs), nil
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/sippy/pkg/apis/junit"
+	sippyprocessingv1 "github.com/openshift/sippy/pkg/apis/sippyprocessing/v1"
+)
+
+func TestJobRunFromJUnit(t *testing.T) {
+	suites, err := junit.Parse([]byte(`<testsuites>
+<testsuite name="openshift-tests">
+  <testcase name="passes"/>
+  <testcase name="fails"><failure message="boom">output</failure></testcase>
+  <testcase name="flakes"><failure message="boom"/></testcase>
+  <testcase name="flakes"/>
+  <testcase name="skipped"><skipped message="not applicable"/></testcase>
+</testsuite>
+<testsuite name="other-suite">
+  <testcase name="flakes"><failure message="boom"/></testcase>
+</testsuite>
+</testsuites>`))
+	require.NoError(t, err)
+
+	jobRun := jobRunFromJUnit(suites, "4.22", []string{"Platform:metal"})
+	assert.Equal(t, UploadedJUnitJobName, jobRun.ProwJob.Name)
+	assert.Equal(t, "4.22", jobRun.ProwJob.Release)
+	assert.Equal(t, 4, jobRun.TestCount, "skipped tests are not counted")
+	assert.True(t, jobRun.Failed)
+
+	require.Len(t, jobRun.Tests, 2)
+	assert.Equal(t, "fails", jobRun.Tests[0].Test.Name)
+	assert.Equal(t, "openshift-tests", jobRun.Tests[0].Suite.Name)
+	assert.Equal(t, int(sippyprocessingv1.TestStatusFailure), jobRun.Tests[0].Status)
+	assert.Equal(t, "flakes", jobRun.Tests[1].Test.Name)
+	assert.Equal(t, "other-suite", jobRun.Tests[1].Suite.Name, "a pass in another suite does not make a flake")
+}
+
+func TestImportantJobVariants(t *testing.T) {
+	variants, err := ImportantJobVariants([]string{"Network:ovn", "Platform:metal", "Architecture:arm64"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Platform:metal", "Architecture:arm64", "Network:ovn"}, variants)
+
+	_, err = ImportantJobVariants([]string{"Network:ovn", "CustomLab:rack-7"})
+	assert.ErrorContains(t, err, `variant "CustomLab" is not one of the supported variants`)
+	_, err = ImportantJobVariants([]string{"Platform"})
+	assert.ErrorContains(t, err, "must be of the form Name:value")
+	_, err = ImportantJobVariants(nil)
+	assert.ErrorContains(t, err, "at least one variant is required")
+}
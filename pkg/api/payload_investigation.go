@@ -0,0 +1,306 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/db/query"
+	"github.com/openshift/sippy/pkg/filter"
+)
+
+const (
+	// DefaultSuspectBlockerScore is the blocker score at which a test is considered a likely cause of a rejected streak.
+	DefaultSuspectBlockerScore = 50
+
+	// timingOnlyWeight scales the score of pull requests that only landed when a test started failing, so that
+	// pull requests whose repository overlaps with the failing test's component rank first.
+	timingOnlyWeight = 0.1
+
+	payloadInvestigationLink = "%s/api/payloads/investigation?%s"
+	payloadDiffLink          = "%s/api/payloads/diff?%s"
+	payloadTestFailuresLink  = "%s/api/releases/test_failures?%s"
+)
+
+// testOwner is the component ownership of a test.
+type testOwner struct {
+	Name          string
+	Component     string
+	JiraComponent string
+}
+
+// GetPayloadInvestigation investigates the current rejected streak of a payload stream, ranking the pull requests
+// most likely to have caused it.
+func GetPayloadInvestigation(dbc *db.DB, scorer BlockerScorer, release, stream, arch string, minBlockerScore int, reportEnd time.Time, req *http.Request) (*apitype.PayloadInvestigation, error) {
+	logger := log.WithFields(log.Fields{"release": release, "stream": stream, "arch": arch})
+
+	analysis, lastPayloads, err := analyzePayloadStream(dbc, scorer, release, stream, arch, &filter.FilterOptions{Filter: &filter.Filter{}}, reportEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := getTestOwners(dbc, suspectTestNames(analysis.TestFailures, minBlockerScore))
+	if err != nil {
+		return nil, err
+	}
+	suspects := findSuspectTests(analysis.ConsecutiveFailedPayloads, analysis.TestFailures, owners, minBlockerScore)
+
+	previous := previousPayloads(lastPayloads)
+	prsByPayload := map[string][]models.ReleasePullRequest{}
+	for _, suspect := range suspects {
+		payload := suspect.FirstFailedPayload
+		if _, ok := prsByPayload[payload]; ok {
+			continue
+		}
+		if _, ok := previous[payload]; !ok {
+			prev, err := query.GetPreviousPayload(dbc.DB, payload)
+			if err != nil {
+				logger.WithError(err).Warnf("unable to find payload before %s", payload)
+				prsByPayload[payload] = nil
+				continue
+			}
+			previous[payload] = prev.ReleaseTag
+		}
+		prs, err := query.GetPayloadDiff(dbc.DB, previous[payload], payload)
+		if err != nil {
+			return nil, err
+		}
+		prsByPayload[payload] = prs
+	}
+
+	baseURL := GetBaseURL(req)
+	investigation := &apitype.PayloadInvestigation{
+		Release:             release,
+		Stream:              stream,
+		Architecture:        arch,
+		RejectedStreak:      reversed(analysis.ConsecutiveFailedPayloads),
+		SuspectTests:        suspects,
+		Timeline:            buildPayloadTimeline(baseURL, analysis.ConsecutiveFailedPayloads, previous, suspects, prsByPayload),
+		CulpritPullRequests: rankCulpritPullRequests(suspects, prsByPayload),
+	}
+	params := url.Values{"release": {release}, "stream": {stream}, "arch": {arch}}.Encode()
+	investigation.Links = map[string]string{
+		"self":          fmt.Sprintf(payloadInvestigationLink, baseURL, params),
+		"test_failures": fmt.Sprintf(payloadTestFailuresLink, baseURL, params),
+	}
+	return investigation, nil
+}
+
+func suspectTestNames(failures []*apitype.TestFailureAnalysis, minBlockerScore int) []string {
+	var names []string
+	for _, f := range failures {
+		if f.BlockerScore >= minBlockerScore {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// getTestOwners looks up the components of the given tests. A test in several suites takes the highest priority
+// ownership.
+func getTestOwners(dbc *db.DB, names []string) (map[string]testOwner, error) {
+	owners := map[string]testOwner{}
+	if len(names) == 0 {
+		return owners, nil
+	}
+	var ownerships []models.TestOwnership
+	res := dbc.DB.Where("name IN ?", names).Order("priority ASC").Find(&ownerships)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	for _, o := range ownerships {
+		owners[o.Name] = testOwner{Name: o.Name, Component: o.Component, JiraComponent: o.JiraComponent}
+	}
+	return owners, nil
+}
+
+// previousPayloads maps each payload to the payload before it in the stream, for payloads ordered most recent first.
+func previousPayloads(payloads []models.ReleaseTag) map[string]string {
+	previous := map[string]string{}
+	for i := 0; i < len(payloads)-1; i++ {
+		previous[payloads[i].ReleaseTag] = payloads[i+1].ReleaseTag
+	}
+	return previous
+}
+
+// findSuspectTests picks the tests likely to be blocking the streak, and finds the oldest payload in the streak each
+// failed in. Tests missing from a payload are often infra failures, so the oldest failure marks when it started.
+func findSuspectTests(streak []string, failures []*apitype.TestFailureAnalysis, owners map[string]testOwner, minBlockerScore int) []apitype.SuspectTest {
+	suspects := []apitype.SuspectTest{}
+	for _, f := range failures {
+		if f.BlockerScore < minBlockerScore {
+			continue
+		}
+		suspect := apitype.SuspectTest{
+			Name:          f.Name,
+			BlockerScore:  f.BlockerScore,
+			Component:     owners[f.Name].Component,
+			JiraComponent: owners[f.Name].JiraComponent,
+		}
+		for _, payload := range streak {
+			if _, ok := f.FailedPayloads[payload]; ok {
+				suspect.FirstFailedPayload = payload
+				suspect.FailedPayloads++
+			}
+		}
+		if suspect.FirstFailedPayload == "" {
+			continue
+		}
+		suspects = append(suspects, suspect)
+	}
+	sort.SliceStable(suspects, func(i, j int) bool {
+		if suspects[i].BlockerScore != suspects[j].BlockerScore {
+			return suspects[i].BlockerScore > suspects[j].BlockerScore
+		}
+		return suspects[i].Name < suspects[j].Name
+	})
+	return suspects
+}
+
+// rankCulpritPullRequests scores each pull request that landed in a payload where a suspect test started failing by
+// that test's blocker score. Pull requests whose repository matches the test's component take the full score, others
+// only a fraction of it, as most pull requests in a payload are unrelated to any given failure.
+func rankCulpritPullRequests(suspects []apitype.SuspectTest, prsByPayload map[string][]models.ReleasePullRequest) []apitype.CulpritPullRequest {
+	culprits := map[string]*apitype.CulpritPullRequest{}
+	var order []string
+	for _, suspect := range suspects {
+		for _, pr := range prsByPayload[suspect.FirstFailedPayload] {
+			culprit, ok := culprits[pr.URL]
+			if !ok {
+				culprit = &apitype.CulpritPullRequest{
+					URL:               pr.URL,
+					PullRequestID:     pr.PullRequestID,
+					Repository:        pr.Name,
+					Description:       pr.Description,
+					BugURL:            pr.BugURL,
+					Payload:           suspect.FirstFailedPayload,
+					Tests:             []string{},
+					MatchedComponents: []string{},
+					Reasons:           []string{},
+				}
+				culprits[pr.URL] = culprit
+				order = append(order, pr.URL)
+			}
+
+			weight := float64(suspect.BlockerScore) / 100
+			culprit.Tests = append(culprit.Tests, suspect.Name)
+			matched := matchingComponent(pr.Name, suspect.Component, suspect.JiraComponent)
+			if matched == "" {
+				culprit.Score += weight * timingOnlyWeight
+				culprit.Reasons = append(culprit.Reasons, fmt.Sprintf("landed in %s when %q (blocker score %d) started failing",
+					suspect.FirstFailedPayload, suspect.Name, suspect.BlockerScore))
+				continue
+			}
+			culprit.Score += weight
+			if !containsString(culprit.MatchedComponents, matched) {
+				culprit.MatchedComponents = append(culprit.MatchedComponents, matched)
+			}
+			culprit.Reasons = append(culprit.Reasons, fmt.Sprintf("landed in %s when %q (blocker score %d) started failing, and %s matches its component %s",
+				suspect.FirstFailedPayload, suspect.Name, suspect.BlockerScore, pr.Name, matched))
+		}
+	}
+
+	ranked := make([]apitype.CulpritPullRequest, 0, len(order))
+	for _, u := range order {
+		culprit := culprits[u]
+		culprit.Score = float64(int(culprit.Score*1000+0.5)) / 1000
+		ranked = append(ranked, *culprit)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].URL < ranked[j].URL
+	})
+	return ranked
+}
+
+func buildPayloadTimeline(baseURL string, streak []string, previous map[string]string, suspects []apitype.SuspectTest, prsByPayload map[string][]models.ReleasePullRequest) []apitype.PayloadTimelineEntry {
+	newlyFailing := map[string][]string{}
+	for _, suspect := range suspects {
+		newlyFailing[suspect.FirstFailedPayload] = append(newlyFailing[suspect.FirstFailedPayload], suspect.Name)
+	}
+
+	timeline := []apitype.PayloadTimelineEntry{}
+	for _, payload := range reversed(streak) {
+		entry := apitype.PayloadTimelineEntry{
+			ReleaseTag:         payload,
+			PreviousReleaseTag: previous[payload],
+			PullRequests:       len(prsByPayload[payload]),
+			NewlyFailingTests:  newlyFailing[payload],
+		}
+		if entry.NewlyFailingTests == nil {
+			entry.NewlyFailingTests = []string{}
+		}
+		params := url.Values{"toPayload": {payload}}
+		if entry.PreviousReleaseTag != "" {
+			params.Set("fromPayload", entry.PreviousReleaseTag)
+		}
+		entry.Links = map[string]string{"diff": fmt.Sprintf(payloadDiffLink, baseURL, params.Encode())}
+		timeline = append(timeline, entry)
+	}
+	return timeline
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// normalizeComponentName reduces repository and component names to comparable keys, dropping the affixes that
+// operator repositories commonly add, so cluster-kube-apiserver-operator and kube-apiserver compare equal.
+func normalizeComponentName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimPrefix(name, "openshift-")
+	name = strings.TrimPrefix(name, "cluster-")
+	name = strings.TrimSuffix(name, "-operator")
+	name = strings.TrimSuffix(name, " operator")
+	return nonAlphanumeric.ReplaceAllString(name, "")
+}
+
+// matchingComponent returns the first of the components that overlaps with the repository. Jira components are often
+// hierarchical, such as "Networking / ovn-kubernetes", so each level is compared. Short names must match exactly to
+// avoid spurious matches.
+func matchingComponent(repository string, components ...string) string {
+	repo := normalizeComponentName(repository)
+	if repo == "" {
+		return ""
+	}
+	for _, component := range components {
+		for _, part := range strings.Split(component, "/") {
+			key := normalizeComponentName(part)
+			if key == "" {
+				continue
+			}
+			if key == repo ||
+				(len(key) >= 4 && strings.Contains(repo, key)) ||
+				(len(repo) >= 4 && strings.Contains(key, repo)) {
+				return component
+			}
+		}
+	}
+	return ""
+}
+
+func reversed(s []string) []string {
+	r := make([]string, 0, len(s))
+	for i := len(s) - 1; i >= 0; i-- {
+		r = append(r, s[i])
+	}
+	return r
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+func TestMatchingComponent(t *testing.T) {
+	tests := []struct {
+		repository string
+		components []string
+		expected   string
+	}{
+		{repository: "cluster-kube-apiserver-operator", components: []string{"kube-apiserver"}, expected: "kube-apiserver"},
+		{repository: "machine-config-operator", components: []string{"Machine Config Operator"}, expected: "Machine Config Operator"},
+		{repository: "ovn-kubernetes", components: []string{"", "Networking / ovn-kubernetes"}, expected: "Networking / ovn-kubernetes"},
+		{repository: "cluster-network-operator", components: []string{"Networking"}, expected: "Networking"},
+		{repository: "cluster-etcd-operator", components: []string{"Etcd"}, expected: "Etcd"},
+		{repository: "oc", components: []string{"oc"}, expected: "oc"},
+		{repository: "ocs-operator", components: []string{"oc"}, expected: ""},
+		{repository: "installer", components: []string{"Storage"}, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repository, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchingComponent(tt.repository, tt.components...))
+		})
+	}
+}
+
+func TestPayloadInvestigation(t *testing.T) {
+	// Most recent first, as the streak is reported by the stream analysis.
+	streak := []string{"4.22.0-0.nightly-3", "4.22.0-0.nightly-2", "4.22.0-0.nightly-1"}
+	failures := []*apitype.TestFailureAnalysis{
+		{Name: "etcd test", BlockerScore: 100, FailedPayloads: map[string]*apitype.FailedPayload{
+			"4.22.0-0.nightly-3": {}, "4.22.0-0.nightly-2": {},
+		}},
+		{Name: "network test", BlockerScore: 75, FailedPayloads: map[string]*apitype.FailedPayload{
+			"4.22.0-0.nightly-3": {}, "4.22.0-0.nightly-1": {},
+		}},
+		{Name: "flaky test", BlockerScore: 25, FailedPayloads: map[string]*apitype.FailedPayload{
+			"4.22.0-0.nightly-3": {},
+		}},
+	}
+	owners := map[string]testOwner{
+		"etcd test":    {Component: "Etcd", JiraComponent: "Etcd"},
+		"network test": {Component: "Networking", JiraComponent: "Networking / ovn-kubernetes"},
+	}
+
+	suspects := findSuspectTests(streak, failures, owners, DefaultSuspectBlockerScore)
+	require.Len(t, suspects, 2)
+	assert.Equal(t, "etcd test", suspects[0].Name)
+	assert.Equal(t, "4.22.0-0.nightly-2", suspects[0].FirstFailedPayload)
+	assert.Equal(t, 2, suspects[0].FailedPayloads)
+	assert.Equal(t, "4.22.0-0.nightly-1", suspects[1].FirstFailedPayload)
+
+	prsByPayload := map[string][]models.ReleasePullRequest{
+		"4.22.0-0.nightly-1": {
+			{URL: "https://github.com/openshift/ovn-kubernetes/pull/1", Name: "ovn-kubernetes"},
+			{URL: "https://github.com/openshift/installer/pull/2", Name: "installer"},
+		},
+		"4.22.0-0.nightly-2": {
+			{URL: "https://github.com/openshift/cluster-etcd-operator/pull/3", Name: "cluster-etcd-operator"},
+			{URL: "https://github.com/openshift/console/pull/4", Name: "console"},
+		},
+	}
+	culprits := rankCulpritPullRequests(suspects, prsByPayload)
+	require.Len(t, culprits, 4)
+	assert.Equal(t, "cluster-etcd-operator", culprits[0].Repository)
+	assert.Equal(t, 1.0, culprits[0].Score)
+	assert.Equal(t, []string{"Etcd"}, culprits[0].MatchedComponents)
+	assert.Equal(t, "ovn-kubernetes", culprits[1].Repository)
+	assert.Equal(t, 0.75, culprits[1].Score)
+	assert.Equal(t, []string{"Networking / ovn-kubernetes"}, culprits[1].MatchedComponents)
+	assert.Equal(t, "console", culprits[2].Repository, "timing only matches rank by blocker score")
+	assert.Equal(t, 0.1, culprits[2].Score)
+	assert.Empty(t, culprits[2].MatchedComponents)
+
+	previous := map[string]string{
+		"4.22.0-0.nightly-3": "4.22.0-0.nightly-2",
+		"4.22.0-0.nightly-2": "4.22.0-0.nightly-1",
+		"4.22.0-0.nightly-1": "4.22.0-0.nightly-0",
+	}
+	timeline := buildPayloadTimeline("https://sippy.example.com", streak, previous, suspects, prsByPayload)
+	require.Len(t, timeline, 3)
+	assert.Equal(t, "4.22.0-0.nightly-1", timeline[0].ReleaseTag, "timeline is oldest first")
+	assert.Equal(t, []string{"network test"}, timeline[0].NewlyFailingTests)
+	assert.Equal(t, 2, timeline[0].PullRequests)
+	assert.Equal(t, "https://sippy.example.com/api/payloads/diff?fromPayload=4.22.0-0.nightly-0&toPayload=4.22.0-0.nightly-1", timeline[0].Links["diff"])
+	assert.Empty(t, timeline[2].NewlyFailingTests)
+}
@@ -3,7 +3,6 @@ package api
 import (
 	"context"
 	"fmt"
-	"math"
 	"net/http"
 	"sort"
 	"time"
@@ -70,7 +69,17 @@ func ListPayloadJobRuns(dbClient *db.DB, filterOpts *filter.FilterOptions, relea
 
 // GetPayloadStreamTestFailures loads the most recent payloads for a stream and attempts to search for most commonly
 // failing tests, possible perma-failing blockers, etc.
-func GetPayloadStreamTestFailures(dbc *db.DB, release, stream, arch string, filterOpts *filter.FilterOptions, reportEnd time.Time) ([]*apitype.TestFailureAnalysis, error) {
+func GetPayloadStreamTestFailures(dbc *db.DB, scorer BlockerScorer, release, stream, arch string, filterOpts *filter.FilterOptions, reportEnd time.Time) ([]*apitype.TestFailureAnalysis, error) {
+	result, _, err := analyzePayloadStream(dbc, scorer, release, stream, arch, filterOpts, reportEnd)
+	if err != nil {
+		return nil, err
+	}
+	return result.TestFailures, nil
+}
+
+// analyzePayloadStream scores the test failures in a payload stream, and also returns the payloads analyzed, most
+// recent first.
+func analyzePayloadStream(dbc *db.DB, scorer BlockerScorer, release, stream, arch string, filterOpts *filter.FilterOptions, reportEnd time.Time) (*apitype.PayloadStreamAnalysis, []models.ReleaseTag, error) {
 
 	logger := log.WithFields(log.Fields{
 		"release": release,
@@ -86,14 +95,14 @@ func GetPayloadStreamTestFailures(dbc *db.DB, release, stream, arch string, filt
 	lastPayloads, err := query.GetLastPayloadTags(dbc.DB, release,
 		stream, arch, reportEnd)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	logger.WithField("payloads", len(lastPayloads)).Debug("loaded last payloads")
 	result.PayloadsAnalyzed = len(lastPayloads)
 
 	if len(lastPayloads) == 0 {
 		logger.Debug("no payload tags found")
-		return result.TestFailures, nil
+		return result, lastPayloads, nil
 	}
 
 	result.LastPhase = lastPayloads[0].Phase
@@ -130,7 +139,7 @@ func GetPayloadStreamTestFailures(dbc *db.DB, release, stream, arch string, filt
 		Order("release_tag DESC")
 	q, err = filter.FilterableDBResult(q, filterOpts, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	q.Find(&failedTests)
 	logger.WithField("failedTestCount", len(failedTests)).Debug("found failed tests")
@@ -142,60 +151,18 @@ func GetPayloadStreamTestFailures(dbc *db.DB, release, stream, arch string, filt
 
 	for _, v := range testNameToAnalysis {
 		testFailures = append(testFailures, v)
-		calculateBlockerScore(result.ConsecutiveFailedPayloads, v)
+	}
+	if err := scorer.LoadSignals(dbc, release, result.ConsecutiveFailedPayloads, testFailures, reportEnd.Add(-14*24*time.Hour)); err != nil {
+		logger.WithError(err).Warn("unable to load blocker signals, scoring on payload failures alone")
+	}
+	for _, v := range testFailures {
+		scorer.Score(result.ConsecutiveFailedPayloads, v)
 	}
 
 	// sort so the most likely blocker test failures are first in the slice:
 	sort.Slice(testFailures, func(i, j int) bool { return testFailures[i].BlockerScore >= testFailures[j].BlockerScore })
 	result.TestFailures = testFailures
-	return result.TestFailures, nil
-}
-
-// calculateBlockerScore uses the list of most recent failed payloads, and compares to the failures we found
-// for a particular test, then attempts to calculate a blocker score between 0.0 (not a blocker) and 1.0 (almost
-// certainly a blocker) based on a number of criteria.
-//
-// consecutiveFailedPayloadTags is the list of our current streak of rejected payload tags. If most recent payload
-// was accepted, this list will be empty, and we don't have much processing to do.
-func calculateBlockerScore(consecutiveFailedPayloadTags []string, ta *apitype.TestFailureAnalysis) {
-	if len(consecutiveFailedPayloadTags) == 0 {
-		// our most recent state is Accepted, could be intermittent, but for the purposes of a blocker
-		// we have to assume 0.
-		ta.BlockerScore = 0
-		ta.BlockerScoreReasons = append(ta.BlockerScoreReasons, "most recent payload was Accepted, test may be failing intermittently but cannot be fully blocking")
-		return
-	}
-
-	failedInConsecPayloads := 0
-	var failedInConsecBreakFound bool
-	failedInStreak := 0
-	for _, payloadTag := range consecutiveFailedPayloadTags {
-		if _, ok := ta.FailedPayloads[payloadTag]; ok {
-			if !failedInConsecBreakFound {
-				failedInConsecPayloads++
-			}
-			failedInStreak++
-		} else {
-			// We didn't fail in a payload in the current streak of failures, but this could be infra
-			// failures, so we keep checking if we failed in more beyond this.
-			failedInConsecBreakFound = true
-		}
-	}
-
-	// Currently assuming 25% per consecutive failure, at 4 we are 100% sure this is a blocker.
-	ta.BlockerScore = int(math.Min(float64(failedInConsecPayloads*25), 100))
-	message := fmt.Sprintf("failed in %d most recent rejected payloads", failedInConsecPayloads)
-	ta.BlockerScoreReasons = append(ta.BlockerScoreReasons, message)
-
-	// Override the score if we see we failed in a more substantial portion of the current rejected streak
-	// (a test can disappear in a run if it fails on infra or other reasons).
-	failedInStreakPercentage := int((float64(failedInStreak) / float64(len(consecutiveFailedPayloadTags))) * 100)
-	ta.BlockerScoreReasons = append(ta.BlockerScoreReasons,
-		fmt.Sprintf("failed in %d/%d of current rejected payload streak", failedInStreak, len(consecutiveFailedPayloadTags)))
-	if ta.BlockerScore >= 50 && failedInStreakPercentage >= ta.BlockerScore {
-		ta.BlockerScore = failedInStreakPercentage
-	}
-
+	return result, lastPayloads, nil
 }
 
 // GetPayloadTestFailures loads the test failures for a specific payload across all of it's jobs. At present,
@@ -291,6 +291,15 @@ func (tests TestsAPIResultBQ) limit(req *http.Request) TestsAPIResultBQ {
 }
 
 func makeTestsResultsSpec(w http.ResponseWriter, req *http.Request, release string) (TestResultsSpec, bool) {
+	spec, err := parseTestsResultsSpec(req, release)
+	if err != nil {
+		RespondWithJSON(http.StatusBadRequest, w, map[string]interface{}{"code": http.StatusBadRequest, "message": err.Error()})
+		return TestResultsSpec{}, false
+	}
+	return spec, true
+}
+
+func parseTestsResultsSpec(req *http.Request, release string) (TestResultsSpec, error) {
 	// Collapse means to produce an aggregated test result of all variant (NURP+ - network, upgrade, release, platform)
 	// combos. Uncollapsed results shows you the per-NURP+ result for each test (currently approx. 50,000 rows: filtering
 	// is advised)
@@ -311,8 +320,7 @@ func makeTestsResultsSpec(w http.ResponseWriter, req *http.Request, release stri
 	if queryFilter != "" {
 		fil = &filter.Filter{}
 		if err := json.Unmarshal([]byte(queryFilter), fil); err != nil {
-			RespondWithJSON(http.StatusBadRequest, w, map[string]interface{}{"code": http.StatusBadRequest, "message": "Could not marshal query:" + err.Error()})
-			return TestResultsSpec{}, false
+			return TestResultsSpec{}, fmt.Errorf("could not marshal query: %w", err)
 		}
 	}
 
@@ -320,8 +328,7 @@ func makeTestsResultsSpec(w http.ResponseWriter, req *http.Request, release stri
 	// If requesting a two day report, we make the comparison between the last
 	// period (typically 7 days) and the last two days.
 	if period != "" && period != "default" && period != "current" && period != "twoDay" {
-		RespondWithJSON(http.StatusBadRequest, w, map[string]interface{}{"code": http.StatusBadRequest, "message": "Unknown period"})
-		return TestResultsSpec{}, false
+		return TestResultsSpec{}, fmt.Errorf("unknown period %q", period)
 	} else if period != "twoDay" {
 		period = "default" // standardize to a single specification as this becomes part of cache key
 	}
@@ -332,7 +339,7 @@ func makeTestsResultsSpec(w http.ResponseWriter, req *http.Request, release stri
 		Collapse:       collapse,
 		IncludeOverall: includeOverall,
 		Filter:         fil,
-	}, true
+	}, nil
 }
 
 func PrintTestsJSONFromDB(
@@ -345,18 +352,36 @@ func PrintTestsJSONFromDB(
 		return
 	}
 
-	result, err := spec.buildTestsResultsFromPostgres(req.Context(), dbc, cacheClient)
+	testsResult, err := spec.testsReportFromPostgres(req, dbc, cacheClient)
 	if err != nil {
 		RespondWithJSON(http.StatusInternalServerError, w, map[string]interface{}{"code": http.StatusInternalServerError, "message": "Error building job report:" + err.Error()})
 		return
 	}
 
+	RespondWithJSON(http.StatusOK, w, testsResult)
+}
+
+// GetTestsReportFromDB returns the tests report as served by the tests API for the same request parameters,
+// including its sorting, limit and caching.
+func GetTestsReportFromDB(req *http.Request, dbc *db.DB, cacheClient cache.Cache, release string) ([]apitype.Test, error) {
+	spec, err := parseTestsResultsSpec(req, release)
+	if err != nil {
+		return nil, err
+	}
+	return spec.testsReportFromPostgres(req, dbc, cacheClient)
+}
+
+func (spec *TestResultsSpec) testsReportFromPostgres(req *http.Request, dbc *db.DB, cacheClient cache.Cache) ([]apitype.Test, error) {
+	result, err := spec.buildTestsResultsFromPostgres(req.Context(), dbc, cacheClient)
+	if err != nil {
+		return nil, err
+	}
+
 	testsResult := result.TestsAPIResult.sort(req).limit(req)
 	if result.Test != nil {
 		testsResult = append([]apitype.Test{*result.Test}, testsResult...)
 	}
-
-	RespondWithJSON(http.StatusOK, w, testsResult)
+	return testsResult, nil
 }
 
 func PrintTestsJSONFromBigQuery(release string, w http.ResponseWriter, req *http.Request, bqc *bq.Client) {
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+const variantDriftLink = "%s/api/variants/drift"
+
+// GetVariantDriftReport returns a saved variant drift report, or the most recent one when id is zero. A nil report
+// is returned when there is none.
+func GetVariantDriftReport(dbc *db.DB, id uint, req *http.Request) (*models.VariantDriftReport, error) {
+	var reports []models.VariantDriftReport
+	q := dbc.DB.Order("created_at DESC").Limit(1)
+	if id != 0 {
+		q = q.Where("id = ?", id)
+	}
+	if res := q.Find(&reports); res.Error != nil {
+		return nil, res.Error
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	report := reports[0]
+	baseURL := GetBaseURL(req)
+	report.Links = map[string]string{
+		"self":   fmt.Sprintf(variantDriftLink+"?id=%d", baseURL, report.ID),
+		"latest": fmt.Sprintf(variantDriftLink, baseURL),
+	}
+	return &report, nil
+}
@@ -35,8 +35,14 @@ type Comparison string
 const (
 	PassRate    Comparison = "pass_rate"
 	FisherExact Comparison = "fisher_exact"
+	Bayesian    Comparison = "bayesian"
 )
 
+// ComparisonModes are the comparisons that can be selected for tests with basis data.
+func ComparisonModes() []Comparison {
+	return []Comparison{FisherExact, Bayesian}
+}
+
 const (
 	// FailedFixedRegression indicates someone has claimed the bug is fix, but we see failures past the resolution time
 	FailedFixedRegression Status = -1000
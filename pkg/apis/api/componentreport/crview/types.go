@@ -14,6 +14,7 @@ type View struct {
 	AdvancedOptions reqopts.Advanced           `json:"advanced_options" yaml:"advanced_options"`
 
 	Metrics            Metrics            `json:"metrics" yaml:"metrics"`
+	Disruption         Disruption         `json:"disruption" yaml:"disruption"`
 	RegressionTracking RegressionTracking `json:"regression_tracking" yaml:"regression_tracking"`
 	AutomateJira       AutomateJira       `json:"automate_jira" yaml:"automate_jira"`
 	PrimeCache         PrimeCache         `json:"prime_cache" yaml:"prime_cache"`
@@ -23,6 +24,12 @@ type Metrics struct {
 	Enabled bool `json:"enabled" yaml:"enabled"`
 }
 
+// Disruption enables comparing disruption per backend between the view's base and sample releases, for job runs
+// matching its included variants, and publishing the comparison as metrics.
+type Disruption struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
 type RegressionTracking struct {
 	Enabled bool `json:"enabled" yaml:"enabled"`
 }
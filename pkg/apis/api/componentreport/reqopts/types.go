@@ -1,8 +1,10 @@
 package reqopts
 
 import (
+	"slices"
 	"time"
 
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
 	"github.com/openshift/sippy/pkg/apis/cache"
 	"github.com/openshift/sippy/pkg/util/sets"
 )
@@ -116,4 +118,117 @@ type Advanced struct {
 	// caused by fundamental infrastructure issues (e.g., install failures, upgrade failures).
 	// When multiple key tests fail in the same job, only the highest priority (earliest in list) test is included.
 	KeyTestNames []string `json:"key_test_names,omitempty" yaml:"key_test_names,omitempty"`
+	// ComparisonMode selects the statistical test used to compare sample and base pass rates when a test
+	// has basis data and pass rate mode is not in effect. Empty means Fisher's Exact test.
+	ComparisonMode crtest.Comparison `json:"comparison_mode,omitempty" yaml:"comparison_mode,omitempty"`
+	// ThresholdOverrides replace Confidence, PityFactor or MinimumFailure for some tests, for components whose
+	// tests are inherently noisier or more critical than the rest of the view.
+	ThresholdOverrides []ThresholdOverride `json:"threshold_overrides,omitempty" yaml:"threshold_overrides,omitempty"`
+	// ExcludedJobRunLabels removes job runs carrying any of these labels from the analysis, as a way to drop known
+	// noise. Nil means DefaultExcludedJobRunLabels, an empty list excludes nothing.
+	ExcludedJobRunLabels []string `json:"excluded_job_run_labels,omitempty" yaml:"excluded_job_run_labels,omitempty"`
+}
+
+// DefaultExcludedJobRunLabels are excluded when a request does not specify its own labels.
+var DefaultExcludedJobRunLabels = []string{"InfraFailure"}
+
+// JobRunLabelsToExclude returns the labels whose job runs are left out of the analysis.
+func (a Advanced) JobRunLabelsToExclude() []string {
+	if a.ExcludedJobRunLabels == nil {
+		return DefaultExcludedJobRunLabels
+	}
+	return a.ExcludedJobRunLabels
+}
+
+// ExcludedJobRunLabelsCacheKey returns the sorted labels to exclude for use in cache keys, or nil when they are the
+// defaults so that keys for requests not using the option are unchanged.
+func (a Advanced) ExcludedJobRunLabelsCacheKey() *[]string {
+	labels := sets.NewString(a.JobRunLabelsToExclude()...)
+	if labels.Equal(sets.NewString(DefaultExcludedJobRunLabels...)) {
+		return nil
+	}
+	sorted := labels.List()
+	return &sorted
+}
+
+// ExcludedJobRunLabel returns the first excluded label found in the given job run labels, if any.
+func (a Advanced) ExcludedJobRunLabel(jobLabels []string) (string, bool) {
+	for _, l := range a.JobRunLabelsToExclude() {
+		if slices.Contains(jobLabels, l) {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// ThresholdOverride applies to tests matching all of its non-empty Component, Capability and TestID. When several
+// overrides match a test, a test ID match beats a capability match which beats a component match, and later
+// overrides win ties. Unset thresholds fall through to less specific overrides and then the view.
+type ThresholdOverride struct {
+	Component  string `json:"component,omitempty" yaml:"component,omitempty"`
+	Capability string `json:"capability,omitempty" yaml:"capability,omitempty"`
+	TestID     string `json:"test_id,omitempty" yaml:"test_id,omitempty"`
+
+	Confidence     *int `json:"confidence,omitempty" yaml:"confidence,omitempty"`
+	PityFactor     *int `json:"pity_factor,omitempty" yaml:"pity_factor,omitempty"`
+	MinimumFailure *int `json:"minimum_failure,omitempty" yaml:"minimum_failure,omitempty"`
+}
+
+func (o ThresholdOverride) specificity() int {
+	s := 0
+	if o.Component != "" {
+		s++
+	}
+	if o.Capability != "" {
+		s += 2
+	}
+	if o.TestID != "" {
+		s += 4
+	}
+	return s
+}
+
+func (o ThresholdOverride) matches(component string, capabilities []string, testID string) bool {
+	if o.specificity() == 0 {
+		return false
+	}
+	return (o.Component == "" || o.Component == component) &&
+		(o.Capability == "" || slices.Contains(capabilities, o.Capability)) &&
+		(o.TestID == "" || o.TestID == testID)
+}
+
+// Thresholds are the significance settings in effect for a single test.
+type Thresholds struct {
+	Confidence     int
+	PityFactor     int
+	MinimumFailure int
+	// Overridden is true if any threshold override matched the test.
+	Overridden bool
+}
+
+// ThresholdsFor resolves the significance settings for a test, applying any matching overrides.
+func (a Advanced) ThresholdsFor(component string, capabilities []string, testID string) Thresholds {
+	t := Thresholds{Confidence: a.Confidence, PityFactor: a.PityFactor, MinimumFailure: a.MinimumFailure}
+	var matched []ThresholdOverride
+	for _, o := range a.ThresholdOverrides {
+		if o.matches(component, capabilities, testID) {
+			matched = append(matched, o)
+		}
+	}
+	slices.SortStableFunc(matched, func(x, y ThresholdOverride) int {
+		return x.specificity() - y.specificity()
+	})
+	for _, o := range matched {
+		t.Overridden = true
+		if o.Confidence != nil {
+			t.Confidence = *o.Confidence
+		}
+		if o.PityFactor != nil {
+			t.PityFactor = *o.PityFactor
+		}
+		if o.MinimumFailure != nil {
+			t.MinimumFailure = *o.MinimumFailure
+		}
+	}
+	return t
 }
@@ -0,0 +1,109 @@
+package reqopts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholdsFor(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	opts := Advanced{
+		Confidence:     95,
+		PityFactor:     5,
+		MinimumFailure: 3,
+		ThresholdOverrides: []ThresholdOverride{
+			{TestID: "test-flaky", Confidence: intPtr(90)},
+			{Component: "Storage", Confidence: intPtr(99), MinimumFailure: intPtr(5)},
+			{Component: "Networking", Capability: "Disruption", PityFactor: intPtr(10)},
+			{Capability: "Disruption", PityFactor: intPtr(8)},
+			{Component: "Storage", Confidence: intPtr(98)},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		component    string
+		capabilities []string
+		testID       string
+		expected     Thresholds
+	}{
+		{
+			name:      "no override",
+			component: "etcd",
+			testID:    "test-1",
+			expected:  Thresholds{Confidence: 95, PityFactor: 5, MinimumFailure: 3},
+		},
+		{
+			name:      "later component override wins ties, unset values fall through",
+			component: "Storage",
+			testID:    "test-1",
+			expected:  Thresholds{Confidence: 98, PityFactor: 5, MinimumFailure: 5, Overridden: true},
+		},
+		{
+			name:      "test ID beats component",
+			component: "Storage",
+			testID:    "test-flaky",
+			expected:  Thresholds{Confidence: 90, PityFactor: 5, MinimumFailure: 5, Overridden: true},
+		},
+		{
+			name:         "component and capability beats capability alone",
+			component:    "Networking",
+			capabilities: []string{"Routing", "Disruption"},
+			testID:       "test-2",
+			expected:     Thresholds{Confidence: 95, PityFactor: 10, MinimumFailure: 3, Overridden: true},
+		},
+		{
+			name:         "capability alone",
+			component:    "kube-apiserver",
+			capabilities: []string{"Disruption"},
+			testID:       "test-3",
+			expected:     Thresholds{Confidence: 95, PityFactor: 8, MinimumFailure: 3, Overridden: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, opts.ThresholdsFor(tt.component, tt.capabilities, tt.testID))
+		})
+	}
+}
+
+func TestExcludedJobRunLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		excluded  []string
+		jobLabels []string
+		label     string
+		excludes  bool
+	}{
+		{
+			name:      "defaults exclude infra failures",
+			jobLabels: []string{"ClusterDNSFlake", "InfraFailure"},
+			label:     "InfraFailure",
+			excludes:  true,
+		},
+		{
+			name:      "defaults keep other labels",
+			jobLabels: []string{"ClusterDNSFlake"},
+		},
+		{
+			name:      "configured labels replace defaults",
+			excluded:  []string{"ClusterDNSFlake"},
+			jobLabels: []string{"InfraFailure", "ClusterDNSFlake"},
+			label:     "ClusterDNSFlake",
+			excludes:  true,
+		},
+		{
+			name:      "empty list excludes nothing",
+			excluded:  []string{},
+			jobLabels: []string{"InfraFailure"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, excludes := Advanced{ExcludedJobRunLabels: tt.excluded}.ExcludedJobRunLabel(tt.jobLabels)
+			assert.Equal(t, tt.label, label)
+			assert.Equal(t, tt.excludes, excludes)
+		})
+	}
+}
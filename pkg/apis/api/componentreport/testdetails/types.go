@@ -2,6 +2,7 @@ package testdetails
 
 import (
 	"math/big"
+	"slices"
 	"time"
 
 	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
@@ -27,6 +28,9 @@ type Report struct {
 	// and can be used in some capacity.
 	Analyses []Analysis `json:"analyses"`
 
+	// ExcludedJobRuns are the job runs left out of the analyses because they carried an excluded label.
+	ExcludedJobRuns []ExcludedJobRun `json:"excluded_job_runs,omitempty"`
+
 	// Links contains HATEOAS-style links for this report (not stored in database)
 	Links map[string]string `json:"links,omitempty"`
 }
@@ -76,6 +80,9 @@ type TestComparison struct {
 	// FisherExact indicates the confidence of a regression after applying Fisher's Exact Test.
 	FisherExact *float64 `json:"fisher_exact,omitempty"`
 
+	// Bayesian holds the posterior comparison of pass rates when the Bayesian comparison mode is used.
+	Bayesian *BayesianAnalysis `json:"bayesian,omitempty"`
+
 	// BaseStats may not be present in the response, i.e. new tests regressed because of their pass rate.
 	BaseStats *ReleaseStats `json:"base_stats,omitempty"`
 
@@ -85,6 +92,36 @@ type TestComparison struct {
 	// Regression is populated with data on when we first detected this regression. If unset it implies
 	// the regression tracker has not yet run to find it, or you're using report params/a view without regression tracking.
 	Regression *models.TestRegression `json:"regression,omitempty"`
+
+	// Adjustments names the middleware or view threshold overrides that altered the inputs or outcome of this
+	// comparison, for example the regression tracker lowering the required confidence while a regression is open.
+	Adjustments []string `json:"adjustments,omitempty"`
+}
+
+// AddAdjustment records that the named middleware adjusted this comparison.
+func (tc *TestComparison) AddAdjustment(name string) {
+	if !slices.Contains(tc.Adjustments, name) {
+		tc.Adjustments = append(tc.Adjustments, name)
+	}
+}
+
+// BayesianAnalysis summarizes a beta-binomial comparison of the sample and base pass rates,
+// each modeled with a uniform Beta(1, 1) prior updated with observed passes and failures.
+type BayesianAnalysis struct {
+	// ProbabilityOfRegression is the posterior probability that the sample pass rate is below
+	// the base pass rate by more than the pity factor.
+	ProbabilityOfRegression float64 `json:"probability_of_regression"`
+	// ProbabilityOfImprovement is the posterior probability that the sample pass rate is above the base pass rate.
+	ProbabilityOfImprovement float64 `json:"probability_of_improvement"`
+	// CredibleLevel is the mass of the equal-tailed credible intervals, e.g. 0.95.
+	CredibleLevel          float64          `json:"credible_level"`
+	BaseCredibleInterval   CredibleInterval `json:"base_credible_interval"`
+	SampleCredibleInterval CredibleInterval `json:"sample_credible_interval"`
+}
+
+type CredibleInterval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
 }
 
 type ReleaseStats struct {
@@ -105,6 +142,16 @@ type JobStats struct {
 	Significant       bool          `json:"significant"`
 }
 
+// ExcludedJobRun identifies a job run that was not counted, and the label that excluded it.
+type ExcludedJobRun struct {
+	Release   string    `json:"release"`
+	JobName   string    `json:"job_name"`
+	JobURL    string    `json:"job_url"`
+	JobRunID  string    `json:"job_run_id"`
+	StartTime time.Time `json:"start_time"`
+	Label     string    `json:"label"`
+}
+
 type JobRunStats struct {
 	JobURL    string    `json:"job_url"`
 	JobRunID  string    `json:"job_run_id"`
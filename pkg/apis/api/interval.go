@@ -59,3 +59,55 @@ type LegacyEventIntervalList struct {
 	Items                  []LegacyEventInterval `json:"items"`
 	IntervalFilesAvailable []string              `json:"intervalFilesAvailable"`
 }
+
+// IntervalAggregateReport summarizes the intervals of many job runs, so that recurring disruption or operator
+// conditions can be investigated without opening each run.
+type IntervalAggregateReport struct {
+	Release  string    `json:"release"`
+	JobNames []string  `json:"job_names"`
+	Variants []string  `json:"variants"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	// RunsAnalyzed is the number of runs whose intervals were loaded; runs that could not be loaded, or have no
+	// interval files yet, report an error.
+	RunsAnalyzed int                    `json:"runs_analyzed"`
+	Runs         []IntervalAggregateRun `json:"runs"`
+	Aggregates   []IntervalAggregate    `json:"aggregates"`
+	Links        map[string]string      `json:"links"`
+}
+
+// IntervalAggregateRun is a job run included in an IntervalAggregateReport.
+type IntervalAggregateRun struct {
+	ProwJobRunID int64             `json:"prow_job_run_id"`
+	JobName      string            `json:"job_name"`
+	URL          string            `json:"url"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Failed       bool              `json:"failed"`
+	Error        string            `json:"error,omitempty"`
+	Links        map[string]string `json:"links"`
+}
+
+// IntervalAggregate is the statistics for one kind of interval, identified by its source, locator and reason,
+// across the runs of an IntervalAggregateReport. Durations are in seconds.
+type IntervalAggregate struct {
+	Source      string `json:"source"`
+	LocatorType string `json:"locator_type"`
+	// Locator holds the locator keys that identify the same thing in every run, such as the cluster operator or
+	// disruption backend, rather than per run names such as pods or nodes.
+	Locator   string `json:"locator"`
+	Condition string `json:"condition,omitempty"`
+	Reason    string `json:"reason"`
+
+	Occurrences   int     `json:"occurrences"`
+	Runs          int     `json:"runs"`
+	RunPercentage float64 `json:"run_percentage"`
+
+	MedianDuration float64 `json:"median_duration"`
+	P95Duration    float64 `json:"p95_duration"`
+	MaxDuration    float64 `json:"max_duration"`
+
+	// FailureOverlaps counts the occurrences that overlapped a failed test in the same run, and
+	// RunsWithFailureOverlap the runs where at least one did.
+	FailureOverlaps        int `json:"failure_overlaps"`
+	RunsWithFailureOverlap int `json:"runs_with_failure_overlap"`
+}
@@ -823,6 +823,60 @@ type PayloadStreamAnalysis struct {
 	ConsecutiveFailedPayloads []string `json:"consecutive_failed_payloads"`
 }
 
+// PayloadInvestigation lines up when the likely blocking tests of a rejected payload streak started failing with the
+// pull requests that landed in those payloads, and ranks the pull requests most likely to have caused the rejections.
+type PayloadInvestigation struct {
+	Release      string `json:"release"`
+	Stream       string `json:"stream"`
+	Architecture string `json:"architecture"`
+	// RejectedStreak is the current streak of rejected payloads, oldest first. It is empty if the most recent
+	// payload was accepted.
+	RejectedStreak []string `json:"rejected_streak"`
+	// Timeline has an entry per payload in the rejected streak, oldest first.
+	Timeline            []PayloadTimelineEntry `json:"timeline"`
+	SuspectTests        []SuspectTest          `json:"suspect_tests"`
+	CulpritPullRequests []CulpritPullRequest   `json:"culprit_pull_requests"`
+	Links               map[string]string      `json:"links"`
+}
+
+// PayloadTimelineEntry is a payload in a rejected streak, with the suspect tests that started failing in it.
+type PayloadTimelineEntry struct {
+	ReleaseTag         string `json:"release_tag"`
+	PreviousReleaseTag string `json:"previous_release_tag"`
+	// PullRequests is the number of pull requests that landed in the payload, only counted for payloads in which a
+	// suspect test started failing.
+	PullRequests      int               `json:"pull_requests"`
+	NewlyFailingTests []string          `json:"newly_failing_tests"`
+	Links             map[string]string `json:"links"`
+}
+
+// SuspectTest is a test whose blocker score makes it a likely cause of a rejected payload streak.
+type SuspectTest struct {
+	Name          string `json:"name"`
+	BlockerScore  int    `json:"blocker_score"`
+	Component     string `json:"component,omitempty"`
+	JiraComponent string `json:"jira_component,omitempty"`
+	// FirstFailedPayload is the oldest payload in the rejected streak the test failed in.
+	FirstFailedPayload string `json:"first_failed_payload"`
+	FailedPayloads     int    `json:"failed_payloads"`
+}
+
+// CulpritPullRequest is a pull request that landed in a payload where suspect tests started failing, scored by the
+// blocker scores of those tests and boosted when its repository overlaps with their components.
+type CulpritPullRequest struct {
+	URL           string `json:"url"`
+	PullRequestID string `json:"pull_request_id"`
+	Repository    string `json:"repository"`
+	Description   string `json:"description"`
+	BugURL        string `json:"bug_url,omitempty"`
+	// Payload is the payload the pull request landed in.
+	Payload           string   `json:"payload"`
+	Score             float64  `json:"score"`
+	Tests             []string `json:"tests"`
+	MatchedComponents []string `json:"matched_components"`
+	Reasons           []string `json:"reasons"`
+}
+
 // TestFailureAnalysis represents a test and the number of times it failed over some number of jobs.
 type TestFailureAnalysis struct {
 	Name string `json:"name"`
@@ -837,10 +891,25 @@ type TestFailureAnalysis struct {
 	// BlockerScoreReasons explain to humans why the blocker_score was given.
 	BlockerScoreReasons []string `json:"blocker_score_reasons"`
 
+	// BlockerSignals are the signals beyond payload failures that contributed to the blocker score.
+	BlockerSignals *BlockerSignals `json:"blocker_signals,omitempty"`
+
 	// FailedPayloads contains information about where this test failed in a specific rejected payload.
 	FailedPayloads map[string]*FailedPayload `json:"failed_payloads"`
 }
 
+// BlockerSignals are what is known about a failing test beyond the payloads it failed in.
+type BlockerSignals struct {
+	// BlockingJob is set when the test failed in a blocking payload job.
+	BlockingJob bool `json:"blocking_job"`
+	// OpenTriage is set when a regression of the test has an open component readiness triage.
+	OpenTriage bool `json:"open_triage"`
+	// IntentionalRegression is set when the test has a regression allowance in the release.
+	IntentionalRegression bool `json:"intentional_regression"`
+	// PeriodicFailures is the number of times the test failed in periodic jobs that are not part of payloads.
+	PeriodicFailures int `json:"periodic_failures"`
+}
+
 type FailedPayload struct {
 	// FailedJobs is a list of job names the test failed in for this payload.
 	FailedJobs []string `json:"failed_jobs"`
@@ -1019,6 +1088,53 @@ type DisruptionReportRow struct {
 	OS                       string  `json:"os"`
 }
 
+// DisruptionWindow is the release and time range one side of a DisruptionComparison is drawn from.
+type DisruptionWindow struct {
+	Release string    `json:"release"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// DisruptionComparison compares disruption per backend between a base and a sample window, for the job runs
+// matching the included variants.
+type DisruptionComparison struct {
+	View            string                        `json:"view,omitempty"`
+	Base            DisruptionWindow              `json:"base"`
+	Sample          DisruptionWindow              `json:"sample"`
+	IncludeVariants map[string][]string           `json:"include_variants,omitempty"`
+	Confidence      int                           `json:"confidence"`
+	Backends        []DisruptionBackendComparison `json:"backends"`
+	Links           map[string]string             `json:"links,omitempty"`
+}
+
+// DisruptionStats are the disruption percentiles, in seconds, of a backend's job runs in one window.
+type DisruptionStats struct {
+	JobRuns            int     `json:"job_runs"`
+	RunsWithDisruption int     `json:"runs_with_disruption"`
+	P50                float64 `json:"p50"`
+	P95                float64 `json:"p95"`
+	P99                float64 `json:"p99"`
+}
+
+// DisruptionBackendComparison compares one backend's disruption between the windows. Deltas are sample minus base.
+// A change is significant when either the disruption rate, the fraction of job runs seeing any disruption, or the
+// distribution of disruption seconds per job run changed, as percentiles of mostly zero disruption say little on
+// their own.
+type DisruptionBackendComparison struct {
+	BackendName string          `json:"backend_name"`
+	Base        DisruptionStats `json:"base"`
+	Sample      DisruptionStats `json:"sample"`
+	P50Delta    float64         `json:"p50_delta"`
+	P95Delta    float64         `json:"p95_delta"`
+	P99Delta    float64         `json:"p99_delta"`
+	// RateFisherExact is the p-value of Fisher's exact test on the disruption rate.
+	RateFisherExact float64 `json:"rate_fisher_exact"`
+	// SecondsMannWhitney is the p-value of the Mann-Whitney U test on the disruption seconds per job run.
+	SecondsMannWhitney float64 `json:"seconds_mann_whitney"`
+	Significant        bool    `json:"significant"`
+	Status             string  `json:"status"`
+}
+
 type SippyViews struct {
 	ComponentReadiness []crview.View `json:"component_readiness" yaml:"component_readiness"`
 }
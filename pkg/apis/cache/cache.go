@@ -9,6 +9,33 @@ import (
 type Cache interface {
 	Get(ctx context.Context, key string, duration time.Duration) ([]byte, error)
 	Set(ctx context.Context, key string, content []byte, duration time.Duration) error
+	// List returns the entries whose keys start with prefix; an empty prefix lists everything.
+	List(ctx context.Context, prefix string) ([]Entry, error)
+	// Delete removes the entry for key. Deleting a key that is not present is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// TTLGetter is implemented by caches that can report how long an item has left along with its content.
+type TTLGetter interface {
+	// GetWithTTL returns the item for key as Get does, and how long it has left, or zero if it does not expire.
+	GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, error)
+}
+
+// Entry describes an item held in a cache, without its content.
+type Entry struct {
+	Key string `json:"key"`
+	// Size is the stored size in bytes, so it reflects any compression. Zero when the backend cannot report it cheaply.
+	Size int64 `json:"size"`
+	// ExpiresAt is nil when the entry does not expire.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// TTL returns how long the entry has left as of now, or zero if it does not expire.
+func (e Entry) TTL(now time.Time) time.Duration {
+	if e.ExpiresAt == nil {
+		return 0
+	}
+	return e.ExpiresAt.Sub(now)
 }
 
 type APIResponse struct {
@@ -37,6 +64,10 @@ type RequestOptions struct {
 	// RefreshRecent indicates a more discriminating approach to ForceRefresh.
 	// When set, queries that provide a data end date will refresh if that end date is newer than "StableAge".
 	RefreshRecent bool
+	// StaleWhileRevalidate, when set, is how long past expiry an entry may still be served while a single
+	// background regeneration refreshes it, instead of blocking the request on regeneration. Set it only for the
+	// outermost lookup of a request, never on options passed to the queries that generate the entry.
+	StaleWhileRevalidate time.Duration
 }
 
 var StandardStableAgeCR = time.Hour * 24 * 7    // age at which component readiness data should be considered "stable"
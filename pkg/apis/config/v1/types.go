@@ -4,6 +4,7 @@ type SippyConfig struct {
 	Prow                     ProwConfig               `yaml:"prow"`
 	Releases                 map[string]ReleaseConfig `yaml:"releases"`
 	ComponentReadinessConfig ComponentReadinessConfig `yaml:"componentReadiness"`
+	BlockerScoring           BlockerScoringConfig     `yaml:"blockerScoring,omitempty"`
 }
 
 type ProwConfig struct {
@@ -42,3 +43,30 @@ type VariantJunitTableOverride struct {
 	// As with views, this is specified as a string of the form end-90d.
 	RelativeStart string `yaml:"relativeStart,omitempty"`
 }
+
+// BlockerScoringConfig tunes how payload test failures are scored as likely blockers.
+type BlockerScoringConfig struct {
+	// Default overrides the built-in weights for all streams.
+	Default BlockerScoreWeights `yaml:"default,omitempty"`
+	// Streams override the weights for a payload stream, keyed by stream (e.g. nightly) or by release and stream
+	// (e.g. 4.22-nightly). The most specific key wins, and weights it omits are inherited.
+	Streams map[string]BlockerScoreWeights `yaml:"streams,omitempty"`
+}
+
+// BlockerScoreWeights are the points a test failure scores towards a blocker score between 0 and 100. Omitted weights
+// are inherited from the less specific configuration.
+type BlockerScoreWeights struct {
+	// ConsecutiveFailure is scored for each of the most recent rejected payloads the test failed in without a break.
+	ConsecutiveFailure *int `yaml:"consecutiveFailure,omitempty"`
+	// StreakOverrideMinimum is the score at which the percentage of the rejected streak the test failed in replaces
+	// the score, if higher, as tests can be missing from a payload's results due to infrastructure failures.
+	StreakOverrideMinimum *int `yaml:"streakOverrideMinimum,omitempty"`
+	// BlockingJob is scored when the test failed in a blocking payload job.
+	BlockingJob *int `yaml:"blockingJob,omitempty"`
+	// OpenTriage is scored when the test has an open component readiness triage.
+	OpenTriage *int `yaml:"openTriage,omitempty"`
+	// IntentionalRegression is scored when the test has a regression allowance in the release.
+	IntentionalRegression *int `yaml:"intentionalRegression,omitempty"`
+	// PeriodicFailures is scored when the test also fails in periodic jobs that are not part of payloads.
+	PeriodicFailures *int `yaml:"periodicFailures,omitempty"`
+}
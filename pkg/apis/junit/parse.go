@@ -0,0 +1,19 @@
+package junit
+
+import (
+	"encoding/xml"
+)
+
+// Parse reads JUnit XML whose root element is either testsuites or a single testsuite.
+func Parse(data []byte) (*TestSuites, error) {
+	suites := &TestSuites{}
+	if err := xml.Unmarshal(data, suites); err == nil {
+		return suites, nil
+	}
+
+	suite := &TestSuite{}
+	if err := xml.Unmarshal(data, suite); err != nil {
+		return nil, err
+	}
+	return &TestSuites{Suites: []*TestSuite{suite}}, nil
+}
@@ -34,3 +34,21 @@ func Test_CanUnmarshalJunit(t *testing.T) {
 	}
 
 }
+
+func Test_ParseSingleTestSuite(t *testing.T) {
+	suites, err := Parse([]byte(`<testsuite name="openshift-tests"><testcase name="a"/><testcase name="b"><failure message="boom"/></testcase></testsuite>`))
+	if err != nil {
+		t.Fatalf("could not parse: %s", err.Error())
+	}
+	if len(suites.Suites) != 1 || len(suites.Suites[0].TestCases) != 2 {
+		t.Fatalf("expected one suite with two test cases, got %+v", suites)
+	}
+
+	suites, err = Parse([]byte(junitXML))
+	if err != nil {
+		t.Fatalf("could not parse: %s", err.Error())
+	}
+	if len(suites.Suites) != 1 || len(suites.Suites[0].TestCases) != 14 {
+		t.Fatalf("expected one suite with 14 test cases, got %d suites", len(suites.Suites))
+	}
+}
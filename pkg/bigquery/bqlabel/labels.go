@@ -76,6 +76,7 @@ const (
 	TDJunitBase                         QueryValue = "test-details-junit-base"
 	TDJunitSample                       QueryValue = "test-details-junit-sample"
 	DisruptionDelta                     QueryValue = "disruption-delta"
+	DisruptionComparison                QueryValue = "disruption-comparison"
 	ReleaseAllReleases                  QueryValue = "release-all-releases"
 	BugLoaderJobBugMappings             QueryValue = "bug-loader-job-bug-mappings"
 	BugLoaderTestBugMappings            QueryValue = "bug-loader-test-bug-mappings"
@@ -101,6 +102,8 @@ const (
 	JobVariants                         QueryValue = "job-variants"
 	PRTestResults                       QueryValue = "pr-test-results"
 	CacheLookup                         QueryValue = "cache-lookup"
+	CacheList                           QueryValue = "cache-list"
+	CacheDelete                         QueryValue = "cache-delete"
 )
 
 // sanitizeLabelValue sanitizes a label value to meet BigQuery requirements:
@@ -3,6 +3,7 @@ package bigquerycache
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -316,6 +317,105 @@ func (c Cache) Set(ctx context.Context, key string, content []byte, duration tim
 	return nil
 }
 
+// List merges what the warm cache holds with the unexpired entries in the backend table. Sizes come only from the
+// warm cache, since measuring stored data in the table would scan (and bill for) all of it.
+func (c Cache) List(ctx context.Context, prefix string) ([]cache.Entry, error) {
+	byKey := map[string]cache.Entry{}
+	if c.client.Cache != nil {
+		warm, err := c.client.Cache.List(ctx, prefix)
+		if err != nil {
+			logrus.WithError(err).Warn("error listing warm cache")
+		}
+		for _, entry := range warm {
+			byKey[entry.Key] = entry
+		}
+	}
+
+	query := c.client.Query(ctx, bqlabel.CacheList, fmt.Sprintf(
+		"SELECT key, MAX(expiration) AS expiration FROM `%s.%s` "+
+			`WHERE %s > TIMESTAMP(@expByNowTime)
+		  AND expiration > TIMESTAMP(@expTime)
+		  AND STARTS_WITH(key, @prefixParam)
+		GROUP BY key`,
+		c.client.Dataset, cachedTable, partitionColumn))
+	query.Parameters = []bigquery.QueryParameter{
+		{
+			Name:  "expByNowTime",
+			Value: time.Now().Add(-1 * c.maxExpiration).Format(time.RFC3339),
+		},
+		{
+			Name:  "expTime",
+			Value: time.Now().Format(time.RFC3339),
+		},
+		{
+			Name:  "prefixParam",
+			Value: prefix,
+		},
+	}
+	it, err := sippybq.LoggedRead(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		record := CacheRecord{}
+		err := it.Next(&record)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry := byKey[record.Key]
+		entry.Key = record.Key
+		expiration := record.Expiration
+		entry.ExpiresAt = &expiration
+		byKey[record.Key] = entry
+	}
+
+	entries := make([]cache.Entry, 0, len(byKey))
+	for _, entry := range byKey {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+// Delete removes the key from the warm cache and the backend table. BigQuery refuses DML against rows still in
+// the streaming buffer, so an entry written in roughly the last half hour cannot be deleted until it is flushed.
+func (c Cache) Delete(ctx context.Context, key string) error {
+	if c.client.Cache != nil {
+		if err := c.client.Cache.Delete(ctx, key); err != nil {
+			logrus.WithError(err).Errorf("Failure deleting %s from warm cache", key)
+		}
+	}
+	if c.readOnly {
+		return fmt.Errorf("cannot delete %s from read only persistent cache", key)
+	}
+
+	query := c.client.Query(ctx, bqlabel.CacheDelete, fmt.Sprintf(
+		"DELETE FROM `%s.%s` WHERE %s > TIMESTAMP(@expByNowTime) AND key = @keyParam",
+		c.client.Dataset, cachedTable, partitionColumn))
+	query.Parameters = []bigquery.QueryParameter{
+		{
+			Name:  "expByNowTime",
+			Value: time.Now().Add(-1 * c.maxExpiration).Format(time.RFC3339),
+		},
+		{
+			Name:  "keyParam",
+			Value: key,
+		},
+	}
+	job, err := query.Run(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return status.Err()
+}
+
 // Save implements the ValueSaver interface.
 // Can just use the struct as well
 func (c *CacheRecord) Save() (row map[string]bigquery.Value, insertID string, err error) {
@@ -10,6 +10,7 @@ import (
 	// simple checksum usage for validation
 	"crypto/md5" // nolint:gosec
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/openshift/sippy/pkg/apis/cache"
@@ -72,6 +73,21 @@ func (c Cache) Set(ctx context.Context, key string, content []byte, duration tim
 	return c.Cache.Set(ctx, cachePrefix+key, data, duration)
 }
 
+func (c Cache) List(ctx context.Context, prefix string) ([]cache.Entry, error) {
+	entries, err := c.Cache.List(ctx, cachePrefix+prefix)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Key = strings.TrimPrefix(entries[i].Key, cachePrefix)
+	}
+	return entries, nil
+}
+
+func (c Cache) Delete(ctx context.Context, key string) error {
+	return c.Cache.Delete(ctx, cachePrefix+key)
+}
+
 func compress(value []byte) ([]byte, [16]byte, error) {
 	var buf bytes.Buffer
 	sum := md5.Sum(value) // nolint:gosec
@@ -39,3 +39,24 @@ func TestCompression(t *testing.T) {
 	validation := string(uncompressed)
 	assert.Equal(t, data, validation)
 }
+
+func TestListAndDelete(t *testing.T) {
+	backing := &util.PseudoCache{Cache: make(map[string][]byte)}
+	cache, err := NewCompressedCache(backing)
+	assert.Nil(t, err, "Failed to create compression cache: %v", err)
+
+	assert.Nil(t, cache.Set(context.TODO(), "report~1", []byte("first"), time.Hour))
+	assert.Nil(t, cache.Set(context.TODO(), "report~2", []byte("second"), time.Hour))
+	backing.Cache["report~uncompressed"] = []byte("not ours")
+
+	entries, err := cache.List(context.TODO(), "report~")
+	assert.Nil(t, err, "Failed to list cache data: %v", err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "report~1", entries[0].Key)
+	assert.Equal(t, int64(len(backing.Cache[cachePrefix+"report~1"])), entries[0].Size)
+
+	assert.Nil(t, cache.Delete(context.TODO(), "report~1"))
+	_, ok := backing.Cache[cachePrefix+"report~1"]
+	assert.False(t, ok)
+	assert.Contains(t, backing.Cache, "report~uncompressed")
+}
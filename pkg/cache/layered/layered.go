@@ -0,0 +1,81 @@
+package layered
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/apis/cache"
+)
+
+// Cache checks a fast local tier before falling through to a shared remote tier such as redis, copying
+// remote hits into the local tier so repeated requests to the same instance avoid the network round trip.
+type Cache struct {
+	local  cache.Cache
+	remote cache.Cache
+	// localTTL caps how long an item lives in the local tier, limiting how stale an instance can be
+	// relative to the remote tier once another instance has refreshed it.
+	localTTL time.Duration
+}
+
+func NewLayeredCache(local, remote cache.Cache, localTTL time.Duration) *Cache {
+	return &Cache{
+		local:    local,
+		remote:   remote,
+		localTTL: localTTL,
+	}
+}
+
+func (c *Cache) Get(ctx context.Context, key string, duration time.Duration) ([]byte, error) {
+	if data, err := c.local.Get(ctx, key, duration); err == nil && data != nil {
+		return data, nil
+	}
+
+	data, remaining, err := c.getRemote(ctx, key, duration)
+	if err != nil || data == nil {
+		return data, err
+	}
+	// the local copy must not outlive the remote item, or it would be served after the remote tier expired it
+	if err := c.local.Set(ctx, key, data, c.localDuration(remaining)); err != nil {
+		log.WithError(err).Warnf("error back-filling local cache for %s", key)
+	}
+	return data, nil
+}
+
+// getRemote reads an item from the remote tier, along with how long it has left when the remote tier can report it.
+// Otherwise, as for items that do not expire, the remaining time is zero and the local copy lives for localTTL.
+func (c *Cache) getRemote(ctx context.Context, key string, duration time.Duration) ([]byte, time.Duration, error) {
+	if ttlGetter, ok := c.remote.(cache.TTLGetter); ok {
+		return ttlGetter.GetWithTTL(ctx, key)
+	}
+	data, err := c.remote.Get(ctx, key, duration)
+	return data, 0, err
+}
+
+func (c *Cache) Set(ctx context.Context, key string, content []byte, duration time.Duration) error {
+	if err := c.local.Set(ctx, key, content, c.localDuration(duration)); err != nil {
+		log.WithError(err).Warnf("error setting local cache for %s", key)
+	}
+	return c.remote.Set(ctx, key, content, duration)
+}
+
+// List reports what the remote tier holds, since the local tier only ever holds a subset of it.
+func (c *Cache) List(ctx context.Context, prefix string) ([]cache.Entry, error) {
+	return c.remote.List(ctx, prefix)
+}
+
+// Delete only reaches this instance's local tier; other instances keep their copy for up to localTTL.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	if err := c.local.Delete(ctx, key); err != nil {
+		log.WithError(err).Warnf("error deleting %s from local cache", key)
+	}
+	return c.remote.Delete(ctx, key)
+}
+
+func (c *Cache) localDuration(duration time.Duration) time.Duration {
+	if c.localTTL > 0 && (duration <= 0 || duration > c.localTTL) {
+		return c.localTTL
+	}
+	return duration
+}
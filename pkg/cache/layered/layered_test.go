@@ -0,0 +1,96 @@
+package layered
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/sippy/pkg/cache/memory"
+	"github.com/openshift/sippy/pkg/util"
+)
+
+func TestLayeredCacheBackfillsLocal(t *testing.T) {
+	local, err := memory.NewMemoryCache(1024, 0)
+	require.NoError(t, err)
+	remote := &util.PseudoCache{Cache: map[string][]byte{"key": []byte("remote value")}}
+	c := NewLayeredCache(local, remote, time.Minute)
+
+	data, err := c.Get(context.TODO(), "key", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "remote value", string(data))
+
+	data, err = local.Get(context.TODO(), "key", time.Hour)
+	require.NoError(t, err, "remote hit should be copied into the local tier")
+	assert.Equal(t, "remote value", string(data))
+
+	// once local has it, the remote tier is no longer consulted
+	delete(remote.Cache, "key")
+	data, err = c.Get(context.TODO(), "key", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "remote value", string(data))
+}
+
+func TestLayeredCacheSetWritesBothTiers(t *testing.T) {
+	local, err := memory.NewMemoryCache(1024, 0)
+	require.NoError(t, err)
+	remote := &util.PseudoCache{Cache: map[string][]byte{}}
+	c := NewLayeredCache(local, remote, time.Minute)
+
+	require.NoError(t, c.Set(context.TODO(), "key", []byte("value"), time.Hour))
+	assert.Equal(t, "value", string(remote.Cache["key"]))
+	data, err := local.Get(context.TODO(), "key", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "value", string(data))
+}
+
+func TestLayeredCacheMiss(t *testing.T) {
+	local, err := memory.NewMemoryCache(1024, 0)
+	require.NoError(t, err)
+	c := NewLayeredCache(local, &util.PseudoCache{Cache: map[string][]byte{}}, time.Minute)
+
+	data, err := c.Get(context.TODO(), "missing", time.Hour)
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Equal(t, 0, local.Len())
+}
+
+func TestLocalDuration(t *testing.T) {
+	c := NewLayeredCache(nil, nil, time.Minute)
+	assert.Equal(t, time.Minute, c.localDuration(0))
+	assert.Equal(t, time.Minute, c.localDuration(time.Hour))
+	assert.Equal(t, time.Second, c.localDuration(time.Second))
+
+	uncapped := NewLayeredCache(nil, nil, 0)
+	assert.Equal(t, time.Hour, uncapped.localDuration(time.Hour))
+}
+
+// ttlCache is a remote tier that reports the same remaining TTL for every item.
+type ttlCache struct {
+	util.PseudoCache
+	ttl time.Duration
+}
+
+func (c *ttlCache) GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	data, err := c.Get(ctx, key, 0)
+	return data, c.ttl, err
+}
+
+func TestLayeredCacheBackfillExpiresWithRemote(t *testing.T) {
+	local, err := memory.NewMemoryCache(1024, 0)
+	require.NoError(t, err)
+	remote := &ttlCache{PseudoCache: util.PseudoCache{Cache: map[string][]byte{"key": []byte("remote value")}}, ttl: 5 * time.Second}
+	c := NewLayeredCache(local, remote, time.Minute)
+
+	before := time.Now()
+	_, err = c.Get(context.TODO(), "key", time.Hour)
+	require.NoError(t, err)
+
+	entries, err := local.List(context.TODO(), "key")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].ExpiresAt)
+	assert.LessOrEqual(t, entries[0].TTL(before), 6*time.Second, "the local copy expires with the remote item, not after localTTL")
+}
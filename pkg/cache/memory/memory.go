@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/sippy/pkg/apis/cache"
+)
+
+// ErrCacheMiss is returned by Get when the key is not present or has expired, mirroring the
+// error a redis client returns so callers treat both the same way.
+var ErrCacheMiss = errors.New("memory cache: key not found")
+
+// Cache is an in-process LRU cache bounded by the total size of its keys and values. It suits a
+// single sippy instance without redis, or the near tier of a layered cache in front of one.
+type Cache struct {
+	maxBytes   int64
+	defaultTTL time.Duration
+	now        func() time.Time
+
+	lock    sync.Mutex
+	size    int64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type entry struct {
+	key       string
+	content   []byte
+	expiresAt time.Time
+}
+
+func (e *entry) size() int64 {
+	return int64(len(e.key) + len(e.content))
+}
+
+// NewMemoryCache creates a cache holding at most maxBytes of keys and values. defaultTTL applies to items
+// set without a duration; zero means such items only leave the cache when evicted.
+func NewMemoryCache(maxBytes int64, defaultTTL time.Duration) (*Cache, error) {
+	if maxBytes <= 0 {
+		return nil, errors.New("memory cache size must be greater than zero")
+	}
+	return &Cache{
+		maxBytes:   maxBytes,
+		defaultTTL: defaultTTL,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}, nil
+}
+
+func (c *Cache) Get(_ context.Context, key string, _ time.Duration) ([]byte, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	e := elem.Value.(*entry)
+	if !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt) {
+		c.remove(elem)
+		return nil, ErrCacheMiss
+	}
+	c.order.MoveToFront(elem)
+	return e.content, nil
+}
+
+func (c *Cache) Set(_ context.Context, key string, content []byte, duration time.Duration) error {
+	if duration <= 0 {
+		duration = c.defaultTTL
+	}
+	e := &entry{key: key, content: content}
+	if duration > 0 {
+		e.expiresAt = c.now().Add(duration)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	// an item that can never fit would otherwise flush everything else on its way through
+	if e.size() > c.maxBytes {
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(e)
+	c.size += e.size()
+	for c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *Cache) List(_ context.Context, prefix string) ([]cache.Entry, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	entries := []cache.Entry{}
+	for key, elem := range c.entries {
+		e := elem.Value.(*entry)
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if !e.expiresAt.IsZero() && !now.Before(e.expiresAt) {
+			continue
+		}
+		item := cache.Entry{Key: key, Size: int64(len(e.content))}
+		if !e.expiresAt.IsZero() {
+			expiresAt := e.expiresAt
+			item.ExpiresAt = &expiresAt
+		}
+		entries = append(entries, item)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+func (c *Cache) Delete(_ context.Context, key string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	return nil
+}
+
+// Len returns the number of items currently held, including any expired items not yet evicted.
+func (c *Cache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache) remove(elem *list.Element) {
+	e := c.order.Remove(elem).(*entry)
+	delete(c.entries, e.key)
+	c.size -= e.size()
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCacheGetSet(t *testing.T) {
+	c, err := NewMemoryCache(1024, 0)
+	require.NoError(t, err)
+
+	_, err = c.Get(context.TODO(), "missing", time.Hour)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	require.NoError(t, c.Set(context.TODO(), "key", []byte("value"), time.Hour))
+	data, err := c.Get(context.TODO(), "key", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "value", string(data))
+
+	require.NoError(t, c.Set(context.TODO(), "key", []byte("replaced"), time.Hour))
+	data, err = c.Get(context.TODO(), "key", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "replaced", string(data))
+	assert.Equal(t, 1, c.Len())
+	assert.Equal(t, int64(len("key")+len("replaced")), c.size)
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, err := NewMemoryCache(1024, 10*time.Minute)
+	require.NoError(t, err)
+	c.now = func() time.Time { return now }
+
+	require.NoError(t, c.Set(context.TODO(), "explicit", []byte("a"), time.Minute))
+	require.NoError(t, c.Set(context.TODO(), "default", []byte("b"), 0))
+
+	now = now.Add(2 * time.Minute)
+	_, err = c.Get(context.TODO(), "explicit", 0)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	_, err = c.Get(context.TODO(), "default", 0)
+	assert.NoError(t, err)
+
+	now = now.Add(10 * time.Minute)
+	_, err = c.Get(context.TODO(), "default", 0)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	// each entry is a 2 byte key plus 8 byte value, so three fit
+	c, err := NewMemoryCache(30, 0)
+	require.NoError(t, err)
+	value := []byte("12345678")
+
+	require.NoError(t, c.Set(context.TODO(), "k1", value, 0))
+	require.NoError(t, c.Set(context.TODO(), "k2", value, 0))
+	require.NoError(t, c.Set(context.TODO(), "k3", value, 0))
+	// touching k1 makes k2 the least recently used
+	_, err = c.Get(context.TODO(), "k1", 0)
+	require.NoError(t, err)
+	require.NoError(t, c.Set(context.TODO(), "k4", value, 0))
+
+	_, err = c.Get(context.TODO(), "k2", 0)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	for _, key := range []string{"k1", "k3", "k4"} {
+		_, err = c.Get(context.TODO(), key, 0)
+		assert.NoError(t, err, key)
+	}
+
+	// an item larger than the whole cache is dropped rather than evicting everything
+	require.NoError(t, c.Set(context.TODO(), "huge", make([]byte, 100), 0))
+	_, err = c.Get(context.TODO(), "huge", 0)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	assert.Equal(t, 3, c.Len())
+}
+
+func TestNewMemoryCacheRequiresSize(t *testing.T) {
+	_, err := NewMemoryCache(0, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestMemoryCacheListAndDelete(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, err := NewMemoryCache(1024, 0)
+	require.NoError(t, err)
+	c.now = func() time.Time { return now }
+
+	require.NoError(t, c.Set(context.TODO(), "ComponentReport~a", []byte("abc"), time.Hour))
+	require.NoError(t, c.Set(context.TODO(), "ComponentReport~b", []byte("de"), 0))
+	require.NoError(t, c.Set(context.TODO(), "TestDetailsReport~a", []byte("f"), time.Hour))
+
+	entries, err := c.List(context.TODO(), "ComponentReport~")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "ComponentReport~a", entries[0].Key)
+	assert.Equal(t, int64(3), entries[0].Size)
+	assert.Equal(t, time.Hour, entries[0].TTL(now))
+	assert.Nil(t, entries[1].ExpiresAt)
+
+	require.NoError(t, c.Delete(context.TODO(), "ComponentReport~a"))
+	require.NoError(t, c.Delete(context.TODO(), "missing"))
+	entries, err = c.List(context.TODO(), "")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, int64(len("ComponentReport~b")+len("de")+len("TestDetailsReport~a")+len("f")), c.size)
+}
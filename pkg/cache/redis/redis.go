@@ -2,11 +2,14 @@ package redis
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	r "gopkg.in/redis.v5"
+
+	"github.com/openshift/sippy/pkg/apis/cache"
 )
 
 const prefix = "_SIPPY_"
@@ -40,6 +43,31 @@ func (c Cache) Get(_ context.Context, key string, _ time.Duration) ([]byte, erro
 	return c.client.Get(prefix + key).Bytes()
 }
 
+// GetWithTTL looks up an item and its remaining TTL in one round-trip.
+func (c Cache) GetWithTTL(_ context.Context, key string) ([]byte, time.Duration, error) {
+	before := time.Now()
+	defer func(key string, before time.Time) {
+		logrus.Infof("Redis Cache GetWithTTL completed in %s for %s", time.Since(before), key)
+	}(key, before)
+
+	var get *r.StringCmd
+	var ttl *r.DurationCmd
+	_, err := c.client.Pipelined(func(pipe *r.Pipeline) error {
+		get = pipe.Get(prefix + key)
+		ttl = pipe.PTTL(prefix + key)
+		return nil
+	})
+	if err != nil && err != r.Nil {
+		return nil, 0, err
+	}
+	data, err := get.Bytes()
+	if err != nil {
+		return nil, 0, err
+	}
+	// negative when the item does not expire
+	return data, max(ttl.Val(), 0), nil
+}
+
 func (c Cache) Set(_ context.Context, key string, content []byte, duration time.Duration) error {
 	before := time.Now()
 	defer func(key string, before time.Time) {
@@ -47,3 +75,80 @@ func (c Cache) Set(_ context.Context, key string, content []byte, duration time.
 	}(key, before)
 	return c.client.Set(prefix+key, content, duration).Err()
 }
+
+func (c Cache) List(_ context.Context, keyPrefix string) ([]cache.Entry, error) {
+	before := time.Now()
+	defer func(keyPrefix string, before time.Time) {
+		logrus.Infof("Redis Cache List completed in %s for %s", time.Since(before), keyPrefix)
+	}(keyPrefix, before)
+
+	entries := []cache.Entry{}
+	// SCAN rather than KEYS so a large cache doesn't block redis for other clients
+	iter := c.client.Scan(0, globEscaper.Replace(prefix+keyPrefix)+"*", listBatchSize).Iterator()
+	var keys []string
+	for iter.Next() {
+		keys = append(keys, iter.Val())
+		if len(keys) == listBatchSize {
+			batch, err := c.describe(keys, before)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, batch...)
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	batch, err := c.describe(keys, before)
+	if err != nil {
+		return nil, err
+	}
+	return append(entries, batch...), nil
+}
+
+// listBatchSize is how many keys List scans at a time, and looks up the TTL and size of in a single pipeline.
+const listBatchSize = 1000
+
+// describe looks up the TTL and size of the given keys in one round-trip.
+func (c Cache) describe(keys []string, now time.Time) ([]cache.Entry, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	ttls := make([]*r.DurationCmd, len(keys))
+	sizes := make([]*r.IntCmd, len(keys))
+	_, err := c.client.Pipelined(func(pipe *r.Pipeline) error {
+		for i, key := range keys {
+			ttls[i] = pipe.PTTL(key)
+			sizes[i] = pipe.StrLen(key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]cache.Entry, 0, len(keys))
+	for i, key := range keys {
+		ttl := ttls[i].Val()
+		if ttl == -2*time.Millisecond {
+			// expired or deleted since the scan found it
+			continue
+		}
+		entry := cache.Entry{Key: strings.TrimPrefix(key, prefix), Size: sizes[i].Val()}
+		if ttl > 0 {
+			expiresAt := now.Add(ttl)
+			entry.ExpiresAt = &expiresAt
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (c Cache) Delete(_ context.Context, key string) error {
+	return c.client.Del(prefix + key).Err()
+}
+
+// globEscaper escapes the characters redis treats specially in a MATCH pattern; cache keys are often JSON
+// and regularly contain brackets.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
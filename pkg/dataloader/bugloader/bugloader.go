@@ -23,6 +23,7 @@ import (
 	"github.com/openshift/sippy/pkg/bigquery"
 	"github.com/openshift/sippy/pkg/db"
 	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/webhooks"
 )
 
 const (
@@ -66,6 +67,8 @@ type BugLoader struct {
 	dbc    *db.DB
 	bqc    *bigquery.Client
 	errors []error
+	// webhookBaseURL is the sippy URL event links point to.
+	webhookBaseURL string
 }
 
 type bigQueryBug struct {
@@ -84,10 +87,11 @@ type bigQueryBug struct {
 	ReleaseBlocker  string             `bigquery:"release_blocker"`
 }
 
-func New(dbc *db.DB, bqc *bigquery.Client) *BugLoader {
+func New(dbc *db.DB, bqc *bigquery.Client, webhookBaseURL string) *BugLoader {
 	return &BugLoader{
-		dbc: dbc,
-		bqc: bqc,
+		dbc:            dbc,
+		bqc:            bqc,
+		webhookBaseURL: webhookBaseURL,
 	}
 }
 
@@ -273,6 +277,12 @@ func (bl *BugLoader) updateTriages(triages []models.Triage) {
 			res = bl.dbc.DB.WithContext(context.WithValue(context.Background(), models.CurrentUserKey, "bug-loader")).Save(&t)
 			if res.Error != nil {
 				bl.addError(logger, res.Error, fmt.Sprintf("error updating triage: %q (%d)", t.Description, t.ID))
+				continue
+			}
+			if !resolved && t.Resolved.Valid {
+				if err := webhooks.Notify(bl.dbc.DB, bl.webhookBaseURL, webhooks.NewTriageEvent(webhooks.TriageResolved, t)); err != nil {
+					logger.WithError(err).Errorf("error queuing webhook events for triage %d", t.ID)
+				}
 			}
 		}
 	}
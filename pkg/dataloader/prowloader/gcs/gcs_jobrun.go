@@ -2,7 +2,6 @@ package gcs
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"regexp"
@@ -108,20 +107,12 @@ func (j *GCSJobRun) GetCombinedJUnitTestSuites(ctx context.Context) (*junit.Test
 			continue
 		}
 
-		// try as testsuites first just in case we are one
-		currTestSuites := &junit.TestSuites{}
-		testSuitesErr := xml.Unmarshal(junitContent, currTestSuites)
-		if testSuitesErr == nil {
-			testSuites.Suites = append(testSuites.Suites, currTestSuites.Suites...)
-			continue
-		}
-
-		currTestSuite := &junit.TestSuite{}
-		if testSuiteErr := xml.Unmarshal(junitContent, currTestSuite); testSuiteErr != nil {
-			log.WithError(testSuiteErr).Warningf("error parsing content for jobrun in file %s path %s", junitFile, j.gcsProwJobPath)
+		currTestSuites, err := junit.Parse(junitContent)
+		if err != nil {
+			log.WithError(err).Warningf("error parsing content for jobrun in file %s path %s", junitFile, j.gcsProwJobPath)
 			continue
 		}
-		testSuites.Suites = append(testSuites.Suites, currTestSuite)
+		testSuites.Suites = append(testSuites.Suites, currTestSuites.Suites...)
 	}
 
 	return testSuites, nil
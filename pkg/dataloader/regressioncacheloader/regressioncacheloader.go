@@ -178,6 +178,17 @@ func (l *RegressionCacheLoader) Load() {
 		}
 	}
 
+	// Scorecards are recorded after closing so that regressions which rolled off today no longer count as open.
+	for _, view := range l.views {
+		if result, ok := releaseResults[view.SampleRelease.Name]; !ok || result.hadErrors || !view.RegressionTracking.Enabled {
+			continue
+		}
+		if err := componentreadiness.RecordReadinessScorecard(l.dbc, view, time.Now()); err != nil {
+			l.logger.WithError(err).Errorf("error recording readiness scorecard for view %s", view.Name)
+			l.errs = append(l.errs, err)
+		}
+	}
+
 	// ResolveTriages is a global operation (not per-release), so we only run it
 	// once after all releases have been processed, and only if no releases had errors.
 	if !anyErrors {
@@ -91,8 +91,14 @@ func (d *DB) UpdateSchema(reportEnd *time.Time) error {
 		&models.TestRegression{},
 		&models.RegressionJobRun{},
 		&models.RegressionView{},
+		&models.RegressionSnapshot{},
+		&models.ReadinessScorecard{},
+		&models.VariantDriftReport{},
 		&models.Triage{},
 		&models.AuditLog{},
+		&models.RegressionAllowance{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
 		&models.ChatRating{},
 		&models.ChatConversation{},
 		&jobrunscan.Label{},
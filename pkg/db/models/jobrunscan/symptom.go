@@ -24,19 +24,19 @@ type SymptomContent struct {
 
 	// Type of matcher
 	// Simple types: "string", "regex", "jq", "xpath", "none"
-	// Compound type: "cel" (Common Expression Language against label names)
+	// Compound type: "label_expression" (boolean expression over applied label IDs)
 	MatcherType string `gorm:"type:varchar(50);not null" json:"matcher_type"`
 
 	// File pattern for simple matchers (glob pattern)
 	// Examples: "**/build-log.txt", "**/e2e-timelines/**/*.json"
-	// Null for CEL matcher type
+	// Null for the label_expression matcher type
 	FilePattern string `gorm:"type:varchar(500)" json:"file_pattern,omitempty"`
 
 	// Match string - interpretation depends on MatcherType:
 	// - "string": substring to find in file
 	// - "regex": regular expression pattern
 	// - "none": ignored (just checks file existence)
-	// - "cel": CEL expression referencing applied labels (e.g. "DNSTimeout && !OperatorError")
+	// - "label_expression": label IDs combined with !, &&, || and parentheses (e.g. "DNSTimeout && !OperatorError")
 	MatchString string `gorm:"type:text" json:"match_string,omitempty"`
 
 	// Labels to apply when this symptom matches (typically none or one, but can be multiple)
@@ -64,5 +64,9 @@ const (
 	MatcherTypeString = "string" // Simple substring match
 	MatcherTypeRegex  = "regex"  // Regular expression match
 	MatcherTypeFile   = "none"   // File exists (no content match)
-	MatcherTypeCEL    = "cel"    // Common Expression Language for compound logic
+	// MatcherTypeLabelExpression is a boolean expression over the label IDs applied to a job run
+	MatcherTypeLabelExpression = "label_expression"
+	// MatcherTypeCEL is reserved for Common Expression Language expressions, which are not supported: the API
+	// rejects it and the scanner reports symptoms of this type as errors instead of evaluating them
+	MatcherTypeCEL = "cel"
 )
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// RegressionAllowance records a product owner's approval to ship a release with a known regression in a test.
+// While in effect, component readiness will not flag the regression in the allowed release, and will continue
+// to hold the following release to the pass rate seen before the regression.
+type RegressionAllowance struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Release is the release in which the regression is allowed.
+	Release  string `json:"release" gorm:"not null;uniqueIndex:idx_regression_allowances_key"`
+	TestID   string `json:"test_id" gorm:"not null;uniqueIndex:idx_regression_allowances_key"`
+	TestName string `json:"test_name" gorm:"not null"`
+	// Variants are the "Name:Value" pairs identifying the regressed column, sorted.
+	Variants      pq.StringArray `json:"variants" gorm:"type:text[];not null;uniqueIndex:idx_regression_allowances_key"`
+	JiraComponent string         `json:"jira_component" gorm:"not null"`
+
+	// PreviousRelease and the Previous* counts describe test results before the regression; they become the
+	// basis when the allowed release is later compared against.
+	PreviousRelease   string `json:"previous_release" gorm:"not null"`
+	PreviousSuccesses int    `json:"previous_successes"`
+	PreviousFailures  int    `json:"previous_failures"`
+	PreviousFlakes    int    `json:"previous_flakes"`
+	// Regressed* counts describe the results being allowed.
+	RegressedSuccesses int `json:"regressed_successes"`
+	RegressedFailures  int `json:"regressed_failures"`
+	RegressedFlakes    int `json:"regressed_flakes"`
+
+	JiraBug                   string `json:"jira_bug" gorm:"not null"`
+	ReasonToAllowInsteadOfFix string `json:"reason_to_allow_instead_of_fix" gorm:"not null"`
+
+	// ExpiresAt is when the allowance stops applying; nil means it never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index"`
+
+	// Links contains REST links for clients to follow for this allowance, injected by the API and not stored in the DB.
+	Links map[string]string `json:"links,omitempty" gorm:"-"`
+}
+
+const OldRegressionAllowanceKey contextKey = "old_regression_allowance"
+
+// Expired reports whether the allowance has stopped applying as of the given time.
+func (a *RegressionAllowance) Expired(at time.Time) bool {
+	return a.ExpiresAt != nil && !at.Before(*a.ExpiresAt)
+}
+
+func (a *RegressionAllowance) BeforeUpdate(db *gorm.DB) error {
+	return a.before(db)
+}
+
+func (a *RegressionAllowance) BeforeDelete(db *gorm.DB) error {
+	return a.before(db)
+}
+
+func (a *RegressionAllowance) before(db *gorm.DB) error {
+	if existing := db.Statement.Context.Value(OldRegressionAllowanceKey); existing != nil {
+		return nil
+	}
+
+	var old RegressionAllowance
+	if err := db.First(&old, a.ID).Error; err != nil {
+		return err
+	}
+
+	db.Statement.Context = context.WithValue(db.Statement.Context, OldRegressionAllowanceKey, old)
+	return nil
+}
+
+func (a *RegressionAllowance) AfterUpdate(db *gorm.DB) error {
+	return a.after(db, Update)
+}
+
+func (a *RegressionAllowance) AfterCreate(db *gorm.DB) error {
+	return a.after(db, Create)
+}
+
+func (a *RegressionAllowance) AfterDelete(db *gorm.DB) error {
+	return a.after(db, Delete)
+}
+
+func (a *RegressionAllowance) after(db *gorm.DB, operation OperationType) error {
+	var oldJSON []byte
+	if operation == Update || operation == Delete {
+		old, ok := db.Statement.Context.Value(OldRegressionAllowanceKey).(RegressionAllowance)
+		if !ok {
+			return fmt.Errorf("value of old_regression_allowance is not a RegressionAllowance type")
+		}
+		var err error
+		oldJSON, err = old.marshalJSONForAudit()
+		if err != nil {
+			return fmt.Errorf("error marshalling old regression allowance record: %w", err)
+		}
+	}
+
+	var newJSON []byte
+	if operation != Delete {
+		var err error
+		newJSON, err = a.marshalJSONForAudit()
+		if err != nil {
+			return fmt.Errorf("error marshalling new regression allowance record: %w", err)
+		}
+	}
+	user := db.Statement.Context.Value(CurrentUserKey)
+	if user == nil {
+		return fmt.Errorf("current user not found in context")
+	}
+	audit := AuditLog{
+		TableName: "regression_allowances",
+		Operation: string(operation),
+		RowID:     a.ID,
+		User:      user.(string),
+		OldData:   oldJSON,
+		NewData:   newJSON,
+	}
+
+	return db.Create(&audit).Error
+}
+
+func (a *RegressionAllowance) marshalJSONForAudit() ([]byte, error) {
+	type Alias RegressionAllowance
+	auditJSON := Alias(*a)
+	auditJSON.Links = nil
+	return json.Marshal(auditJSON)
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ReadinessScorecard is a daily roll up of the regressions in a component readiness view, recorded so release
+// managers can chart how a release burned down its regressions on the way to GA.
+type ReadinessScorecard struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ViewName  string    `json:"view_name" gorm:"not null;uniqueIndex:idx_readiness_scorecard_key"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_readiness_scorecard_key"`
+	Release   string    `json:"release" gorm:"not null;index"`
+
+	OpenRegressions      int `json:"open_regressions"`
+	TriagedRegressions   int `json:"triaged_regressions"`
+	UntriagedRegressions int `json:"untriaged_regressions"`
+
+	// MeanHoursToTriage is the mean time from opening to first triage for regressions ever seen in the view,
+	// and is nil when none have been triaged.
+	MeanHoursToTriage *float64 `json:"mean_hours_to_triage,omitempty"`
+	// MeanHoursToClose is the mean time from opening to closing for closed regressions ever seen in the view.
+	MeanHoursToClose *float64 `json:"mean_hours_to_close,omitempty"`
+
+	Components ComponentScorecards `json:"components" gorm:"type:jsonb"`
+}
+
+// ComponentScorecard counts the open regressions for a single component.
+type ComponentScorecard struct {
+	Component string `json:"component"`
+	Open      int    `json:"open"`
+	Triaged   int    `json:"triaged"`
+	Untriaged int    `json:"untriaged"`
+}
+
+// ComponentScorecards is stored as a jsonb column.
+type ComponentScorecards []ComponentScorecard
+
+func (c ComponentScorecards) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c)
+}
+
+func (c *ComponentScorecards) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for ComponentScorecards: %T", value)
+	}
+	return json.Unmarshal(data, c)
+}
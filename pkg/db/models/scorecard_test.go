@@ -0,0 +1,18 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentScorecardsRoundTrip(t *testing.T) {
+	in := ComponentScorecards{{Component: "etcd", Open: 2, Triaged: 1, Untriaged: 1}}
+	value, err := in.Value()
+	require.NoError(t, err)
+
+	var out ComponentScorecards
+	require.NoError(t, out.Scan(value))
+	assert.Equal(t, in, out)
+}
@@ -233,6 +233,9 @@ type TestRegression struct {
 	// Views tracks which component readiness views this regression has been observed in.
 	Views []RegressionView `json:"views,omitempty" gorm:"foreignKey:TestRegressionID;constraint:OnDelete:CASCADE;"`
 
+	// Snapshots holds one record per day per view of how the regressed test compared while this regression was open.
+	Snapshots []RegressionSnapshot `json:"snapshots,omitempty" gorm:"foreignKey:TestRegressionID;constraint:OnDelete:CASCADE;"`
+
 	// Links contains HATEOAS-style links for this regression record (not stored in database)
 	Links map[string]string `json:"links,omitempty" gorm:"-"`
 }
@@ -247,6 +250,39 @@ type RegressionView struct {
 	ClosedAt         sql.NullTime `json:"closed_at"`
 }
 
+// RegressionSnapshot records how a regressed test compared against its basis in a view on a given day, so the
+// evolution of a regression can be charted after the sample window has moved on. The last sync of the day wins.
+type RegressionSnapshot struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	TestRegressionID uint      `json:"test_regression_id" gorm:"not null;uniqueIndex:idx_regression_snapshot_key"`
+	ViewName         string    `json:"view_name" gorm:"not null;uniqueIndex:idx_regression_snapshot_key"`
+	Date             time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_regression_snapshot_key"`
+
+	// Status is the crtest.Status code the test was reported with.
+	Status      int    `json:"status"`
+	Comparison  string `json:"comparison,omitempty"`
+	BaseRelease string `json:"base_release,omitempty"`
+
+	BaseSuccessCount   int     `json:"base_success_count"`
+	BaseFailureCount   int     `json:"base_failure_count"`
+	BaseFlakeCount     int     `json:"base_flake_count"`
+	BaseSuccessRate    float64 `json:"base_success_rate"`
+	SampleSuccessCount int     `json:"sample_success_count"`
+	SampleFailureCount int     `json:"sample_failure_count"`
+	SampleFlakeCount   int     `json:"sample_flake_count"`
+	SampleSuccessRate  float64 `json:"sample_success_rate"`
+	// PassRateDelta is the sample success rate minus the base success rate.
+	PassRateDelta float64 `json:"pass_rate_delta"`
+	// FisherExact is the p-value of the comparison, when fisher's exact test was used.
+	FisherExact *float64 `json:"fisher_exact,omitempty"`
+
+	// Adjustments names the middleware that adjusted the comparison, e.g. a regression allowance.
+	Adjustments  pq.StringArray `json:"adjustments,omitempty" gorm:"type:text[]"`
+	Explanations pq.StringArray `json:"explanations,omitempty" gorm:"type:text[]"`
+}
+
 // RegressionJobRun represents a single job run observed during the lifetime of a regression.
 // It stores data from BigQuery so we don't depend on the job existing in PostgreSQL's prow_job_runs table.
 type RegressionJobRun struct {
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// VariantDriftReport records how the job variants stored in BigQuery and Postgres differ from those the variant
+// registry expects, so silent variant changes are noticed before they break component readiness comparisons.
+type VariantDriftReport struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+
+	ExpectedJobs int `json:"expected_jobs"`
+	// Unexpected is set when any store drifted in a way the drift policy did not allow.
+	Unexpected bool               `json:"unexpected"`
+	Stores     VariantStoreDrifts `json:"stores" gorm:"type:jsonb"`
+
+	// Links contains REST links for clients to follow, injected by the API and not stored in the DB.
+	Links map[string]string `json:"links,omitempty" gorm:"-"`
+}
+
+// VariantStoreDrift categorizes the jobs whose stored variants differ from the expected variants.
+type VariantStoreDrift struct {
+	// Store is bigquery or postgres.
+	Store      string `json:"store"`
+	StoredJobs int    `json:"stored_jobs"`
+	// Added are expected jobs missing from the store, and Removed are stored jobs no longer expected.
+	Added   []string          `json:"added"`
+	Removed []string          `json:"removed"`
+	Changed []JobVariantDrift `json:"changed"`
+	// Unexpected is set when the drift policy did not allow this drift.
+	Unexpected bool `json:"unexpected"`
+}
+
+// JobVariantDrift lists the variants that changed for a single job.
+type JobVariantDrift struct {
+	JobName string          `json:"job_name"`
+	Changes []VariantChange `json:"changes"`
+}
+
+// VariantChange is a single variant's stored and expected value. Stored is empty when the variant is new, and
+// Expected is empty when the variant is no longer expected.
+type VariantChange struct {
+	Variant  string `json:"variant"`
+	Stored   string `json:"stored,omitempty"`
+	Expected string `json:"expected,omitempty"`
+}
+
+// VariantStoreDrifts is stored as a jsonb column.
+type VariantStoreDrifts []VariantStoreDrift
+
+func (d VariantStoreDrifts) Value() (driver.Value, error) {
+	if d == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(d)
+}
+
+func (d *VariantStoreDrifts) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*d = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for VariantStoreDrifts: %T", value)
+	}
+	return json.Unmarshal(data, d)
+}
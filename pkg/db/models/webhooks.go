@@ -0,0 +1,56 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// WebhookSubscription registers an endpoint that receives signed JSON events for regression and triage
+// lifecycle changes. Each filter is optional; when set, only events for regressions matching it are sent.
+type WebhookSubscription struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name string `json:"name" gorm:"not null"`
+	URL  string `json:"url" gorm:"not null"`
+	// Secret is the HMAC key used to sign deliveries. It is only returned when the subscription is created.
+	Secret    string `json:"secret,omitempty" gorm:"not null"`
+	CreatedBy string `json:"created_by"`
+	Disabled  bool   `json:"disabled" gorm:"not null;default:false"`
+
+	// EventTypes limits the events sent, all events are sent if empty.
+	EventTypes   pq.StringArray `json:"event_types" gorm:"type:text[]"`
+	Views        pq.StringArray `json:"views" gorm:"type:text[]"`
+	Components   pq.StringArray `json:"components" gorm:"type:text[]"`
+	Capabilities pq.StringArray `json:"capabilities" gorm:"type:text[]"`
+	// Variants are "Name:Value" pairs. A regression matches if, for every variant name listed, it has one of the listed values.
+	Variants pq.StringArray `json:"variants" gorm:"type:text[]"`
+
+	// Links contains REST links for clients to follow for this subscription, injected by the API and not stored in the DB.
+	Links map[string]string `json:"links,omitempty" gorm:"-"`
+}
+
+// WebhookDelivery is a single event queued for a subscription. Deliveries are recorded when the event happens,
+// and sent (and retried) by the webhook dispatcher in sippy-daemon.
+type WebhookDelivery struct {
+	ID             uint                 `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	SubscriptionID uint                 `json:"subscription_id" gorm:"not null;index"`
+	Subscription   *WebhookSubscription `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+	EventID        string               `json:"event_id" gorm:"not null"`
+	EventType      string               `json:"event_type" gorm:"not null"`
+	Payload        json.RawMessage      `json:"payload" gorm:"type:jsonb;not null"`
+
+	Attempts       int          `json:"attempts"`
+	NextAttempt    time.Time    `json:"next_attempt" gorm:"index"`
+	LastStatusCode int          `json:"last_status_code,omitempty"`
+	LastError      string       `json:"last_error,omitempty"`
+	Delivered      sql.NullTime `json:"delivered"`
+	// Failed is set once the dispatcher has given up on the delivery.
+	Failed bool `json:"failed" gorm:"not null;default:false"`
+}
@@ -43,3 +43,34 @@ func ListRegressions(dbc *db.DB, release string) ([]models.TestRegression, error
 	}
 	return regressions, res.Error
 }
+
+// RegressionFailureOutput is the output of a regressed test in one of the job runs recorded against its regression.
+// Output is empty when the job run or its test output is no longer in the database.
+type RegressionFailureOutput struct {
+	RegressionID uint
+	ProwJobRunID string
+	Output       string
+}
+
+// ListRegressionFailureOutputs returns the job runs recorded for the given regressions, along with the failure
+// output of the regressed test in each run where sippy still has it. Test results are only searched from shortly
+// before each regression opened, as the job runs recorded against it are those that led to and followed its opening.
+func ListRegressionFailureOutputs(dbc *db.DB, regressionIDs []uint) ([]RegressionFailureOutput, error) {
+	var rows []RegressionFailureOutput
+	if len(regressionIDs) == 0 {
+		return rows, nil
+	}
+	res := dbc.DB.Table("regression_job_runs rjr").
+		Select("rjr.regression_id, rjr.prow_job_run_id, COALESCE(o.output, '') AS output").
+		Joins("JOIN test_regressions r ON r.id = rjr.regression_id").
+		Joins("LEFT JOIN tests t ON t.name = r.test_name").
+		Joins("LEFT JOIN prow_job_run_tests pjrt ON pjrt.test_id = t.id AND pjrt.prow_job_run_id = CAST(rjr.prow_job_run_id AS bigint) "+
+			"AND pjrt.created_at >= r.opened - INTERVAL '14 days'").
+		Joins("LEFT JOIN prow_job_run_test_outputs o ON o.prow_job_run_test_id = pjrt.id").
+		Where("rjr.regression_id IN ?", regressionIDs).
+		Scan(&rows)
+	if res.Error != nil {
+		log.WithError(res.Error).Error("error listing regression failure outputs")
+	}
+	return rows, res.Error
+}
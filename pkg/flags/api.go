@@ -10,6 +10,7 @@ type APIFlags struct {
 	ListenAddr           string
 	MetricsAddr          string
 	ChatAPIURL           string
+	WebhookAllowedHosts  []string
 }
 
 func NewAPIFlags() *APIFlags {
@@ -24,4 +25,5 @@ func (f *APIFlags) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&f.ListenAddr, "listen", f.ListenAddr, "The address to serve analysis reports on (default :8080)")
 	fs.StringVar(&f.MetricsAddr, "listen-metrics", f.MetricsAddr, "The address to serve prometheus metrics on (default :2112)")
 	fs.StringVar(&f.ChatAPIURL, "chat-api", f.ChatAPIURL, "URL of the sippy-chat service to proxy chat requests to")
+	fs.StringArrayVar(&f.WebhookAllowedHosts, "webhook-allowed-host", f.WebhookAllowedHosts, "Host (and its subdomains) webhook subscriptions may send to (one per arg instance)")
 }
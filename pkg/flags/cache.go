@@ -1,6 +1,7 @@
 package flags
 
 import (
+	"fmt"
 	"os"
 	"time"
 
@@ -12,12 +13,23 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/openshift/sippy/pkg/apis/cache"
+	"github.com/openshift/sippy/pkg/cache/layered"
+	"github.com/openshift/sippy/pkg/cache/memory"
 	"github.com/openshift/sippy/pkg/cache/redis"
 )
 
+const (
+	CacheBackendRedis   = "redis"
+	CacheBackendMemory  = "memory"
+	CacheBackendLayered = "layered"
+)
+
 // CacheFlags holds caching configuration information for Sippy.
 type CacheFlags struct {
+	CacheBackend               string
 	RedisURL                   string
+	MemoryCacheSize            int64
+	MemoryCacheTTL             time.Duration
 	PersistentCacheDurationMax time.Duration
 	PersistentCacheDurationMin time.Duration
 	EnablePersistentCacheWrite bool
@@ -30,6 +42,21 @@ func NewCacheFlags() *CacheFlags {
 }
 
 func (f *CacheFlags) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&f.CacheBackend,
+		"cache-backend",
+		CacheBackendRedis,
+		"Cache backend to use: redis (only when --redis-url is set), memory (in-process LRU), or layered (in-process LRU in front of redis)")
+
+	fs.Int64Var(&f.MemoryCacheSize,
+		"memory-cache-size",
+		512*1024*1024,
+		"Maximum bytes held by the in-process cache for the memory and layered backends")
+
+	fs.DurationVar(&f.MemoryCacheTTL,
+		"memory-cache-ttl",
+		time.Hour,
+		"Maximum time an item is held by the in-process cache for the memory and layered backends")
+
 	fs.StringVar(&f.RedisURL,
 		"redis-url",
 		os.Getenv("REDIS_URL"),
@@ -66,11 +93,34 @@ func (f *CacheFlags) BindFlags(fs *pflag.FlagSet) {
 }
 
 func (f *CacheFlags) GetCacheClient() (cache.Cache, error) {
-	if f.RedisURL != "" {
-		return redis.NewRedisCache(f.RedisURL)
+	switch f.CacheBackend {
+	case "", CacheBackendRedis:
+		if f.RedisURL != "" {
+			return redis.NewRedisCache(f.RedisURL)
+		}
+		return nil, nil
+	case CacheBackendMemory:
+		c, err := memory.NewMemoryCache(f.MemoryCacheSize, f.MemoryCacheTTL)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case CacheBackendLayered:
+		if f.RedisURL == "" {
+			return nil, fmt.Errorf("the %s cache backend requires --redis-url", CacheBackendLayered)
+		}
+		local, err := memory.NewMemoryCache(f.MemoryCacheSize, f.MemoryCacheTTL)
+		if err != nil {
+			return nil, err
+		}
+		remote, err := redis.NewRedisCache(f.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		return layered.NewLayeredCache(local, remote, f.MemoryCacheTTL), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", f.CacheBackend)
 	}
-
-	return nil, nil
 }
 
 func (f *CacheFlags) GetPersistentCacheClient(bqclient *bigquery.Client) (cache.Cache, error) {
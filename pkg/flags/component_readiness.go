@@ -3,9 +3,11 @@ package flags
 import (
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v3"
@@ -20,6 +22,7 @@ var (
 type ComponentReadinessFlags struct {
 	ComponentReadinessViewsFile string
 	CRTimeRoundingFactor        time.Duration
+	CRStaleWhileRevalidate      time.Duration
 	CORSAllowedOrigin           string
 }
 
@@ -31,6 +34,8 @@ func (f *ComponentReadinessFlags) BindFlags(fs *pflag.FlagSet) {
 	factorUsage := fmt.Sprintf("Set the rounding factor for component readiness release time. The time will be rounded down to the nearest multiple of the factor. Maximum value is %v", maxCRTimeRoundingFactor)
 	fs.StringVar(&f.ComponentReadinessViewsFile, "views", "", "Optional yaml file for predefined Component Readiness views")
 	fs.DurationVar(&f.CRTimeRoundingFactor, "component-readiness-time-rounding-factor", defaultCRTimeRoundingFactor, factorUsage)
+	fs.DurationVar(&f.CRStaleWhileRevalidate, "component-readiness-stale-while-revalidate", 0,
+		"How long past expiry a cached component report may be served, marked stale, while it is regenerated in the background. Zero disables serving stale reports")
 	fs.StringVar(&f.CORSAllowedOrigin, "cors-allowed-origin", "*", "Optional allowed origin for CORS")
 }
 
@@ -66,6 +71,22 @@ func (f *ComponentReadinessFlags) validateViews(views *api.SippyViews) error {
 				}
 			}
 		}
+		if mode := view.AdvancedOptions.ComparisonMode; mode != "" && !slices.Contains(crtest.ComparisonModes(), mode) {
+			return fmt.Errorf("view %s comparison_mode %q must be one of: %s, %s", view.Name, mode, crtest.FisherExact, crtest.Bayesian)
+		}
+		for i, o := range view.AdvancedOptions.ThresholdOverrides {
+			if o.Component == "" && o.Capability == "" && o.TestID == "" {
+				return fmt.Errorf("view %s threshold_overrides[%d] must set at least one of component, capability or test_id", view.Name, i)
+			}
+			if o.Confidence != nil && (*o.Confidence <= 0 || *o.Confidence >= 100) {
+				return fmt.Errorf("view %s threshold_overrides[%d] confidence must be between 1 and 99", view.Name, i)
+			}
+		}
+		for i, label := range view.AdvancedOptions.ExcludedJobRunLabels {
+			if label == "" {
+				return fmt.Errorf("view %s excluded_job_run_labels[%d] must not be empty", view.Name, i)
+			}
+		}
 	}
 
 	return nil
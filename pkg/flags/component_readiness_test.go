@@ -5,6 +5,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crview"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
 )
 
 // TestProductionViewsConfiguration validates that the production config/views.yaml
@@ -59,3 +64,63 @@ func TestViewsValidationLogic(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateThresholdOverrides(t *testing.T) {
+	confidence := func(c int) *int { return &c }
+	tests := []struct {
+		name      string
+		overrides []reqopts.ThresholdOverride
+		errorMsg  string
+	}{
+		{
+			name:      "valid overrides",
+			overrides: []reqopts.ThresholdOverride{{Component: "Storage", Confidence: confidence(99)}, {TestID: "openshift-tests:abc"}},
+		},
+		{
+			name:      "override without a key",
+			overrides: []reqopts.ThresholdOverride{{Confidence: confidence(99)}},
+			errorMsg:  "must set at least one of component, capability or test_id",
+		},
+		{
+			name:      "confidence out of range",
+			overrides: []reqopts.ThresholdOverride{{Component: "Storage", Confidence: confidence(100)}},
+			errorMsg:  "confidence must be between 1 and 99",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			views := &api.SippyViews{ComponentReadiness: []crview.View{{
+				Name:            "4.22-main",
+				AdvancedOptions: reqopts.Advanced{Confidence: 95, ThresholdOverrides: tt.overrides},
+			}}}
+			err := NewComponentReadinessFlags().validateViews(views)
+			if tt.errorMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestValidateExcludedJobRunLabels(t *testing.T) {
+	views := &api.SippyViews{ComponentReadiness: []crview.View{{
+		Name:            "4.22-audit",
+		AdvancedOptions: reqopts.Advanced{Confidence: 95, ExcludedJobRunLabels: []string{}},
+	}}}
+	assert.NoError(t, NewComponentReadinessFlags().validateViews(views))
+
+	views.ComponentReadiness[0].AdvancedOptions.ExcludedJobRunLabels = []string{"InfraFailure", ""}
+	assert.ErrorContains(t, NewComponentReadinessFlags().validateViews(views), "excluded_job_run_labels[1] must not be empty")
+}
+
+func TestValidateComparisonMode(t *testing.T) {
+	views := &api.SippyViews{ComponentReadiness: []crview.View{{
+		Name:            "4.22-main",
+		AdvancedOptions: reqopts.Advanced{Confidence: 95, ComparisonMode: crtest.Bayesian},
+	}}}
+	assert.NoError(t, NewComponentReadinessFlags().validateViews(views))
+
+	views.ComponentReadiness[0].AdvancedOptions.ComparisonMode = "bayes"
+	assert.ErrorContains(t, NewComponentReadinessFlags().validateViews(views), `view 4.22-main comparison_mode "bayes" must be one of`)
+}
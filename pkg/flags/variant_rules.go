@@ -0,0 +1,25 @@
+package flags
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/sippy/pkg/variantregistry"
+)
+
+// VariantRulesFlags holds the location of the declarative rules used to derive job variants.
+type VariantRulesFlags struct {
+	Path string
+}
+
+func NewVariantRulesFlags() *VariantRulesFlags {
+	return &VariantRulesFlags{}
+}
+
+func (f *VariantRulesFlags) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&f.Path, "variant-rules", f.Path, "Path to a variant rules file, defaults to the rules built into sippy")
+}
+
+// GetRuleSet loads and validates the variant rules.
+func (f *VariantRulesFlags) GetRuleSet() (*variantregistry.RuleSet, error) {
+	return variantregistry.LoadRuleSet(f.Path)
+}
@@ -0,0 +1,26 @@
+package flags
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// WebhookFlags configures delivery of regression and triage webhook events.
+type WebhookFlags struct {
+	Delivery         bool
+	DeliveryInterval time.Duration
+	AllowedHosts     []string
+}
+
+func NewWebhookFlags() *WebhookFlags {
+	return &WebhookFlags{
+		DeliveryInterval: 30 * time.Second,
+	}
+}
+
+func (f *WebhookFlags) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&f.Delivery, "webhook-delivery", f.Delivery, "Enable delivery of regression and triage events to webhook subscriptions")
+	fs.DurationVar(&f.DeliveryInterval, "webhook-delivery-interval", f.DeliveryInterval, "How often to check for pending webhook deliveries")
+	fs.StringArrayVar(&f.AllowedHosts, "webhook-allowed-host", f.AllowedHosts, "Host (and its subdomains) webhook deliveries may be sent to (one per arg instance)")
+}
@@ -2,14 +2,14 @@ package mcp
 
 import (
 	"context"
+	"io/fs"
 	"net/http"
 
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 
-	"github.com/openshift/sippy/pkg/apis/cache"
-	"github.com/openshift/sippy/pkg/bigquery"
-	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/mcp/prompts"
+	"github.com/openshift/sippy/pkg/mcp/resources"
 	"github.com/openshift/sippy/pkg/mcp/tools"
 )
 
@@ -18,7 +18,7 @@ type MCPServer struct {
 	streamSrv *server.StreamableHTTPServer
 }
 
-func NewMCPServer(ctx context.Context, sippyServer *http.Server, dbClient *db.DB, bigQueryClient *bigquery.Client, cacheClient cache.Cache) *MCPServer {
+func NewMCPServer(ctx context.Context, sippyServer *http.Server, deps *tools.ToolDependencies, promptsFS fs.FS) *MCPServer {
 	hooks := &server.Hooks{}
 
 	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
@@ -34,6 +34,7 @@ func NewMCPServer(ctx context.Context, sippyServer *http.Server, dbClient *db.DB
 		"0.0.1",
 		server.WithLogging(),
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
 		server.WithPromptCapabilities(false),
 		server.WithRecovery(),
 		server.WithHooks(hooks),
@@ -41,15 +42,19 @@ func NewMCPServer(ctx context.Context, sippyServer *http.Server, dbClient *db.DB
 	log.Debug("Created MCP server instance")
 
 	// Register tools
-	if dbClient != nil {
-		tools.RegisterTools(mcpServer, dbClient, bigQueryClient, cacheClient)
+	if deps.DBClient != nil {
+		tools.RegisterTools(mcpServer, deps)
 		log.Debug("Registered MCP tools")
+		resources.RegisterResources(mcpServer, deps)
+	}
+	if promptsFS != nil {
+		prompts.RegisterPrompts(mcpServer, promptsFS)
 	}
 
 	streamSrv := server.NewStreamableHTTPServer(
 		mcpServer,
 		server.WithStreamableHTTPServer(sippyServer),
-		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context { return ctx }),
+		server.WithHTTPContextFunc(tools.WithHTTPRequest),
 	)
 
 	return &MCPServer{
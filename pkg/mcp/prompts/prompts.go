@@ -0,0 +1,170 @@
+// Package prompts offers the prompt templates shared with the chat service, which live in chat/prompts,
+// as MCP prompts.
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// Prompt is a prompt definition in the format documented in chat/prompts/README.md.
+type Prompt struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Hide        bool       `yaml:"hide"`
+	Arguments   []Argument `yaml:"arguments"`
+	Prompt      string     `yaml:"prompt"`
+
+	template template
+}
+
+type Argument struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	// Type is string, integer or array. MCP prompt arguments are always strings, so array
+	// arguments are given as a comma separated list.
+	Type    string `yaml:"type"`
+	Default any    `yaml:"default"`
+}
+
+// Load reads every prompt definition in fsys. Prompts that cannot be parsed are skipped with a warning,
+// so one bad file does not take the rest with it.
+func Load(fsys fs.FS) ([]Prompt, error) {
+	var prompts []Prompt
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".yaml" {
+			return nil
+		}
+		prompt, err := loadPrompt(fsys, p)
+		if err != nil {
+			log.WithError(err).WithField("file", p).Warning("skipping prompt that cannot be offered over MCP")
+			return nil
+		}
+		prompts = append(prompts, prompt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts, nil
+}
+
+func loadPrompt(fsys fs.FS, p string) (Prompt, error) {
+	prompt := Prompt{}
+	data, err := fs.ReadFile(fsys, p)
+	if err != nil {
+		return prompt, err
+	}
+	if err := yaml.Unmarshal(data, &prompt); err != nil {
+		return prompt, err
+	}
+	if prompt.Name == "" || prompt.Prompt == "" {
+		return prompt, fmt.Errorf("name and prompt are required")
+	}
+	if prompt.template, err = parseTemplate(prompt.Prompt); err != nil {
+		return prompt, err
+	}
+	return prompt, nil
+}
+
+// Render fills in the template with the given arguments, falling back to each argument's default.
+func (p Prompt) Render(args map[string]string) (string, error) {
+	vars := map[string][]string{}
+	for _, arg := range p.Arguments {
+		value, ok := args[arg.Name]
+		switch {
+		case ok && value != "":
+			if arg.Type == "array" {
+				vars[arg.Name] = splitList(value)
+			} else {
+				vars[arg.Name] = []string{value}
+			}
+		case arg.Default != nil:
+			vars[arg.Name] = defaultValues(arg.Default)
+		case arg.Required:
+			return "", fmt.Errorf("missing required argument %s", arg.Name)
+		}
+	}
+	return p.template.render(vars), nil
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func defaultValues(value any) []string {
+	if list, ok := value.([]any); ok {
+		result := make([]string, 0, len(list))
+		for _, v := range list {
+			result = append(result, fmt.Sprint(v))
+		}
+		return result
+	}
+	return []string{fmt.Sprint(value)}
+}
+
+// Definition returns the MCP prompt definition.
+func (p Prompt) Definition() mcp.Prompt {
+	opts := []mcp.PromptOption{mcp.WithPromptDescription(p.Description)}
+	for _, arg := range p.Arguments {
+		description := arg.Description
+		if arg.Type == "array" {
+			description += " (comma separated list)"
+		}
+		if arg.Default != nil {
+			description += fmt.Sprintf(" (default: %s)", strings.Join(defaultValues(arg.Default), ", "))
+		}
+		argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(description)}
+		if arg.Required {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+		}
+		opts = append(opts, mcp.WithArgument(arg.Name, argOpts...))
+	}
+	return mcp.NewPrompt(p.Name, opts...)
+}
+
+// RegisterPrompts registers each prompt in fsys with the server. Hidden prompts are included: they are only
+// hidden from the chat UI's slash commands, and MCP clients list prompts explicitly.
+func RegisterPrompts(mcpServer *server.MCPServer, fsys fs.FS) {
+	prompts, err := Load(fsys)
+	if err != nil {
+		log.WithError(err).Error("error loading MCP prompts")
+		return
+	}
+	for _, prompt := range prompts {
+		mcpServer.AddPrompt(prompt.Definition(), handler(prompt))
+		log.WithField("prompt", prompt.Name).Info("Registered MCP prompt")
+	}
+}
+
+func handler(prompt Prompt) server.PromptHandlerFunc {
+	return func(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		text, err := prompt.Render(request.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewGetPromptResult(prompt.Description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		}), nil
+	}
+}
@@ -0,0 +1,121 @@
+package prompts
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		vars     map[string][]string
+		want     string
+	}{
+		{
+			name:     "variable",
+			template: "Analyze job run {{ prow_job_run_id }}.",
+			vars:     map[string][]string{"prow_job_run_id": {"123"}},
+			want:     "Analyze job run 123.",
+		},
+		{
+			name:     "join",
+			template: "Payloads: {{ payloads | join(', ') }}",
+			vars:     map[string][]string{"payloads": {"a", "b"}},
+			want:     "Payloads: a, b",
+		},
+		{
+			name:     "if true",
+			template: "start{% if notes %} notes: {{ notes }}{% endif %} end",
+			vars:     map[string][]string{"notes": {"x"}},
+			want:     "start notes: x end",
+		},
+		{
+			name:     "if missing",
+			template: "start{% if notes %} notes: {{ notes }}{% endif %} end",
+			want:     "start end",
+		},
+		{
+			name:     "else and not",
+			template: "{% if not a %}no a{% else %}{% if b %}a and b{% endif %}{% endif %}",
+			vars:     map[string][]string{"a": {"1"}, "b": {"2"}},
+			want:     "a and b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseTemplate(tt.template)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tmpl.render(tt.vars))
+		})
+	}
+}
+
+func TestParseTemplateRejectsUnsupported(t *testing.T) {
+	for _, text := range []string{
+		"{% for x in xs %}{{ x }}{% endfor %}",
+		"{{ name | upper }}",
+		"{% if a %}unterminated",
+		"{% endif %}",
+	} {
+		_, err := parseTemplate(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestLoadAndRender(t *testing.T) {
+	fsys := fstest.MapFS{
+		"jobs/analysis.yaml": {Data: []byte(`name: analysis
+description: Analyze some runs
+arguments:
+  - name: runs
+    description: Job run IDs
+    required: true
+    type: array
+  - name: tags
+    description: Tags
+    type: array
+    default: [one, two]
+prompt: |
+  Runs {{ runs | join(' and ') }} tagged {{ tags | join(',') }}.
+`)},
+		"broken.yaml": {Data: []byte("name: broken\nprompt: '{% for x in y %}'\n")},
+		"README.md":   {Data: []byte("# not a prompt")},
+	}
+
+	prompts, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, prompts, 1)
+
+	p := prompts[0]
+	assert.Equal(t, "analysis", p.Definition().Name)
+	text, err := p.Render(map[string]string{"runs": "1, 2"})
+	require.NoError(t, err)
+	assert.Equal(t, "Runs 1 and 2 tagged one,two.\n", text)
+
+	_, err = p.Render(map[string]string{})
+	assert.Error(t, err, "missing required argument should fail")
+}
+
+// TestLoadChatPrompts makes sure every prompt shared with the chat service can be offered over MCP.
+func TestLoadChatPrompts(t *testing.T) {
+	fsys := os.DirFS("../../../chat/prompts")
+	files := 0
+	require.NoError(t, fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && path.Ext(p) == ".yaml" {
+			files++
+		}
+		return err
+	}))
+
+	prompts, err := Load(fsys)
+	require.NoError(t, err)
+	assert.NotZero(t, files)
+	assert.Len(t, prompts, files)
+}
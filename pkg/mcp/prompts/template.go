@@ -0,0 +1,144 @@
+package prompts
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The prompt templates in chat/prompts are written for Jinja2, which the chat service renders in Python.
+// They only use variable substitution, the join filter and if blocks, so that is all this supports;
+// templates using anything else fail to parse and are not offered over MCP.
+var (
+	tagRegexp      = regexp.MustCompile(`\{\{\s*(.*?)\s*\}\}|\{%\s*(.*?)\s*%\}`)
+	variableRegexp = regexp.MustCompile(`^(\w+)(?:\s*\|\s*join\(\s*(?:'([^']*)'|"([^"]*)")\s*\))?$`)
+	ifRegexp       = regexp.MustCompile(`^if\s+(not\s+)?(\w+)$`)
+)
+
+// node is a piece of a parsed template.
+type node interface {
+	render(sb *strings.Builder, vars map[string][]string)
+}
+
+type textNode string
+
+func (n textNode) render(sb *strings.Builder, _ map[string][]string) {
+	sb.WriteString(string(n))
+}
+
+type variableNode struct {
+	name string
+	// join is the separator for list values; a value used without the join filter renders as its first element
+	join *string
+}
+
+func (n variableNode) render(sb *strings.Builder, vars map[string][]string) {
+	values := vars[n.name]
+	switch {
+	case n.join != nil:
+		sb.WriteString(strings.Join(values, *n.join))
+	case len(values) > 0:
+		sb.WriteString(values[0])
+	}
+}
+
+type ifNode struct {
+	name      string
+	negate    bool
+	then, els []node
+	inElse    bool
+}
+
+func (n *ifNode) render(sb *strings.Builder, vars map[string][]string) {
+	body := n.els
+	if truthy(vars[n.name]) != n.negate {
+		body = n.then
+	}
+	for _, child := range body {
+		child.render(sb, vars)
+	}
+}
+
+// truthy follows Jinja2: undefined, empty strings and empty lists are false.
+func truthy(values []string) bool {
+	for _, v := range values {
+		if v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+type template []node
+
+func parseTemplate(text string) (template, error) {
+	root := &ifNode{}
+	stack := []*ifNode{root}
+	appendNode := func(n node) {
+		top := stack[len(stack)-1]
+		if top.inElse {
+			top.els = append(top.els, n)
+		} else {
+			top.then = append(top.then, n)
+		}
+	}
+
+	pos := 0
+	for _, loc := range tagRegexp.FindAllStringSubmatchIndex(text, -1) {
+		if loc[0] > pos {
+			appendNode(textNode(text[pos:loc[0]]))
+		}
+		pos = loc[1]
+
+		if loc[2] >= 0 {
+			expr := text[loc[2]:loc[3]]
+			m := variableRegexp.FindStringSubmatch(expr)
+			if m == nil {
+				return nil, fmt.Errorf("unsupported expression {{ %s }}", expr)
+			}
+			v := variableNode{name: m[1]}
+			if strings.Contains(expr, "|") {
+				sep := m[2] + m[3]
+				v.join = &sep
+			}
+			appendNode(v)
+			continue
+		}
+
+		stmt := text[loc[4]:loc[5]]
+		switch {
+		case ifRegexp.MatchString(stmt):
+			m := ifRegexp.FindStringSubmatch(stmt)
+			n := &ifNode{name: m[2], negate: m[1] != ""}
+			appendNode(n)
+			stack = append(stack, n)
+		case stmt == "else":
+			if len(stack) == 1 || stack[len(stack)-1].inElse {
+				return nil, fmt.Errorf("unexpected {%% else %%}")
+			}
+			stack[len(stack)-1].inElse = true
+		case stmt == "endif":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("unexpected {%% endif %%}")
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			return nil, fmt.Errorf("unsupported statement {%% %s %%}", stmt)
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("missing {%% endif %%}")
+	}
+	if pos < len(text) {
+		appendNode(textNode(text[pos:]))
+	}
+	return root.then, nil
+}
+
+func (t template) render(vars map[string][]string) string {
+	sb := &strings.Builder{}
+	for _, n := range t {
+		n.render(sb, vars)
+	}
+	return sb.String()
+}
@@ -0,0 +1,159 @@
+// Package resources exposes stable, addressable sippy objects as MCP resources, so clients can browse them
+// by URI instead of working out tool arguments.
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/componentreadiness"
+	"github.com/openshift/sippy/pkg/mcp/tools"
+)
+
+const (
+	releaseHealthURI = "sippy://releases/{release}/health"
+	viewURI          = "sippy://views/{view}"
+	regressionURI    = "sippy://regressions/{id}"
+	jobRunURI        = "sippy://job-runs/{id}"
+)
+
+// RegisterResources registers the resource templates whose dependencies are configured, plus a concrete
+// resource for each component readiness view so clients can discover them by listing.
+func RegisterResources(mcpServer *server.MCPServer, deps *tools.ToolDependencies) {
+	r := &resources{BaseTool: tools.NewBaseTool(deps)}
+
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(releaseHealthURI, "Release health",
+		mcp.WithTemplateDescription("Health of a release: payload acceptance, install and upgrade success, and job pass rates."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), r.releaseHealth)
+
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(viewURI, "Component readiness view",
+		mcp.WithTemplateDescription("A component readiness view definition from views.yaml: the releases compared, variants included and analysis options."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), r.view)
+	for _, v := range deps.Views {
+		mcpServer.AddResource(mcp.NewResource(expand(viewURI, "view", v.Name), v.Name,
+			mcp.WithResourceDescription(fmt.Sprintf("Component readiness view comparing %s against %s", v.SampleRelease.Name, v.BaseRelease.Name)),
+			mcp.WithMIMEType("application/json"),
+		), func(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return jsonContents(request.Params.URI, v)
+		})
+	}
+
+	if deps.CRDataProvider != nil {
+		mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(regressionURI, "Component readiness regression",
+			mcp.WithTemplateDescription("A component readiness regression by ID, with its triages and links to test details."),
+			mcp.WithTemplateMIMEType("application/json"),
+		), r.regression)
+	}
+
+	if deps.GCSClient != nil {
+		mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(jobRunURI, "Prow job run summary",
+			mcp.WithTemplateDescription("Summary of a prow job run: the job, its result, the tests that failed and the cluster data recorded for it."),
+			mcp.WithTemplateMIMEType("application/json"),
+		), r.jobRun)
+	}
+	log.Debug("Registered MCP resources")
+}
+
+type resources struct {
+	*tools.BaseTool
+}
+
+func (r *resources) releaseHealth(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	deps := r.GetDependencies()
+	reportEnd := time.Now()
+	if deps.ReportEnd != nil {
+		reportEnd = deps.ReportEnd()
+	}
+	health, err := api.ReleaseHealthReports(deps.DBClient, argument(request, "release"), reportEnd)
+	if err != nil {
+		return nil, err
+	}
+	return jsonContents(request.Params.URI, health)
+}
+
+func (r *resources) view(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	name := argument(request, "view")
+	v, found := componentreadiness.FindViewByName(name, r.GetDependencies().Views)
+	if !found {
+		return nil, fmt.Errorf("view %q not found", name)
+	}
+	return jsonContents(request.Params.URI, v)
+}
+
+func (r *resources) regression(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	id, err := strconv.Atoi(argument(request, "id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid regression id: %w", err)
+	}
+	allReleases, err := r.GetReleases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting releases: %w", err)
+	}
+	deps := r.GetDependencies()
+	regression, err := componentreadiness.GetRegression(deps.DBClient, id, deps.Views, allReleases,
+		deps.CRTimeRoundingFactor, r.NewRequest(ctx, url.Values{}))
+	if err != nil {
+		return nil, err
+	}
+	if regression == nil {
+		return nil, fmt.Errorf("regression %d not found", id)
+	}
+	return jsonContents(request.Params.URI, regression)
+}
+
+func (r *resources) jobRun(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	id, err := strconv.ParseInt(argument(request, "id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prow job run id: %w", err)
+	}
+	deps := r.GetDependencies()
+	summary, err := api.GetJobRunSummary(ctx, deps.DBClient, deps.GCSClient, id)
+	if err != nil {
+		return nil, err
+	}
+	return jsonContents(request.Params.URI, summary)
+}
+
+// argument returns a variable matched from the resource URI template.
+func argument(request mcp.ReadResourceRequest, name string) string {
+	switch v := request.Params.Arguments[name].(type) {
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	case string:
+		return v
+	}
+	return ""
+}
+
+// expand fills a single variable of a URI template.
+func expand(uriTemplate, name, value string) string {
+	return strings.Replace(uriTemplate, "{"+name+"}", url.PathEscape(value), 1)
+}
+
+func jsonContents(uri string, data any) ([]mcp.ResourceContents, error) {
+	text, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling resource: %w", err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(text),
+		},
+	}, nil
+}
@@ -0,0 +1,21 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	assert.Equal(t, "sippy://views/4.20-main", expand(viewURI, "view", "4.20-main"))
+	assert.Equal(t, "sippy://views/a%2Fb", expand(viewURI, "view", "a/b"))
+}
+
+func TestArgument(t *testing.T) {
+	request := mcp.ReadResourceRequest{}
+	request.Params.Arguments = map[string]any{"id": []string{"42"}, "release": "4.20"}
+	assert.Equal(t, "42", argument(request, "id"))
+	assert.Equal(t, "4.20", argument(request, "release"))
+	assert.Empty(t, argument(request, "view"))
+}
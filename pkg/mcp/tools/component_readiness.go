@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/componentreadiness"
+	"github.com/openshift/sippy/pkg/api/componentreadiness/utils"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/reqopts"
+	sippyv1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
+)
+
+// ComponentReportTool implements the get_component_report MCP tool
+type ComponentReportTool struct {
+	*BaseTool
+}
+
+// NewComponentReportTool creates a new component report tool instance
+func NewComponentReportTool(deps *ToolDependencies) *ComponentReportTool {
+	return &ComponentReportTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the component report tool
+func (ct *ComponentReportTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("get_component_report",
+		mcp.WithDescription("Get the component readiness report for a view, comparing test pass rates in the sample release against the basis release. By default only the cells containing regressed tests are returned."),
+		mcp.WithString("view", mcp.Required(), mcp.Description("Name of the component readiness view, for example 4.20-main")),
+		mcp.WithString("component", mcp.Description("Limit the report to a single component")),
+		mcp.WithString("capability", mcp.Description("Limit the report to a single capability of the component")),
+		mcp.WithBoolean("regressed_only", mcp.DefaultBool(true), mcp.Description("Only return cells with regressed tests")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// GetHandler returns the request handler for the component report tool
+func (ct *ComponentReportTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling get_component_report tool call")
+
+		view, err := request.RequireString("view")
+		if err != nil {
+			return ct.CreateErrorResponse(err)
+		}
+		params := url.Values{"view": []string{view}}
+		setIfNotEmpty(params, "component", request.GetString("component", ""))
+		setIfNotEmpty(params, "capability", request.GetString("capability", ""))
+
+		options, warnings, _, err := ct.componentReportOptions(ctx, params)
+		if err != nil {
+			return ct.CreateErrorResponse(err)
+		}
+		report, errs := componentreadiness.GetComponentReport(ctx, ct.deps.CRDataProvider, ct.deps.DBClient, options,
+			api.GetBaseFrontendURL(ct.NewRequest(ctx, params)))
+		if len(errs) > 0 {
+			return ct.CreateErrorResponse(fmt.Errorf("error querying component report: %v", errs))
+		}
+		report.Warnings = warnings
+
+		if request.GetBool("regressed_only", true) {
+			report = regressedOnly(report)
+		}
+		return ct.CreateJSONResponse(report)
+	}
+}
+
+// regressedOnly trims a report down to the cells with regressed tests; the full report is mostly
+// green cells, which are a lot of tokens to tell a client that nothing is wrong.
+func regressedOnly(report componentreport.ComponentReport) componentreport.ComponentReport {
+	rows := []componentreport.ReportRow{}
+	for _, row := range report.Rows {
+		var columns []componentreport.ReportColumn
+		for _, column := range row.Columns {
+			if len(column.RegressedTests) > 0 {
+				columns = append(columns, column)
+			}
+		}
+		if len(columns) > 0 {
+			row.Columns = columns
+			rows = append(rows, row)
+		}
+	}
+	report.Rows = rows
+	return report
+}
+
+// TestDetailsTool implements the get_test_details MCP tool
+type TestDetailsTool struct {
+	*BaseTool
+}
+
+// NewTestDetailsTool creates a new test details tool instance
+func NewTestDetailsTool(deps *ToolDependencies) *TestDetailsTool {
+	return &TestDetailsTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the test details tool
+func (tt *TestDetailsTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("get_test_details",
+		mcp.WithDescription("Get component readiness test details for one test in a view: the statistical comparison of sample and basis results, plus the job runs behind them. Regressed tests in a component report or regression carry the identifiers needed here."),
+		mcp.WithString("view", mcp.Required(), mcp.Description("Name of the component readiness view")),
+		mcp.WithString("test_id", mcp.Required(), mcp.Description("The test ID, for example openshift-tests:abcd1234")),
+		mcp.WithString("component", mcp.Description("The component the test belongs to")),
+		mcp.WithString("capability", mcp.Description("The capability the test belongs to")),
+		mcp.WithArray("variants", mcp.WithStringItems(),
+			mcp.Description("Variants identifying the report cell, as Name:value pairs such as Platform:aws")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// GetHandler returns the request handler for the test details tool
+func (tt *TestDetailsTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling get_test_details tool call")
+
+		view, err := request.RequireString("view")
+		if err != nil {
+			return tt.CreateErrorResponse(err)
+		}
+		testID, err := request.RequireString("test_id")
+		if err != nil {
+			return tt.CreateErrorResponse(err)
+		}
+		params := url.Values{"view": []string{view}, "testId": []string{testID}}
+		setIfNotEmpty(params, "component", request.GetString("component", ""))
+		setIfNotEmpty(params, "capability", request.GetString("capability", ""))
+		for _, variant := range request.GetStringSlice("variants", nil) {
+			name, value, ok := strings.Cut(variant, ":")
+			if !ok {
+				return tt.CreateErrorResponse(fmt.Errorf("variant %q is not in Name:value form", variant))
+			}
+			params.Set(name, value)
+		}
+
+		options, _, releases, err := tt.componentReportOptions(ctx, params)
+		if err != nil {
+			return tt.CreateErrorResponse(err)
+		}
+		details, errs := componentreadiness.GetTestDetails(ctx, tt.deps.CRDataProvider, tt.deps.DBClient, options, releases,
+			api.GetBaseURL(tt.NewRequest(ctx, params)))
+		if len(errs) > 0 {
+			return tt.CreateErrorResponse(fmt.Errorf("error querying test details: %v", errs))
+		}
+		return tt.CreateJSONResponse(details)
+	}
+}
+
+// componentReportOptions parses params exactly as the component readiness API parses its query string, so
+// reports generated for MCP clients share cache entries with the UI.
+func (bt *BaseTool) componentReportOptions(ctx context.Context, params url.Values) (reqopts.RequestOptions, []string, []sippyv1.Release, error) {
+	allJobVariants, errs := componentreadiness.GetJobVariants(ctx, bt.deps.CRDataProvider)
+	if len(errs) > 0 {
+		return reqopts.RequestOptions{}, nil, nil, fmt.Errorf("failed to get job variants")
+	}
+	allReleases, err := bt.GetReleases(ctx)
+	if err != nil {
+		return reqopts.RequestOptions{}, nil, nil, err
+	}
+	options, warnings, err := utils.ParseComponentReportRequest(bt.deps.Views, allReleases, bt.NewRequest(ctx, params),
+		allJobVariants, bt.deps.CRTimeRoundingFactor, bt.deps.VariantJunitTableOverrides)
+	if err != nil {
+		return reqopts.RequestOptions{}, nil, nil, err
+	}
+	options.CacheOption.StaleWhileRevalidate = bt.deps.CRStaleWhileRevalidate
+	return options, warnings, allReleases, nil
+}
+
+func setIfNotEmpty(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
@@ -0,0 +1,158 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/jobartifacts"
+	"github.com/openshift/sippy/pkg/util"
+)
+
+// JobRunRiskAnalysisTool implements the get_job_run_risk_analysis MCP tool
+type JobRunRiskAnalysisTool struct {
+	*BaseTool
+}
+
+// NewJobRunRiskAnalysisTool creates a new job run risk analysis tool instance
+func NewJobRunRiskAnalysisTool(deps *ToolDependencies) *JobRunRiskAnalysisTool {
+	return &JobRunRiskAnalysisTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the job run risk analysis tool
+func (rt *JobRunRiskAnalysisTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("get_job_run_risk_analysis",
+		mcp.WithDescription("Analyze the test failures in a prow job run, rating how likely each is to be a real problem based on how often the test fails in similar jobs."),
+		// job run IDs exceed the precision of a JSON number once parsed as a float, so they are passed as strings
+		mcp.WithString("prow_job_run_id", mcp.Required(), mcp.Description("The prow job run ID")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// GetHandler returns the request handler for the job run risk analysis tool
+func (rt *JobRunRiskAnalysisTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling get_job_run_risk_analysis tool call")
+
+		jobRunIDStr, err := request.RequireString("prow_job_run_id")
+		if err != nil {
+			return rt.CreateErrorResponse(err)
+		}
+		jobRunID, err := strconv.ParseInt(jobRunIDStr, 10, 64)
+		if err != nil {
+			return rt.CreateErrorResponse(fmt.Errorf("unable to parse prow_job_run_id: %w", err))
+		}
+
+		logger := log.WithField("func", "JobRunRiskAnalysisTool").WithField("jobRunID", jobRunID)
+		jobRun, err := api.FetchJobRun(rt.deps.DBClient, jobRunID, false, nil, logger)
+		if err != nil {
+			return rt.CreateErrorResponse(err)
+		}
+		result, err := api.JobRunRiskAnalysis(ctx, logger, rt.deps.DBClient, rt.deps.BigQueryClient, rt.deps.CacheClient, jobRun, false)
+		if err != nil {
+			return rt.CreateErrorResponse(err)
+		}
+		return rt.CreateJSONResponse(result)
+	}
+}
+
+// QueryJobArtifactsTool implements the query_job_artifacts MCP tool
+type QueryJobArtifactsTool struct {
+	*BaseTool
+}
+
+// NewQueryJobArtifactsTool creates a new job artifacts query tool instance
+func NewQueryJobArtifactsTool(deps *ToolDependencies) *QueryJobArtifactsTool {
+	return &QueryJobArtifactsTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the job artifacts query tool
+func (qt *QueryJobArtifactsTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("query_job_artifacts",
+		mcp.WithDescription("Find artifact files from prow job runs matching a path glob, optionally searching their content for a string or regular expression and returning matching lines with context."),
+		mcp.WithArray("prow_job_run_ids", mcp.Required(), mcp.WithStringItems(), mcp.Description("The prow job run IDs to search")),
+		mcp.WithString("path_glob", mcp.Required(), mcp.Description("Glob matched against artifact paths relative to each job run, for example artifacts/*e2e*/gather-extra/build-log.txt")),
+		mcp.WithString("text_contains", mcp.Description("Return lines containing this string")),
+		mcp.WithString("text_regex", mcp.Description("Return lines matching this regular expression; ignored if text_contains is set")),
+		mcp.WithNumber("before_context", mcp.Description("Lines of context to include before each match")),
+		mcp.WithNumber("after_context", mcp.Description("Lines of context to include after each match")),
+		mcp.WithNumber("max_file_matches", mcp.Description("Maximum number of matches to return per file")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// GetHandler returns the request handler for the job artifacts query tool
+func (qt *QueryJobArtifactsTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling query_job_artifacts tool call")
+
+		q := &jobartifacts.JobArtifactQuery{
+			GcsBucket: qt.deps.GCSClient.Bucket(util.GcsBucketRoot),
+			DbClient:  qt.deps.DBClient,
+			Cache:     qt.deps.CacheClient,
+			JobRunIDs: []int64{},
+		}
+		jobRunIDs, err := request.RequireStringSlice("prow_job_run_ids")
+		if err != nil {
+			return qt.CreateErrorResponse(err)
+		}
+		for _, idStr := range jobRunIDs {
+			id, err := strconv.ParseInt(idStr, 10, 64)
+			if err != nil {
+				return qt.CreateErrorResponse(fmt.Errorf("unable to parse prow job run id %q: %w", idStr, err))
+			}
+			q.JobRunIDs = append(q.JobRunIDs, id)
+		}
+		if q.PathGlob, err = request.RequireString("path_glob"); err != nil {
+			return qt.CreateErrorResponse(err)
+		}
+		if q.ContentMatcher, err = qt.contentMatcher(ctx, request); err != nil {
+			return qt.CreateErrorResponse(err)
+		}
+
+		return qt.CreateJSONResponse(qt.deps.JobArtifactsManager.Query(ctx, q))
+	}
+}
+
+// contentMatcher builds the matcher from the same parameters, and with the same limits, as the job artifacts API.
+func (qt *QueryJobArtifactsTool) contentMatcher(ctx context.Context, request mcp.CallToolRequest) (jobartifacts.ContentMatcher, error) {
+	contains := request.GetString("text_contains", "")
+	regexStr := request.GetString("text_regex", "")
+	if contains == "" && regexStr == "" {
+		return nil, nil // nil matcher means don't bother reading the artifacts, just return metadata
+	}
+
+	params := url.Values{}
+	for arg, name := range map[string]string{
+		"before_context":   "beforeContext",
+		"after_context":    "afterContext",
+		"max_file_matches": "maxFileMatches",
+	} {
+		if value := request.GetInt(arg, -1); value >= 0 {
+			params.Set(name, strconv.Itoa(value))
+		}
+	}
+	contextBefore, contextAfter, maxMatches, errs := jobartifacts.ParseLineMatcherParams(qt.NewRequest(ctx, params))
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid line matcher parameters: %v", errs)
+	}
+
+	if contains != "" {
+		return jobartifacts.NewStringMatcher(contains, contextBefore, contextAfter, maxMatches), nil
+	}
+	re, err := regexp.Compile(regexStr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing text_regex: %w", err)
+	}
+	return jobartifacts.NewRegexMatcher(re, contextBefore, contextAfter, maxMatches), nil
+}
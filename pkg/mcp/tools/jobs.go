@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api"
+	apitype "github.com/openshift/sippy/pkg/apis/api"
+	"github.com/openshift/sippy/pkg/filter"
+)
+
+// defaultListLimit keeps list results to a size an AI client can reasonably consume unless it asks for more.
+const defaultListLimit = 50
+
+const periodTwoDay = "twoDay"
+
+const filterDescription = `Filter in sippy's JSON filter syntax, for example {"items":[{"columnField":"name","operatorValue":"contains","value":"aws"}],"linkOperator":"and"}. ` +
+	`Supported operators include contains, equals, starts with, ends with, has entry, has entry containing, is empty, is not empty, =, !=, <, <=, >, >=, and each item may set "not": true.`
+
+// ListJobsTool implements the list_jobs MCP tool
+type ListJobsTool struct {
+	*BaseTool
+}
+
+// NewListJobsTool creates a new list jobs tool instance
+func NewListJobsTool(deps *ToolDependencies) *ListJobsTool {
+	return &ListJobsTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the list jobs tool
+func (jt *ListJobsTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("list_jobs", withListOptions(
+		mcp.WithDescription("List prow jobs in a release with their pass rates for the current and previous periods, as shown on sippy's jobs page."),
+		mcp.WithString("release", mcp.Required(), mcp.Description("The release, such as 4.20, or Presubmits")),
+		mcp.WithString("period", mcp.Enum("default", periodTwoDay),
+			mcp.Description("default compares the last 7 days to the 7 before; twoDay compares the last 2 days to the 7 before")),
+	)...)
+}
+
+// GetHandler returns the request handler for the list jobs tool
+func (jt *ListJobsTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling list_jobs tool call")
+
+		release, err := request.RequireString("release")
+		if err != nil {
+			return jt.CreateErrorResponse(err)
+		}
+		filterOpts, err := filter.FilterOptionsFromRequest(jt.NewRequest(ctx, listParams(request)), "current_pass_percentage", apitype.SortDescending)
+		if err != nil {
+			return jt.CreateErrorResponse(err)
+		}
+
+		reportEnd := time.Now()
+		if jt.deps.ReportEnd != nil {
+			reportEnd = jt.deps.ReportEnd()
+		}
+		jobs, err := api.JobReportsFromDB(jt.deps.DBClient, release, request.GetString("period", ""), filterOpts,
+			time.Time{}, time.Time{}, time.Time{}, reportEnd)
+		if err != nil {
+			return jt.CreateErrorResponse(fmt.Errorf("error building job report: %w", err))
+		}
+		return jt.CreateJSONResponse(jobs)
+	}
+}
+
+// ListTestsTool implements the list_tests MCP tool
+type ListTestsTool struct {
+	*BaseTool
+}
+
+// NewListTestsTool creates a new list tests tool instance
+func NewListTestsTool(deps *ToolDependencies) *ListTestsTool {
+	return &ListTestsTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the list tests tool
+func (tt *ListTestsTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("list_tests", withListOptions(
+		mcp.WithDescription("List tests in a release with their pass, failure and flake rates for the current and previous periods, as shown on sippy's tests page."),
+		mcp.WithString("release", mcp.Required(), mcp.Description("The release, such as 4.20, or Presubmits")),
+		mcp.WithString("period", mcp.Enum("default", periodTwoDay),
+			mcp.Description("default compares the last 7 days to the 7 before; twoDay compares the last 2 days to the 7 before")),
+		mcp.WithBoolean("collapse", mcp.DefaultBool(true),
+			mcp.Description("Aggregate results across variants; when false each test is reported per variant combination")),
+	)...)
+}
+
+// GetHandler returns the request handler for the list tests tool
+func (tt *ListTestsTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling list_tests tool call")
+
+		release, err := request.RequireString("release")
+		if err != nil {
+			return tt.CreateErrorResponse(err)
+		}
+		params := listParams(request)
+		setIfNotEmpty(params, "period", request.GetString("period", ""))
+		if !request.GetBool("collapse", true) {
+			params.Set("collapse", "false")
+		}
+
+		tests, err := api.GetTestsReportFromDB(tt.NewRequest(ctx, params), tt.deps.DBClient, tt.deps.CacheClient, release)
+		if err != nil {
+			return tt.CreateErrorResponse(fmt.Errorf("error building test report: %w", err))
+		}
+		return tt.CreateJSONResponse(tests)
+	}
+}
+
+// withListOptions adds the filtering, sorting and limit arguments shared by the list tools.
+func withListOptions(opts ...mcp.ToolOption) []mcp.ToolOption {
+	return append(opts,
+		mcp.WithString("filter", mcp.Description(filterDescription)),
+		mcp.WithString("sort_field", mcp.Description("Field to sort by, such as current_pass_percentage or net_improvement")),
+		mcp.WithString("sort", mcp.Enum(string(apitype.SortAscending), string(apitype.SortDescending)), mcp.Description("Sort direction")),
+		mcp.WithNumber("limit", mcp.DefaultNumber(defaultListLimit), mcp.Description("Maximum number of results to return")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// listParams maps the list arguments to the query parameters the HTTP API reads them from.
+func listParams(request mcp.CallToolRequest) url.Values {
+	params := url.Values{}
+	setIfNotEmpty(params, "filter", request.GetString("filter", ""))
+	setIfNotEmpty(params, "sortField", request.GetString("sort_field", ""))
+	setIfNotEmpty(params, "sort", request.GetString("sort", ""))
+	params.Set("limit", strconv.Itoa(request.GetInt("limit", defaultListLimit)))
+	return params
+}
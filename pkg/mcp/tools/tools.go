@@ -4,30 +4,55 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
 
+	"cloud.google.com/go/storage"
+	"github.com/andygrunwald/go-jira"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/componentreadiness/dataprovider"
+	"github.com/openshift/sippy/pkg/api/jobartifacts"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crview"
 	"github.com/openshift/sippy/pkg/apis/cache"
+	configv1 "github.com/openshift/sippy/pkg/apis/config/v1"
+	sippyv1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
 	"github.com/openshift/sippy/pkg/bigquery"
 	"github.com/openshift/sippy/pkg/db"
 )
 
-// RegisterTools registers all available MCP tools with the server
-func RegisterTools(mcpServer *server.MCPServer, dbClient *db.DB, bigQueryClient *bigquery.Client, cacheClient cache.Cache) {
-	// Create tool dependencies
-	deps := &ToolDependencies{
-		DBClient:       dbClient,
-		BigQueryClient: bigQueryClient,
-		CacheClient:    cacheClient,
-	}
-
+// RegisterTools registers all available MCP tools with the server. Tools whose dependencies are not configured
+// on this sippy instance are skipped, mirroring how the HTTP API requires capabilities.
+func RegisterTools(mcpServer *server.MCPServer, deps *ToolDependencies) {
 	// Register all tools
 	tools := []MCPTool{
 		NewReleasesTool(deps),
 		NewHealthTool(deps), // Example tool demonstrating the pattern
-		// Add new tools here following the same pattern
+		NewListJobsTool(deps),
+		NewListTestsTool(deps),
+		NewJobRunRiskAnalysisTool(deps),
+		NewListTriagesTool(deps),
+		NewGetTriageTool(deps),
+		NewSuggestTriagesTool(deps),
+	}
+	if deps.CRDataProvider != nil {
+		tools = append(tools,
+			NewComponentReportTool(deps),
+			NewTestDetailsTool(deps),
+			NewListRegressionsTool(deps),
+			NewGetRegressionTool(deps),
+		)
+	}
+	if deps.EnableWriteTools {
+		tools = append(tools, NewCreateTriageTool(deps))
+	}
+	if deps.GCSClient != nil && deps.JobArtifactsManager != nil {
+		tools = append(tools, NewQueryJobArtifactsTool(deps))
 	}
 
 	for _, tool := range tools {
@@ -41,6 +66,22 @@ type ToolDependencies struct {
 	DBClient       *db.DB
 	BigQueryClient *bigquery.Client
 	CacheClient    cache.Cache
+
+	// CRDataProvider and the settings below let tools produce component readiness reports the same way,
+	// and from the same cache entries, as the HTTP API.
+	CRDataProvider             dataprovider.DataProvider
+	Views                      []crview.View
+	VariantJunitTableOverrides []configv1.VariantJunitTableOverride
+	CRTimeRoundingFactor       time.Duration
+	CRStaleWhileRevalidate     time.Duration
+
+	GCSClient           *storage.Client
+	JobArtifactsManager *jobartifacts.Manager
+	JiraClient          *jira.Client
+	// ReportEnd returns the end of the reporting period, which is pinned in some deployments.
+	ReportEnd func() time.Time
+	// EnableWriteTools registers tools that modify data, as the write endpoints capability does for the HTTP API.
+	EnableWriteTools bool
 }
 
 // MCPTool defines the interface that all MCP tools must implement
@@ -100,3 +141,46 @@ func (bt *BaseTool) CreateTextResponse(text string) *mcp.CallToolResult {
 func (bt *BaseTool) CreateErrorResponse(err error) (*mcp.CallToolResult, error) {
 	return nil, err
 }
+
+type httpRequestKey struct{}
+
+// WithHTTPRequest records the HTTP request carrying an MCP call, so tools can build links and identify the user
+// the same way the HTTP API does.
+func WithHTTPRequest(ctx context.Context, req *http.Request) context.Context {
+	return context.WithValue(ctx, httpRequestKey{}, req)
+}
+
+// NewRequest builds the request that the equivalent HTTP API would have received, so tools can reuse the API's
+// parameter parsing and produce identical cache keys.
+func (bt *BaseTool) NewRequest(ctx context.Context, params url.Values) *http.Request {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/?"+params.Encode(), nil)
+	if orig, ok := ctx.Value(httpRequestKey{}).(*http.Request); ok {
+		req.Host = orig.Host
+		req.TLS = orig.TLS
+		req.Header = orig.Header.Clone()
+	}
+	return req
+}
+
+// RequestUser returns the authenticated user making the call, following the same rules as the HTTP API.
+func (bt *BaseTool) RequestUser(ctx context.Context) string {
+	var user string
+	if orig, ok := ctx.Value(httpRequestKey{}).(*http.Request); ok {
+		user = orig.Header.Get("X-Forwarded-User")
+	}
+	if user == "" && os.Getenv("DEV_MODE") == "1" {
+		user = "developer"
+	}
+	return user
+}
+
+// GetReleases returns release data, preferring BigQuery and falling back to the data provider in mock mode.
+func (bt *BaseTool) GetReleases(ctx context.Context) ([]sippyv1.Release, error) {
+	if bt.deps.BigQueryClient != nil {
+		return api.GetReleases(ctx, bt.deps.BigQueryClient, false)
+	}
+	if bt.deps.CRDataProvider != nil {
+		return bt.deps.CRDataProvider.QueryReleases(ctx)
+	}
+	return nil, fmt.Errorf("no data source available for releases")
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+)
+
+func TestNewRequestCarriesOriginalRequest(t *testing.T) {
+	orig, err := http.NewRequest(http.MethodPost, "http://sippy.example.com/mcp/v1/", nil)
+	require.NoError(t, err)
+	orig.Header.Set("X-Forwarded-Proto", "https")
+	orig.Header.Set("X-Forwarded-User", "someone")
+	ctx := WithHTTPRequest(context.Background(), orig)
+
+	bt := NewBaseTool(&ToolDependencies{})
+	req := bt.NewRequest(ctx, url.Values{"view": []string{"4.20-main"}, "testId": []string{"openshift-tests:abc"}})
+	assert.Equal(t, "https://sippy.example.com", api.GetBaseURL(req))
+	assert.Equal(t, "4.20-main", req.URL.Query().Get("view"))
+	assert.Equal(t, "openshift-tests:abc", req.URL.Query().Get("testId"))
+	assert.Equal(t, "someone", bt.RequestUser(ctx))
+
+	// tools can also be called without an HTTP request, for example in tests
+	req = bt.NewRequest(context.Background(), url.Values{})
+	assert.Empty(t, req.URL.Query())
+	assert.Empty(t, bt.RequestUser(context.Background()))
+}
+
+func TestListParams(t *testing.T) {
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{
+		"filter":     `{"items":[{"columnField":"name","operatorValue":"contains","value":"aws"}]}`,
+		"sort_field": "net_improvement",
+		"limit":      float64(10),
+	}}}
+	params := listParams(request)
+	assert.Equal(t, `{"items":[{"columnField":"name","operatorValue":"contains","value":"aws"}]}`, params.Get("filter"))
+	assert.Equal(t, "net_improvement", params.Get("sortField"))
+	assert.Equal(t, "10", params.Get("limit"))
+	assert.NotContains(t, params, "sort")
+
+	params = listParams(mcp.CallToolRequest{})
+	assert.Equal(t, "50", params.Get("limit"))
+}
+
+func TestRegressedOnly(t *testing.T) {
+	regressed := componentreport.ReportColumn{
+		ColumnIdentification: crtest.ColumnIdentification{Variants: map[string]string{"Platform": "aws"}},
+		Status:               crtest.SignificantRegression,
+		RegressedTests:       []componentreport.ReportTestSummary{{}},
+	}
+	passing := componentreport.ReportColumn{
+		ColumnIdentification: crtest.ColumnIdentification{Variants: map[string]string{"Platform": "gcp"}},
+		Status:               crtest.NotSignificant,
+	}
+	report := componentreport.ComponentReport{
+		Rows: []componentreport.ReportRow{
+			{RowIdentification: crtest.RowIdentification{Component: "etcd"}, Columns: []componentreport.ReportColumn{regressed, passing}},
+			{RowIdentification: crtest.RowIdentification{Component: "kube-apiserver"}, Columns: []componentreport.ReportColumn{passing}},
+		},
+		Warnings: []string{"a warning"},
+	}
+
+	trimmed := regressedOnly(report)
+	require.Len(t, trimmed.Rows, 1)
+	assert.Equal(t, "etcd", trimmed.Rows[0].Component)
+	assert.Equal(t, []componentreport.ReportColumn{regressed}, trimmed.Rows[0].Columns)
+	assert.Equal(t, report.Warnings, trimmed.Warnings)
+	assert.Len(t, report.Rows[0].Columns, 2, "the original report should not be modified")
+}
@@ -0,0 +1,296 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api/componentreadiness"
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crview"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+// ListRegressionsTool implements the list_regressions MCP tool
+type ListRegressionsTool struct {
+	*BaseTool
+}
+
+// NewListRegressionsTool creates a new list regressions tool instance
+func NewListRegressionsTool(deps *ToolDependencies) *ListRegressionsTool {
+	return &ListRegressionsTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the list regressions tool
+func (lt *ListRegressionsTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("list_regressions",
+		mcp.WithDescription("List component readiness regressions tracked for a view or release, including when each was opened and closed, and the triages that reference it. Specify at most one of view and release."),
+		mcp.WithString("view", mcp.Description("Name of the component readiness view")),
+		mcp.WithString("release", mcp.Description("Release to list regressions for, such as 4.20")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// GetHandler returns the request handler for the list regressions tool
+func (lt *ListRegressionsTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling list_regressions tool call")
+
+		view := request.GetString("view", "")
+		release := request.GetString("release", "")
+		if view != "" && release != "" {
+			return lt.CreateErrorResponse(fmt.Errorf("cannot specify both view and release"))
+		}
+		views := lt.deps.Views
+		if view != "" {
+			v, found := componentreadiness.FindViewByName(view, lt.deps.Views)
+			if !found {
+				return lt.CreateErrorResponse(fmt.Errorf("view %q not found", view))
+			}
+			views = []crview.View{v}
+			release = v.SampleRelease.Name
+		}
+
+		allReleases, err := lt.GetReleases(ctx)
+		if err != nil {
+			return lt.CreateErrorResponse(fmt.Errorf("error getting releases: %w", err))
+		}
+		regressions, err := componentreadiness.ListRegressions(lt.deps.DBClient, release, views, allReleases,
+			lt.deps.CRTimeRoundingFactor, lt.NewRequest(ctx, url.Values{}))
+		if err != nil {
+			return lt.CreateErrorResponse(err)
+		}
+		return lt.CreateJSONResponse(regressions)
+	}
+}
+
+// GetRegressionTool implements the get_regression MCP tool
+type GetRegressionTool struct {
+	*BaseTool
+}
+
+// NewGetRegressionTool creates a new get regression tool instance
+func NewGetRegressionTool(deps *ToolDependencies) *GetRegressionTool {
+	return &GetRegressionTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the get regression tool
+func (gt *GetRegressionTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("get_regression",
+		mcp.WithDescription("Get a single component readiness regression by ID, with links to its test details report."),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("The regression ID")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// GetHandler returns the request handler for the get regression tool
+func (gt *GetRegressionTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling get_regression tool call")
+
+		id, err := request.RequireInt("id")
+		if err != nil {
+			return gt.CreateErrorResponse(err)
+		}
+		allReleases, err := gt.GetReleases(ctx)
+		if err != nil {
+			return gt.CreateErrorResponse(fmt.Errorf("error getting releases: %w", err))
+		}
+		regression, err := componentreadiness.GetRegression(gt.deps.DBClient, id, gt.deps.Views, allReleases,
+			gt.deps.CRTimeRoundingFactor, gt.NewRequest(ctx, url.Values{}))
+		if err != nil {
+			return gt.CreateErrorResponse(err)
+		}
+		if regression == nil {
+			return gt.CreateErrorResponse(fmt.Errorf("regression %d not found", id))
+		}
+		return gt.CreateJSONResponse(regression)
+	}
+}
+
+// ListTriagesTool implements the list_triages MCP tool
+type ListTriagesTool struct {
+	*BaseTool
+}
+
+// NewListTriagesTool creates a new list triages tool instance
+func NewListTriagesTool(deps *ToolDependencies) *ListTriagesTool {
+	return &ListTriagesTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the list triages tool
+func (lt *ListTriagesTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("list_triages",
+		mcp.WithDescription("List component readiness triage records, which tie regressions to the bug tracking their cause."),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// GetHandler returns the request handler for the list triages tool
+func (lt *ListTriagesTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling list_triages tool call")
+
+		triages, err := componentreadiness.ListTriages(lt.deps.DBClient, lt.NewRequest(ctx, url.Values{}))
+		if err != nil {
+			return lt.CreateErrorResponse(err)
+		}
+		return lt.CreateJSONResponse(triages)
+	}
+}
+
+// GetTriageTool implements the get_triage MCP tool
+type GetTriageTool struct {
+	*BaseTool
+}
+
+// NewGetTriageTool creates a new get triage tool instance
+func NewGetTriageTool(deps *ToolDependencies) *GetTriageTool {
+	return &GetTriageTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the get triage tool
+func (gt *GetTriageTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("get_triage",
+		mcp.WithDescription("Get a single component readiness triage record by ID, including its bug and regressions."),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("The triage ID")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// GetHandler returns the request handler for the get triage tool
+func (gt *GetTriageTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling get_triage tool call")
+
+		id, err := request.RequireInt("id")
+		if err != nil {
+			return gt.CreateErrorResponse(err)
+		}
+		triage, err := componentreadiness.GetTriage(gt.deps.DBClient, id, gt.NewRequest(ctx, url.Values{}))
+		if err != nil {
+			return gt.CreateErrorResponse(err)
+		}
+		if triage == nil {
+			return gt.CreateErrorResponse(fmt.Errorf("triage %d not found", id))
+		}
+		return gt.CreateJSONResponse(triage)
+	}
+}
+
+// CreateTriageTool implements the create_triage MCP tool
+type CreateTriageTool struct {
+	*BaseTool
+}
+
+// NewCreateTriageTool creates a new create triage tool instance
+func NewCreateTriageTool(deps *ToolDependencies) *CreateTriageTool {
+	return &CreateTriageTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the create triage tool
+func (ct *CreateTriageTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("create_triage",
+		mcp.WithDescription("Create a component readiness triage record linking regressions to the bug that explains them. Use list_regressions to find regression IDs."),
+		mcp.WithString("url", mcp.Required(), mcp.Description("URL of the bug tracking the cause, typically a Jira issue")),
+		mcp.WithString("type", mcp.Required(), mcp.Description("Best guess at the kind of problem"),
+			mcp.Enum(string(models.TriageTypeCIInfra), string(models.TriageTypeProductInfra),
+				string(models.TriageTypeProduct), string(models.TriageTypeTest))),
+		mcp.WithString("description", mcp.Description("Short description of the problem")),
+		mcp.WithArray("regression_ids", mcp.WithNumberItems(), mcp.Description("IDs of the regressions being triaged")),
+		mcp.WithDestructiveHintAnnotation(false),
+	)
+}
+
+// GetHandler returns the request handler for the create triage tool
+func (ct *CreateTriageTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling create_triage tool call")
+
+		triageURL, err := request.RequireString("url")
+		if err != nil {
+			return ct.CreateErrorResponse(err)
+		}
+		triageType, err := request.RequireString("type")
+		if err != nil {
+			return ct.CreateErrorResponse(err)
+		}
+		triage := models.Triage{
+			URL:         triageURL,
+			Type:        models.TriageType(triageType),
+			Description: request.GetString("description", ""),
+		}
+		for _, id := range request.GetIntSlice("regression_ids", nil) {
+			triage.Regressions = append(triage.Regressions, models.TestRegression{ID: uint(id)})
+		}
+
+		user := ct.RequestUser(ctx)
+		log.Infof("triage created via MCP by user: %s", user)
+		dbc := ct.deps.DBClient.DB.WithContext(context.WithValue(ctx, models.CurrentUserKey, user))
+		triage, err = componentreadiness.CreateTriage(dbc, ct.deps.JiraClient, triage, ct.NewRequest(ctx, url.Values{}))
+		if err != nil {
+			return ct.CreateErrorResponse(err)
+		}
+		return ct.CreateJSONResponse(triage)
+	}
+}
+
+// SuggestTriagesTool implements the suggest_triages MCP tool
+type SuggestTriagesTool struct {
+	*BaseTool
+}
+
+// NewSuggestTriagesTool creates a new suggest triages tool instance
+func NewSuggestTriagesTool(deps *ToolDependencies) *SuggestTriagesTool {
+	return &SuggestTriagesTool{
+		BaseTool: NewBaseTool(deps),
+	}
+}
+
+// GetDefinition returns the MCP tool definition for the suggest triages tool
+func (st *SuggestTriagesTool) GetDefinition() mcp.Tool {
+	return mcp.NewTool("suggest_triages",
+		mcp.WithDescription("Suggest triages for groups of open, untriaged regressions that failed in the same job runs with similar output, so a single cause can be triaged once. Each suggestion includes the regressions, a sample of the shared failure output, and a suggested description to pass to create_triage. Specify exactly one of view and release."),
+		mcp.WithString("view", mcp.Description("Name of the component readiness view")),
+		mcp.WithString("release", mcp.Description("Release to suggest triages for, such as 4.20")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
+}
+
+// GetHandler returns the request handler for the suggest triages tool
+func (st *SuggestTriagesTool) GetHandler() func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Debug("Handling suggest_triages tool call")
+
+		view := request.GetString("view", "")
+		release := request.GetString("release", "")
+		if (view == "") == (release == "") {
+			return st.CreateErrorResponse(fmt.Errorf("specify exactly one of view and release"))
+		}
+		if view != "" {
+			v, found := componentreadiness.FindViewByName(view, st.deps.Views)
+			if !found {
+				return st.CreateErrorResponse(fmt.Errorf("view %q not found", view))
+			}
+			release = v.SampleRelease.Name
+		}
+
+		suggestions, err := componentreadiness.GetTriageSuggestions(st.deps.DBClient, release, st.NewRequest(ctx, url.Values{}))
+		if err != nil {
+			return st.CreateErrorResponse(err)
+		}
+		return st.CreateJSONResponse(suggestions)
+	}
+}
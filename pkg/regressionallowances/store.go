@@ -0,0 +1,194 @@
+package regressionallowances
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+// DBLookup finds intentional regressions stored in the regression_allowances table. Until any allowance of a
+// release has been stored, expired or not, the release's allowances compiled into sippy apply instead, so they keep
+// applying before they are imported and stop once they are, letting expiry and deletion take effect. Allowances
+// are loaded once per release on first use, so a lookup should live no longer than the report that uses it.
+type DBLookup struct {
+	dbc *db.DB
+	now time.Time
+
+	lock      sync.Mutex
+	byRelease map[string]map[string]IntentionalRegression
+}
+
+func NewDBLookup(dbc *db.DB) *DBLookup {
+	return &DBLookup{
+		dbc:       dbc,
+		now:       time.Now(),
+		byRelease: map[string]map[string]IntentionalRegression{},
+	}
+}
+
+// IntentionalRegressionFor has the same contract as the package level function of the same name, returning
+// only allowances that have not expired.
+func (l *DBLookup) IntentionalRegressionFor(releaseString string, variant crtest.ColumnIdentification, testID string) *IntentionalRegression {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	targetMap, ok := l.byRelease[releaseString]
+	if !ok {
+		var err error
+		targetMap, err = l.load(releaseString)
+		if err != nil {
+			// not remembered, so the next lookup tries again
+			log.WithError(err).Errorf("error loading regression allowances for release %s", releaseString)
+			return nil
+		}
+		l.byRelease[releaseString] = targetMap
+	}
+
+	if t, ok := targetMap[keyFor(testID, variant)]; ok {
+		log.Debugf("found approved regression: %+v", t)
+		return &t
+	}
+	return nil
+}
+
+func (l *DBLookup) load(releaseString string) (map[string]IntentionalRegression, error) {
+	var allowances []models.RegressionAllowance
+	res := l.dbc.DB.Where("release = ?", releaseString).Find(&allowances)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return releaseAllowances(intentionalRegressions[release(releaseString)], allowances, l.now), nil
+}
+
+// releaseAllowances returns the unexpired stored allowances of a release, or its compiled-in ones if none have been
+// stored.
+func releaseAllowances(embedded map[string]IntentionalRegression, allowances []models.RegressionAllowance, now time.Time) map[string]IntentionalRegression {
+	if len(allowances) == 0 {
+		return embedded
+	}
+	targetMap := make(map[string]IntentionalRegression, len(allowances))
+	for _, allowance := range allowances {
+		if allowance.ExpiresAt != nil && !allowance.ExpiresAt.After(now) {
+			continue
+		}
+		ir := FromAllowance(allowance)
+		targetMap[keyFor(ir.TestID, ir.Variant)] = ir
+	}
+	return targetMap
+}
+
+// FromAllowance converts a stored allowance into the form used by component readiness analysis.
+func FromAllowance(a models.RegressionAllowance) IntentionalRegression {
+	return IntentionalRegression{
+		JiraComponent:             a.JiraComponent,
+		TestID:                    a.TestID,
+		TestName:                  a.TestName,
+		Variant:                   crtest.ColumnIdentification{Variants: variantsFromStrings(a.Variants)},
+		PreviousSuccesses:         a.PreviousSuccesses,
+		PreviousFailures:          a.PreviousFailures,
+		PreviousFlakes:            a.PreviousFlakes,
+		RegressedSuccesses:        a.RegressedSuccesses,
+		RegressedFailures:         a.RegressedFailures,
+		RegressedFlakes:           a.RegressedFlakes,
+		PreviousRelease:           a.PreviousRelease,
+		JiraBug:                   a.JiraBug,
+		ReasonToAllowInsteadOfFix: a.ReasonToAllowInsteadOfFix,
+	}
+}
+
+// ToAllowance converts an intentional regression allowed in the given release into its stored form.
+func ToAllowance(releaseString string, in IntentionalRegression) models.RegressionAllowance {
+	return models.RegressionAllowance{
+		Release:                   releaseString,
+		TestID:                    in.TestID,
+		TestName:                  in.TestName,
+		Variants:                  variantsToStrings(in.Variant.Variants),
+		JiraComponent:             in.JiraComponent,
+		PreviousRelease:           in.PreviousRelease,
+		PreviousSuccesses:         in.PreviousSuccesses,
+		PreviousFailures:          in.PreviousFailures,
+		PreviousFlakes:            in.PreviousFlakes,
+		RegressedSuccesses:        in.RegressedSuccesses,
+		RegressedFailures:         in.RegressedFailures,
+		RegressedFlakes:           in.RegressedFlakes,
+		JiraBug:                   in.JiraBug,
+		ReasonToAllowInsteadOfFix: in.ReasonToAllowInsteadOfFix,
+	}
+}
+
+func variantsToStrings(variants map[string]string) []string {
+	result := make([]string, 0, len(variants))
+	for k, v := range variants {
+		result = append(result, k+":"+v)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func variantsFromStrings(variants []string) map[string]string {
+	result := make(map[string]string, len(variants))
+	for _, variant := range variants {
+		if k, v, ok := strings.Cut(variant, ":"); ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// EmbeddedIntentionalRegressions returns the allowances compiled into sippy from the regressions directory,
+// keyed by release.
+func EmbeddedIntentionalRegressions() map[string][]IntentionalRegression {
+	result := map[string][]IntentionalRegression{}
+	for rel, regressions := range intentionalRegressions {
+		for _, ir := range regressions {
+			result[string(rel)] = append(result[string(rel)], ir)
+		}
+		sort.Slice(result[string(rel)], func(i, j int) bool {
+			a, b := result[string(rel)][i], result[string(rel)][j]
+			return keyFor(a.TestID, a.Variant) < keyFor(b.TestID, b.Variant)
+		})
+	}
+	return result
+}
+
+// ImportEmbeddedAllowances stores the compiled-in allowances in the database, skipping any that already exist,
+// and returns how many were created. The context must carry models.CurrentUserKey for the audit log.
+func ImportEmbeddedAllowances(ctx context.Context, dbc *db.DB) (int, error) {
+	created := 0
+	embedded := EmbeddedIntentionalRegressions()
+	releases := make([]string, 0, len(embedded))
+	for rel := range embedded {
+		releases = append(releases, rel)
+	}
+	sort.Strings(releases)
+
+	for _, rel := range releases {
+		for _, ir := range embedded[rel] {
+			allowance := ToAllowance(rel, ir)
+			var count int64
+			res := dbc.DB.WithContext(ctx).Model(&models.RegressionAllowance{}).
+				Where("release = ? AND test_id = ? AND variants = ?", allowance.Release, allowance.TestID, allowance.Variants).
+				Count(&count)
+			if res.Error != nil {
+				return created, fmt.Errorf("error checking for existing allowance for %s in %s: %w", ir.TestID, rel, res.Error)
+			}
+			if count > 0 {
+				continue
+			}
+			if err := dbc.DB.WithContext(ctx).Create(&allowance).Error; err != nil {
+				return created, fmt.Errorf("error importing allowance for %s in %s: %w", ir.TestID, rel, err)
+			}
+			created++
+		}
+	}
+	return created, nil
+}
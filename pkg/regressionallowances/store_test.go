@@ -0,0 +1,64 @@
+package regressionallowances
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/sippy/pkg/apis/api/componentreport/crtest"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+func TestAllowanceRoundTrip(t *testing.T) {
+	embedded := EmbeddedIntentionalRegressions()
+	require.NotEmpty(t, embedded)
+
+	for rel, regressions := range embedded {
+		for _, ir := range regressions {
+			allowance := ToAllowance(rel, ir)
+			assert.Equal(t, rel, allowance.Release)
+			assert.IsIncreasing(t, []string(allowance.Variants), "variants should be sorted")
+
+			roundTripped := FromAllowance(allowance)
+			assert.Equal(t, ir, roundTripped)
+			assert.Equal(t, keyFor(ir.TestID, ir.Variant), keyFor(roundTripped.TestID, roundTripped.Variant))
+			assert.NoError(t, ValidateIntentionalRegression(roundTripped))
+		}
+	}
+}
+
+func TestVariantsFromStrings(t *testing.T) {
+	assert.Equal(t,
+		map[string]string{"Platform": "aws", "Upgrade": "micro", "Odd": "a:b"},
+		variantsFromStrings([]string{"Platform:aws", "Upgrade:micro", "Odd:a:b", "malformed"}))
+}
+
+func TestReleaseAllowances(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	variant := crtest.ColumnIdentification{Variants: map[string]string{"Platform": "aws"}}
+	compiled := IntentionalRegression{TestID: "test-1", Variant: variant, RegressedSuccesses: 90, JiraBug: "OCPBUGS-1"}
+	embedded := map[string]IntentionalRegression{
+		keyFor("test-1", variant): compiled,
+		keyFor("test-2", variant): {TestID: "test-2", Variant: variant, JiraBug: "OCPBUGS-2"},
+	}
+
+	assert.Equal(t, embedded, releaseAllowances(embedded, nil, now), "compiled-in allowances apply before import")
+
+	stored := ToAllowance("4.22", compiled)
+	stored.RegressedSuccesses = 80
+	lapsed := ToAllowance("4.22", embedded[keyFor("test-2", variant)])
+	lapsed.ExpiresAt = &expired
+	other := ToAllowance("4.22", IntentionalRegression{TestID: "test-3", Variant: variant, JiraBug: "OCPBUGS-3"})
+
+	allowances := releaseAllowances(embedded, []models.RegressionAllowance{stored, lapsed, other}, now)
+	require.Len(t, allowances, 2)
+	assert.Equal(t, 80, allowances[keyFor("test-1", variant)].RegressedSuccesses, "stored allowances replace compiled-in ones")
+	assert.NotContains(t, allowances, keyFor("test-2", variant), "an expired allowance does not fall back to its compiled-in copy")
+	assert.Equal(t, "OCPBUGS-3", allowances[keyFor("test-3", variant)].JiraBug)
+
+	allowances = releaseAllowances(embedded, []models.RegressionAllowance{other}, now)
+	assert.NotContains(t, allowances, keyFor("test-1", variant), "a deleted allowance does not fall back to its compiled-in copy")
+}
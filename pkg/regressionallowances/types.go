@@ -88,6 +88,29 @@ func mustAddIntentionalRegression(release release, in IntentionalRegression) {
 }
 
 func addIntentionalRegression(release release, in IntentionalRegression) error {
+	if err := ValidateIntentionalRegression(in); err != nil {
+		return err
+	}
+
+	var targetMap map[string]IntentionalRegression
+	var ok bool
+	if targetMap, ok = intentionalRegressions[release]; !ok {
+		targetMap = map[string]IntentionalRegression{}
+		intentionalRegressions[release] = targetMap
+	}
+
+	inKey := keyFor(in.TestID, in.Variant)
+	if _, ok := targetMap[inKey]; ok {
+		return fmt.Errorf("test %q was already added", in.TestID)
+	}
+
+	targetMap[inKey] = in
+
+	return nil
+}
+
+// ValidateIntentionalRegression checks that an allowance has everything needed to justify and apply it.
+func ValidateIntentionalRegression(in IntentionalRegression) error {
 	if len(in.JiraComponent) == 0 {
 		return fmt.Errorf("jiraComponent must be specified")
 	}
@@ -118,20 +141,5 @@ func addIntentionalRegression(release release, in IntentionalRegression) error {
 			return fmt.Errorf("%s must be specified", v)
 		}
 	}
-
-	var targetMap map[string]IntentionalRegression
-	var ok bool
-	if targetMap, ok = intentionalRegressions[release]; !ok {
-		targetMap = map[string]IntentionalRegression{}
-		intentionalRegressions[release] = targetMap
-	}
-
-	inKey := keyFor(in.TestID, in.Variant)
-	if _, ok := targetMap[inKey]; ok {
-		return fmt.Errorf("test %q was already added", in.TestID)
-	}
-
-	targetMap[inKey] = in
-
 	return nil
 }
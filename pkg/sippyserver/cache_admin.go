@@ -0,0 +1,108 @@
+package sippyserver
+
+import (
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/componentreadiness"
+	"github.com/openshift/sippy/pkg/apis/cache"
+	"github.com/openshift/sippy/pkg/util/param"
+)
+
+// adminCache is the cache holding component readiness reports. With persistent caching enabled it wraps s.cache,
+// so operating on it reaches both tiers.
+func (s *Server) adminCache() cache.Cache {
+	if s.crDataProvider != nil && s.crDataProvider.Cache() != nil {
+		return s.crDataProvider.Cache()
+	}
+	return s.cache
+}
+
+// selectCacheEntries finds the entries named by the key, prefix or view parameters, writing a failure response
+// and returning false when they can't be determined.
+func (s *Server) selectCacheEntries(w http.ResponseWriter, req *http.Request, c cache.Cache) ([]cache.Entry, bool) {
+	key := param.SafeRead(req, "key")
+	prefix := param.SafeRead(req, "prefix")
+	viewName := param.SafeRead(req, "view")
+
+	var entries []cache.Entry
+	var err error
+	switch {
+	case key != "":
+		entries, err = c.List(req.Context(), key)
+		// list returns everything sharing the prefix, only the exact key is wanted
+		exact := entries[:0]
+		for _, entry := range entries {
+			if entry.Key == key {
+				exact = append(exact, entry)
+			}
+		}
+		entries = exact
+	case viewName != "":
+		if s.views == nil {
+			typedFailureResponse(w, http.StatusBadRequest, APIConfigError, "view", "no views are configured")
+			return nil, false
+		}
+		view, found := componentreadiness.FindViewByName(viewName, s.views.ComponentReadiness)
+		if !found {
+			typedFailureResponse(w, http.StatusBadRequest, ParameterInvalid, "view", "no view named "+viewName)
+			return nil, false
+		}
+		entries, err = componentreadiness.ViewCacheEntries(req.Context(), c, view)
+	default:
+		entries, err = c.List(req.Context(), prefix)
+	}
+	if err != nil {
+		log.WithError(err).Error("error listing cache entries")
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return entries, true
+}
+
+func (s *Server) jsonListCacheEntries(w http.ResponseWriter, req *http.Request) {
+	c := s.adminCache()
+	if c == nil {
+		typedFailureResponse(w, http.StatusBadRequest, APIConfigError, "", "no cache is configured")
+		return
+	}
+	entries, ok := s.selectCacheEntries(w, req, c)
+	if !ok {
+		return
+	}
+	result := api.NewCacheEntries(entries, time.Now(), api.GetBaseURL(req))
+	result.Links = map[string]string{"self": api.GetBaseURL(req) + req.URL.RequestURI()}
+	api.RespondWithJSON(http.StatusOK, w, result)
+}
+
+func (s *Server) jsonDeleteCacheEntries(w http.ResponseWriter, req *http.Request) {
+	c := s.adminCache()
+	if c == nil {
+		typedFailureResponse(w, http.StatusBadRequest, APIConfigError, "", "no cache is configured")
+		return
+	}
+	if param.SafeRead(req, "key") == "" && param.SafeRead(req, "prefix") == "" && param.SafeRead(req, "view") == "" {
+		// refuse to purge the whole cache from a request that forgot its parameters
+		typedFailureResponse(w, http.StatusBadRequest, ParameterMissing, "key", "one of key, prefix or view is required")
+		return
+	}
+	entries, ok := s.selectCacheEntries(w, req, c)
+	if !ok {
+		return
+	}
+
+	log.WithField("user", getUserForRequest(req)).Infof("deleting %d cache entries for %s", len(entries), req.URL.RawQuery)
+	deleted, err := api.DeleteCacheEntries(req.Context(), c, entries)
+	if err != nil {
+		log.WithError(err).Error("error deleting cache entries")
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, map[string]interface{}{
+		"deleted": deleted,
+		"links":   map[string]string{"entries": api.GetBaseURL(req) + "/api/cache"},
+	})
+}
@@ -2,12 +2,16 @@ package sippyserver
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/openshift/sippy/pkg/api"
 	apijobrunscan "github.com/openshift/sippy/pkg/api/jobrunscan"
 	"github.com/openshift/sippy/pkg/db/models/jobrunscan"
+	"github.com/openshift/sippy/pkg/util"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -170,3 +174,46 @@ func (s *Server) jsonDeleteSymptom(w http.ResponseWriter, req *http.Request) {
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Job run symptom scanning
+
+// jsonScanJobRun evaluates all applicable symptoms against a job run and applies the resulting labels.
+// Pass dryRun=true to see what would be applied without writing anything.
+func (s *Server) jsonScanJobRun(w http.ResponseWriter, req *http.Request) {
+	if s.gcsClient == nil {
+		typedFailureResponse(w, http.StatusServiceUnavailable, APIConfigError, "", "server not configured for GCS, unable to use this API")
+		return
+	}
+	jobRunID, err := strconv.ParseInt(mux.Vars(req)["id"], 10, 64)
+	if err != nil {
+		typedFailureResponse(w, http.StatusBadRequest, ParameterInvalid, "id", "job run id must be an integer")
+		return
+	}
+	dryRun := req.URL.Query().Get("dryRun") == "true"
+
+	releases, err := s.getReleases(req.Context())
+	if err != nil {
+		log.WithError(err).Warn("unable to load releases; symptoms filtered by release status or product will not apply")
+	}
+
+	user := getUserForRequest(req)
+	log.Infof("job run %d symptom scan requested by user: %s (dry run: %t)", jobRunID, user, dryRun)
+	scanner := apijobrunscan.NewSymptomScanner(s.db, s.bigQueryClient, s.gcsClient.Bucket(util.GcsBucketRoot), s.cache,
+		s.jobartifactsManager, releases)
+	result, err := scanner.ScanJobRun(req.Context(), jobRunID, dryRun)
+	if errors.Is(err, apijobrunscan.ErrJobRunNotFound) {
+		failureResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		log.WithError(err).Errorf("error scanning job run %d", jobRunID)
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	baseURL := api.GetBaseURL(req)
+	result.Links = map[string]string{
+		"self":     fmt.Sprintf("%s/api/jobs/runs/%d/scan", baseURL, jobRunID),
+		"symptoms": fmt.Sprintf("%s/api/jobs/symptoms", baseURL),
+	}
+	api.RespondWithJSON(http.StatusOK, w, result)
+}
@@ -36,6 +36,7 @@ const (
 const (
 	jobPassRatioMetricName                  = "sippy_job_pass_ratio" // #nosec G101
 	disruptionVsPrevGAMetricName            = "sippy_disruption_vs_prev_ga"
+	disruptionComparisonMetricName          = "sippy_disruption_comparison"
 	payloadHoursSinceLastAcceptedMetricName = "sippy_payloads_hours_since_last_accepted"
 	payloadConsecutiveRejectionsMetricName  = "sippy_payloads_consecutively_rejected"
 	payloadPossibleTestBlockersMetricName   = "sippy_payloads_possible_test_blockers"
@@ -100,6 +101,14 @@ var (
 		Name: "sippy_disruption_vs_prev_ga_relevance",
 		Help: "Rating of how relevant we feel our data is for regression detection.",
 	}, []string{"release", "compare_release", "platform", "backend", "upgrade_type", "master_nodes_updated", "network", "topology", "architecture", "feature_set", "os", "releaseStatus"})
+	disruptionComparisonMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: disruptionComparisonMetricName,
+		Help: "Delta of disruption percentiles between the sample and base releases of views with disruption enabled",
+	}, []string{"delta", "view", "release", "compare_release", "backend", "releaseStatus"})
+	disruptionComparisonRegressedMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sippy_disruption_comparison_regressed",
+		Help: "1 if more job runs see disruption for the backend in the sample release of a view than its base release, with significance.",
+	}, []string{"view", "release", "compare_release", "backend", "releaseStatus"})
 )
 
 func getReleaseStatus(releases []v1.Release, release string) string {
@@ -178,6 +187,7 @@ func RefreshMetricsDB(ctx context.Context, dbc *db.DB, bqc *bqclient.Client, crP
 		if err := refreshDisruptionMetrics(bqc, releases); err != nil {
 			log.WithError(err).Error("error refreshing disruption metrics")
 		}
+		refreshDisruptionComparisonMetrics(ctx, bqc, cacheOptions, views, releases)
 	}
 
 	log.Infof("refresh metrics completed in %s", time.Since(start))
@@ -377,6 +387,43 @@ func refreshDisruptionMetrics(client *bqclient.Client, releases []v1.Release) er
 	return nil
 }
 
+// refreshDisruptionComparisonMetrics publishes the disruption comparison of each view with disruption enabled.
+func refreshDisruptionComparisonMetrics(ctx context.Context, client *bqclient.Client, cacheOptions cache.RequestOptions,
+	views []crview.View, releases []v1.Release) {
+	if client == nil || client.BQ == nil {
+		return
+	}
+	for _, view := range views {
+		if !view.Disruption.Enabled {
+			continue
+		}
+		logger := log.WithField("view", view.Name)
+		opts, err := utils.ViewRequestOptions(releases, view, cacheOptions)
+		if err != nil {
+			logger.WithError(err).Error("error refreshing disruption comparison metrics for view")
+			continue
+		}
+		comparison, errs := api.GetDisruptionComparisonFromBigQuery(ctx, client, opts, "")
+		if len(errs) > 0 {
+			logger.WithField("errors", errs).Error("error refreshing disruption comparison metrics for view")
+			continue
+		}
+
+		releaseStatus := getReleaseStatus(releases, comparison.Sample.Release)
+		for _, b := range comparison.Backends {
+			labels := []string{view.Name, comparison.Sample.Release, comparison.Base.Release, b.BackendName, releaseStatus}
+			disruptionComparisonMetric.WithLabelValues(append([]string{"P50"}, labels...)...).Set(b.P50Delta)
+			disruptionComparisonMetric.WithLabelValues(append([]string{"P95"}, labels...)...).Set(b.P95Delta)
+			disruptionComparisonMetric.WithLabelValues(append([]string{"P99"}, labels...)...).Set(b.P99Delta)
+			regressed := 0.0
+			if b.Status == api.DisruptionRegressed {
+				regressed = 1
+			}
+			disruptionComparisonRegressedMetric.WithLabelValues(labels...).Set(regressed)
+		}
+	}
+}
+
 type promReportType struct {
 	release string
 	period  string
@@ -0,0 +1,128 @@
+package sippyserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/componentreadiness"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/util/param"
+)
+
+// Regression allowance CRUD handlers
+
+func (s *Server) jsonListRegressionAllowances(w http.ResponseWriter, req *http.Request) {
+	includeExpired := req.URL.Query().Get("includeExpired") == "true"
+	allowances, err := componentreadiness.ListRegressionAllowances(s.db, param.SafeRead(req, "release"), includeExpired, req)
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, allowances)
+}
+
+func (s *Server) jsonGetRegressionAllowance(w http.ResponseWriter, req *http.Request) {
+	idStr := mux.Vars(req)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, "invalid ID format: "+idStr)
+		return
+	}
+	allowance, err := componentreadiness.GetRegressionAllowance(s.db, id, req)
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if allowance == nil {
+		failureResponse(w, http.StatusNotFound, "regression allowance not found")
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, allowance)
+}
+
+func (s *Server) jsonCreateRegressionAllowance(w http.ResponseWriter, req *http.Request) {
+	user := getUserForRequest(req)
+	log.Infof("regression allowance POST made by user: %s", user)
+	var allowance models.RegressionAllowance
+	if err := json.NewDecoder(req.Body).Decode(&allowance); err != nil {
+		log.WithError(err).Error("error parsing new regression allowance")
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx := context.WithValue(req.Context(), models.CurrentUserKey, user)
+	allowance, err := componentreadiness.CreateRegressionAllowance(s.db.DB.WithContext(ctx), allowance, req)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusCreated, w, allowance)
+}
+
+func (s *Server) jsonUpdateRegressionAllowance(w http.ResponseWriter, req *http.Request) {
+	idStr := mux.Vars(req)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, "invalid ID format: "+idStr)
+		return
+	}
+
+	user := getUserForRequest(req)
+	log.Infof("regression allowance PUT made by user: %s", user)
+	var allowance models.RegressionAllowance
+	if err := json.NewDecoder(req.Body).Decode(&allowance); err != nil {
+		log.WithError(err).Error("error parsing regression allowance update")
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if id != int(allowance.ID) { // nolint:gosec
+		failureResponse(w, http.StatusBadRequest, "resource regression allowance ID does not match URL")
+		return
+	}
+	ctx := context.WithValue(req.Context(), models.CurrentUserKey, user)
+	allowance, err = componentreadiness.UpdateRegressionAllowance(s.db.DB.WithContext(ctx), allowance, req)
+	if err != nil {
+		log.WithError(err).Error("error updating regression allowance")
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, allowance)
+}
+
+func (s *Server) jsonDeleteRegressionAllowance(w http.ResponseWriter, req *http.Request) {
+	idStr := mux.Vars(req)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, "invalid ID format: "+idStr)
+		return
+	}
+
+	user := getUserForRequest(req)
+	log.Infof("regression allowance DELETE made by user: %s", user)
+	ctx := context.WithValue(req.Context(), models.CurrentUserKey, user)
+	if err := componentreadiness.DeleteRegressionAllowance(s.db.DB.WithContext(ctx), id); err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) jsonGetRegressionAllowanceAuditLogs(w http.ResponseWriter, req *http.Request) {
+	idStr := mux.Vars(req)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, "invalid ID format: "+idStr)
+		return
+	}
+	auditLogs, err := componentreadiness.GetRegressionAllowanceAuditLogs(s.db.DB, id, req)
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, auditLogs)
+}
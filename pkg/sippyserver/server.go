@@ -40,6 +40,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/openshift/sippy/pkg/mcp"
+	mcptools "github.com/openshift/sippy/pkg/mcp/tools"
 
 	v1 "github.com/openshift/sippy/pkg/apis/config/v1"
 
@@ -51,6 +52,7 @@ import (
 	"github.com/openshift/sippy/pkg/api/jobrunintervals"
 	apitype "github.com/openshift/sippy/pkg/apis/api"
 	"github.com/openshift/sippy/pkg/apis/cache"
+	"github.com/openshift/sippy/pkg/apis/junit"
 	sippyv1 "github.com/openshift/sippy/pkg/apis/sippy/v1"
 	sippybq "github.com/openshift/sippy/pkg/bigquery"
 	"github.com/openshift/sippy/pkg/db"
@@ -79,6 +81,7 @@ func NewServer(
 	variantManager testidentification.VariantManager,
 	sippyNG fs.FS,
 	static fs.FS,
+	chatPrompts fs.FS,
 	dbClient *db.DB,
 	gcsClient *storage.Client,
 	gcsBucket string,
@@ -87,35 +90,40 @@ func NewServer(
 	pinnedDateTime *time.Time,
 	cacheClient cache.Cache,
 	crTimeRoundingFactor time.Duration,
+	crStaleWhileRevalidate time.Duration,
 	views *apitype.SippyViews,
 	config *v1.SippyConfig,
 	enableWriteEndpoints bool,
 	chatAPIURL string,
 	jiraClient *jira.Client,
+	webhookAllowedHosts []string,
 ) *Server {
 
 	server := &Server{
-		mode:                 mode,
-		listenAddr:           listenAddr,
-		corsAllowedOrigin:    corsAllowedOrigin,
-		syntheticTestManager: syntheticTestManager,
-		variantManager:       variantManager,
-		jobartifactsManager:  jobartifacts.NewManager(context.Background()),
-		sippyNG:              sippyNG,
-		static:               static,
-		db:                   dbClient,
-		bigQueryClient:       bigQueryClient,
-		crDataProvider:       crDataProvider,
-		pinnedDateTime:       pinnedDateTime,
-		gcsClient:            gcsClient,
-		gcsBucket:            gcsBucket,
-		cache:                cacheClient,
-		crTimeRoundingFactor: crTimeRoundingFactor,
-		views:                views,
-		config:               config,
-		enableWriteAPIs:      enableWriteEndpoints,
-		chatAPIURL:           chatAPIURL,
-		jiraClient:           jiraClient,
+		mode:                   mode,
+		listenAddr:             listenAddr,
+		corsAllowedOrigin:      corsAllowedOrigin,
+		syntheticTestManager:   syntheticTestManager,
+		variantManager:         variantManager,
+		jobartifactsManager:    jobartifacts.NewManager(context.Background()),
+		sippyNG:                sippyNG,
+		static:                 static,
+		chatPrompts:            chatPrompts,
+		db:                     dbClient,
+		bigQueryClient:         bigQueryClient,
+		crDataProvider:         crDataProvider,
+		pinnedDateTime:         pinnedDateTime,
+		gcsClient:              gcsClient,
+		gcsBucket:              gcsBucket,
+		cache:                  cacheClient,
+		crTimeRoundingFactor:   crTimeRoundingFactor,
+		crStaleWhileRevalidate: crStaleWhileRevalidate,
+		views:                  views,
+		config:                 config,
+		enableWriteAPIs:        enableWriteEndpoints,
+		chatAPIURL:             chatAPIURL,
+		jiraClient:             jiraClient,
+		webhookAllowedHosts:    webhookAllowedHosts,
 	}
 
 	if crDataProvider != nil {
@@ -161,6 +169,7 @@ type Server struct {
 	jobartifactsManager  *jobartifacts.Manager
 	sippyNG              fs.FS
 	static               fs.FS
+	chatPrompts          fs.FS
 	httpServer           *http.Server
 	db                   *db.DB
 	bigQueryClient       *sippybq.Client
@@ -170,13 +179,17 @@ type Server struct {
 	gcsBucket            string
 	cache                cache.Cache
 	crTimeRoundingFactor time.Duration
-	capabilities         []string
-	views                *apitype.SippyViews
-	config               *v1.SippyConfig
-	enableWriteAPIs      bool
-	chatAPIURL           string
-	jiraClient           *jira.Client
-	rateLimiters         map[string]*rateLimiter
+	// crStaleWhileRevalidate is how long past expiry component reports may be served stale while regenerating.
+	crStaleWhileRevalidate time.Duration
+	capabilities           []string
+	views                  *apitype.SippyViews
+	config                 *v1.SippyConfig
+	enableWriteAPIs        bool
+	chatAPIURL             string
+	jiraClient             *jira.Client
+	// webhookAllowedHosts are the hosts webhook subscriptions may send to.
+	webhookAllowedHosts []string
+	rateLimiters        map[string]*rateLimiter
 }
 
 // getReleases returns release data, preferring the BigQuery client with caching
@@ -594,7 +607,7 @@ func (s *Server) jsonGetPayloadAnalysis(w http.ResponseWriter, req *http.Request
 		"arch":    arch,
 	}).Info("analyzing payload stream")
 
-	result, err := api.GetPayloadStreamTestFailures(s.db, release, stream, arch, filterOpts, s.GetReportEnd())
+	result, err := api.GetPayloadStreamTestFailures(s.db, s.blockerScorer(release, stream), release, stream, arch, filterOpts, s.GetReportEnd())
 	if err != nil {
 		log.WithError(err).Error("error")
 		failureResponse(w, http.StatusInternalServerError, "Error analyzing payload: "+err.Error())
@@ -604,6 +617,46 @@ func (s *Server) jsonGetPayloadAnalysis(w http.ResponseWriter, req *http.Request
 	api.RespondWithJSON(http.StatusOK, w, result)
 }
 
+// blockerScorer scores the failing tests of a release's payload stream with the configured weights.
+func (s *Server) blockerScorer(release, stream string) api.BlockerScorer {
+	return api.NewBlockerScorer(s.config, release, stream)
+}
+
+// jsonGetPayloadInvestigation correlates when the likely blocking tests of a stream's rejected payload streak started
+// failing with the pull requests that landed in those payloads.
+func (s *Server) jsonGetPayloadInvestigation(w http.ResponseWriter, req *http.Request) {
+	release := s.getParamOrFail(w, req, "release")
+	if release == "" {
+		return
+	}
+	stream := s.getParamOrFail(w, req, "stream")
+	if stream == "" {
+		return
+	}
+	arch := s.getParamOrFail(w, req, "arch")
+	if arch == "" {
+		return
+	}
+	minBlockerScore := api.DefaultSuspectBlockerScore
+	if scoreStr := req.URL.Query().Get("min_blocker_score"); scoreStr != "" {
+		score, err := strconv.Atoi(scoreStr)
+		if err != nil || score < 0 || score > 100 {
+			failureResponse(w, http.StatusBadRequest, "min_blocker_score must be a number between 0 and 100")
+			return
+		}
+		minBlockerScore = score
+	}
+
+	result, err := api.GetPayloadInvestigation(s.db, s.blockerScorer(release, stream), release, stream, arch, minBlockerScore, s.GetReportEnd(), req)
+	if err != nil {
+		log.WithError(err).Error("error investigating payload streak")
+		failureResponse(w, http.StatusInternalServerError, "Error investigating payload streak: "+err.Error())
+		return
+	}
+
+	api.RespondWithJSON(http.StatusOK, w, result)
+}
+
 // jsonGetPayloadTestFailures is an api to fetch information about what tests failed across all jobs in a specific
 // payload.
 func (s *Server) jsonGetPayloadTestFailures(w http.ResponseWriter, req *http.Request) {
@@ -1028,6 +1081,7 @@ func (s *Server) getComponentReportFromRequest(req *http.Request) (componentrepo
 	if err != nil {
 		return componentreport.ComponentReport{}, err
 	}
+	options.CacheOption.StaleWhileRevalidate = s.crStaleWhileRevalidate
 
 	// This baseURL is used to generate links to test_details reports, which are frontend links
 	baseURL := api.GetBaseFrontendURL(req)
@@ -1050,13 +1104,118 @@ func (s *Server) getComponentReportFromRequest(req *http.Request) (componentrepo
 }
 
 func (s *Server) jsonComponentReportFromBigQuery(w http.ResponseWriter, req *http.Request) {
-	outputs, err := s.getComponentReportFromRequest(req)
+	format, err := componentreadiness.ParseExportFormat(req.URL.Query().Get("format"))
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx, cacheStatus := api.WithCacheStatus(req.Context())
+	outputs, err := s.getComponentReportFromRequest(req.WithContext(ctx))
 	if err != nil {
 		failureResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	api.RespondWithJSON(http.StatusOK, w, outputs)
+	setCacheStatusHeaders(w, cacheStatus)
+	if format == componentreadiness.ExportJSON {
+		api.RespondWithJSON(http.StatusOK, w, outputs)
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.WriteHeader(http.StatusOK)
+	if err := componentreadiness.WriteReport(w, format, outputs, param.SafeRead(req, "view")); err != nil {
+		log.WithError(err).Errorf("error writing component report as %s", format)
+	}
+}
+
+// jsonComponentReportDiff compares a view's report at two sample end dates, defaulting to now for the later one.
+func (s *Server) jsonComponentReportDiff(w http.ResponseWriter, req *http.Request) {
+	if s.crDataProvider == nil {
+		failureResponse(w, http.StatusBadRequest, "component report API is only available when a data provider is configured")
+		return
+	}
+	viewName := param.SafeRead(req, "view")
+	from := req.URL.Query().Get("from")
+	if viewName == "" || from == "" {
+		failureResponse(w, http.StatusBadRequest, "'view' and 'from' parameters are required")
+		return
+	}
+	to := req.URL.Query().Get("to")
+	if to == "" {
+		to = "now"
+	}
+	view, found := componentreadiness.FindViewByName(viewName, s.views.ComponentReadiness)
+	if !found {
+		failureResponse(w, http.StatusBadRequest, fmt.Sprintf("View '%s' not found in views", viewName))
+		return
+	}
+	allReleases, err := s.getReleases(req.Context())
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	cacheOpts := cache.RequestOptions{CRTimeRoundingFactor: s.crTimeRoundingFactor, StaleWhileRevalidate: s.crStaleWhileRevalidate}
+	diff, err := componentreadiness.GetReportDiff(req.Context(), s.crDataProvider, s.db, view, allReleases, cacheOpts, from, to, api.GetBaseURL(req))
+	if errors.Is(err, componentreadiness.ErrInvalidDiffTime) {
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, diff)
+}
+
+// setCacheStatusHeaders tells the client when a response was served from stale cache data, and how old it is,
+// while fresh data is generated in the background.
+func setCacheStatusHeaders(w http.ResponseWriter, status *api.CacheStatus) {
+	if stale, age := status.Stale(); stale {
+		w.Header().Set("X-Sippy-Cache-Stale", "true")
+		w.Header().Set("Age", strconv.Itoa(int(age.Seconds())))
+	}
+}
+
+// jsonDisruptionComparison compares disruption per backend between two release windows, taken from the same
+// parameters as the component report or from a view with disruption enabled.
+func (s *Server) jsonDisruptionComparison(w http.ResponseWriter, req *http.Request) {
+	if s.crDataProvider == nil || s.bigQueryClient == nil {
+		failureResponse(w, http.StatusBadRequest, "disruption comparison API is only available when a data provider and BigQuery are configured")
+		return
+	}
+	if viewName := req.URL.Query().Get("view"); viewName != "" {
+		for _, view := range s.views.ComponentReadiness {
+			if view.Name == viewName && !view.Disruption.Enabled {
+				failureResponse(w, http.StatusBadRequest, fmt.Sprintf("disruption is not enabled for view %s", viewName))
+				return
+			}
+		}
+	}
+
+	allJobVariants, errs := componentreadiness.GetJobVariants(req.Context(), s.crDataProvider)
+	if len(errs) > 0 {
+		failureResponse(w, http.StatusBadRequest, "failed to get job variants")
+		return
+	}
+	allReleases, err := s.getReleases(req.Context())
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	reqOptions, _, err := utils.ParseComponentReportRequest(s.views.ComponentReadiness, allReleases, req, allJobVariants, s.crTimeRoundingFactor,
+		s.config.ComponentReadinessConfig.VariantJunitTableOverrides)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comparison, errs := api.GetDisruptionComparisonFromBigQuery(req.Context(), s.bigQueryClient, reqOptions, api.GetBaseURL(req))
+	if len(errs) > 0 {
+		log.WithField("errors", errs).Error("error comparing disruption")
+		failureResponse(w, http.StatusInternalServerError, fmt.Sprintf("error comparing disruption: %v", errs))
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, comparison)
 }
 
 func (s *Server) jsonComponentReportTestDetailsFromBigQuery(w http.ResponseWriter, req *http.Request) {
@@ -1084,8 +1243,10 @@ func (s *Server) jsonComponentReportTestDetailsFromBigQuery(w http.ResponseWrite
 		failureResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	reqOptions.CacheOption.StaleWhileRevalidate = s.crStaleWhileRevalidate
 	baseURL := api.GetBaseURL(req)
-	outputs, errs := componentreadiness.GetTestDetails(req.Context(), s.crDataProvider, s.db, reqOptions, allReleases, baseURL)
+	ctx, cacheStatus := api.WithCacheStatus(req.Context())
+	outputs, errs := componentreadiness.GetTestDetails(ctx, s.crDataProvider, s.db, reqOptions, allReleases, baseURL)
 	if len(errs) > 0 {
 		log.Warningf("%d errors were encountered while querying component test details from big query:", len(errs))
 		for _, err := range errs {
@@ -1094,6 +1255,7 @@ func (s *Server) jsonComponentReportTestDetailsFromBigQuery(w http.ResponseWrite
 		failureResponse(w, http.StatusInternalServerError, fmt.Sprintf("error querying component test details from big query: %v", errs))
 		return
 	}
+	setCacheStatusHeaders(w, cacheStatus)
 	api.RespondWithJSON(http.StatusOK, w, outputs)
 }
 
@@ -1281,6 +1443,30 @@ func (s *Server) jsonVariantsReportFromDB(w http.ResponseWriter, req *http.Reque
 	}
 }
 
+func (s *Server) jsonVariantDriftReport(w http.ResponseWriter, req *http.Request) {
+	var id uint64
+	if idStr := req.URL.Query().Get("id"); idStr != "" {
+		var err error
+		id, err = strconv.ParseUint(idStr, 10, 64)
+		if err != nil {
+			failureResponse(w, http.StatusBadRequest, "invalid id: "+idStr)
+			return
+		}
+	}
+
+	report, err := api.GetVariantDriftReport(s.db, uint(id), req)
+	if err != nil {
+		log.WithError(err).Error("error fetching variant drift report")
+		failureResponse(w, http.StatusInternalServerError, "error fetching variant drift report: "+err.Error())
+		return
+	}
+	if report == nil {
+		failureResponse(w, http.StatusNotFound, "no variant drift report found, run 'sippy variants drift --save'")
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, report)
+}
+
 func (s *Server) jsonJobsReportFromDB(w http.ResponseWriter, req *http.Request) {
 	release := s.getParamOrFail(w, req, "release")
 	if release != "" {
@@ -1498,6 +1684,44 @@ func (s *Server) jsonJobRunRiskAnalysis(w http.ResponseWriter, req *http.Request
 	api.RespondWithJSON(http.StatusOK, w, result)
 }
 
+// maxJUnitUploadBytes bounds the size of JUnit XML accepted for risk analysis.
+const maxJUnitUploadBytes = 64 << 20
+
+// jsonJUnitRiskAnalysis analyzes the failures in JUnit XML posted by clients running conformance outside of prow,
+// given the release and variants the results should be compared with.
+func (s *Server) jsonJUnitRiskAnalysis(w http.ResponseWriter, req *http.Request) {
+	logger := log.WithField("func", "jsonJUnitRiskAnalysis")
+
+	release := s.getParamOrFail(w, req, "release")
+	if release == "" {
+		return
+	}
+	variants, err := api.ImportantJobVariants(req.URL.Query()["variant"])
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxJUnitUploadBytes))
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, fmt.Sprintf("error reading junit xml in request body: %s", err))
+		return
+	}
+	suites, err := junit.Parse(body)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, fmt.Sprintf("error parsing junit xml in request body: %s", err))
+		return
+	}
+
+	result, err := api.JUnitRiskAnalysis(req.Context(), logger, s.db, s.bigQueryClient, s.cache, suites, release, variants)
+	if err != nil {
+		logger.WithError(err).Error("error analyzing junit risk")
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, result)
+}
+
 // jsonJobRunRiskAnalysis is an API to return the intervals origin builds for interesting things that occurred during
 // the test run.
 //
@@ -1557,6 +1781,67 @@ func (s *Server) jsonJobRunIntervals(w http.ResponseWriter, req *http.Request) {
 	api.RespondWithJSON(http.StatusOK, w, result)
 }
 
+// jsonJobRunIntervalsAggregate reports statistics for each kind of interval across the recent runs of a release's
+// jobs, filtered by job name and variant.
+func (s *Server) jsonJobRunIntervalsAggregate(w http.ResponseWriter, req *http.Request) {
+	if s.gcsClient == nil {
+		failureResponse(w, http.StatusBadRequest, "server not configured for GCS, unable to use this API")
+		return
+	}
+
+	release := s.getParamOrFail(w, req, "release")
+	if release == "" {
+		return
+	}
+	opts := jobrunintervals.AggregateOptions{
+		Release:      release,
+		JobNames:     req.URL.Query()["job_name"],
+		Variants:     req.URL.Query()["variant"],
+		Sources:      req.URL.Query()["source"],
+		IntervalFile: param.SafeRead(req, "file"),
+	}
+	if len(opts.JobNames) == 0 && len(opts.Variants) == 0 {
+		failureResponse(w, http.StatusBadRequest, "at least one job_name or variant is required")
+		return
+	}
+
+	maxRuns, err := param.ReadUint(req, "max_runs", jobrunintervals.MaxAggregateRuns)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.MaxRuns = maxRuns
+	if opts.MaxRuns == 0 {
+		opts.MaxRuns = jobrunintervals.DefaultAggregateRuns
+	}
+	opts.FailedOnly, err = param.ReadBool(req, "failed_only", false)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	opts.End = time.Now().UTC()
+	if end := getDateParam("end_date", req); end != nil {
+		opts.End = end.AddDate(0, 0, 1)
+	}
+	opts.Start = opts.End.AddDate(0, 0, -7)
+	if start := getDateParam("start_date", req); start != nil {
+		opts.Start = *start
+	}
+	if !opts.Start.Before(opts.End) {
+		failureResponse(w, http.StatusBadRequest, "start_date must be before end_date")
+		return
+	}
+
+	report, err := jobrunintervals.AggregateJobRunIntervals(req.Context(), s.gcsClient, s.db, s.cache, s.gcsBucket, opts, req)
+	if err != nil {
+		log.WithError(err).Error("error aggregating job run intervals")
+		failureResponse(w, http.StatusInternalServerError, "error aggregating job run intervals: "+err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, report)
+}
+
 // jsonJobRunEvents fetches Kubernetes events from events.json in the job run's GCS artifacts.
 // The file is located at artifacts/*e2e*/gather-extra/artifacts/events.json
 func (s *Server) jsonJobRunEvents(w http.ResponseWriter, req *http.Request) {
@@ -1950,6 +2235,76 @@ func (s *Server) jsonGetRegressionByID(w http.ResponseWriter, req *http.Request)
 	api.RespondWithJSON(http.StatusOK, w, regression)
 }
 
+// jsonGetTriageSuggestions proposes triages for groups of untriaged regressions that appear to share a cause.
+func (s *Server) jsonGetTriageSuggestions(w http.ResponseWriter, req *http.Request) {
+	release := param.SafeRead(req, "release")
+	if view := param.SafeRead(req, "view"); view != "" {
+		if release != "" {
+			failureResponse(w, http.StatusBadRequest, "Cannot specify both 'view' and 'release' parameters. Please use only one.")
+			return
+		}
+		v, found := componentreadiness.FindViewByName(view, s.views.ComponentReadiness)
+		if !found {
+			failureResponse(w, http.StatusBadRequest, fmt.Sprintf("View '%s' not found in views", view))
+			return
+		}
+		release = v.SampleRelease.Name
+	}
+	if release == "" {
+		failureResponse(w, http.StatusBadRequest, "one of 'view' or 'release' is required")
+		return
+	}
+
+	suggestions, err := componentreadiness.GetTriageSuggestions(s.db, release, req)
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, suggestions)
+}
+
+// jsonGetReadinessBurndown returns the daily regression scorecards for a view from branch cut to GA.
+func (s *Server) jsonGetReadinessBurndown(w http.ResponseWriter, req *http.Request) {
+	viewName := param.SafeRead(req, "view")
+	if viewName == "" {
+		failureResponse(w, http.StatusBadRequest, "'view' parameter is required")
+		return
+	}
+	view, found := componentreadiness.FindViewByName(viewName, s.views.ComponentReadiness)
+	if !found {
+		failureResponse(w, http.StatusBadRequest, fmt.Sprintf("View '%s' not found in views", viewName))
+		return
+	}
+
+	burndown, err := componentreadiness.GetReadinessBurndown(req.Context(), s.db, s.crDataProvider, view, req)
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, burndown)
+}
+
+// jsonGetRegressionHistory handles GET requests for the daily snapshots of a regression, optionally for one view.
+func (s *Server) jsonGetRegressionHistory(w http.ResponseWriter, req *http.Request) {
+	idStr := mux.Vars(req)["id"]
+	regressionID, err := strconv.Atoi(idStr)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, "invalid ID format: "+idStr)
+		return
+	}
+
+	history, err := componentreadiness.GetRegressionHistory(s.db, regressionID, req.URL.Query().Get("view"), req)
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if history == nil {
+		failureResponse(w, http.StatusNotFound, "regression not found")
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, history)
+}
+
 func getUserForRequest(req *http.Request) string {
 	user := req.Header.Get("X-Forwarded-User")
 	if user == "" && os.Getenv("DEV_MODE") == "1" {
@@ -2257,7 +2612,26 @@ func (s *Server) Serve() {
 	router.PathPrefix("/static/").Handler(http.FileServer(http.FS(s.static)))
 
 	// Setup MCP Server
-	mcpServer := mcp.NewMCPServer(context.Background(), s.httpServer, s.db, s.bigQueryClient, s.cache)
+	mcpDeps := &mcptools.ToolDependencies{
+		DBClient:               s.db,
+		BigQueryClient:         s.bigQueryClient,
+		CacheClient:            s.cache,
+		CRDataProvider:         s.crDataProvider,
+		CRTimeRoundingFactor:   s.crTimeRoundingFactor,
+		CRStaleWhileRevalidate: s.crStaleWhileRevalidate,
+		GCSClient:              s.gcsClient,
+		JobArtifactsManager:    s.jobartifactsManager,
+		JiraClient:             s.jiraClient,
+		ReportEnd:              s.GetReportEnd,
+		EnableWriteTools:       s.hasCapabilities([]string{WriteEndpointsCapability}),
+	}
+	if s.views != nil {
+		mcpDeps.Views = s.views.ComponentReadiness
+	}
+	if s.config != nil {
+		mcpDeps.VariantJunitTableOverrides = s.config.ComponentReadinessConfig.VariantJunitTableOverrides
+	}
+	mcpServer := mcp.NewMCPServer(context.Background(), s.httpServer, mcpDeps, s.chatPrompts)
 
 	type apiEndpoints struct {
 		EndpointPath      string                                       `json:"path"`
@@ -2329,6 +2703,13 @@ func (s *Server) Serve() {
 			Capabilities: []string{LocalDBCapability},
 			HandlerFunc:  s.jsonJobRunRiskAnalysis,
 		},
+		{
+			EndpointPath: "/api/jobs/runs/risk_analysis/junit",
+			Description:  "Analyzes risks of JUnit results uploaded from outside of prow",
+			Methods:      []string{http.MethodPost},
+			Capabilities: []string{LocalDBCapability},
+			HandlerFunc:  s.jsonJUnitRiskAnalysis,
+		},
 		{
 			EndpointPath: "/api/jobs/runs/intervals",
 			Description:  "Reports intervals of job runs",
@@ -2336,6 +2717,13 @@ func (s *Server) Serve() {
 			CacheTime:    4 * time.Hour,
 			HandlerFunc:  s.jsonJobRunIntervals,
 		},
+		{
+			EndpointPath: "/api/jobs/runs/intervals/aggregate",
+			Description:  "Reports statistics for each kind of interval across many job runs",
+			Capabilities: []string{LocalDBCapability},
+			CacheTime:    1 * time.Hour,
+			HandlerFunc:  s.jsonJobRunIntervalsAggregate,
+		},
 		{
 			EndpointPath: "/api/jobs/runs/events",
 			Description:  "Returns Kubernetes events from job run artifacts (events.json)",
@@ -2343,6 +2731,13 @@ func (s *Server) Serve() {
 			CacheTime:    4 * time.Hour,
 			HandlerFunc:  s.jsonJobRunEvents,
 		},
+		{
+			EndpointPath: "/api/jobs/runs/{id}/scan",
+			Description:  "Evaluates job run symptoms against a job run and applies matching labels",
+			Methods:      []string{http.MethodPost},
+			Capabilities: []string{LocalDBCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonScanJobRun,
+		},
 		{
 			EndpointPath: "/api/jobs/analysis",
 			Description:  "Analyzes jobs from the database",
@@ -2601,6 +2996,12 @@ func (s *Server) Serve() {
 			Capabilities: []string{LocalDBCapability},
 			HandlerFunc:  s.jsonVariantsReportFromDB,
 		},
+		{
+			EndpointPath: "/api/variants/drift",
+			Description:  "Reports how stored job variants differ from the expected variants, as of the last drift check",
+			Capabilities: []string{LocalDBCapability},
+			HandlerFunc:  s.jsonVariantDriftReport,
+		},
 		{
 			EndpointPath: "/api/report_date",
 			Description:  "Displays report date",
@@ -2608,7 +3009,7 @@ func (s *Server) Serve() {
 		},
 		{
 			EndpointPath: "/api/component_readiness",
-			Description:  "Reports component readiness from BigQuery",
+			Description:  "Reports component readiness from BigQuery, as JSON, csv, markdown or junit",
 			Capabilities: []string{ComponentReadinessCapability},
 			HandlerFunc:  s.jsonComponentReportFromBigQuery,
 		},
@@ -2618,6 +3019,18 @@ func (s *Server) Serve() {
 			Capabilities: []string{ComponentReadinessCapability},
 			HandlerFunc:  s.jsonComponentReportTestDetailsFromBigQuery,
 		},
+		{
+			EndpointPath: "/api/disruption/compare",
+			Description:  "Compares disruption per backend between two release windows",
+			Capabilities: []string{ComponentReadinessCapability},
+			HandlerFunc:  s.jsonDisruptionComparison,
+		},
+		{
+			EndpointPath: "/api/component_readiness/report_diff",
+			Description:  "Compares a view's component report between two sample end dates",
+			Capabilities: []string{ComponentReadinessCapability},
+			HandlerFunc:  s.jsonComponentReportDiff,
+		},
 		{
 			EndpointPath: "/api/component_readiness/variants",
 			Description:  "Reports test variants for component readiness from BigQuery",
@@ -2630,6 +3043,48 @@ func (s *Server) Serve() {
 			Capabilities: []string{ComponentReadinessCapability},
 			HandlerFunc:  s.jsonComponentReadinessViews,
 		},
+		{
+			EndpointPath: "/api/component_readiness/allowances",
+			Description:  "List intentional regression allowances",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability},
+			HandlerFunc:  s.jsonListRegressionAllowances,
+		},
+		{
+			EndpointPath: "/api/component_readiness/allowances",
+			Description:  "Create an intentional regression allowance",
+			Methods:      []string{http.MethodPost},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonCreateRegressionAllowance,
+		},
+		{
+			EndpointPath: "/api/component_readiness/allowances/{id}",
+			Description:  "Get a specific intentional regression allowance",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability},
+			HandlerFunc:  s.jsonGetRegressionAllowance,
+		},
+		{
+			EndpointPath: "/api/component_readiness/allowances/{id}",
+			Description:  "Update an intentional regression allowance",
+			Methods:      []string{http.MethodPut},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonUpdateRegressionAllowance,
+		},
+		{
+			EndpointPath: "/api/component_readiness/allowances/{id}",
+			Description:  "Delete an intentional regression allowance",
+			Methods:      []string{http.MethodDelete},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonDeleteRegressionAllowance,
+		},
+		{
+			EndpointPath: "/api/component_readiness/allowances/{id}/audit",
+			Description:  "Get audit logs for a given intentional regression allowance",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability},
+			HandlerFunc:  s.jsonGetRegressionAllowanceAuditLogs,
+		},
 		{
 			EndpointPath: "/api/component_readiness/triages",
 			Description:  "List component readiness regression triage records",
@@ -2679,6 +3134,48 @@ func (s *Server) Serve() {
 			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability},
 			HandlerFunc:  s.jsonGetTriageAuditDetails,
 		},
+		{
+			EndpointPath: "/api/component_readiness/webhooks",
+			Description:  "List webhook subscriptions for regression and triage events",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonListWebhookSubscriptions,
+		},
+		{
+			EndpointPath: "/api/component_readiness/webhooks",
+			Description:  "Create a webhook subscription for regression and triage events",
+			Methods:      []string{http.MethodPost},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonCreateWebhookSubscription,
+		},
+		{
+			EndpointPath: "/api/component_readiness/webhooks/{id}",
+			Description:  "Get a specific webhook subscription",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonGetWebhookSubscription,
+		},
+		{
+			EndpointPath: "/api/component_readiness/webhooks/{id}",
+			Description:  "Update a webhook subscription",
+			Methods:      []string{http.MethodPut},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonUpdateWebhookSubscription,
+		},
+		{
+			EndpointPath: "/api/component_readiness/webhooks/{id}",
+			Description:  "Delete a webhook subscription",
+			Methods:      []string{http.MethodDelete},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonDeleteWebhookSubscription,
+		},
+		{
+			EndpointPath: "/api/component_readiness/webhooks/{id}/deliveries",
+			Description:  "List recent deliveries for a webhook subscription",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability, WriteEndpointsCapability},
+			HandlerFunc:  s.jsonListWebhookDeliveries,
+		},
 		{
 			EndpointPath: "/api/component_readiness/regressions",
 			Description:  "List component readiness test regressions. Supports view OR release query parameters (not both).",
@@ -2699,6 +3196,27 @@ func (s *Server) Serve() {
 			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability},
 			HandlerFunc:  s.jsonRegressionPotentialMatchingTriages,
 		},
+		{
+			EndpointPath: "/api/component_readiness/burndown",
+			Description:  "Get the daily regression scorecards for a view from its release's branch cut to GA",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability},
+			HandlerFunc:  s.jsonGetReadinessBurndown,
+		},
+		{
+			EndpointPath: "/api/component_readiness/triage_suggestions",
+			Description:  "Suggest triages for groups of untriaged regressions that fail in the same job runs with similar output",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability},
+			HandlerFunc:  s.jsonGetTriageSuggestions,
+		},
+		{
+			EndpointPath: "/api/component_readiness/regressions/{id}/history",
+			Description:  "Get the daily pass rate snapshots recorded while a regression was open, optionally for a single view",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{LocalDBCapability, ComponentReadinessCapability},
+			HandlerFunc:  s.jsonGetRegressionHistory,
+		},
 		{
 			EndpointPath: "/api/component_readiness/bugs",
 			Description:  "Create Jira Bugs from component readiness",
@@ -2711,6 +3229,20 @@ func (s *Server) Serve() {
 			Capabilities: []string{},
 			HandlerFunc:  s.jsonCapabilitiesReport,
 		},
+		{
+			EndpointPath: "/api/cache",
+			Description:  "Lists cached entries by key prefix or component readiness view, with their size and TTL",
+			Methods:      []string{http.MethodGet},
+			Capabilities: []string{WriteEndpointsCapability},
+			HandlerFunc:  s.jsonListCacheEntries,
+		},
+		{
+			EndpointPath: "/api/cache",
+			Description:  "Deletes cached entries by key, key prefix or component readiness view",
+			Methods:      []string{http.MethodDelete},
+			Capabilities: []string{WriteEndpointsCapability},
+			HandlerFunc:  s.jsonDeleteCacheEntries,
+		},
 		{
 			EndpointPath: "/api/releases/health",
 			Description:  "Reports health of releases",
@@ -2765,6 +3297,12 @@ func (s *Server) Serve() {
 			Capabilities: []string{LocalDBCapability},
 			HandlerFunc:  s.jsonPayloadDiff,
 		},
+		{
+			EndpointPath: "/api/payloads/investigation",
+			Description:  "Ranks pull requests likely to have caused a stream's rejected payload streak",
+			Capabilities: []string{LocalDBCapability},
+			HandlerFunc:  s.jsonGetPayloadInvestigation,
+		},
 		{
 			EndpointPath: "/api/feature_gates",
 			Description:  "Reports feature gates and their test counts for a particular release",
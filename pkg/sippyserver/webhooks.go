@@ -0,0 +1,122 @@
+package sippyserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/api"
+	"github.com/openshift/sippy/pkg/api/componentreadiness"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+// Webhook subscription CRUD handlers
+
+func (s *Server) jsonListWebhookSubscriptions(w http.ResponseWriter, req *http.Request) {
+	subscriptions, err := componentreadiness.ListWebhookSubscriptions(s.db, req)
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, subscriptions)
+}
+
+func (s *Server) jsonGetWebhookSubscription(w http.ResponseWriter, req *http.Request) {
+	idStr := mux.Vars(req)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, "invalid ID format: "+idStr)
+		return
+	}
+	subscription, err := componentreadiness.GetWebhookSubscription(s.db, id, req)
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if subscription == nil {
+		failureResponse(w, http.StatusNotFound, "webhook subscription not found")
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, subscription)
+}
+
+func (s *Server) jsonCreateWebhookSubscription(w http.ResponseWriter, req *http.Request) {
+	user := getUserForRequest(req)
+	log.Infof("webhook subscription POST made by user: %s", user)
+	var subscription models.WebhookSubscription
+	if err := json.NewDecoder(req.Body).Decode(&subscription); err != nil {
+		log.WithError(err).Error("error parsing new webhook subscription")
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	subscription, err := componentreadiness.CreateWebhookSubscription(s.db.DB.WithContext(req.Context()), subscription, user, s.webhookAllowedHosts, req)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusCreated, w, subscription)
+}
+
+func (s *Server) jsonUpdateWebhookSubscription(w http.ResponseWriter, req *http.Request) {
+	idStr := mux.Vars(req)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, "invalid ID format: "+idStr)
+		return
+	}
+
+	user := getUserForRequest(req)
+	log.Infof("webhook subscription PUT made by user: %s", user)
+	var subscription models.WebhookSubscription
+	if err := json.NewDecoder(req.Body).Decode(&subscription); err != nil {
+		log.WithError(err).Error("error parsing webhook subscription update")
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if id != int(subscription.ID) { // nolint:gosec
+		failureResponse(w, http.StatusBadRequest, "resource webhook subscription ID does not match URL")
+		return
+	}
+	subscription, err = componentreadiness.UpdateWebhookSubscription(s.db.DB.WithContext(req.Context()), subscription, s.webhookAllowedHosts, req)
+	if err != nil {
+		log.WithError(err).Error("error updating webhook subscription")
+		failureResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, subscription)
+}
+
+func (s *Server) jsonDeleteWebhookSubscription(w http.ResponseWriter, req *http.Request) {
+	idStr := mux.Vars(req)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, "invalid ID format: "+idStr)
+		return
+	}
+
+	user := getUserForRequest(req)
+	log.Infof("webhook subscription DELETE made by user: %s", user)
+	if err := componentreadiness.DeleteWebhookSubscription(s.db.DB.WithContext(req.Context()), id); err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) jsonListWebhookDeliveries(w http.ResponseWriter, req *http.Request) {
+	idStr := mux.Vars(req)["id"]
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		failureResponse(w, http.StatusBadRequest, "invalid ID format: "+idStr)
+		return
+	}
+	deliveries, err := componentreadiness.ListWebhookDeliveries(s.db, id)
+	if err != nil {
+		failureResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api.RespondWithJSON(http.StatusOK, w, deliveries)
+}
@@ -40,6 +40,11 @@ var importantVariants = []string{
 	"OS",
 }
 
+// ImportantVariants returns the variants stored with prow jobs in the database.
+func ImportantVariants() []string {
+	return append([]string{}, importantVariants...)
+}
+
 const (
 	NeverStable = "never-stable"
 
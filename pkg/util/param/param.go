@@ -64,6 +64,7 @@ var paramRegexp = map[string]*regexp.Regexp{
 	"samplePRNumber":   uintRegexp,
 	"samplePayloadTag": nameRegexp,
 	"view":             nameRegexp, // component readiness view name
+	"comparisonMode":   wordRegexp,
 	// jobartifacts params
 	"prowJobRuns":        regexp.MustCompile(`^\d+(,\d+)*$`), // comma-separated integers
 	"pathGlob":           nonEmptyRegex,                      // a glob can be anything
@@ -73,6 +74,9 @@ var paramRegexp = map[string]*regexp.Regexp{
 	"maxFileMatches":     uintRegexp,
 	"beforeContext":      uintRegexp,
 	"afterContext":       uintRegexp,
+	// cache admin params
+	"key":    nonEmptyRegex, // cache keys are often JSON
+	"prefix": nonEmptyRegex,
 	// recent test failures params
 	"previousPeriod": wordRegexp,
 	"includeOutputs": boolRegexp,
@@ -0,0 +1,76 @@
+// Package singleflight coalesces concurrent identical work, so that when many requests miss the cache for the
+// same key at once only one of them does the expensive generation. It follows golang.org/x/sync/singleflight,
+// which is not vendored here, with generics in place of interface{} results.
+package singleflight
+
+import (
+	"fmt"
+	"sync"
+)
+
+type call[T any] struct {
+	wg  sync.WaitGroup
+	val T
+	err error
+	// dups counts the callers waiting on this call
+	dups int
+}
+
+// Group coalesces calls by key. The zero value is ready to use.
+type Group[T any] struct {
+	lock  sync.Mutex
+	calls map[string]*call[T]
+}
+
+// Do runs fn for key unless a call for key is already in flight, in which case it waits for that call and returns
+// its result. shared is true for callers that received another caller's result; since that value is also held by
+// the caller that ran fn, anything reachable through it must be treated as read only or copied.
+func (g *Group[T]) Do(key string, fn func() (T, error)) (val T, shared bool, err error) {
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*call[T]{}
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.lock.Unlock()
+		c.wg.Wait()
+		return c.val, true, c.err
+	}
+	c := &call[T]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.lock.Unlock()
+
+	g.run(key, c, fn)
+	return c.val, false, c.err
+}
+
+// Waiters returns how many callers are waiting on the in-flight call for key, not counting the one running it.
+func (g *Group[T]) Waiters(key string) int {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if c, ok := g.calls[key]; ok {
+		return c.dups
+	}
+	return 0
+}
+
+func (g *Group[T]) run(key string, c *call[T], fn func() (T, error)) {
+	defer func() {
+		// waiters must not block forever if fn panics; they get an error and the panic continues in this goroutine
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("coalesced call for %s panicked: %v", key, r)
+			g.finish(key, c)
+			panic(r)
+		}
+		g.finish(key, c)
+	}()
+	c.val, c.err = fn()
+}
+
+func (g *Group[T]) finish(key string, c *call[T]) {
+	g.lock.Lock()
+	delete(g.calls, key)
+	g.lock.Unlock()
+	c.wg.Done()
+}
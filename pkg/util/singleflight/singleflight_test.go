@@ -0,0 +1,87 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoCoalescesConcurrentCalls(t *testing.T) {
+	var g Group[string]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	fn := func() (string, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return "result", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	shared := make([]bool, 5)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], shared[0], _ = g.Do("key", fn)
+	}()
+	<-started
+	for i := 1; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], shared[i], _ = g.Do("key", fn)
+		}(i)
+	}
+	// wait for every caller to join the in-flight call before letting it complete
+	require.Eventually(t, func() bool {
+		g.lock.Lock()
+		defer g.lock.Unlock()
+		return g.calls["key"] != nil && g.calls["key"].dups == 4
+	}, 5*time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, []string{"result", "result", "result", "result", "result"}, results)
+	assert.False(t, shared[0], "the caller that ran fn does not receive a shared result")
+	assert.Equal(t, []bool{true, true, true, true}, shared[1:])
+}
+
+func TestDoSharesErrorsAndForgetsCompletedCalls(t *testing.T) {
+	var g Group[int]
+	_, _, err := g.Do("key", func() (int, error) { return 0, errors.New("boom") })
+	assert.EqualError(t, err, "boom")
+
+	// once a call completes the next one runs again rather than reusing the result
+	val, shared, err := g.Do("key", func() (int, error) { return 2, nil })
+	assert.NoError(t, err)
+	assert.False(t, shared)
+	assert.Equal(t, 2, val)
+}
+
+func TestDoDistinctKeysRunIndependently(t *testing.T) {
+	var g Group[string]
+	a, _, _ := g.Do("a", func() (string, error) { return "a", nil })
+	b, _, _ := g.Do("b", func() (string, error) { return "b", nil })
+	assert.Equal(t, "a", a)
+	assert.Equal(t, "b", b)
+}
+
+func TestDoPanicReleasesWaiters(t *testing.T) {
+	var g Group[string]
+	assert.Panics(t, func() {
+		_, _, _ = g.Do("key", func() (string, error) { panic("oops") })
+	})
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	assert.Empty(t, g.calls)
+}
@@ -3,10 +3,13 @@ package util
 import (
 	"context"
 	"os"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/storage"
+	"github.com/openshift/sippy/pkg/apis/cache"
 	"github.com/openshift/sippy/pkg/dataloader/prowloader/gcs"
 	"github.com/openshift/sippy/pkg/db"
 	"github.com/sirupsen/logrus"
@@ -70,3 +73,19 @@ func (c *PseudoCache) Set(_ context.Context, key string, content []byte, _ time.
 	c.Cache[key] = content
 	return nil
 }
+
+func (c *PseudoCache) List(_ context.Context, prefix string) ([]cache.Entry, error) {
+	var entries []cache.Entry
+	for key, content := range c.Cache {
+		if strings.HasPrefix(key, prefix) {
+			entries = append(entries, cache.Entry{Key: key, Size: int64(len(content))})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+func (c *PseudoCache) Delete(_ context.Context, key string) error {
+	delete(c.Cache, key)
+	return nil
+}
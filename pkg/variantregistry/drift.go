@@ -0,0 +1,196 @@
+package variantregistry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+	"github.com/openshift/sippy/pkg/testidentification"
+)
+
+const (
+	DriftStoreBigQuery = "bigquery"
+	DriftStorePostgres = "postgres"
+
+	DriftAdded   = "added"
+	DriftRemoved = "removed"
+	DriftChanged = "changed"
+)
+
+// DriftPolicy decides which drift is unexpected. Jobs are routinely added by new releases, so by default only changed
+// and removed jobs are unexpected.
+type DriftPolicy struct {
+	// FailOn are the drift categories that are unexpected: added, removed and changed.
+	FailOn []string
+	// IgnoredVariants are variants whose changes are never unexpected.
+	IgnoredVariants []string
+}
+
+func DefaultDriftPolicy() DriftPolicy {
+	return DriftPolicy{FailOn: []string{DriftChanged, DriftRemoved}}
+}
+
+func (p DriftPolicy) Validate() error {
+	for _, category := range p.FailOn {
+		switch category {
+		case DriftAdded, DriftRemoved, DriftChanged:
+		default:
+			return fmt.Errorf("unknown drift category %q, must be one of added, removed or changed", category)
+		}
+	}
+	return nil
+}
+
+func (p DriftPolicy) failsOn(category string) bool {
+	for _, c := range p.FailOn {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Unexpected reports whether a store's drift is not allowed by the policy.
+func (p DriftPolicy) Unexpected(drift models.VariantStoreDrift) bool {
+	if p.failsOn(DriftAdded) && len(drift.Added) > 0 {
+		return true
+	}
+	if p.failsOn(DriftRemoved) && len(drift.Removed) > 0 {
+		return true
+	}
+	if !p.failsOn(DriftChanged) {
+		return false
+	}
+	ignored := map[string]bool{}
+	for _, v := range p.IgnoredVariants {
+		ignored[v] = true
+	}
+	for _, job := range drift.Changed {
+		for _, change := range job.Changes {
+			if !ignored[change.Variant] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DiffJobVariants categorizes the jobs whose stored variants differ from the expected variants. When keys is set, only
+// those variants are compared. When changedOnly is set, jobs missing from either side are not reported, for stores
+// that only hold a subset of jobs.
+func DiffJobVariants(store string, expected, stored JobVariants, keys []string, changedOnly bool) models.VariantStoreDrift {
+	drift := models.VariantStoreDrift{
+		Store:      store,
+		StoredJobs: len(stored),
+		Added:      []string{},
+		Removed:    []string{},
+		Changed:    []models.JobVariantDrift{},
+	}
+
+	for job, expectedVariants := range expected {
+		storedVariants, ok := stored[job]
+		if !ok {
+			if !changedOnly {
+				drift.Added = append(drift.Added, job)
+			}
+			continue
+		}
+		if changes := diffVariants(filterVariantKeys(expectedVariants, keys), filterVariantKeys(storedVariants, keys)); len(changes) > 0 {
+			drift.Changed = append(drift.Changed, models.JobVariantDrift{JobName: job, Changes: changes})
+		}
+	}
+	if !changedOnly {
+		for job := range stored {
+			if _, ok := expected[job]; !ok {
+				drift.Removed = append(drift.Removed, job)
+			}
+		}
+	}
+
+	sort.Strings(drift.Added)
+	sort.Strings(drift.Removed)
+	sort.Slice(drift.Changed, func(i, j int) bool { return drift.Changed[i].JobName < drift.Changed[j].JobName })
+	return drift
+}
+
+func diffVariants(expected, stored map[string]string) []models.VariantChange {
+	var changes []models.VariantChange
+	for k, v := range expected {
+		if stored[k] != v {
+			changes = append(changes, models.VariantChange{Variant: k, Stored: stored[k], Expected: v})
+		}
+	}
+	for k, v := range stored {
+		if _, ok := expected[k]; !ok {
+			changes = append(changes, models.VariantChange{Variant: k, Stored: v})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Variant < changes[j].Variant })
+	return changes
+}
+
+func filterVariantKeys(variants map[string]string, keys []string) map[string]string {
+	if len(keys) == 0 {
+		return variants
+	}
+	filtered := map[string]string{}
+	for _, k := range keys {
+		if v, ok := variants[k]; ok {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
+type prowJobVariants struct {
+	Name     string
+	Variants pq.StringArray `gorm:"type:text[]"`
+}
+
+// LoadPostgresJobVariants reads the variants stored with prow jobs in the database. Only the important variants are
+// stored there, as "Key:Value" strings alongside markers such as never-stable, which are skipped.
+func LoadPostgresJobVariants(dbc *db.DB) (JobVariants, error) {
+	var jobs []prowJobVariants
+	if res := dbc.DB.Model(&models.ProwJob{}).Select("name, variants").Find(&jobs); res.Error != nil {
+		return nil, res.Error
+	}
+	return parsePostgresJobVariants(jobs), nil
+}
+
+func parsePostgresJobVariants(jobs []prowJobVariants) JobVariants {
+	variants := JobVariants{}
+	for _, job := range jobs {
+		jobVariants := map[string]string{}
+		for _, v := range job.Variants {
+			key, value, ok := strings.Cut(v, ":")
+			if !ok {
+				continue
+			}
+			jobVariants[key] = value
+		}
+		variants[job.Name] = jobVariants
+	}
+	return variants
+}
+
+// PostgresDrift compares the expected variants with those stored with prow jobs. The database keeps jobs long after
+// they stop running and only stores the important variants, so only changes to those variants on known jobs are
+// reported.
+func PostgresDrift(expected, stored JobVariants) models.VariantStoreDrift {
+	return DiffJobVariants(DriftStorePostgres, expected, stored, testidentification.ImportantVariants(), true)
+}
+
+// NewVariantDriftReport combines each store's drift into a report, flagging drift the policy does not allow.
+func NewVariantDriftReport(expected JobVariants, policy DriftPolicy, drifts ...models.VariantStoreDrift) models.VariantDriftReport {
+	report := models.VariantDriftReport{ExpectedJobs: len(expected)}
+	for _, drift := range drifts {
+		drift.Unexpected = policy.Unexpected(drift)
+		report.Unexpected = report.Unexpected || drift.Unexpected
+		report.Stores = append(report.Stores, drift)
+	}
+	return report
+}
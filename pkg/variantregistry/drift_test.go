@@ -0,0 +1,86 @@
+package variantregistry
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+func TestDiffJobVariants(t *testing.T) {
+	expected := JobVariants{
+		"job-a": {"Platform": "aws", "Network": "ovn", "Topology": "ha"},
+		"job-b": {"Platform": "gcp", "Network": "ovn"},
+		"job-c": {"Platform": "azure"},
+	}
+	stored := JobVariants{
+		"job-a": {"Platform": "aws", "Network": "sdn", "Topology": "ha", "Owner": "eng"},
+		"job-b": {"Platform": "gcp", "Network": "ovn"},
+		"job-d": {"Platform": "metal"},
+	}
+
+	drift := DiffJobVariants(DriftStoreBigQuery, expected, stored, nil, false)
+	assert.Equal(t, DriftStoreBigQuery, drift.Store)
+	assert.Equal(t, 3, drift.StoredJobs)
+	assert.Equal(t, []string{"job-c"}, drift.Added)
+	assert.Equal(t, []string{"job-d"}, drift.Removed)
+	require.Len(t, drift.Changed, 1)
+	assert.Equal(t, "job-a", drift.Changed[0].JobName)
+	assert.Equal(t, []models.VariantChange{
+		{Variant: "Network", Stored: "sdn", Expected: "ovn"},
+		{Variant: "Owner", Stored: "eng"},
+	}, drift.Changed[0].Changes)
+
+	drift = DiffJobVariants(DriftStorePostgres, expected, stored, []string{"Platform", "Topology"}, true)
+	assert.Empty(t, drift.Added)
+	assert.Empty(t, drift.Removed)
+	assert.Empty(t, drift.Changed, "only the given keys are compared")
+}
+
+func TestDriftPolicyUnexpected(t *testing.T) {
+	added := models.VariantStoreDrift{Added: []string{"job-a"}}
+	changed := models.VariantStoreDrift{Changed: []models.JobVariantDrift{
+		{JobName: "job-a", Changes: []models.VariantChange{{Variant: "Owner", Stored: "eng", Expected: "sd"}}},
+	}}
+
+	policy := DefaultDriftPolicy()
+	require.NoError(t, policy.Validate())
+	assert.False(t, policy.Unexpected(added), "new jobs are expected by default")
+	assert.True(t, policy.Unexpected(changed))
+
+	policy.IgnoredVariants = []string{"Owner"}
+	assert.False(t, policy.Unexpected(changed))
+
+	policy = DriftPolicy{FailOn: []string{DriftAdded}}
+	assert.True(t, policy.Unexpected(added))
+	assert.False(t, policy.Unexpected(changed))
+
+	assert.Error(t, DriftPolicy{FailOn: []string{"renamed"}}.Validate())
+}
+
+func TestPostgresDrift(t *testing.T) {
+	stored := parsePostgresJobVariants([]prowJobVariants{
+		{Name: "job-a", Variants: pq.StringArray{"Platform:aws", "Network:sdn", "never-stable"}},
+		{Name: "job-old", Variants: pq.StringArray{"Platform:aws"}},
+	})
+	assert.Equal(t, map[string]string{"Platform": "aws", "Network": "sdn"}, stored["job-a"])
+
+	expected := JobVariants{
+		"job-a":   {"Platform": "aws", "Network": "ovn", "CloudRegion": "us-east-1"},
+		"job-new": {"Platform": "aws"},
+	}
+	report := NewVariantDriftReport(expected, DefaultDriftPolicy(), PostgresDrift(expected, stored))
+	assert.Equal(t, 2, report.ExpectedJobs)
+	assert.True(t, report.Unexpected)
+	require.Len(t, report.Stores, 1)
+	drift := report.Stores[0]
+	assert.True(t, drift.Unexpected)
+	assert.Empty(t, drift.Added, "jobs missing from postgres are not reported")
+	assert.Empty(t, drift.Removed, "old jobs kept in postgres are not reported")
+	require.Len(t, drift.Changed, 1)
+	assert.Equal(t, []models.VariantChange{{Variant: "Network", Stored: "sdn", Expected: "ovn"}}, drift.Changed[0].Changes,
+		"variants not stored in postgres are ignored")
+}
@@ -197,17 +197,24 @@ func (s *JobVariantsLoader) applyQueryLabels(queryLabel bqlabel.QueryValue, q *b
 }
 
 func (s *JobVariantsLoader) loadCurrentJobVariants() (map[string]map[string]string, error) {
-	sql := `SELECT * FROM ` +
-		fmt.Sprintf("%s.%s.%s", s.bigQueryProject, s.bigQueryDataSet, s.bigQueryTable) +
-		` ORDER BY job_name, variant_name`
-	query := s.bqClient.Query(sql)
-	s.applyQueryLabels(bqlabel.VariantRegistryLoadCurrentVariants, query)
-	it, err := query.Read(context.TODO())
+	table := fmt.Sprintf("%s.%s.%s", s.bigQueryProject, s.bigQueryDataSet, s.bigQueryTable)
+	return LoadCurrentJobVariants(context.TODO(), s.bqClient, s.bqOpContext, table)
+}
+
+// LoadCurrentJobVariants reads the job variants currently stored in the fully qualified BigQuery table.
+func LoadCurrentJobVariants(ctx context.Context, bqClient *bigquery.Client, opCtx bqlabel.OperationalContext, table string) (JobVariants, error) {
+	sql := `SELECT * FROM ` + table + ` ORDER BY job_name, variant_name`
+	query := bqClient.Query(sql)
+	bqlabel.Context{
+		OperationalContext: opCtx,
+		RequestContext:     bqlabel.RequestContext{Query: bqlabel.VariantRegistryLoadCurrentVariants},
+	}.ApplyLabels(query)
+	it, err := query.Read(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "error querying current job variants")
 	}
 
-	currentVariants := map[string]map[string]string{}
+	currentVariants := JobVariants{}
 
 	for {
 		jv := jobVariant{}
@@ -39,6 +39,7 @@ type OCPVariantLoader struct {
 	bigQueryDataSet              string
 	bigQueryTable                string
 	gcsClient                    *storage.Client
+	rules                        *RuleSet
 }
 
 func NewOCPVariantLoader(
@@ -49,6 +50,7 @@ func NewOCPVariantLoader(
 	config *v1.SippyConfig,
 	views []crview.View,
 	syntheticReleaseJobOverrides map[string]string,
+	rules *RuleSet,
 ) *OCPVariantLoader {
 	return &OCPVariantLoader{
 		BigQueryClient:               bigQueryClient,
@@ -60,6 +62,7 @@ func NewOCPVariantLoader(
 		bigQueryProject:              bigQueryProject,
 		bigQueryDataSet:              bigQueryDataSet,
 		bigQueryTable:                bigQueryTable,
+		rules:                        rules,
 	}
 }
 
@@ -261,97 +264,115 @@ ORDER BY j.prowjob_job_name;
 	return variantsByJob, nil
 }
 
-// fileVariantsToIgnore are values in the cluster-data.json that vary by run, and are not consistent for the job itself.
-// These are unsuited for variants.
-var fileVariantsToIgnore = map[string]bool{
-	"CloudRegion":        true,
-	"CloudZone":          true,
-	"MasterNodesUpdated": true,
+func (v *OCPVariantLoader) calculateVariantsForJob(jLog logrus.FieldLogger, jobName string, variantFile map[string]string, osData clusterDataOS) map[string]string {
+	return v.evaluateVariants(jLog, jobName, variantFile, osData, nil)
 }
 
-func (v *OCPVariantLoader) calculateVariantsForJob(jLog logrus.FieldLogger, jobName string, variantFile map[string]string, osData clusterDataOS) map[string]string {
+// ExplainJobVariants calculates a job's variants as the loader would, given the contents of a run's cluster-data.json
+// if any, and records what set each variant.
+func (v *OCPVariantLoader) ExplainJobVariants(jLog logrus.FieldLogger, jobName string, clusterData []byte) (map[string]string, []VariantSource, error) {
+	variantFile := map[string]string{}
+	var osData clusterDataOS
+	if len(clusterData) > 0 {
+		var err error
+		variantFile, err = prowloader.ParseVariantDataFile(clusterData)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "unable to parse cluster data file")
+		}
+		osData = parseClusterDataOS(clusterData)
+	}
+
+	sources := map[string]VariantSource{}
+	variants := v.evaluateVariants(jLog, jobName, variantFile, osData, sources)
+	explanation := make([]VariantSource, 0, len(sources))
+	for _, source := range sources {
+		explanation = append(explanation, source)
+	}
+	sort.Slice(explanation, func(i, j int) bool {
+		return explanation[i].Variant < explanation[j].Variant
+	})
+	return variants, explanation, nil
+}
+
+func (v *OCPVariantLoader) ruleSet() *RuleSet {
+	if v.rules == nil {
+		return DefaultRuleSet()
+	}
+	return v.rules
+}
+
+func (v *OCPVariantLoader) evaluateVariants(jLog logrus.FieldLogger, jobName string, variantFile map[string]string, osData clusterDataOS, sources map[string]VariantSource) map[string]string {
+	rules := v.ruleSet()
+
 	// Calculate variants based on job name:
-	variants := v.identifyVariants(jLog, jobName, osData)
+	variants := v.identifyVariants(jLog, jobName, variantFile, osData, sources)
 
 	// Carefully merge in the values read from cluster-data.json or any arbitrary variants data file
 	// containing a map. Some properties will be ignored as they are job RUN specific, not job specific.
-	// Others we need to carefully decide who wins in the event is a mismatch.
+	// Others we need to carefully decide who wins in the event is a mismatch, per the variant's rules.
 	for k, v := range variantFile {
-		if k == VariantNetworkStack {
-			// Use ipv6 / ipv4 for consistency with a lot of pre-existing code.
-			v = strings.ToLower(v)
-		}
-		if fileVariantsToIgnore[k] {
+		if rules.ignoresVariantFileKey(k) {
 			continue
 		}
+		var policy VariantFilePolicy
+		if vr, ok := rules.Variant(k); ok {
+			policy = vr.VariantFile
+		}
+		if policy.Lowercase {
+			v = strings.ToLower(v)
+		}
 		jnv, ok := variants[k]
 		if !ok {
 			// Job name did not return this variant, use the value from the file
 			variants[k] = v
+			recordSource(sources, VariantSource{Variant: k, Value: v, Source: SourceVariantFile})
 			continue
-		} else if jnv != v {
-			if v == "" {
-				// If the cluster data file returned an empty value for a variant we calculated from the job
-				// name, we just use the job name version. (i.e. FromRelease)
-				continue
-			}
-			// Check and log mismatches between what we read from the file vs determined from job name:
-			jLog = jLog.WithFields(logrus.Fields{
-				"variant":  k,
-				"fromJob":  jnv,
-				"fromFile": v,
-			})
-
-			switch k {
-			case VariantPlatform:
-				// ROSA is identified as AWS, but we want to keep it in a separate bucket
-				if jnv == "rosa" {
-					continue
-				}
-				// OSD GCP is identified as GCP, but we want to keep it in a separate bucket
-				if jnv == "osd-gcp" {
-					continue
-				}
-				variants[k] = v
-			case VariantArch:
-				// Job name identification wins for arch, heterogenous jobs can show cluster data with
-				// amd64 as it's read from a single node.
-				jLog.Infof("variant mismatch: using %s from job name", k)
-				continue
-			case VariantTopology:
-				// Topology mismatches on Compact as the job cluster data reports ha.
-				jLog.Infof("variant mismatch: using %s from job name", k)
-				continue
-			case VariantNetworkStack:
-				// Discovered in https://issues.redhat.com/browse/TRT-1777
-				// 4.13+ gained cluster-data.json but it was not able to detect dualstack, so
-				// jobs in this range were categorized as ipv4 mistakenly.
-				// For 4.21+, cluster-data.json network stack detection is reliable, so use it.
-				releaseVersion := releaseVersionFromVariants(jLog, variants)
-				releaseLabel := "unknown"
-				if releaseVersion != nil {
-					releaseLabel = releaseVersion.String()
-				}
-				clusterDataReliable, _ := version.NewVersion("4.21")
-				if releaseVersion != nil && releaseVersion.GreaterThanOrEqual(clusterDataReliable) {
-					jLog.Infof("variant mismatch: using %s from cluster-data (release %s >= 4.21)", k, releaseLabel)
-					variants[k] = v
-				} else {
-					jLog.Infof("variant mismatch: using %s from job name (release %s < 4.21)", k, releaseLabel)
-				}
+		} else if jnv == v || v == "" {
+			// If the cluster data file returned an empty value for a variant we calculated from the job
+			// name, we just use the job name version. (i.e. FromRelease)
+			continue
+		}
+
+		// Check and log mismatches between what we read from the file vs determined from job name:
+		mLog := jLog.WithFields(logrus.Fields{
+			"variant":  k,
+			"fromJob":  jnv,
+			"fromFile": v,
+		})
+		switch {
+		case policy.PreferJobName:
+			mLog.Infof("variant mismatch: using %s from job name", k)
+			continue
+		case util.StrSliceContains(policy.KeepJobNameValues, jnv):
+			continue
+		case policy.release != nil:
+			releaseVersion := releaseVersionFromVariants(mLog, variants)
+			if releaseVersion == nil || !policy.release.Check(releaseVersion) {
+				mLog.Infof("variant mismatch: using %s from job name (release %v does not satisfy %s)", k, releaseVersion, policy.Release)
 				continue
-			default:
-				jLog.Infof("variant mismatch: using %s from job run variants file", k)
-				variants[k] = v
 			}
 		}
+		mLog.Infof("variant mismatch: using %s from job run variants file", k)
+		variants[k] = v
+		recordSource(sources, VariantSource{Variant: k, Value: v, Source: SourceVariantFile, Detail: fmt.Sprintf("overrides %s from job name", jnv)})
 	}
 
+	tier := variants[VariantJobTier]
 	v.adjustJobTierBasedOnView(jLog, jobName, variants)
+	if variants[VariantJobTier] != tier {
+		recordSource(sources, VariantSource{Variant: VariantJobTier, Value: variants[VariantJobTier], Source: SourceView,
+			Detail: fmt.Sprintf("%s tier job is not included in the %s-main view", tier, variants[VariantRelease])})
+	}
 
 	return variants
 }
 
+func recordSource(sources map[string]VariantSource, source VariantSource) {
+	if sources != nil {
+		sources[source.Variant] = source
+	}
+}
+
 // adjustJobTierBasedOnView checks if a job with a component readiness tier (blocking/standard/informing)
 // has variant values that are filtered out by the release-main view. If so, it downgrades the tier
 // to candidate since the job would not appear in component readiness anyway.
@@ -473,218 +494,32 @@ func parseClusterDataOS(data []byte) clusterDataOS {
 	return raw.OS
 }
 
-func (v *OCPVariantLoader) identifyVariants(jLog logrus.FieldLogger, jobName string, osData clusterDataOS) map[string]string {
-	variants := map[string]string{}
-	for _, setter := range []func(jLog logrus.FieldLogger, variants map[string]string, jobName string){
-		v.setRelease, // Keep release first, other setters may look up release info in variants map
-		setAggregation,
-		setPlatform,
-		setInstaller,
-		setArchitecture,
-		setNetwork,
-		setTopology,
-		setNetworkStack,
-		setSuite,
-		setOwner,
-		setSecurityMode,
-		setFeatureSet,
-		setScheduler,
-		setNetworkAccess,
-		setCGroupMode,
-		setLayeredProduct,
-		setContainerRuntime,
-		setProcedure,
-		osData.setOS,
-		v.setJobTier, // Keep this near last, it relies on other variants like owner
-	} {
-		setter(jLog, variants, jobName)
+func (v *OCPVariantLoader) identifyVariants(jLog logrus.FieldLogger, jobName string, variantFile map[string]string, osData clusterDataOS, sources map[string]VariantSource) map[string]string {
+	builtinSetters := map[string]func(jLog logrus.FieldLogger, variants map[string]string, jobName string){
+		BuiltinRelease:        v.setRelease,
+		BuiltinOS:             osData.setOS,
+		BuiltinReleaseJobTier: v.setReleaseJobTier,
 	}
 
-	if len(variants) == 0 {
-		jLog.WithField("job", jobName).Warn("unable to determine any variants for job")
-		return map[string]string{}
+	eval := &ruleEvaluation{
+		jLog:        jLog,
+		jobName:     jobName,
+		variantFile: variantFile,
+		variants:    map[string]string{},
+		sources:     sources,
 	}
-
-	return variants
-}
-
-func setAggregation(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	aggregationPatterns := []struct {
-		substring   string
-		aggregation string
-	}{
-		{"aggregated-", "aggregated"},
-		{"aggregator-", "aggregated"},
+	rules := v.ruleSet()
+	for i := range rules.Variants {
+		vr := &rules.Variants[i]
+		eval.evaluate(vr, builtinSetters[vr.Builtin])
 	}
 
-	variants[VariantAggregation] = VariantNoValue
-	for _, entry := range aggregationPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantAggregation] = entry.aggregation
-			return
-		}
-	}
-}
-
-func setOwner(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	ownerPatterns := []struct {
-		substring string
-		owner     string
-	}{
-		{"-osd", "service-delivery"},
-		{"-rosa", "service-delivery"},
-		{"-openshift-online", "service-delivery"},
-		{"-telco5g", "cnf"},
-		{"-perfscale", "perfscale"},
-		{"-chaos-", "chaos"},
-		{"-azure-aro-hcp", "aro"},
-		{"-qe", "qe"}, // Keep this one below perfscale
-		{"-openshift-tests-private", "qe"},
-		{"-openshift-verification-tests", "qe"},
-		{"-openshift-distributed-tracing", "qe"},
-		{"-oadp-", "oadp"},
-		{"-lp-interop", "mpiit"}, // MPEX Integrity and Interop Team
-	}
-
-	for _, entry := range ownerPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantOwner] = entry.owner
-			return
-		}
-	}
-
-	variants[VariantOwner] = "eng"
-}
-
-func setSuite(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	suitePatterns := []struct {
-		substring string
-		suite     string
-	}{
-		{"-serial", "serial"},
-		{"-etcd-scaling", "etcd-scaling"},
-		{"conformance", "parallel"}, // Jobs with "conformance" but no explicit serial are probably parallel
-		{"-e2e-external-", "parallel"},
-	}
-
-	for _, entry := range suitePatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantSuite] = entry.suite
-			return
-		}
-	}
-
-	variants[VariantSuite] = "unknown" // Default case for jobs not running suites
-}
-
-func setSecurityMode(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	securityPatterns := []struct {
-		substring string
-		mode      string
-	}{
-		{"-fips", "fips"},
-	}
-
-	variants[VariantSecurityMode] = VariantDefaultValue
-	for _, entry := range securityPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantSecurityMode] = entry.mode
-			return
-		}
-	}
-}
-
-func setFeatureSet(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	featurePatterns := []struct {
-		substring string
-		feature   string
-	}{
-		{"-techpreview", "techpreview"},
-		{"-tp-", "techpreview"},
-	}
-
-	variants[VariantFeatureSet] = VariantDefaultValue
-	for _, entry := range featurePatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantFeatureSet] = entry.feature
-			return
-		}
-	}
-}
-
-func setScheduler(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	schedulerPatterns := []struct {
-		substring string
-		scheduler string
-	}{
-		{"-rt", "realtime"},
-	}
-
-	variants[VariantScheduler] = VariantDefaultValue
-	for _, entry := range schedulerPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantScheduler] = entry.scheduler
-			return
-		}
-	}
-}
-
-func setNetworkAccess(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	networkPatterns := []struct {
-		substring string
-		access    string
-	}{
-		{"-proxy", "proxy"},
-		{"-metal-ipi-ovn-ipv6", "disconnected"},
-
-		// NAT Instance is a temporary testing variant to analyze the
-		// impacts of a cost reduction strategy in ephemeral test accounts.
-		// https://github.com/openshift/ci-tools/pull/4534 .
-		{"-nat-instance", "nat-instance"},
-	}
-
-	variants[VariantNetworkAccess] = VariantDefaultValue
-	for _, entry := range networkPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantNetworkAccess] = entry.access
-			return
-		}
-	}
-}
-
-func setNetworkStack(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	networkPatterns := []struct {
-		substring string
-		stack     string
-	}{
-		{"-dualstack", "dual"},
-		{"-ipv6", "ipv6"},
-	}
-
-	for _, entry := range networkPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantNetworkStack] = entry.stack
-			return
-		}
+	if len(eval.variants) == 0 {
+		jLog.WithField("job", jobName).Warn("unable to determine any variants for job")
+		return map[string]string{}
 	}
 
-	variants[VariantNetworkStack] = "ipv4"
+	return eval.variants
 }
 
 func (v *OCPVariantLoader) setRelease(logger logrus.FieldLogger, variants map[string]string, jobName string) {
@@ -739,143 +574,8 @@ func (v *OCPVariantLoader) setRelease(logger logrus.FieldLogger, variants map[st
 	}
 }
 
-// setJobTier sets the jobTier for a job, with values like this:
-//
-//		blocking: blocking job on payloads, covered by component readiness
-//		informing: informing job on payloads, covered by component readiness
-//		standard: should be visible in default views (component readiness, sippy), covered by component readiness
-//	 	rare: highly reliable jobs that run at a reduced frequency
-//		candidate: not covered by component readiness, but may be promoted in the future
-//		hidden: data should still be synced, but not shown by default
-//		excluded: data should not be synced, and excluded from all views
-//
-// Note: blocking/informing/standard tiers may be downgraded to candidate by
-// adjustJobTierBasedOnView if the job's variants don't match the release-main view.
-func (v *OCPVariantLoader) setJobTier(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	jobTierPatterns := []struct {
-		substrings []string
-		jobTier    string
-	}{
-		// Rarely run
-		{[]string{"-cpu-partitioning"}, "rare"},
-		{[]string{"-etcd-scaling"}, "rare"},
-
-		// QE jobs allowlisted for Component Readiness
-		{[]string{"-automated-release"}, "standard"},
-
-		// OVN-Kubernetes BGP Virtualization jobs allowed for Component Readiness
-		{[]string{"-ovn-bgp-virt"}, "standard"},
-
-		// Add two-node-fencing for component readiness
-		{[]string{"-two-node-fencing-recovery"}, "standard"},
-		{[]string{"-two-node-fencing-dualstack-recovery"}, "standard"},
-		{[]string{"-two-node-fencing-ipv6-recovery"}, "standard"},
-
-		// Excluded jobs
-		{[]string{"-okd"}, "excluded"},
-		{[]string{"-recovery"}, "excluded"},
-		{[]string{"alibaba"}, "excluded"},
-		{[]string{"-osde2e-"}, "excluded"},
-
-		// OVN-Kubernetes BGP jobs; candidate tier to collect data while stabilizing
-		{[]string{"-bgp-"}, "candidate"},
-
-		// Experimental new jobs using nested vsphere lvl 2 environment,
-		// not ready to make release blocking yet.
-		{[]string{"-vsphere-host-groups"}, "candidate"},
-
-		// All 4.19/4.20 MCO jobs default to candidate
-		{[]string{"machine-config-operator-release-4.19"}, "candidate"},
-		{[]string{"machine-config-operator-release-4.20"}, "candidate"},
-
-		// Cloud MCO disruptive jobs set to standard for component readiness
-		// This also includes techpreview variants
-		{[]string{"e2e-aws-mco-disruptive"}, "standard"},
-		{[]string{"e2e-azure-mco-disruptive"}, "standard"},
-		{[]string{"e2e-gcp-mco-disruptive"}, "standard"},
-
-		// All remaining MCO periodic jobs default to candidate
-		{[]string{"machine-config-operator"}, "candidate"},
-
-		// Konflux jobs aren't ready yet
-		{[]string{"-konflux"}, "candidate"},
-		{[]string{"-console-operator-"}, "candidate"}, // https://issues.redhat.com/browse/OCPBUGS-54873
-
-		{[]string{"-nat-instance"}, "candidate"},
-
-		// Operator Framework extended test jobs are not yet stable enough to make release readiness.
-		// Mark candidate to collect data in Sippy while working on stabilization.
-		{[]string{"periodic-ci-openshift-operator-framework-operator-controller-", "-extended-"}, "candidate"},
-		{[]string{"periodic-ci-openshift-operator-framework-olm-", "-extended-"}, "candidate"},
-
-		// GCP multi-operator periodic jobs are not yet stable enough for component readiness
-		{[]string{"e2e-gcp-multi-operator-periodic"}, "candidate"},
-
-		// Hidden jobs
-		{[]string{"-cilium"}, "hidden"},
-		{[]string{"-disruptive"}, "hidden"},
-		{[]string{"-rollback"}, "hidden"},
-		{[]string{"aggregator-"}, "hidden"},
-		{[]string{"-out-of-change"}, "hidden"},
-		{[]string{"-sno-fips-recert"}, "hidden"},
-		{[]string{"aggregated"}, "hidden"},
-		{[]string{"-cert-rotation-shutdown-"}, "hidden"}, // may want to go to rare at some point
-		{[]string{"-vsphere-insights-runtime"}, "hidden"},
-
-		{[]string{"-4.19-e2e-metal-ipi-serial-ovn-ipv6-techpreview-"}, "candidate"},      // new jobs in https://github.com/openshift/release/pull/64143 have failures that need to be addressed, don't want to regress 4.19
-		{[]string{"-4.19-e2e-metal-ipi-serial-ovn-dualstack-techpreview-"}, "candidate"}, // new jobs in https://github.com/openshift/release/pull/64143 have failures that need to be addressed, don't want to regress 4.19
-
-		// Only a select few Hypershift jobs are ready for blocking signal, the rest will default to candidate below.
-		{[]string{"periodic-ci-openshift-hypershift-", "-e2e-azure-aks-ovn-conformance"}, "standard"},
-		{[]string{"periodic-ci-openshift-hypershift-", "-e2e-aws-ovn-conformance"}, "standard"},
-
-		// All other Hypershift jobs will default to candidate.
-		{[]string{"periodic-ci-openshift-hypershift-"}, "candidate"},
-
-		// Storage team job preparing for RHEL 10 to detect regressions early, not yet stable, jsafrane would like to promote eventually:
-		{[]string{"periodic-ci-openshift-cluster-storage-operator", "upgrade-check-dev-symlinks"}, "candidate"},
-
-		// z-stream techpreview jobs should generally upgrade correctly, however also get wedged in some cases (e.g. when we forcibly change an API from alpha to stable).
-		{[]string{"-techpreview-upgrade"}, "candidate"},
-
-		// Custom DNS techpreview jobs - candidate tier to collect data while stabilizing
-		{[]string{"-custom-dns-techpreview"}, "candidate"},
-
-		// AWS European Sovereign Cloud techpreview jobs - candidate tier to collect data while stabilizing
-		{[]string{"-eusc-techpreview"}, "candidate"},
-
-		// AWS DualStack Techpreview jobs - candidate tier to collect data while stabilizing
-		{[]string{"-aws-ovn-dualstack"}, "candidate"},
-		{[]string{"-aws-ovn-installer-dualstack-ipv6-primary-techpreview"}, "candidate"},
-		{[]string{"-aws-ovn-installer-dualstack-ipv4-primary-techpreview"}, "candidate"},
-
-		{[]string{"periodic-ci-openshift-hypershift-", "-mce-e2e-agent-", "-metal-conformance"}, "candidate"},
-	}
-
-	for _, jobTierPattern := range jobTierPatterns {
-		allMatch := true
-		for _, substring := range jobTierPattern.substrings {
-			if !strings.Contains(jobNameLower, substring) {
-				allMatch = false
-				break
-			}
-		}
-		if allMatch {
-			variants[VariantJobTier] = jobTierPattern.jobTier
-			return
-		}
-	}
-
-	// QE default is hidden, we'll opt jobs in above as they stabilize and are
-	// ready for component readiness.
-	if variants[VariantOwner] == "qe" {
-		variants[VariantJobTier] = "hidden"
-		return
-	}
-
-	// Determine job tier from release configuration
+// setReleaseJobTier sets the tier of jobs no job tier rule matched from the release configuration.
+func (v *OCPVariantLoader) setReleaseJobTier(_ logrus.FieldLogger, variants map[string]string, jobName string) {
 	release := variants[VariantRelease]
 
 	// after the master -> main branch renaming in release some of the master job names in our config have been renamed
@@ -894,116 +594,6 @@ func (v *OCPVariantLoader) setJobTier(_ logrus.FieldLogger, variants map[string]
 	}
 }
 
-func setProcedure(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	// with multi value support we need a build up the value
-	base := ""
-	procedureValue := VariantNoValue
-
-	if strings.Contains(jobNameLower, "-serial") {
-		base = concatProcedureValues(base, "serial")
-		procedureValue = base
-	}
-	// Job procedure patterns
-	procedurePatterns := []struct {
-		substring string
-		procedure string
-	}{
-		{"-etcd-scaling", concatProcedureValues(base, "etcd-scaling")},
-		{"-cpu-partitioning", concatProcedureValues(base, "cpu-partitioning")},
-		{"-automated-release", concatProcedureValues(base, "automated-release")},
-		{"-cert-rotation-shutdown-", concatProcedureValues(base, "cert-rotation-shutdown")},
-		{"-console-operator-", concatProcedureValues(base, "console-operator")},
-		{"-ipsec", concatProcedureValues(base, "ipsec")},
-		{"-network-flow-matrix", concatProcedureValues(base, "network-flow-matrix")},
-		{"-ocl", concatProcedureValues(base, "on-cluster-layering")},
-		{"-machine-config-operator", concatProcedureValues(base, "machine-config-operator")},
-		{"-usernamespace", concatProcedureValues(base, "usernamespace")},
-	}
-
-	for _, entry := range procedurePatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantProcedure] = entry.procedure
-			return
-		}
-	}
-
-	// Default procedure
-	variants[VariantProcedure] = procedureValue
-}
-
-func concatProcedureValues(base, addition string) string {
-	if len(base) == 0 {
-		return addition
-	}
-	// shouldn't get called this way
-	if len(addition) == 0 {
-		return base
-	}
-
-	return fmt.Sprintf("%s-%s", base, addition)
-}
-
-func setTopology(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	topologyPatterns := []struct {
-		substring string
-		topology  string
-	}{
-		{"-sno-", "single"},                       // Previously single-node
-		{"-single-node", "single"},                // Alternative format
-		{"-two-node-arbiter", "two-node-arbiter"}, // Two-node
-		{"-two-node-fencing", "two-node-fencing"}, // Two-node
-		{"-tna-", "two-node-arbiter"},             // Two-node alternative format
-		{"-tnf-", "two-node-fencing"},             // Two-node alternative format
-		{"-hypershift", "external"},
-		{"-hcp", "external"},
-		{"_hcp", "external"},
-		{"-compact", "compact"},
-		{"-microshift", "microshift"},
-	}
-
-	for _, entry := range topologyPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantTopology] = entry.topology
-			return
-		}
-	}
-
-	variants[VariantTopology] = "ha" // Default to "ha"
-}
-
-func setInstaller(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	installationPatterns := []struct {
-		substring string
-		installer string
-	}{
-		{"-assisted", "assisted"},
-		{"-azure-aro-hcp", "aro"}, // check before hypershift as job name includes -hcp
-		{"-hypershift", "hypershift"},
-		{"-hcp", "hypershift"},
-		{"_hcp", "hypershift"},
-		{"-upi", "upi"},
-		{"-agent", "agent"},
-		{"-e2e-external-aws", "upi"}, // clusters with platform type external can be installed in any provider with no installer automation (upi).
-		{"-e2e-external-vsphere", "upi"},
-		{"-e2e-oci-assisted", "assisted"},
-	}
-
-	for _, entry := range installationPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantInstaller] = entry.installer
-			return
-		}
-	}
-
-	variants[VariantInstaller] = "ipi" // Assume ipi by default
-}
-
 // upgradeVariant returns a variant inferred from the slice of releases involved in a job and its name
 //   - releases need to be sorted and unique
 func upgradeVariant(logger logrus.FieldLogger, releases []version.Version, jobName string) string {
@@ -1047,47 +637,6 @@ func upgradeVariant(logger logrus.FieldLogger, releases []version.Version, jobNa
 	return "micro"
 }
 
-func setPlatform(jLog logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	// Order matters here: patterns must be checked in a specific sequence
-	platformPatterns := []struct {
-		substring string
-		platform  string
-	}{
-		{"-e2e-external-aws", "external-aws"}, // platform type external can be installed in any provider. Syntax platformType(provider).
-		{"-e2e-external-vsphere", "external-vsphere"},
-		{"-e2e-oci-assisted", "external-oci"},
-		{"-rosa", "rosa"}, // Keep above AWS as many ROSA jobs also mention AWS
-		{"-aws", "aws"},
-		{"-alibaba", "alibaba"},
-		{"-azure-aro-hcp", "aro"},
-		{"-azure", "azure"},
-		{"-aks", "azure"},
-		{"-osd-ccs-gcp", "osd-gcp"},
-		{"-gcp", "gcp"},
-		{"-libvirt", "libvirt"},
-		{"-metal", "metal"},
-		{"-nutanix", "nutanix"},
-		{"-openstack", "openstack"},
-		{"-ovirt", "ovirt"},
-		{"-vsphere", "vsphere"},
-
-		// there is no cluster for the periodics-default-catalog-consistency jobs
-		// forcing to aws to include signal in CR main view without adding 'none' platform
-		{"-periodics-default-catalog-consistency", "aws"},
-	}
-
-	for _, entry := range platformPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantPlatform] = entry.platform
-			return
-		}
-	}
-
-	jLog.WithField("jobName", jobName).Warn("unable to determine platform from job name")
-}
-
 var majorMinorRegexp = regexp.MustCompile(`\d+\.\d+`)
 
 // extractRelease returns a slice of unique major.minor version strings found in the job name sorted
@@ -1116,118 +665,6 @@ func extractReleases(jobName string) []version.Version {
 	return mm
 }
 
-func setArchitecture(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	architecturePatterns := []struct {
-		substring    string
-		architecture string
-	}{
-		{"-arm64", "arm64"},
-		{"-multi-a-a", "arm64"},
-		{"-arm", "arm64"},
-		{"-ppc64le", "ppc64le"},
-		{"-multi-p-p", "ppc64le"},
-		{"-s390x", "s390x"},
-		{"-multi-z-z", "s390x"},
-		{"-heterogeneous", "multi"},
-		{"-multi", "multi"},
-	}
-
-	// the use of multi in these cases do not apply to architecture so drop them out from evaluation
-	ignorePatterns := []string{
-		"-multi-vcenter-",
-		"-multi-network-",
-		"-multisubnets-",
-		"-multitenant",
-		"-multiarch",
-		"-multinet",
-	}
-	for _, ignore := range ignorePatterns {
-		jobNameLower = strings.ReplaceAll(jobNameLower, ignore, "-")
-	}
-
-	for _, entry := range architecturePatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantArch] = entry.architecture
-			return
-		}
-	}
-
-	variants[VariantArch] = "amd64"
-}
-
-func setNetwork(jLog logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	networkPatterns := []struct {
-		substring string
-		network   string
-	}{
-		{"-ovn", "ovn"},
-		{"-sdn", "sdn"},
-		{"-cilium", "cilium"},
-	}
-
-	// Check jobName for explicit network type
-	for _, entry := range networkPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantNetwork] = entry.network
-			return
-		}
-	}
-
-	// Get release version from variants
-	releaseVersion := releaseVersionFromVariants(jLog, variants)
-	if releaseVersion == nil {
-		return
-	}
-
-	// Determine network based on release
-	ovnBecomesDefault, _ := version.NewVersion("4.12")
-
-	if releaseVersion.GreaterThanOrEqual(ovnBecomesDefault) {
-		variants[VariantNetwork] = "ovn"
-	} else {
-		variants[VariantNetwork] = "sdn"
-	}
-}
-
-func setContainerRuntime(jLog logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	runtimePatterns := []struct {
-		substring string
-		runtime   string
-	}{
-		{"-crun", "crun"},
-		{"-runc", "runc"},
-	}
-
-	// Check jobName for explicit container runtime
-	for _, entry := range runtimePatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantContainerRuntime] = entry.runtime
-			return
-		}
-	}
-
-	// Get release version from variants
-	releaseVersion := releaseVersionFromVariants(jLog, variants)
-	if releaseVersion == nil {
-		return
-	}
-
-	// Determine container runtime based on release
-	crunBecomesDefault, _ := version.NewVersion("4.18")
-
-	if releaseVersion.GreaterThanOrEqual(crunBecomesDefault) {
-		variants[VariantContainerRuntime] = "crun"
-	} else {
-		variants[VariantContainerRuntime] = "runc"
-	}
-}
-
 func releaseVersionFromVariants(jLog logrus.FieldLogger, variants map[string]string) *version.Version {
 	release, exists := variants[VariantFromRelease]
 	if !exists {
@@ -1252,60 +689,6 @@ func releaseVersionFromVariants(jLog logrus.FieldLogger, variants map[string]str
 	return nil
 }
 
-func setCGroupMode(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	cgroupPatterns := []struct {
-		substring string
-		mode      string
-	}{
-		{"-cgroupsv1", "v1"},
-	}
-
-	variants[VariantCGroupMode] = "v2" // Default to v2
-	for _, entry := range cgroupPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantCGroupMode] = entry.mode
-			return
-		}
-	}
-}
-
-func setLayeredProduct(_ logrus.FieldLogger, variants map[string]string, jobName string) {
-	jobNameLower := strings.ToLower(jobName)
-
-	layeredProductPatterns := []struct {
-		substring string
-		product   string
-	}{
-		{"-lp-interop-cr-cnv", "lp-interop-virt"},
-		{"-quay-cr", "lp-interop-quay"},
-		{"-lp-interop-cr-openshift-pipelines", "lp-interop-openshift-pipelines"},
-		{"-lp-interop-cr-acs-latest", "lp-interop-acs-latest"},
-		{"-lp-interop-cr-acs", "lp-interop-acs"},
-		{"-lp-interop-cr-odf", "lp-interop-odf"},
-		{"-lp-interop-cr-redhat-openshift-gitops", "lp-interop-gitops"},
-		{"-lp-interop-cr-fusion-access", "lp-interop-fusion-access"},
-		{"-lp-interop-cr-mta", "lp-interop-mta"},
-		{"-lp-interop-cr-oadp", "lp-interop-oadp"},
-		{"-lp-interop-cr-servicemesh", "lp-interop-servicemesh"},
-		{"-lp-interop-cr-operator-e2e", "lp-interop-serverless"},
-		{"-coo-", "lp-interop-coo"},
-		{"-virt", "virt"},
-		{"-cnv", "virt"},
-		{"-kubevirt", "virt"},
-		{"-oadp-", "oadp"},
-	}
-
-	variants[VariantLayeredProduct] = VariantNoValue
-	for _, entry := range layeredProductPatterns {
-		if strings.Contains(jobNameLower, entry.substring) {
-			variants[VariantLayeredProduct] = entry.product
-			return
-		}
-	}
-}
-
 func (os clusterDataOS) setOS(_ logrus.FieldLogger, variants map[string]string, _ string) {
 	resolved := os
 	if resolved.Default == "" {
@@ -2304,7 +2304,7 @@ func TestVariantsSnapshot(t *testing.T) {
 
 	log := logrus.WithField("test", "TestVariantsSnapshot")
 
-	snapshot := NewVariantSnapshot(cfg, views.ComponentReadiness, syntheticReleaseJobOverrides, log)
+	snapshot := NewVariantSnapshot(cfg, views.ComponentReadiness, syntheticReleaseJobOverrides, nil, log)
 
 	newVariants, err := snapshot.Identify()
 	assert.NoError(t, err)
@@ -0,0 +1,338 @@
+package variantregistry
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed variant_rules.yaml
+var defaultRuleSetYAML []byte
+
+// RuleSet is an ordered, declarative description of how variants are derived from a job. Variants are evaluated in
+// order, so rules may depend on variants set earlier in the list.
+type RuleSet struct {
+	// IgnoredVariantFileKeys are keys in a job run's variants file (cluster-data.json) that vary by run rather than
+	// by job, and are unsuited for variants.
+	IgnoredVariantFileKeys []string       `yaml:"ignoredVariantFileKeys,omitempty"`
+	Variants               []VariantRules `yaml:"variants"`
+
+	byName map[string]*VariantRules
+}
+
+// VariantRules derives a single variant. The first matching rule sets the value. When none match, the builtin runs if
+// there is one, and the default is used if the variant is still unset. Without a default the variant is left unset.
+type VariantRules struct {
+	Name string `yaml:"name"`
+	// Builtin names logic implemented in Go that cannot be expressed as rules, such as parsing releases out of the
+	// job name or looking up a job's tier in the sippy config. A builtin may set more than one variant.
+	Builtin string `yaml:"builtin,omitempty"`
+	Default string `yaml:"default,omitempty"`
+	// IgnoredSubstrings are replaced with "-" in the job name before matching, for substrings that look like a rule's
+	// but mean something else, such as "-multi-vcenter-" for the multi architecture.
+	IgnoredSubstrings []string          `yaml:"ignoredSubstrings,omitempty"`
+	Rules             []Rule            `yaml:"rules,omitempty"`
+	VariantFile       VariantFilePolicy `yaml:"variantFile,omitempty"`
+}
+
+// Rule sets a variant's value when all of its conditions hold. Job name conditions match against the lower cased job
+// name, with the variant's ignored substrings removed.
+type Rule struct {
+	Value string `yaml:"value"`
+	// Contains are substrings that must all be in the job name.
+	Contains []string `yaml:"contains,omitempty"`
+	// Matches is a regular expression the job name must match.
+	Matches string `yaml:"matches,omitempty"`
+	// Release is a version constraint, such as ">= 4.12", that the job's release must satisfy. Rules with a release
+	// constraint never match jobs whose release is unknown.
+	Release string `yaml:"release,omitempty"`
+	// Variants are values that variants evaluated earlier must have.
+	Variants map[string]string `yaml:"variants,omitempty"`
+	// VariantFile are regular expressions that values in the job run's variants file must match.
+	VariantFile map[string]string `yaml:"variantFile,omitempty"`
+
+	matches     *regexp.Regexp
+	release     version.Constraints
+	variantFile map[string]*regexp.Regexp
+}
+
+// VariantFilePolicy decides which value wins when the job name and the job run's variants file disagree. By default
+// the variants file wins.
+type VariantFilePolicy struct {
+	// PreferJobName always keeps the value derived from the job name.
+	PreferJobName bool `yaml:"preferJobName,omitempty"`
+	// KeepJobNameValues are job name values that are kept, typically more specific buckets than the file reports,
+	// such as rosa over aws.
+	KeepJobNameValues []string `yaml:"keepJobNameValues,omitempty"`
+	// Release is a version constraint the job's release must satisfy for the variants file to win, for files that
+	// only became reliable in later releases.
+	Release string `yaml:"release,omitempty"`
+	// Lowercase lower cases the variants file value.
+	Lowercase bool `yaml:"lowercase,omitempty"`
+
+	release version.Constraints
+}
+
+// VariantSource records what set a variant's value, for explaining a job's variants.
+type VariantSource struct {
+	Variant string `json:"variant"`
+	Value   string `json:"value"`
+	// Source is one of rule, builtin, default, variantFile or view.
+	Source string `json:"source"`
+	// Rule is the index of the matching rule within the variant's rules.
+	Rule   *int   `json:"rule,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+const (
+	SourceRule        = "rule"
+	SourceBuiltin     = "builtin"
+	SourceDefault     = "default"
+	SourceVariantFile = "variantFile"
+	SourceView        = "view"
+)
+
+const (
+	BuiltinRelease        = "release"
+	BuiltinOS             = "os"
+	BuiltinReleaseJobTier = "releaseJobTier"
+)
+
+var builtins = map[string]bool{BuiltinRelease: true, BuiltinOS: true, BuiltinReleaseJobTier: true}
+
+var DefaultRuleSet = sync.OnceValue(func() *RuleSet {
+	rs, err := ParseRuleSet(defaultRuleSetYAML)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default variant rules: %v", err))
+	}
+	return rs
+})
+
+// LoadRuleSet reads a rule set from a file, or returns the default rule set when path is empty.
+func LoadRuleSet(path string) (*RuleSet, error) {
+	if path == "" {
+		return DefaultRuleSet(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := ParseRuleSet(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variant rules in %s: %w", path, err)
+	}
+	return rs, nil
+}
+
+// ParseRuleSet parses and validates a YAML rule set. Unknown fields are rejected so typos do not silently disable a
+// rule.
+func ParseRuleSet(data []byte) (*RuleSet, error) {
+	rs := &RuleSet{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(rs); err != nil {
+		return nil, err
+	}
+	if err := rs.compile(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (rs *RuleSet) compile() error {
+	rs.byName = map[string]*VariantRules{}
+	for i := range rs.Variants {
+		vr := &rs.Variants[i]
+		if vr.Name == "" {
+			return fmt.Errorf("variant %d has no name", i)
+		}
+		if _, dup := rs.byName[vr.Name]; dup {
+			return fmt.Errorf("variant %s is defined more than once", vr.Name)
+		}
+		rs.byName[vr.Name] = vr
+		if vr.Builtin != "" && !builtins[vr.Builtin] {
+			return fmt.Errorf("variant %s has unknown builtin %q", vr.Name, vr.Builtin)
+		}
+		if vr.VariantFile.Release != "" {
+			c, err := version.NewConstraint(vr.VariantFile.Release)
+			if err != nil {
+				return fmt.Errorf("variant %s has invalid variantFile release constraint: %w", vr.Name, err)
+			}
+			vr.VariantFile.release = c
+		}
+		for j := range vr.Rules {
+			r := &vr.Rules[j]
+			if len(r.Contains) == 0 && r.Matches == "" && r.Release == "" && len(r.Variants) == 0 && len(r.VariantFile) == 0 {
+				return fmt.Errorf("variant %s rule %d has no conditions", vr.Name, j)
+			}
+			if r.Matches != "" {
+				re, err := regexp.Compile(r.Matches)
+				if err != nil {
+					return fmt.Errorf("variant %s rule %d has invalid regexp: %w", vr.Name, j, err)
+				}
+				r.matches = re
+			}
+			if r.Release != "" {
+				c, err := version.NewConstraint(r.Release)
+				if err != nil {
+					return fmt.Errorf("variant %s rule %d has invalid release constraint: %w", vr.Name, j, err)
+				}
+				r.release = c
+			}
+			if len(r.VariantFile) > 0 {
+				r.variantFile = map[string]*regexp.Regexp{}
+				for k, expr := range r.VariantFile {
+					re, err := regexp.Compile(expr)
+					if err != nil {
+						return fmt.Errorf("variant %s rule %d has invalid variantFile regexp for %s: %w", vr.Name, j, k, err)
+					}
+					r.variantFile[k] = re
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Variant returns the rules for a variant, if the rule set defines it.
+func (rs *RuleSet) Variant(name string) (*VariantRules, bool) {
+	vr, ok := rs.byName[name]
+	return vr, ok
+}
+
+func (rs *RuleSet) ignoresVariantFileKey(key string) bool {
+	for _, k := range rs.IgnoredVariantFileKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleEvaluation is the state for evaluating a rule set against a single job.
+type ruleEvaluation struct {
+	jLog        logrus.FieldLogger
+	jobName     string
+	variantFile map[string]string
+	variants    map[string]string
+	sources     map[string]VariantSource
+
+	releaseResolved bool
+	release         *version.Version
+}
+
+// releaseVersion is resolved once the first release constrained rule needs it, as the release builtin runs first.
+func (e *ruleEvaluation) releaseVersion() *version.Version {
+	if !e.releaseResolved {
+		e.release = releaseVersionFromVariants(e.jLog, e.variants)
+		e.releaseResolved = true
+	}
+	return e.release
+}
+
+func (e *ruleEvaluation) record(source VariantSource) {
+	recordSource(e.sources, source)
+}
+
+func (e *ruleEvaluation) evaluate(vr *VariantRules, builtin func(logrus.FieldLogger, map[string]string, string)) {
+	name := strings.ToLower(e.jobName)
+	for _, ignore := range vr.IgnoredSubstrings {
+		name = strings.ReplaceAll(name, ignore, "-")
+	}
+
+	for i := range vr.Rules {
+		if e.matches(&vr.Rules[i], name) {
+			e.variants[vr.Name] = vr.Rules[i].Value
+			idx := i
+			e.record(VariantSource{Variant: vr.Name, Value: vr.Rules[i].Value, Source: SourceRule, Rule: &idx, Detail: vr.Rules[i].String()})
+			return
+		}
+	}
+
+	if builtin != nil {
+		before := make(map[string]string, len(e.variants))
+		for k, v := range e.variants {
+			before[k] = v
+		}
+		builtin(e.jLog, e.variants, e.jobName)
+		for k, v := range e.variants {
+			if old, ok := before[k]; !ok || old != v {
+				e.record(VariantSource{Variant: k, Value: v, Source: SourceBuiltin, Detail: vr.Builtin})
+			}
+		}
+		for k := range before {
+			if _, ok := e.variants[k]; !ok {
+				delete(e.sources, k)
+			}
+		}
+		e.releaseResolved = false
+	}
+
+	if _, ok := e.variants[vr.Name]; ok {
+		return
+	}
+	if vr.Default != "" {
+		e.variants[vr.Name] = vr.Default
+		e.record(VariantSource{Variant: vr.Name, Value: vr.Default, Source: SourceDefault})
+		return
+	}
+	e.jLog.WithField("jobName", e.jobName).Warnf("unable to determine %s from job name", vr.Name)
+}
+
+func (e *ruleEvaluation) matches(r *Rule, name string) bool {
+	for _, substring := range r.Contains {
+		if !strings.Contains(name, substring) {
+			return false
+		}
+	}
+	if r.matches != nil && !r.matches.MatchString(name) {
+		return false
+	}
+	for k, want := range r.Variants {
+		if e.variants[k] != want {
+			return false
+		}
+	}
+	for k, re := range r.variantFile {
+		v, ok := e.variantFile[k]
+		if !ok || !re.MatchString(v) {
+			return false
+		}
+	}
+	if r.release != nil {
+		rv := e.releaseVersion()
+		if rv == nil || !r.release.Check(rv) {
+			return false
+		}
+	}
+	return true
+}
+
+// String describes a rule's conditions for explaining a job's variants.
+func (r *Rule) String() string {
+	var conditions []string
+	if len(r.Contains) > 0 {
+		conditions = append(conditions, fmt.Sprintf("contains %q", r.Contains))
+	}
+	if r.Matches != "" {
+		conditions = append(conditions, fmt.Sprintf("matches %q", r.Matches))
+	}
+	if r.Release != "" {
+		conditions = append(conditions, fmt.Sprintf("release %s", r.Release))
+	}
+	if len(r.Variants) > 0 {
+		conditions = append(conditions, fmt.Sprintf("variants %v", r.Variants))
+	}
+	if len(r.VariantFile) > 0 {
+		conditions = append(conditions, fmt.Sprintf("variant file %v", r.VariantFile))
+	}
+	return strings.Join(conditions, ", ")
+}
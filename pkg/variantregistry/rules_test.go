@@ -0,0 +1,156 @@
+package variantregistry
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/openshift/sippy/pkg/apis/config/v1"
+)
+
+func TestDefaultRuleSet(t *testing.T) {
+	rs := DefaultRuleSet()
+	require.NotEmpty(t, rs.Variants)
+	assert.Equal(t, VariantRelease, rs.Variants[0].Name, "release must be evaluated first")
+	assert.Equal(t, VariantJobTier, rs.Variants[len(rs.Variants)-1].Name, "job tier relies on other variants")
+}
+
+func TestParseRuleSetValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		err   string
+	}{
+		{
+			name:  "unknown field",
+			rules: "variants:\n  - name: Platform\n    rules:\n      - {value: aws, contain: [-aws]}\n",
+			err:   "field contain not found",
+		},
+		{
+			name:  "duplicate variant",
+			rules: "variants:\n  - name: Platform\n  - name: Platform\n",
+			err:   "defined more than once",
+		},
+		{
+			name:  "unknown builtin",
+			rules: "variants:\n  - name: Release\n    builtin: magic\n",
+			err:   "unknown builtin",
+		},
+		{
+			name:  "rule without conditions",
+			rules: "variants:\n  - name: Platform\n    rules:\n      - {value: aws}\n",
+			err:   "has no conditions",
+		},
+		{
+			name:  "invalid release constraint",
+			rules: "variants:\n  - name: Network\n    rules:\n      - {value: ovn, release: \"newer than 4.12\"}\n",
+			err:   "invalid release constraint",
+		},
+		{
+			name:  "invalid regexp",
+			rules: "variants:\n  - name: Platform\n    rules:\n      - {value: aws, matches: \"(\"}\n",
+			err:   "invalid regexp",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRuleSet([]byte(tt.rules))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestCustomRuleSet(t *testing.T) {
+	rules, err := ParseRuleSet([]byte(`
+ignoredVariantFileKeys: [CloudRegion]
+variants:
+  - name: Release
+    builtin: release
+  - name: Platform
+    variantFile:
+      keepJobNameValues: [neocloud]
+    rules:
+      - {value: neocloud, matches: "-neo(cloud)?-"}
+      - {value: aws, contains: ["-aws"]}
+  - name: Network
+    rules:
+      - {value: ovn, release: ">= 4.12"}
+      - {value: sdn, release: "< 4.12"}
+  - name: Topology
+    default: ha
+    variantFile:
+      preferJobName: true
+    rules:
+      - {value: single, contains: ["-sno-"]}
+  - name: Owner
+    default: eng
+    rules:
+      - {value: neocloud, variants: {Platform: neocloud}}
+  - name: Installer
+    default: ipi
+    rules:
+      - {value: agent, variantFile: {InstallerType: "^agent"}}
+`))
+	require.NoError(t, err)
+	loader := &OCPVariantLoader{config: &v1.SippyConfig{}, rules: rules}
+	log := logrus.WithField("test", "TestCustomRuleSet")
+
+	variants := loader.calculateVariantsForJob(log, "periodic-ci-openshift-release-master-nightly-4.11-e2e-neo-sno-serial",
+		map[string]string{"Platform": "aws", "Topology": "ha", "CloudRegion": "us-east-1", "InstallerType": "agent-based"}, clusterDataOS{})
+	assert.Equal(t, map[string]string{
+		VariantRelease:      "4.11",
+		VariantReleaseMajor: "4",
+		VariantReleaseMinor: "11",
+		VariantUpgrade:      VariantNoValue,
+		VariantPlatform:     "neocloud",
+		VariantNetwork:      "sdn",
+		VariantTopology:     "single",
+		VariantOwner:        "neocloud",
+		VariantInstaller:    "agent",
+		"InstallerType":     "agent-based",
+	}, variants)
+
+	variants = loader.calculateVariantsForJob(log, "periodic-ci-openshift-release-master-nightly-4.20-e2e-aws", map[string]string{"Platform": "gcp"}, clusterDataOS{})
+	assert.Equal(t, "gcp", variants[VariantPlatform], "variants file wins by default")
+	assert.Equal(t, "ovn", variants[VariantNetwork])
+	assert.Equal(t, "eng", variants[VariantOwner])
+	assert.Equal(t, "ipi", variants[VariantInstaller])
+
+	variants = loader.calculateVariantsForJob(log, "periodic-ci-openshift-release-master-nightly-e2e-aws", nil, clusterDataOS{})
+	assert.NotContains(t, variants, VariantNetwork, "release constrained rules do not match unknown releases")
+}
+
+func TestExplainJobVariants(t *testing.T) {
+	loader := &OCPVariantLoader{config: &v1.SippyConfig{
+		Releases: map[string]v1.ReleaseConfig{
+			"4.20": {BlockingJobs: []string{"periodic-ci-openshift-release-master-nightly-4.20-e2e-aws-ovn-serial"}},
+		},
+	}}
+
+	variants, explanation, err := loader.ExplainJobVariants(logrus.WithField("test", "TestExplainJobVariants"),
+		"periodic-ci-openshift-release-master-nightly-4.20-e2e-aws-ovn-serial",
+		[]byte(`{"Platform": "aws", "NetworkStack": "IPv4", "Foo": "bar", "CloudZone": "a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "bar", variants["Foo"])
+	assert.NotContains(t, variants, "CloudZone")
+
+	sources := map[string]VariantSource{}
+	for _, s := range explanation {
+		sources[s.Variant] = s
+	}
+	require.Len(t, sources, len(variants), "every variant is explained")
+
+	assert.Equal(t, SourceRule, sources[VariantPlatform].Source)
+	require.NotNil(t, sources[VariantPlatform].Rule)
+	assert.Equal(t, 4, *sources[VariantPlatform].Rule)
+	assert.Equal(t, `contains ["-aws"]`, sources[VariantPlatform].Detail)
+	assert.Equal(t, SourceBuiltin, sources[VariantReleaseMinor].Source)
+	assert.Equal(t, BuiltinRelease, sources[VariantReleaseMinor].Detail)
+	assert.Equal(t, SourceDefault, sources[VariantNetworkStack].Source)
+	assert.Equal(t, SourceVariantFile, sources["Foo"].Source)
+	assert.Equal(t, "blocking", sources[VariantJobTier].Value)
+	assert.Equal(t, BuiltinReleaseJobTier, sources[VariantJobTier].Detail)
+}
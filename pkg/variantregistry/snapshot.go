@@ -18,21 +18,23 @@ type VariantSnapshot struct {
 	config                       *v1.SippyConfig
 	views                        []crview.View
 	syntheticReleaseJobOverrides map[string]string
+	rules                        *RuleSet
 	log                          logrus.FieldLogger
 }
 
-func NewVariantSnapshot(config *v1.SippyConfig, views []crview.View, syntheticReleaseJobOverrides map[string]string, log logrus.FieldLogger) *VariantSnapshot {
+func NewVariantSnapshot(config *v1.SippyConfig, views []crview.View, syntheticReleaseJobOverrides map[string]string, rules *RuleSet, log logrus.FieldLogger) *VariantSnapshot {
 	return &VariantSnapshot{
 		config:                       config,
 		views:                        views,
 		syntheticReleaseJobOverrides: syntheticReleaseJobOverrides,
+		rules:                        rules,
 		log:                          log,
 	}
 }
 
 func (s *VariantSnapshot) Identify() (JobVariants, error) {
 	newVariants := map[string]map[string]string{}
-	variantSyncer := OCPVariantLoader{config: s.config, views: s.views, syntheticReleaseJobOverrides: s.syntheticReleaseJobOverrides}
+	variantSyncer := OCPVariantLoader{config: s.config, views: s.views, syntheticReleaseJobOverrides: s.syntheticReleaseJobOverrides, rules: s.rules}
 	for _, releaseCfg := range s.config.Releases {
 		for job := range releaseCfg.Jobs {
 			if isIgnoredJob(job) {
@@ -0,0 +1,177 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/sippy/pkg/db"
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+const (
+	EventHeader     = "X-Sippy-Event"
+	EventIDHeader   = "X-Sippy-Event-Id"
+	TimestampHeader = "X-Sippy-Timestamp"
+	// SignatureHeader is "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the
+	// subscription secret. Including the timestamp lets receivers reject replayed deliveries.
+	SignatureHeader = "X-Sippy-Signature"
+
+	maxAttempts = 6
+	batchSize   = 100
+)
+
+// Dispatcher sends queued webhook deliveries, retrying failures with exponential backoff. It implements
+// sippyserver.DaemonProcess.
+type Dispatcher struct {
+	dbc          *db.DB
+	client       *http.Client
+	interval     time.Duration
+	allowedHosts []string
+}
+
+// NewDispatcher creates a dispatcher that only sends to subscription URLs allowed by allowedHosts (see ValidateURL),
+// so a subscription stored before the allowlist changed cannot be used to reach other hosts.
+func NewDispatcher(dbc *db.DB, interval time.Duration, allowedHosts []string) *Dispatcher {
+	return &Dispatcher{
+		dbc: dbc,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			// a redirect could lead anywhere, so report it as the response rather than following it
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		interval:     interval,
+		allowedHosts: allowedHosts,
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("webhook dispatcher shutting down")
+			return
+		case <-ticker.C:
+			if err := d.dispatchPending(ctx); err != nil {
+				log.WithError(err).Error("error dispatching webhook deliveries")
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) dispatchPending(ctx context.Context) error {
+	var deliveries []models.WebhookDelivery
+	res := d.dbc.DB.WithContext(ctx).
+		Preload("Subscription").
+		Where("delivered IS NULL AND NOT failed AND next_attempt <= ?", time.Now()).
+		Order("id").
+		Limit(batchSize).
+		Find(&deliveries)
+	if res.Error != nil {
+		return fmt.Errorf("error listing pending webhook deliveries: %w", res.Error)
+	}
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return nil
+		}
+		delivery := &deliveries[i]
+		if delivery.Subscription == nil || delivery.Subscription.Disabled {
+			delivery.Failed = true
+			delivery.LastError = "subscription disabled"
+		} else if err := ValidateURL(delivery.Subscription.URL, d.allowedHosts); err != nil {
+			delivery.Failed = true
+			delivery.LastError = err.Error()
+		} else {
+			statusCode, err := d.send(ctx, *delivery.Subscription, *delivery)
+			recordAttempt(delivery, statusCode, err, time.Now())
+		}
+		if res := d.dbc.DB.Omit("Subscription").Save(delivery); res.Error != nil {
+			return fmt.Errorf("error updating webhook delivery %d: %w", delivery.ID, res.Error)
+		}
+	}
+	return nil
+}
+
+// recordAttempt updates the delivery with the outcome of an attempt, scheduling a retry after a failure.
+func recordAttempt(delivery *models.WebhookDelivery, statusCode int, err error, now time.Time) {
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+	if err == nil {
+		delivery.Delivered = sql.NullTime{Valid: true, Time: now}
+		return
+	}
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= maxAttempts {
+		delivery.Failed = true
+		log.WithError(err).Warningf("giving up on webhook delivery %d after %d attempts", delivery.ID, delivery.Attempts)
+		return
+	}
+	delivery.NextAttempt = now.Add(time.Minute << (delivery.Attempts - 1))
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sippy-webhooks")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(EventIDHeader, delivery.EventID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// ValidateURL checks that a subscription URL is an https URL whose host is one of allowedHosts or a subdomain of
+// one, so subscriptions cannot direct sippy at internal services. No URL is valid when allowedHosts is empty.
+func ValidateURL(rawURL string, allowedHosts []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return fmt.Errorf("url must be an absolute https URL without credentials: %q", rawURL)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(strings.TrimPrefix(allowed, "."))
+		if allowed != "" && (host == allowed || strings.HasSuffix(host, "."+allowed)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook host %q is not allowed, must be one of %v or a subdomain", host, allowedHosts)
+}
+
+// Sign returns the signature header value for a payload.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,127 @@
+// Package webhooks notifies subscribed endpoints of component readiness regression and triage lifecycle events.
+//
+// Events are recorded as deliveries, one per matching subscription, at the point they happen (the regression
+// tracker, triage API and bug loader). The Dispatcher, run by sippy-daemon, sends them with an HMAC signature and
+// retries failures, so an unavailable endpoint never slows down or breaks the code producing events.
+package webhooks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+type EventType string
+
+const (
+	RegressionOpened   EventType = "regression.opened"
+	RegressionReopened EventType = "regression.reopened"
+	RegressionClosed   EventType = "regression.closed"
+	// RegressionFailedAfterResolution is sent the first time a regression fails after a triage it belongs to was
+	// marked resolved, which usually means the fix did not work.
+	RegressionFailedAfterResolution EventType = "regression.failed_after_resolution"
+	TriageCreated                   EventType = "triage.created"
+	TriageUpdated                   EventType = "triage.updated"
+	TriageResolved                  EventType = "triage.resolved"
+)
+
+var EventTypes = []EventType{
+	RegressionOpened,
+	RegressionReopened,
+	RegressionClosed,
+	RegressionFailedAfterResolution,
+	TriageCreated,
+	TriageUpdated,
+	TriageResolved,
+}
+
+// DefaultBaseURL is the default for the --webhook-base-url flag, used for event links when the event is not
+// produced while serving an API request.
+const DefaultBaseURL = "https://sippy.dptools.openshift.org"
+
+const (
+	regressionLink = "%s/api/component_readiness/regressions/%d"
+	triageLink     = "%s/api/component_readiness/triages/%d"
+)
+
+func ValidEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if string(t) == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the JSON body posted to subscribers.
+type Event struct {
+	ID         string                 `json:"id"`
+	Type       EventType              `json:"type"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Regression *models.TestRegression `json:"regression,omitempty"`
+	Triage     *models.Triage         `json:"triage,omitempty"`
+	// Views are the component readiness views the affected regressions have been seen in.
+	Views []string          `json:"views,omitempty"`
+	Links map[string]string `json:"links"`
+}
+
+// NewRegressionEvent creates an event for a regression. views may be given when known, for example the view
+// a regression was opened in; otherwise they are looked up when the event is recorded.
+func NewRegressionEvent(eventType EventType, regression models.TestRegression, views ...string) Event {
+	return Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		Timestamp:  time.Now().UTC(),
+		Regression: trimRegression(regression),
+		Views:      views,
+	}
+}
+
+// NewTriageEvent creates an event for a triage and its regressions.
+func NewTriageEvent(eventType EventType, triage models.Triage) Event {
+	regressions := make([]models.TestRegression, 0, len(triage.Regressions))
+	for _, r := range triage.Regressions {
+		regressions = append(regressions, *trimRegression(r))
+	}
+	triage.Regressions = regressions
+	triage.Bug = nil
+	return Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Triage:    &triage,
+	}
+}
+
+// trimRegression drops the associations that would make payloads large or recursive.
+func trimRegression(regression models.TestRegression) *models.TestRegression {
+	regression.JobRuns = nil
+	regression.Triages = nil
+	regression.Views = nil
+	regression.Links = nil
+	return &regression
+}
+
+// regressions returns the regressions the event is about, which subscription filters are matched against.
+func (e Event) regressions() []models.TestRegression {
+	if e.Regression != nil {
+		return []models.TestRegression{*e.Regression}
+	}
+	if e.Triage != nil {
+		return e.Triage.Regressions
+	}
+	return nil
+}
+
+func (e *Event) injectLinks(baseURL string) {
+	e.Links = map[string]string{}
+	if e.Regression != nil {
+		e.Links["regression"] = fmt.Sprintf(regressionLink, baseURL, e.Regression.ID)
+	}
+	if e.Triage != nil {
+		e.Links["triage"] = fmt.Sprintf(triageLink, baseURL, e.Triage.ID)
+	}
+}
@@ -0,0 +1,134 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/sippy/pkg/db/models"
+)
+
+// Notify records a delivery of each event for every enabled subscription it matches. The dispatcher sends them.
+func Notify(dbc *gorm.DB, baseURL string, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	var subscriptions []models.WebhookSubscription
+	if res := dbc.Where("disabled = ?", false).Find(&subscriptions); res.Error != nil {
+		return fmt.Errorf("error listing webhook subscriptions: %w", res.Error)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, event := range events {
+		if len(event.Views) == 0 {
+			views, err := regressionViews(dbc, event.regressions())
+			if err != nil {
+				return err
+			}
+			event.Views = views
+		}
+		event.injectLinks(baseURL)
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("error marshaling webhook event: %w", err)
+		}
+		for _, sub := range subscriptions {
+			if !Matches(sub, event) {
+				continue
+			}
+			deliveries = append(deliveries, models.WebhookDelivery{
+				SubscriptionID: sub.ID,
+				EventID:        event.ID,
+				EventType:      string(event.Type),
+				Payload:        payload,
+				NextAttempt:    time.Now(),
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if res := dbc.Create(&deliveries); res.Error != nil {
+		return fmt.Errorf("error recording webhook deliveries: %w", res.Error)
+	}
+	log.Infof("queued %d webhook deliveries for %d events", len(deliveries), len(events))
+	return nil
+}
+
+// regressionViews returns the views the regressions have been seen in, active or not, since subscribers
+// filtering on a view want to hear about a regression closing in it too.
+func regressionViews(dbc *gorm.DB, regressions []models.TestRegression) ([]string, error) {
+	ids := make([]uint, 0, len(regressions))
+	for _, r := range regressions {
+		ids = append(ids, r.ID)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var views []string
+	res := dbc.Model(&models.RegressionView{}).
+		Where("test_regression_id IN ?", ids).
+		Distinct().Order("view_name").Pluck("view_name", &views)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error looking up regression views: %w", res.Error)
+	}
+	return views, nil
+}
+
+// Matches reports whether a subscription wants an event. Component, capability and variant filters must all
+// match one of the event's regressions, so a triage event matches if any of its regressions does.
+func Matches(sub models.WebhookSubscription, event Event) bool {
+	if len(sub.EventTypes) > 0 && !sets.New[string](sub.EventTypes...).Has(string(event.Type)) {
+		return false
+	}
+	if len(sub.Views) > 0 && !sets.New[string](sub.Views...).HasAny(event.Views...) {
+		return false
+	}
+	if len(sub.Components) == 0 && len(sub.Capabilities) == 0 && len(sub.Variants) == 0 {
+		return true
+	}
+
+	variantFilter := map[string]sets.Set[string]{}
+	for _, v := range sub.Variants {
+		name, value, _ := strings.Cut(v, ":")
+		if variantFilter[name] == nil {
+			variantFilter[name] = sets.New[string]()
+		}
+		variantFilter[name].Insert(value)
+	}
+	for _, r := range event.regressions() {
+		if len(sub.Components) > 0 && !sets.New[string](sub.Components...).Has(r.Component) {
+			continue
+		}
+		if len(sub.Capabilities) > 0 && !sets.New[string](sub.Capabilities...).Has(r.Capability) {
+			continue
+		}
+		if matchesVariants(variantFilter, r.Variants) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesVariants(filter map[string]sets.Set[string], variants []string) bool {
+	values := map[string]string{}
+	for _, v := range variants {
+		name, value, _ := strings.Cut(v, ":")
+		values[name] = value
+	}
+	for name, allowed := range filter {
+		value, ok := values[name]
+		if !ok || !allowed.Has(value) {
+			return false
+		}
+	}
+	return true
+}
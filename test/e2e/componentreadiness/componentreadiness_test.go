@@ -67,7 +67,7 @@ func TestRegressionCacheLoader(t *testing.T) {
 	require.NoError(t, err, "error getting releases from bigquery")
 
 	// Build a regression store
-	regressionStore := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	regressionStore := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 
 	// Create and run the loader
 	loader, err := regressioncacheloader.New(
@@ -36,7 +36,7 @@ func cleanupAllRegressions(dbc *db.DB) {
 func Test_RegressionTracker(t *testing.T) {
 	dbc := util.CreateE2EPostgresConnection(t)
 	// Pass nil jiraClient as we don't want to comment on jiras in the e2e test
-	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 	newRegression := componentreport.ReportTestSummary{
 		TestComparison: testdetails.TestComparison{
 			BaseStats: &testdetails.ReleaseStats{
@@ -280,7 +280,7 @@ func cleanupJobRuns(dbc *db.DB) {
 
 func Test_RegressionJobRuns(t *testing.T) {
 	dbc := util.CreateE2EPostgresConnection(t)
-	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 	view := crview.View{
 		Name: "4.19-main",
 		SampleRelease: reqopts.RelativeRelease{
@@ -442,7 +442,7 @@ func cleanupRegressionViews(dbc *db.DB) {
 
 func Test_RegressionViews(t *testing.T) {
 	dbc := util.CreateE2EPostgresConnection(t)
-	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 	view := crview.View{
 		Name: "4.19-main",
 		SampleRelease: reqopts.RelativeRelease{
@@ -713,7 +713,7 @@ func Test_RegressionViews(t *testing.T) {
 
 func Test_CrossCompareIsolation(t *testing.T) {
 	dbc := util.CreateE2EPostgresConnection(t)
-	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 	rLog := log.WithField("test", "cross-compare-isolation")
 
 	standardView := crview.View{
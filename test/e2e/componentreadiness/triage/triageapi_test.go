@@ -52,7 +52,7 @@ func cleanupAllTriages(dbc *db.DB) {
 func Test_TriageAPI(t *testing.T) {
 	dbc := util.CreateE2EPostgresConnection(t)
 	// jiraClient is intentionally nil to prevent commenting on jiras
-	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 
 	jiraBug := createBug(t, dbc.DB)
 	defer dbc.DB.Delete(jiraBug)
@@ -536,7 +536,7 @@ func Test_TriageAPI(t *testing.T) {
 func Test_RegressionAPI(t *testing.T) {
 	dbc := util.CreateE2EPostgresConnection(t)
 	// jiraClient is intentionally nil to prevent commenting on jiras
-	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 
 	testRegression1 := createTestRegression(t, tracker, view, "faketestid1")
 	defer dbc.DB.Delete(testRegression1)
@@ -600,7 +600,7 @@ func Test_RegressionAPI(t *testing.T) {
 // Test_RegressionPotentialMatchingTriages tests the /api/component_readiness/regressions/{id}/matches endpoint
 func Test_RegressionPotentialMatchingTriages(t *testing.T) {
 	dbc := util.CreateE2EPostgresConnection(t)
-	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 
 	jiraBug := createBug(t, dbc.DB)
 	defer dbc.DB.Delete(jiraBug)
@@ -832,7 +832,7 @@ func Test_TriageRawDB(t *testing.T) {
 	dbc := util.CreateE2EPostgresConnection(t)
 	dbWithContext := dbc.DB.WithContext(context.WithValue(context.TODO(), models.CurrentUserKey, "developer"))
 	// jiraClient is intentionally nil to prevent commenting on jiras
-	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 
 	testRegression := createTestRegression(t, tracker, view, "faketestid")
 	defer dbc.DB.Delete(testRegression)
@@ -981,7 +981,7 @@ func assertTriageDataMatches(t *testing.T, expectedTriage, actualTriage models.T
 // Test_TriagePotentialMatchingRegressions tests the /api/component_readiness/triages/{id}/matches endpoint
 func Test_TriagePotentialMatchingRegressions(t *testing.T) {
 	dbc := util.CreateE2EPostgresConnection(t)
-	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil)
+	tracker := componentreadiness.NewPostgresRegressionStore(dbc, nil, "")
 
 	// Create test regressions with various characteristics for matching.
 	// We use job run overlap and name similarity as matching signals.